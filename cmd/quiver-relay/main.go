@@ -0,0 +1,64 @@
+// Command quiver-relay is the reference relay for the netbridge
+// relay strategy. Hosts that cannot forward ports connect to it
+// with one of its tokens and have it listen for players on their
+// behalf.
+package main
+
+import (
+	"errors"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/rabbytesoftware/quiver/internal/infrastructure/netbridge/relay"
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+)
+
+var errNoTokens = errors.New("no tokens given, set -tokens or QUIVER_RELAY_TOKENS")
+
+func main() {
+	listen := flag.String("listen", ":7000", "address hosts connect to")
+	tokens := flag.String("tokens", os.Getenv("QUIVER_RELAY_TOKENS"), "comma separated tokens hosts authenticate with")
+	ports := flag.String("ports", "", "ports and ranges hosts may expose, such as 27000-28000; any when empty")
+	flag.Parse()
+
+	server, err := newServer(*tokens, *ports)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	go func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		<-signals
+
+		server.Close()
+	}()
+
+	log.Printf("quiver-relay listening on %s", *listen)
+	if err := server.ListenAndServe(*listen); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func newServer(tokens string, ports string) (*relay.Server, error) {
+	var accepted []string
+	for _, token := range strings.Split(tokens, ",") {
+		if token = strings.TrimSpace(token); token != "" {
+			accepted = append(accepted, token)
+		}
+	}
+	if len(accepted) == 0 {
+		return nil, errNoTokens
+	}
+
+	allowed, err := port.ParseRanges(ports)
+	if err != nil {
+		return nil, err
+	}
+
+	return relay.NewServer(accepted, allowed), nil
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewServer(t *testing.T) {
+	if _, err := newServer("alpha, beta", "27000-28000"); err != nil {
+		t.Errorf("newServer() returned error: %v", err)
+	}
+
+	if _, err := newServer(" , ", ""); !errors.Is(err, errNoTokens) {
+		t.Errorf("Expected errNoTokens, got %v", err)
+	}
+
+	if _, err := newServer("alpha", "28000-27000"); err == nil {
+		t.Error("Expected an invalid port range to be refused")
+	}
+}
@@ -30,4 +30,6 @@ func main() {
 	if err != nil {
 		watcher.Unforeseen(errors.Throw(errors.FailedDependency, err.Error(), nil))
 	}
+
+	internal.Shutdown()
 }
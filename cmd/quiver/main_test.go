@@ -11,6 +11,7 @@ import (
 
 func TestMainComponents(t *testing.T) {
 	// Test that we can create the internal components
+	t.Setenv("QUIVER_DATABASE_PATH", t.TempDir())
 	internal := internal.NewInternal()
 	if internal == nil {
 		t.Error("Expected internal to be created")
@@ -35,6 +36,7 @@ func TestMainComponents(t *testing.T) {
 
 func TestMainLogic(t *testing.T) {
 	// Test the main logic without actually running main()
+	t.Setenv("QUIVER_DATABASE_PATH", t.TempDir())
 	internal := internal.NewInternal()
 	_ = internal.GetCore().GetWatcher()
 
@@ -70,6 +72,7 @@ func TestMainLogic(t *testing.T) {
 
 // Test that internal.Run() can be called without panicking
 func TestInternalRun(t *testing.T) {
+	t.Setenv("QUIVER_DATABASE_PATH", t.TempDir())
 	internal := internal.NewInternal()
 
 	// Start internal.Run() in a goroutine and stop it quickly
@@ -376,3 +376,174 @@ func TestQueryService_EdgeCases(t *testing.T) {
 		t.Errorf("Expected empty body, got %q", body)
 	}
 }
+
+func TestQueryService_ArrowDryRunQueries(t *testing.T) {
+	service := &QueryService{}
+	if err := service.loadFromMemory(); err != nil {
+		t.Fatalf("loadFromMemory() returned error: %v", err)
+	}
+	m := NewMatcher(service.Queries)
+
+	testCases := []struct {
+		input  string
+		url    string
+		method string
+	}{
+		{"arrow add cs2", "/api/v1/arrow/${arg1}/install", "POST"},
+		{"arrow add cs2 --dry-run", "/api/v1/arrow/${arg1}/install?dry_run=true", "POST"},
+		{"arrow update cs2 --dry-run", "/api/v1/arrow/${arg1}/update?dry_run=true", "PUT"},
+		{"arrow remove cs2", "/api/v1/arrow/${arg1}", "DELETE"},
+		{"arrow remove cs2 --dry-run", "/api/v1/arrow/${arg1}?dry_run=true", "DELETE"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			match, err := m.Match(tc.input)
+			if err != nil || match.REST == nil {
+				t.Fatalf("Expected %q to match a query, got %v", tc.input, err)
+			}
+			if match.REST.URL != tc.url || match.REST.Method != tc.method {
+				t.Errorf("Expected %s %s, got %s %s", tc.method, tc.url, match.REST.Method, match.REST.URL)
+			}
+			if match.Variables["arg1"] != "cs2" {
+				t.Errorf("Expected arg1 to be cs2, got %q", match.Variables["arg1"])
+			}
+		})
+	}
+}
+
+func TestQueryService_ArrowPolicyQueries(t *testing.T) {
+	service := &QueryService{}
+	if err := service.loadFromMemory(); err != nil {
+		t.Fatalf("loadFromMemory() returned error: %v", err)
+	}
+	m := NewMatcher(service.Queries)
+
+	testCases := []struct {
+		input  string
+		url    string
+		window string
+	}{
+		{"arrow policy cs2 automatic *+4-5+*+*+*", "/api/v1/arrow/${arg1}/policy?mode=${arg2}&window=${arg3}", "*+4-5+*+*+*"},
+		{"arrow policy cs2 notify", "/api/v1/arrow/${arg1}/policy?mode=${arg2}", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			match, err := m.Match(tc.input)
+			if err != nil || match.REST == nil {
+				t.Fatalf("Expected %q to match a query, got %v", tc.input, err)
+			}
+			if match.REST.URL != tc.url || match.REST.Method != "PUT" {
+				t.Errorf("Expected PUT %s, got %s %s", tc.url, match.REST.Method, match.REST.URL)
+			}
+			if match.Variables["arg3"] != tc.window {
+				t.Errorf("Expected arg3 to be %q, got %q", tc.window, match.Variables["arg3"])
+			}
+		})
+	}
+}
+
+func TestQueryService_InstanceQueries(t *testing.T) {
+	service := &QueryService{}
+	if err := service.loadFromMemory(); err != nil {
+		t.Fatalf("loadFromMemory() returned error: %v", err)
+	}
+	m := NewMatcher(service.Queries)
+
+	testCases := []struct {
+		input  string
+		url    string
+		method string
+	}{
+		{"arrow backup cs2 --quiesce", "/api/v1/arrow/${arg1}/backups?quiesce=true", "POST"},
+		{"arrow backup cs2", "/api/v1/arrow/${arg1}/backups", "POST"},
+		{"arrow backups cs2", "/api/v1/arrow/${arg1}/backups", "GET"},
+		{"arrow backups remove cs2 20250701T040000.000Z", "/api/v1/arrow/${arg1}/backups/${arg2}", "DELETE"},
+		{"arrow restore cs2 20250701T040000.000Z", "/api/v1/arrow/${arg1}/backups/${arg2}/restore", "POST"},
+		{"arrow retention cs2 7 30", "/api/v1/arrow/${arg1}/backups/retention?keep=${arg2}&max_age_days=${arg3}", "PUT"},
+		{"arrow retention cs2 7", "/api/v1/arrow/${arg1}/backups/retention?keep=${arg2}", "PUT"},
+		{"arrow remove cs2 --purge", "/api/v1/arrow/${arg1}?purge=true", "DELETE"},
+		{"arrow remove cs2", "/api/v1/arrow/${arg1}", "DELETE"},
+		{"arrow purge cs2", "/api/v1/arrow/${arg1}/data", "DELETE"},
+		{"arrow templates cs2", "/api/v1/arrow/${arg1}/templates", "GET"},
+		{"arrow render cs2 --force", "/api/v1/arrow/${arg1}/templates/render?force=true", "POST"},
+		{"arrow render cs2", "/api/v1/arrow/${arg1}/templates/render", "POST"},
+		{"arrow variables cs2", "/api/v1/arrow/${arg1}/variables", "GET"},
+		{"arrow set cs2 PORT 27016 --restart", "/api/v1/arrow/${arg1}/variables?name=${arg2}&value=${arg3}&restart=true", "PUT"},
+		{"arrow set cs2 PORT 27016", "/api/v1/arrow/${arg1}/variables?name=${arg2}&value=${arg3}", "PUT"},
+		{"arrow unset cs2 PORT", "/api/v1/arrow/${arg1}/variables?name=${arg2}", "PUT"},
+		{"arrow audit cs2", "/api/v1/arrow/${arg1}/variables/audit", "GET"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			match, err := m.Match(tc.input)
+			if err != nil || match.REST == nil {
+				t.Fatalf("Expected %q to match a query, got %v", tc.input, err)
+			}
+			if match.REST.URL != tc.url || match.REST.Method != tc.method {
+				t.Errorf("Expected %s %s, got %s %s", tc.method, tc.url, match.REST.Method, match.REST.URL)
+			}
+		})
+	}
+}
+
+func TestQueryService_SystemQueries(t *testing.T) {
+	service := &QueryService{}
+	if err := service.loadFromMemory(); err != nil {
+		t.Fatalf("loadFromMemory() returned error: %v", err)
+	}
+
+	testCases := []struct {
+		input string
+		url   string
+	}{
+		{"system host", "/api/v1/system/host"},
+		{"system netbridge", "/api/v1/system/netbridge"},
+	}
+
+	for _, tc := range testCases {
+		match, err := NewMatcher(service.Queries).Match(tc.input)
+		if err != nil || match.REST == nil {
+			t.Fatalf("Expected %q to match a query, got %v", tc.input, err)
+		}
+		if match.REST.URL != tc.url || match.REST.Method != "GET" {
+			t.Errorf("Expected GET %s, got %s %s", tc.url, match.REST.Method, match.REST.URL)
+		}
+	}
+}
+
+func TestQueryService_TaskQueries(t *testing.T) {
+	service := &QueryService{}
+	if err := service.loadFromMemory(); err != nil {
+		t.Fatalf("loadFromMemory() returned error: %v", err)
+	}
+	m := NewMatcher(service.Queries)
+
+	testCases := []struct {
+		input  string
+		url    string
+		method string
+	}{
+		{"task list", "/api/v1/task", "GET"},
+		{"task add cs2 method rotate_map */30+*+*+*+*", "/api/v1/task?arrow=${arg1}&action=method&method=${arg2}&cron=${arg3}", "POST"},
+		{"task add cs2 restart 0+4+*+*+*", "/api/v1/task?arrow=${arg1}&action=${arg2}&cron=${arg3}", "POST"},
+		{"task add cs2 backup @daily --quiesce", "/api/v1/task?arrow=${arg1}&action=backup&quiesce=true&cron=${arg2}", "POST"},
+		{"task add cs2 backup @daily", "/api/v1/task?arrow=${arg1}&action=${arg2}&cron=${arg3}", "POST"},
+		{"task run 7c9e6679-7425-40de-944b-e07fc1f90ae7", "/api/v1/task/${arg1}/run", "POST"},
+		{"task remove 7c9e6679-7425-40de-944b-e07fc1f90ae7", "/api/v1/task/${arg1}", "DELETE"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			match, err := m.Match(tc.input)
+			if err != nil || match.REST == nil {
+				t.Fatalf("Expected %q to match a query, got %v", tc.input, err)
+			}
+			if match.REST.URL != tc.url || match.REST.Method != tc.method {
+				t.Errorf("Expected %s %s, got %s %s", tc.method, tc.url, match.REST.Method, match.REST.URL)
+			}
+		})
+	}
+}
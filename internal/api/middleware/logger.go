@@ -2,9 +2,13 @@ package middleware
 
 import (
 	"fmt"
+	"net/url"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/rabbytesoftware/quiver/internal/core/secrets"
 	"github.com/rabbytesoftware/quiver/internal/core/watcher"
 	"github.com/sirupsen/logrus"
 )
@@ -25,7 +29,7 @@ func WatcherLogger() gin.HandlerFunc {
 		bodySize := c.Writer.Size()
 
 		if raw != "" {
-			path = path + "?" + raw
+			path = path + "?" + redactQuery(raw)
 		}
 
 		message := fmt.Sprintf("%s %s %d %v %s %d",
@@ -61,6 +65,33 @@ func WatcherLogger() gin.HandlerFunc {
 	}
 }
 
+// sensitiveParams are query parameters that may carry a secret,
+// such as the value of a sensitive variable being set.
+var sensitiveParams = []string{"value", "password", "token", "secret"}
+
+// redactQuery masks sensitive parameters of a raw query string. They
+// cannot be left to the watcher, since a request may fail before its
+// values are registered as secrets.
+func redactQuery(raw string) string {
+	pairs := strings.Split(raw, "&")
+
+	for i, pair := range pairs {
+		key, _, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+
+		if name, err := url.QueryUnescape(key); err == nil && slices.ContainsFunc(
+			sensitiveParams,
+			func(param string) bool { return strings.EqualFold(name, param) },
+		) {
+			pairs[i] = key + "=" + secrets.Mask
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
+
 func WatcherRecovery() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		defer func() {
@@ -61,6 +61,24 @@ func TestWatcherLogger_WithQuery(t *testing.T) {
 	}
 }
 
+func TestRedactQuery(t *testing.T) {
+	testCases := []struct {
+		raw      string
+		expected string
+	}{
+		{"name=PASSWORD&value=hunter2&restart=true", "name=PASSWORD&value=********&restart=true"},
+		{"Token=abc", "Token=********"},
+		{"force=true", "force=true"},
+		{"name=PORT&value", "name=PORT&value"},
+	}
+
+	for _, tc := range testCases {
+		if got := redactQuery(tc.raw); got != tc.expected {
+			t.Errorf("redactQuery(%q) = %q, expected %q", tc.raw, got, tc.expected)
+		}
+	}
+}
+
 func TestWatcherLogger_ErrorStatus(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -0,0 +1,138 @@
+package arrows
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+)
+
+// Backup archives an installed arrow. With quiesce=true a
+// running instance is stopped while the archive is written.
+func (h *ArrowsHandler) Backup() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		namespace, ok := namespaceParam(c)
+		if !ok {
+			return
+		}
+
+		quiesce, err := strconv.ParseBool(c.DefaultQuery("quiesce", "false"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "invalid quiesce value: " + c.Query("quiesce"),
+			})
+			return
+		}
+
+		backup, err := h.usecases.Backup(c.Request.Context(), namespace, quiesce)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, backup)
+	}
+}
+
+// Backups lists the backups of an installed arrow, newest first.
+func (h *ArrowsHandler) Backups() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		namespace, ok := namespaceParam(c)
+		if !ok {
+			return
+		}
+
+		backups, err := h.usecases.Backups(c.Request.Context(), namespace)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, backups)
+	}
+}
+
+func (h *ArrowsHandler) RestoreBackup() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		namespace, ok := namespaceParam(c)
+		if !ok {
+			return
+		}
+
+		if err := h.usecases.RestoreBackup(c.Request.Context(), namespace, c.Param("id")); err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"restored": c.Param("id"),
+		})
+	}
+}
+
+func (h *ArrowsHandler) DeleteBackup() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		namespace, ok := namespaceParam(c)
+		if !ok {
+			return
+		}
+
+		if err := h.usecases.DeleteBackup(c.Request.Context(), namespace, c.Param("id")); err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// SetBackupRetention changes how many backups of an installed arrow
+// are kept. The retention is read from a JSON body, or from the keep
+// and max_age_days query parameters when there is none.
+func (h *ArrowsHandler) SetBackupRetention() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		namespace, ok := namespaceParam(c)
+		if !ok {
+			return
+		}
+
+		var retention arrow.BackupRetention
+
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&retention); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+		} else {
+			for param, value := range map[string]*int{
+				"keep":         &retention.Keep,
+				"max_age_days": &retention.MaxAgeDays,
+			} {
+				raw := c.Query(param)
+				if raw == "" {
+					continue
+				}
+
+				parsed, err := strconv.Atoi(raw)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{
+						"error": "invalid " + param + ": " + raw,
+					})
+					return
+				}
+				*value = parsed
+			}
+		}
+
+		updated, err := h.usecases.SetBackupRetention(c.Request.Context(), namespace, retention)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, updated)
+	}
+}
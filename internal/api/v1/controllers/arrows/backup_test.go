@@ -0,0 +1,64 @@
+package arrows
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestArrowsHandler_Backups(t *testing.T) {
+	router := newTestRouter(t)
+
+	testCases := []struct {
+		name   string
+		method string
+		path   string
+		status int
+	}{
+		{"backup", http.MethodPost, "/api/v1/arrow/cs2/backups?quiesce=true", http.StatusNotFound},
+		{"invalid quiesce", http.MethodPost, "/api/v1/arrow/cs2/backups?quiesce=maybe", http.StatusBadRequest},
+		{"list", http.MethodGet, "/api/v1/arrow/cs2/backups", http.StatusNotFound},
+		{"restore", http.MethodPost, "/api/v1/arrow/cs2/backups/20250701T040000.000Z/restore", http.StatusNotFound},
+		{"delete", http.MethodDelete, "/api/v1/arrow/cs2/backups/20250701T040000.000Z", http.StatusNotFound},
+		{"invalid namespace", http.MethodGet, "/api/v1/arrow/cs2@nope/backups", http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := perform(router, tc.method, tc.path)
+			if recorder.Code != tc.status {
+				t.Errorf("Expected status %d, got %d: %s", tc.status, recorder.Code, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestArrowsHandler_SetBackupRetention(t *testing.T) {
+	router := newTestRouter(t)
+
+	testCases := []struct {
+		name   string
+		path   string
+		body   string
+		status int
+	}{
+		{"query", "/api/v1/arrow/cs2/backups/retention?keep=7&max_age_days=30", "", http.StatusNotFound},
+		{"body", "/api/v1/arrow/cs2/backups/retention", `{"keep": 7}`, http.StatusNotFound},
+		{"negative", "/api/v1/arrow/cs2/backups/retention?keep=-1", "", http.StatusBadRequest},
+		{"not a number", "/api/v1/arrow/cs2/backups/retention?keep=many", "", http.StatusBadRequest},
+		{"malformed body", "/api/v1/arrow/cs2/backups/retention", `{"keep":`, http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPut, tc.path, strings.NewReader(tc.body))
+			router.ServeHTTP(recorder, request)
+
+			if recorder.Code != tc.status {
+				t.Errorf("Expected status %d, got %d: %s", tc.status, recorder.Code, recorder.Body.String())
+			}
+		})
+	}
+}
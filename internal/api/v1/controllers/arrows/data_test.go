@@ -0,0 +1,29 @@
+package arrows
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestArrowsHandler_PurgeData(t *testing.T) {
+	router := newTestRouter(t)
+
+	testCases := []struct {
+		name   string
+		path   string
+		status int
+	}{
+		{"purge leftover data", "/api/v1/arrow/cs2/data", http.StatusOK},
+		{"invalid namespace", "/api/v1/arrow/cs2@nope/data", http.StatusBadRequest},
+		{"uninstall with purge", "/api/v1/arrow/cs2?purge=true", http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := perform(router, http.MethodDelete, tc.path)
+			if recorder.Code != tc.status {
+				t.Errorf("Expected status %d, got %d: %s", tc.status, recorder.Code, recorder.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package arrows
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/runtime"
+	usecase "github.com/rabbytesoftware/quiver/internal/usecases/arrows"
+)
+
+type ArrowsHandler struct {
+	usecases *usecase.ArrowsUsecase
+}
+
+func NewArrowsHandler(
+	usecases *usecase.ArrowsUsecase,
+) *ArrowsHandler {
+	return &ArrowsHandler{
+		usecases: usecases,
+	}
+}
+
+// Install installs an arrow and its dependencies, or only previews
+// the plan when called with ?dry_run=true.
+func (h *ArrowsHandler) Install() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		namespace, ok := namespaceParam(c)
+		if !ok {
+			return
+		}
+
+		if dryRun(c) {
+			h.preview(c, namespace, runtime.ActionInstall)
+			return
+		}
+
+		plan, err := h.usecases.Install(c.Request.Context(), namespace)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, plan)
+	}
+}
+
+func (h *ArrowsHandler) Update() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		namespace, ok := namespaceParam(c)
+		if !ok {
+			return
+		}
+
+		if dryRun(c) {
+			h.preview(c, namespace, runtime.ActionUpdate)
+			return
+		}
+
+		plan, err := h.usecases.Update(c.Request.Context(), namespace)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, plan)
+	}
+}
+
+func (h *ArrowsHandler) Uninstall() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		namespace, ok := namespaceParam(c)
+		if !ok {
+			return
+		}
+
+		if dryRun(c) {
+			h.preview(c, namespace, runtime.ActionUninstall)
+			return
+		}
+
+		purge, _ := strconv.ParseBool(c.DefaultQuery("purge", "false"))
+
+		if err := h.usecases.Uninstall(c.Request.Context(), namespace, purge); err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": namespace.String() + " uninstalled",
+		})
+	}
+}
+
+// PurgeData deletes the data directory an uninstalled arrow left behind.
+func (h *ArrowsHandler) PurgeData() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		namespace, ok := namespaceParam(c)
+		if !ok {
+			return
+		}
+
+		if err := h.usecases.PurgeData(c.Request.Context(), namespace); err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": namespace.Name() + " data purged",
+		})
+	}
+}
+
+// Outdated lists installed arrows with newer releases. The last
+// periodic check is returned unless called with ?refresh=true.
+func (h *ArrowsHandler) Outdated() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		refresh, _ := strconv.ParseBool(c.DefaultQuery("refresh", "false"))
+
+		c.JSON(http.StatusOK, h.usecases.Outdated(c.Request.Context(), refresh))
+	}
+}
+
+func (h *ArrowsHandler) preview(
+	c *gin.Context,
+	namespace arrow.ArrowNamespace,
+	action runtime.Action,
+) {
+	preview, err := h.usecases.Preview(c.Request.Context(), namespace, action)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
+
+func namespaceParam(c *gin.Context) (arrow.ArrowNamespace, bool) {
+	// ? A bare arrow name is accepted here and means the newest
+	// ? release, so only the name and constraint are checked.
+	namespace := arrow.ArrowNamespace(c.Param("namespace"))
+	if _, err := namespace.Constraint(); err != nil || namespace.Name() == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid arrow namespace " + strconv.Quote(namespace.String()),
+		})
+		return "", false
+	}
+
+	return namespace, true
+}
+
+func dryRun(c *gin.Context) bool {
+	value, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	return err == nil && value
+}
+
+func respondError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+
+	switch {
+	case errors.Is(err, usecase.ErrArrowNotFound),
+		errors.Is(err, usecase.ErrNotInstalled),
+		errors.Is(err, usecase.ErrBackupNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, usecase.ErrDependencyConflict),
+		errors.Is(err, usecase.ErrDependencyCycle),
+		errors.Is(err, usecase.ErrLockfileMismatch),
+		errors.Is(err, usecase.ErrNoRollback),
+		errors.Is(err, usecase.ErrDataInUse),
+		errors.Is(err, usecase.ErrRequirementsNotMet):
+		status = http.StatusConflict
+	case errors.Is(err, usecase.ErrUnsupportedAction),
+		errors.Is(err, usecase.ErrInvalidPolicy),
+		errors.Is(err, usecase.ErrInvalidRetention),
+		errors.Is(err, usecase.ErrInvalidVariable):
+		status = http.StatusBadRequest
+	}
+
+	c.JSON(status, gin.H{
+		"error": err.Error(),
+	})
+}
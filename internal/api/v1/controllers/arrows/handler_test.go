@@ -0,0 +1,143 @@
+package arrows
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rabbytesoftware/quiver/internal/infrastructure"
+	"github.com/rabbytesoftware/quiver/internal/repositories"
+	usecase "github.com/rabbytesoftware/quiver/internal/usecases/arrows"
+)
+
+func newTestRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	t.Setenv("QUIVER_DATABASE_PATH", t.TempDir())
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	usecases := usecase.NewArrowsUsecase(
+		repositories.NewRepositories(infrastructure.NewInfrastructure()),
+	)
+	SetupRoutes(router.Group("/api/v1/arrow"), usecases)
+
+	return router
+}
+
+func perform(router *gin.Engine, method, path string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(method, path, nil))
+
+	return recorder
+}
+
+func TestArrowsHandler_InvalidNamespace(t *testing.T) {
+	router := newTestRouter(t)
+
+	recorder := perform(router, http.MethodPost, "/api/v1/arrow/cs2@not-a-version/install?dry_run=true")
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", recorder.Code)
+	}
+}
+
+func TestArrowsHandler_NotInstalled(t *testing.T) {
+	router := newTestRouter(t)
+
+	testCases := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodPut, "/api/v1/arrow/cs2/update?dry_run=true"},
+		{http.MethodPut, "/api/v1/arrow/cs2/update"},
+		{http.MethodDelete, "/api/v1/arrow/cs2?dry_run=true"},
+		{http.MethodDelete, "/api/v1/arrow/cs2"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+			recorder := perform(router, tc.method, tc.path)
+			if recorder.Code != http.StatusNotFound {
+				t.Errorf("Expected status 404, got %d", recorder.Code)
+			}
+
+			var body map[string]string
+			if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil || body["error"] == "" {
+				t.Errorf("Expected an error body, got %s", recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestArrowsHandler_Outdated(t *testing.T) {
+	router := newTestRouter(t)
+
+	for _, path := range []string{"/api/v1/arrow/outdated", "/api/v1/arrow/outdated?refresh=true"} {
+		recorder := perform(router, http.MethodGet, path)
+		if recorder.Code != http.StatusOK {
+			t.Errorf("Expected status 200 for %s, got %d", path, recorder.Code)
+		}
+
+		var report usecase.OutdatedReport
+		if err := json.Unmarshal(recorder.Body.Bytes(), &report); err != nil {
+			t.Errorf("Expected an outdated report, got %s", recorder.Body.String())
+		}
+		if report.CheckedAt.IsZero() {
+			t.Errorf("Expected checked_at to be set for %s", path)
+		}
+	}
+}
+
+func TestRespondError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		err      error
+		expected int
+	}{
+		{fmt.Errorf("wrapped: %w", usecase.ErrArrowNotFound), http.StatusNotFound},
+		{usecase.ErrNotInstalled, http.StatusNotFound},
+		{usecase.ErrDependencyConflict, http.StatusConflict},
+		{usecase.ErrDependencyCycle, http.StatusConflict},
+		{usecase.ErrLockfileMismatch, http.StatusConflict},
+		{usecase.ErrNoRollback, http.StatusConflict},
+		{usecase.ErrRequirementsNotMet, http.StatusConflict},
+		{usecase.ErrUnsupportedAction, http.StatusBadRequest},
+		{usecase.ErrInvalidPolicy, http.StatusBadRequest},
+		{errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+
+		respondError(c, tc.err)
+		if recorder.Code != tc.expected {
+			t.Errorf("Expected status %d for %v, got %d", tc.expected, tc.err, recorder.Code)
+		}
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := map[string]bool{
+		"/":               false,
+		"/?dry_run=true":  true,
+		"/?dry_run=1":     true,
+		"/?dry_run=false": false,
+		"/?dry_run=maybe": false,
+	}
+
+	for path, expected := range testCases {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, path, nil)
+
+		if dryRun(c) != expected {
+			t.Errorf("Expected dryRun() to be %v for %s", expected, path)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package arrows
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rabbytesoftware/quiver/internal/models/lockfile"
+)
+
+// ExportLockfile returns the lockfile of this host as JSON,
+// or as YAML when called with ?format=yaml.
+func (h *ArrowsHandler) ExportLockfile() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		format := lockfile.Format(c.DefaultQuery("format", string(lockfile.FormatJSON)))
+
+		lock, err := h.usecases.ExportLockfile(c.Request.Context())
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		data, err := lock.Marshal(format)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		contentType := "application/json"
+		if format == lockfile.FormatYAML {
+			contentType = "application/yaml"
+		}
+
+		c.Data(http.StatusOK, contentType, data)
+	}
+}
+
+// ImportLockfile installs the arrows of a lockfile sent as the
+// request body in either YAML or JSON.
+func (h *ArrowsHandler) ImportLockfile() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		lock, err := lockfile.Parse(data)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		plan, err := h.usecases.ImportLockfile(c.Request.Context(), lock)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, plan)
+	}
+}
@@ -0,0 +1,65 @@
+package arrows
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rabbytesoftware/quiver/internal/models/lockfile"
+)
+
+func TestArrowsHandler_ExportLockfile(t *testing.T) {
+	router := newTestRouter(t)
+
+	testCases := []struct {
+		path        string
+		status      int
+		contentType string
+	}{
+		{"/api/v1/arrow/lockfile", http.StatusOK, "application/json"},
+		{"/api/v1/arrow/lockfile?format=yaml", http.StatusOK, "application/yaml"},
+		{"/api/v1/arrow/lockfile?format=toml", http.StatusBadRequest, "application/json"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			recorder := perform(router, http.MethodGet, tc.path)
+			if recorder.Code != tc.status {
+				t.Errorf("Expected status %d, got %d", tc.status, recorder.Code)
+			}
+			if !strings.HasPrefix(recorder.Header().Get("Content-Type"), tc.contentType) {
+				t.Errorf("Expected content type %s, got %s", tc.contentType, recorder.Header().Get("Content-Type"))
+			}
+			if tc.status == http.StatusOK && !strings.Contains(recorder.Body.String(), lockfile.LockfileV1) {
+				t.Errorf("Expected a lockfile body, got %s", recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestArrowsHandler_ImportLockfile(t *testing.T) {
+	router := newTestRouter(t)
+
+	testCases := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"malformed", "lockfile: [", http.StatusBadRequest},
+		{"unsupported", "lockfile: quiver-lock@v9", http.StatusBadRequest},
+		{"empty", `{"lockfile": "quiver-lock@v1", "arrows": []}`, http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/api/v1/arrow/lockfile", strings.NewReader(tc.body))
+			router.ServeHTTP(recorder, request)
+
+			if recorder.Code != tc.status {
+				t.Errorf("Expected status %d, got %d: %s", tc.status, recorder.Code, recorder.Body.String())
+			}
+		})
+	}
+}
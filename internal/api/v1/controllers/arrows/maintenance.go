@@ -0,0 +1,50 @@
+package arrows
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/schedule"
+)
+
+// SetUpdatePolicy changes the update policy of an installed arrow.
+// The policy is read from a JSON body, or from the mode and window
+// query parameters when there is none.
+func (h *ArrowsHandler) SetUpdatePolicy() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		namespace, ok := namespaceParam(c)
+		if !ok {
+			return
+		}
+
+		policy := arrow.UpdatePolicy{
+			Mode:   arrow.UpdateMode(c.Query("mode")),
+			Window: schedule.Cron(c.Query("window")),
+		}
+
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&policy); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+		}
+
+		updated, err := h.usecases.SetUpdatePolicy(c.Request.Context(), namespace, policy)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, updated)
+	}
+}
+
+// Maintenance lists what the update policies did, oldest first.
+func (h *ArrowsHandler) Maintenance() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, h.usecases.Maintenance())
+	}
+}
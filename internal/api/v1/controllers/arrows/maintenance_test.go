@@ -0,0 +1,50 @@
+package arrows
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestArrowsHandler_SetUpdatePolicy(t *testing.T) {
+	router := newTestRouter(t)
+
+	testCases := []struct {
+		name   string
+		path   string
+		body   string
+		status int
+	}{
+		{"query", "/api/v1/arrow/cs2/policy?mode=automatic&window=*+4-5+*+*+*", "", http.StatusNotFound},
+		{"body", "/api/v1/arrow/cs2/policy", `{"mode": "notify"}`, http.StatusNotFound},
+		{"missing window", "/api/v1/arrow/cs2/policy?mode=automatic", "", http.StatusBadRequest},
+		{"invalid window", "/api/v1/arrow/cs2/policy", `{"mode": "notify", "window": "* 25 * * *"}`, http.StatusBadRequest},
+		{"unknown mode", "/api/v1/arrow/cs2/policy?mode=sometimes", "", http.StatusBadRequest},
+		{"malformed body", "/api/v1/arrow/cs2/policy", `{"mode":`, http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPut, tc.path, strings.NewReader(tc.body))
+			router.ServeHTTP(recorder, request)
+
+			if recorder.Code != tc.status {
+				t.Errorf("Expected status %d, got %d: %s", tc.status, recorder.Code, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestArrowsHandler_Maintenance(t *testing.T) {
+	router := newTestRouter(t)
+
+	recorder := perform(router, http.MethodGet, "/api/v1/arrow/maintenance")
+	if recorder.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", recorder.Code)
+	}
+	if recorder.Body.String() != "[]" {
+		t.Errorf("Expected an empty list, got %s", recorder.Body.String())
+	}
+}
@@ -0,0 +1,43 @@
+package arrows
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Rollback restores the version an arrow ran at before its last update.
+func (h *ArrowsHandler) Rollback() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		namespace, ok := namespaceParam(c)
+		if !ok {
+			return
+		}
+
+		restored, err := h.usecases.Rollback(c.Request.Context(), namespace)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, restored)
+	}
+}
+
+// History lists the versions an installed arrow ran at, oldest first.
+func (h *ArrowsHandler) History() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		namespace, ok := namespaceParam(c)
+		if !ok {
+			return
+		}
+
+		history, err := h.usecases.History(c.Request.Context(), namespace)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, history)
+	}
+}
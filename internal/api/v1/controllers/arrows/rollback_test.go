@@ -0,0 +1,30 @@
+package arrows
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestArrowsHandler_Rollback(t *testing.T) {
+	router := newTestRouter(t)
+
+	testCases := []struct {
+		method string
+		path   string
+		status int
+	}{
+		{http.MethodPost, "/api/v1/arrow/cs2/rollback", http.StatusNotFound},
+		{http.MethodPost, "/api/v1/arrow/cs2@invalid/rollback", http.StatusBadRequest},
+		{http.MethodGet, "/api/v1/arrow/cs2/history", http.StatusNotFound},
+		{http.MethodGet, "/api/v1/arrow/cs2@invalid/history", http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+			recorder := perform(router, tc.method, tc.path)
+			if recorder.Code != tc.status {
+				t.Errorf("Expected status %d, got %d: %s", tc.status, recorder.Code, recorder.Body.String())
+			}
+		})
+	}
+}
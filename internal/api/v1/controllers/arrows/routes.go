@@ -6,4 +6,33 @@ import (
 )
 
 func SetupRoutes(router *gin.RouterGroup, usecases *usecase.ArrowsUsecase) {
+	if router == nil {
+		return
+	}
+
+	handler := NewArrowsHandler(usecases)
+
+	router.GET("/outdated", handler.Outdated())
+	router.GET("/maintenance", handler.Maintenance())
+	router.GET("/lockfile", handler.ExportLockfile())
+	router.POST("/lockfile", handler.ImportLockfile())
+
+	router.GET("/:namespace", handler.Status())
+	router.POST("/:namespace/install", handler.Install())
+	router.PUT("/:namespace/update", handler.Update())
+	router.POST("/:namespace/rollback", handler.Rollback())
+	router.GET("/:namespace/history", handler.History())
+	router.PUT("/:namespace/policy", handler.SetUpdatePolicy())
+	router.GET("/:namespace/templates", handler.Templates())
+	router.POST("/:namespace/templates/render", handler.RenderTemplates())
+	router.GET("/:namespace/variables", handler.Variables())
+	router.PUT("/:namespace/variables", handler.SetVariables())
+	router.GET("/:namespace/variables/audit", handler.VariableChanges())
+	router.POST("/:namespace/backups", handler.Backup())
+	router.GET("/:namespace/backups", handler.Backups())
+	router.PUT("/:namespace/backups/retention", handler.SetBackupRetention())
+	router.POST("/:namespace/backups/:id/restore", handler.RestoreBackup())
+	router.DELETE("/:namespace/backups/:id", handler.DeleteBackup())
+	router.DELETE("/:namespace/data", handler.PurgeData())
+	router.DELETE("/:namespace", handler.Uninstall())
 }
@@ -1,6 +1,7 @@
 package arrows
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -69,9 +70,10 @@ func TestSetupRoutes_Comprehensive(t *testing.T) {
 		}
 	}()
 
-	// Test multiple calls
+	// Test multiple calls, each on its own group since gin rejects
+	// registering the same route twice
 	for i := 0; i < 3; i++ {
-		SetupRoutes(group, usecases)
+		SetupRoutes(group.Group(fmt.Sprintf("/%d", i)), usecases)
 	}
 
 	// Test that the router group is still valid
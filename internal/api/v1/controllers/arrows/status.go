@@ -0,0 +1,26 @@
+package arrows
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Status reports whether an installed arrow runs and whether its
+// ports are forwarded and reachable from the internet.
+func (h *ArrowsHandler) Status() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		namespace, ok := namespaceParam(c)
+		if !ok {
+			return
+		}
+
+		status, err := h.usecases.Status(c.Request.Context(), namespace)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, status)
+	}
+}
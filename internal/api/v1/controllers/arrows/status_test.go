@@ -0,0 +1,28 @@
+package arrows
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestArrowsHandler_Status(t *testing.T) {
+	router := newTestRouter(t)
+
+	testCases := []struct {
+		path   string
+		status int
+	}{
+		{"/api/v1/arrow/cs2", http.StatusNotFound},
+		{"/api/v1/arrow/cs2@invalid", http.StatusBadRequest},
+		{"/api/v1/arrow/outdated", http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			recorder := perform(router, http.MethodGet, tc.path)
+			if recorder.Code != tc.status {
+				t.Errorf("Expected status %d, got %d: %s", tc.status, recorder.Code, recorder.Body.String())
+			}
+		})
+	}
+}
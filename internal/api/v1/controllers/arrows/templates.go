@@ -0,0 +1,55 @@
+package arrows
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Templates shows what rendering the config templates of an
+// installed arrow would change, as a diff per file.
+func (h *ArrowsHandler) Templates() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		namespace, ok := namespaceParam(c)
+		if !ok {
+			return
+		}
+
+		changes, err := h.usecases.Templates(c.Request.Context(), namespace)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, changes)
+	}
+}
+
+// RenderTemplates writes the config templates of an installed arrow.
+// Files edited by hand are reported as conflicts and only
+// overwritten with ?force=true.
+func (h *ArrowsHandler) RenderTemplates() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		namespace, ok := namespaceParam(c)
+		if !ok {
+			return
+		}
+
+		force, err := strconv.ParseBool(c.DefaultQuery("force", "false"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "invalid force value: " + c.Query("force"),
+			})
+			return
+		}
+
+		changes, err := h.usecases.RenderTemplates(c.Request.Context(), namespace, force)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, changes)
+	}
+}
@@ -0,0 +1,31 @@
+package arrows
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestArrowsHandler_Templates(t *testing.T) {
+	router := newTestRouter(t)
+
+	testCases := []struct {
+		name   string
+		method string
+		path   string
+		status int
+	}{
+		{"plan", http.MethodGet, "/api/v1/arrow/cs2/templates", http.StatusNotFound},
+		{"render", http.MethodPost, "/api/v1/arrow/cs2/templates/render", http.StatusNotFound},
+		{"force", http.MethodPost, "/api/v1/arrow/cs2/templates/render?force=true", http.StatusNotFound},
+		{"invalid force", http.MethodPost, "/api/v1/arrow/cs2/templates/render?force=always", http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := perform(router, tc.method, tc.path)
+			if recorder.Code != tc.status {
+				t.Errorf("Expected status %d, got %d: %s", tc.status, recorder.Code, recorder.Body.String())
+			}
+		})
+	}
+}
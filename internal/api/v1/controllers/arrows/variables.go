@@ -0,0 +1,155 @@
+package arrows
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/rabbytesoftware/quiver/internal/core/secrets"
+)
+
+// actorHeader names who changes an instance, for the audit trail.
+const actorHeader = "X-Quiver-Actor"
+
+type variablesRequest struct {
+	Values  map[string]*string `json:"values"`
+	Restart bool               `json:"restart"`
+}
+
+// authorizedToReveal reports whether the request carries the reveal
+// token as a bearer token. Nobody is authorized while no token is set.
+func authorizedToReveal(c *gin.Context) bool {
+	token := secrets.RevealToken()
+	if token == "" {
+		return false
+	}
+
+	presented, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(presented), []byte(token)) == 1
+}
+
+// Variables lists the variables of an installed arrow with their
+// current values. Sensitive values are masked unless ?reveal=true is
+// sent along with the configured reveal token.
+func (h *ArrowsHandler) Variables() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		namespace, ok := namespaceParam(c)
+		if !ok {
+			return
+		}
+
+		reveal, err := strconv.ParseBool(c.DefaultQuery("reveal", "false"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "invalid reveal value: " + c.Query("reveal"),
+			})
+			return
+		}
+
+		if reveal && !authorizedToReveal(c) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "revealing sensitive values requires the reveal token",
+			})
+			return
+		}
+
+		values, err := h.usecases.Variables(c.Request.Context(), namespace, reveal)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, values)
+	}
+}
+
+// VariableChanges returns the audit trail of the variables of an
+// installed arrow.
+func (h *ArrowsHandler) VariableChanges() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		namespace, ok := namespaceParam(c)
+		if !ok {
+			return
+		}
+
+		changes, err := h.usecases.VariableChanges(c.Request.Context(), namespace)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, changes)
+	}
+}
+
+// SetVariables changes variables of an installed arrow. The values
+// are read from a JSON body, where null resets a variable to its
+// default, or from the name and value query parameters when there is
+// none. With restart, a running instance is restarted.
+func (h *ArrowsHandler) SetVariables() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		namespace, ok := namespaceParam(c)
+		if !ok {
+			return
+		}
+
+		var request variablesRequest
+
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&request); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+		} else {
+			name := c.Query("name")
+			if name == "" {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": "name is required",
+				})
+				return
+			}
+
+			request.Values = map[string]*string{name: nil}
+			if value, ok := c.GetQuery("value"); ok {
+				request.Values[name] = &value
+			}
+
+			restart, err := strconv.ParseBool(c.DefaultQuery("restart", "false"))
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": "invalid restart value: " + c.Query("restart"),
+				})
+				return
+			}
+			request.Restart = restart
+		}
+
+		actor := c.GetHeader(actorHeader)
+		if actor == "" {
+			actor = c.ClientIP()
+		}
+
+		result, err := h.usecases.SetVariables(
+			c.Request.Context(),
+			namespace,
+			request.Values,
+			actor,
+			request.Restart,
+		)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
@@ -0,0 +1,104 @@
+package arrows
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rabbytesoftware/quiver/internal/core/secrets"
+)
+
+func TestArrowsHandler_Variables(t *testing.T) {
+	router := newTestRouter(t)
+
+	testCases := []struct {
+		name   string
+		method string
+		path   string
+		status int
+	}{
+		{"list", http.MethodGet, "/api/v1/arrow/cs2/variables", http.StatusNotFound},
+		{"audit", http.MethodGet, "/api/v1/arrow/cs2/variables/audit", http.StatusNotFound},
+		{"set", http.MethodPut, "/api/v1/arrow/cs2/variables?name=PORT&value=27016", http.StatusNotFound},
+		{"reset", http.MethodPut, "/api/v1/arrow/cs2/variables?name=PORT&restart=true", http.StatusNotFound},
+		{"missing name", http.MethodPut, "/api/v1/arrow/cs2/variables?value=27016", http.StatusBadRequest},
+		{"invalid restart", http.MethodPut, "/api/v1/arrow/cs2/variables?name=PORT&restart=soon", http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := perform(router, tc.method, tc.path)
+			if recorder.Code != tc.status {
+				t.Errorf("Expected status %d, got %d: %s", tc.status, recorder.Code, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestArrowsHandler_Variables_Reveal(t *testing.T) {
+	router := newTestRouter(t)
+	path := "/api/v1/arrow/cs2/variables?reveal=true"
+
+	testCases := []struct {
+		name          string
+		token         string
+		authorization string
+		status        int
+	}{
+		{"disabled", "", "Bearer ", http.StatusForbidden},
+		{"missing token", "reveal-token", "", http.StatusForbidden},
+		{"wrong token", "reveal-token", "Bearer guess", http.StatusForbidden},
+		{"authorized", "reveal-token", "Bearer reveal-token", http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(secrets.RevealTokenEnv, tc.token)
+
+			request := httptest.NewRequest(http.MethodGet, path, nil)
+			if tc.authorization != "" {
+				request.Header.Set("Authorization", tc.authorization)
+			}
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+
+			if recorder.Code != tc.status {
+				t.Errorf("Expected status %d, got %d: %s", tc.status, recorder.Code, recorder.Body.String())
+			}
+		})
+	}
+
+	if recorder := perform(router, http.MethodGet, "/api/v1/arrow/cs2/variables?reveal=maybe"); recorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, recorder.Code)
+	}
+}
+
+func TestArrowsHandler_SetVariables_Body(t *testing.T) {
+	router := newTestRouter(t)
+
+	testCases := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"values", `{"values": {"PORT": "27016", "PASSWORD": null}, "restart": true}`, http.StatusNotFound},
+		{"invalid json", `{"values": [`, http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPut, "/api/v1/arrow/cs2/variables", strings.NewReader(tc.body))
+			request.Header.Set("Content-Type", "application/json")
+			request.Header.Set(actorHeader, "admin")
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+
+			if recorder.Code != tc.status {
+				t.Errorf("Expected status %d, got %d: %s", tc.status, recorder.Code, recorder.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package system
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	usecase "github.com/rabbytesoftware/quiver/internal/usecases/system"
+)
+
+type SystemHandler struct {
+	usecases *usecase.SystemUsecase
+}
+
+func NewSystemHandler(
+	usecases *usecase.SystemUsecase,
+) *SystemHandler {
+	return &SystemHandler{
+		usecases: usecases,
+	}
+}
+
+// Host reports what was detected about the machine Quiver runs on.
+func (h *SystemHandler) Host() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		host, err := h.usecases.Host(c.Request.Context())
+		if errors.Is(err, usecase.ErrProbeUnavailable) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, host)
+	}
+}
+
+// Netbridge reports which port forwarding strategy is active.
+func (h *SystemHandler) Netbridge() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		status, err := h.usecases.Netbridge(c.Request.Context())
+		if errors.Is(err, usecase.ErrNetbridgeUnavailable) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, status)
+	}
+}
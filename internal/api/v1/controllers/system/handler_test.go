@@ -0,0 +1,91 @@
+package system
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/rabbytesoftware/quiver/internal/infrastructure"
+	"github.com/rabbytesoftware/quiver/internal/infrastructure/netbridge"
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+	"github.com/rabbytesoftware/quiver/internal/models/requirement"
+	"github.com/rabbytesoftware/quiver/internal/repositories"
+	usecase "github.com/rabbytesoftware/quiver/internal/usecases/system"
+)
+
+func TestSystemHandler_Host(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		name     string
+		usecases *usecase.SystemUsecase
+		status   int
+	}{
+		{
+			"probed",
+			usecase.NewSystemUsecase(repositories.NewRepositories(infrastructure.NewInfrastructure())),
+			http.StatusOK,
+		},
+		{"unavailable", usecase.NewSystemUsecase(nil), http.StatusServiceUnavailable},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := gin.New()
+			SetupRoutes(router.Group("/api/v1/system"), tc.usecases)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/system/host", nil))
+
+			if recorder.Code != tc.status {
+				t.Fatalf("Expected status %d, got %d: %s", tc.status, recorder.Code, recorder.Body.String())
+			}
+
+			if tc.status == http.StatusOK {
+				var host requirement.Host
+				if err := json.Unmarshal(recorder.Body.Bytes(), &host); err != nil || host.CpuCores <= 0 {
+					t.Errorf("Expected a host report, got %s", recorder.Body.String())
+				}
+			}
+		})
+	}
+}
+
+func TestSystemHandler_Netbridge(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	disabled := &infrastructure.Infrastructure{Netbridge: netbridge.NewNetbridgeWith(false)}
+
+	testCases := []struct {
+		name     string
+		usecases *usecase.SystemUsecase
+		status   int
+	}{
+		{"reported", usecase.NewSystemUsecase(repositories.NewRepositories(disabled)), http.StatusOK},
+		{"unavailable", usecase.NewSystemUsecase(nil), http.StatusServiceUnavailable},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := gin.New()
+			SetupRoutes(router.Group("/api/v1/system"), tc.usecases)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/system/netbridge", nil))
+
+			if recorder.Code != tc.status {
+				t.Fatalf("Expected status %d, got %d: %s", tc.status, recorder.Code, recorder.Body.String())
+			}
+
+			if tc.status == http.StatusOK {
+				var status port.NetbridgeStatus
+				if err := json.Unmarshal(recorder.Body.Bytes(), &status); err != nil || status.Enabled || status.Available {
+					t.Errorf("Expected a disabled netbridge, got %s", recorder.Body.String())
+				}
+			}
+		})
+	}
+}
@@ -6,4 +6,12 @@ import (
 )
 
 func SetupRoutes(router *gin.RouterGroup, usecases *usecase.SystemUsecase) {
+	if router == nil {
+		return
+	}
+
+	handler := NewSystemHandler(usecases)
+
+	router.GET("/host", handler.Host())
+	router.GET("/netbridge", handler.Netbridge())
 }
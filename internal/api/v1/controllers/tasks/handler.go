@@ -0,0 +1,202 @@
+package tasks
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rabbytesoftware/quiver/internal/models/schedule"
+	"github.com/rabbytesoftware/quiver/internal/usecases/arrows"
+	usecase "github.com/rabbytesoftware/quiver/internal/usecases/tasks"
+)
+
+type TasksHandler struct {
+	usecases *usecase.TasksUsecase
+}
+
+func NewTasksHandler(
+	usecases *usecase.TasksUsecase,
+) *TasksHandler {
+	return &TasksHandler{
+		usecases: usecases,
+	}
+}
+
+// taskRequest describes a new task, sent as a JSON body or as query
+// parameters when there is none. Tasks are enabled unless "enabled"
+// is false.
+type taskRequest struct {
+	Arrow   string              `json:"arrow" form:"arrow"`
+	Name    string              `json:"name" form:"name"`
+	Cron    schedule.Cron       `json:"cron" form:"cron"`
+	Action  schedule.TaskAction `json:"action" form:"action"`
+	Method  string              `json:"method" form:"method"`
+	Quiesce bool                `json:"quiesce" form:"quiesce"`
+	Enabled *bool               `json:"enabled" form:"enabled"`
+}
+
+func (h *TasksHandler) List() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tasks, err := h.usecases.List(c.Request.Context())
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, tasks)
+	}
+}
+
+func (h *TasksHandler) Create() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request taskRequest
+
+		bind := c.ShouldBindQuery
+		if c.Request.ContentLength != 0 {
+			bind = c.ShouldBindJSON
+		}
+
+		if err := bind(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		task := &schedule.Task{
+			Arrow:   request.Arrow,
+			Name:    request.Name,
+			Cron:    request.Cron,
+			Action:  request.Action,
+			Method:  request.Method,
+			Quiesce: request.Quiesce,
+			Enabled: request.Enabled == nil || *request.Enabled,
+		}
+
+		created, err := h.usecases.Create(c.Request.Context(), task)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, created)
+	}
+}
+
+func (h *TasksHandler) Get() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+
+		task, err := h.usecases.Get(c.Request.Context(), id)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, task)
+	}
+}
+
+func (h *TasksHandler) Delete() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+
+		if err := h.usecases.Delete(c.Request.Context(), id); err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "task " + id.String() + " deleted",
+		})
+	}
+}
+
+// RunNow runs a task right away and returns the outcome.
+func (h *TasksHandler) RunNow() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+
+		run, err := h.usecases.RunNow(c.Request.Context(), id)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, run)
+	}
+}
+
+func (h *TasksHandler) SetEnabled(enabled bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+
+		task, err := h.usecases.SetEnabled(c.Request.Context(), id, enabled)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, task)
+	}
+}
+
+// Runs lists the latest runs of a task, newest first.
+func (h *TasksHandler) Runs() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+
+		runs, err := h.usecases.Runs(c.Request.Context(), id)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, runs)
+	}
+}
+
+func idParam(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid task id " + c.Param("id"),
+		})
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
+
+func respondError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+
+	switch {
+	case errors.Is(err, usecase.ErrTaskNotFound), errors.Is(err, arrows.ErrNotInstalled):
+		status = http.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidTask):
+		status = http.StatusBadRequest
+	case errors.Is(err, usecase.ErrTaskRunning):
+		status = http.StatusConflict
+	}
+
+	c.JSON(status, gin.H{
+		"error": err.Error(),
+	})
+}
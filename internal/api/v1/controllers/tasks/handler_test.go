@@ -0,0 +1,133 @@
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rabbytesoftware/quiver/internal/infrastructure"
+	"github.com/rabbytesoftware/quiver/internal/repositories"
+	"github.com/rabbytesoftware/quiver/internal/usecases/arrows"
+	usecase "github.com/rabbytesoftware/quiver/internal/usecases/tasks"
+)
+
+func newTestRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	t.Setenv("QUIVER_DATABASE_PATH", t.TempDir())
+	gin.SetMode(gin.TestMode)
+
+	repos := repositories.NewRepositories(infrastructure.NewInfrastructure())
+
+	router := gin.New()
+	SetupRoutes(
+		router.Group("/api/v1/task"),
+		usecase.NewTasksUsecase(repos, arrows.NewArrowsUsecase(repos)),
+	)
+
+	return router
+}
+
+func perform(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(method, path, strings.NewReader(body)))
+
+	return recorder
+}
+
+func TestSetupRoutes_NilRouter(t *testing.T) {
+	SetupRoutes(nil, nil)
+}
+
+func TestTasksHandler_List(t *testing.T) {
+	router := newTestRouter(t)
+
+	recorder := perform(router, http.MethodGet, "/api/v1/task", "")
+	if recorder.Code != http.StatusOK || recorder.Body.String() != "[]" {
+		t.Errorf("Expected an empty list, got %d %s", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestTasksHandler_Create(t *testing.T) {
+	router := newTestRouter(t)
+
+	testCases := []struct {
+		name   string
+		query  string
+		body   string
+		status int
+	}{
+		{"malformed", "", `{"arrow":`, http.StatusBadRequest},
+		{"query", "?arrow=cs2&action=restart&cron=0+4+*+*+*", "", http.StatusNotFound},
+		{"query invalid cron", "?arrow=cs2&action=restart&cron=0+4", "", http.StatusBadRequest},
+		{"invalid cron", "", `{"arrow": "cs2", "cron": "0 4 * *", "action": "restart"}`, http.StatusBadRequest},
+		{"invalid action", "", `{"arrow": "cs2", "cron": "0 4 * * *", "action": "reboot"}`, http.StatusBadRequest},
+		{"not installed", "", `{"arrow": "cs2", "cron": "0 4 * * *", "action": "restart"}`, http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := perform(router, http.MethodPost, "/api/v1/task"+tc.query, tc.body)
+			if recorder.Code != tc.status {
+				t.Errorf("Expected status %d, got %d: %s", tc.status, recorder.Code, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestTasksHandler_ByID(t *testing.T) {
+	router := newTestRouter(t)
+	missing := uuid.New().String()
+
+	testCases := []struct {
+		method string
+		path   string
+		status int
+	}{
+		{http.MethodGet, "/api/v1/task/not-a-uuid", http.StatusBadRequest},
+		{http.MethodGet, "/api/v1/task/" + missing, http.StatusNotFound},
+		{http.MethodDelete, "/api/v1/task/" + missing, http.StatusNotFound},
+		{http.MethodPost, "/api/v1/task/" + missing + "/run", http.StatusNotFound},
+		{http.MethodPut, "/api/v1/task/" + missing + "/enable", http.StatusNotFound},
+		{http.MethodPut, "/api/v1/task/" + missing + "/disable", http.StatusNotFound},
+		{http.MethodGet, "/api/v1/task/" + missing + "/runs", http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+			recorder := perform(router, tc.method, tc.path, "")
+			if recorder.Code != tc.status {
+				t.Errorf("Expected status %d, got %d: %s", tc.status, recorder.Code, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestRespondError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		err      error
+		expected int
+	}{
+		{fmt.Errorf("wrapped: %w", usecase.ErrTaskNotFound), http.StatusNotFound},
+		{arrows.ErrNotInstalled, http.StatusNotFound},
+		{usecase.ErrInvalidTask, http.StatusBadRequest},
+		{usecase.ErrTaskRunning, http.StatusConflict},
+		{errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+
+		respondError(c, tc.err)
+		if recorder.Code != tc.expected {
+			t.Errorf("Expected status %d for %v, got %d", tc.expected, tc.err, recorder.Code)
+		}
+	}
+}
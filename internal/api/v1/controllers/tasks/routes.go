@@ -0,0 +1,23 @@
+package tasks
+
+import (
+	"github.com/gin-gonic/gin"
+	usecase "github.com/rabbytesoftware/quiver/internal/usecases/tasks"
+)
+
+func SetupRoutes(router *gin.RouterGroup, usecases *usecase.TasksUsecase) {
+	if router == nil {
+		return
+	}
+
+	handler := NewTasksHandler(usecases)
+
+	router.GET("", handler.List())
+	router.POST("", handler.Create())
+	router.GET("/:id", handler.Get())
+	router.DELETE("/:id", handler.Delete())
+	router.POST("/:id/run", handler.RunNow())
+	router.PUT("/:id/enable", handler.SetEnabled(true))
+	router.PUT("/:id/disable", handler.SetEnabled(false))
+	router.GET("/:id/runs", handler.Runs())
+}
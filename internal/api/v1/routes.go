@@ -6,6 +6,7 @@ import (
 	"github.com/rabbytesoftware/quiver/internal/api/v1/controllers/health"
 	"github.com/rabbytesoftware/quiver/internal/api/v1/controllers/quivers"
 	"github.com/rabbytesoftware/quiver/internal/api/v1/controllers/system"
+	"github.com/rabbytesoftware/quiver/internal/api/v1/controllers/tasks"
 	"github.com/rabbytesoftware/quiver/internal/usecases"
 )
 
@@ -26,6 +27,10 @@ func SetupRoutes(router *gin.Engine, usecases *usecases.Usecases) {
 			v1.Group("/system"),
 			usecases.System,
 		)
+		tasks.SetupRoutes(
+			v1.Group("/task"),
+			usecases.Tasks,
+		)
 		healthHandler.SetupRoutes(
 			v1,
 		)
@@ -18,11 +18,38 @@ var (
 type Netbridge struct {
 	Enabled      bool   `yaml:"enabled"`
 	AllowedPorts string `yaml:"allowed_ports"`
+	// Strategies are the port forwarding mechanisms to probe,
+	// in order of preference: upnp, pcp, natpmp and relay.
+	Strategies []string `yaml:"strategies"`
+	// STUNServers and EchoURLs are asked for the public IP, in
+	// that order, when the gateway does not report it.
+	STUNServers []string `yaml:"stun_servers"`
+	EchoURLs    []string `yaml:"echo_urls"`
+	// ReachabilityURL is a service that connects back to forwarded
+	// ports to test them from the internet; when empty, TCP ports
+	// are tested by connecting to the public IP from this host.
+	ReachabilityURL string `yaml:"reachability_url"`
+	// Firewall opens forwarded ports on this host as well:
+	// "nftables", or empty for none. With FirewallDryRun the rules
+	// are printed instead of applied.
+	Firewall       string `yaml:"firewall"`
+	FirewallDryRun bool   `yaml:"firewall_dry_run"`
+	// RelayAddress (host:port) and RelayToken reach the relay the
+	// relay strategy exposes ports through.
+	RelayAddress string `yaml:"relay_address"`
+	RelayToken   string `yaml:"relay_token"`
 }
 
 type Arrows struct {
 	Repositories []string `yaml:"repositories"`
 	InstallDir   string   `yaml:"install_dir"`
+	// UpdateCheckInterval is a Go duration such as "6h";
+	// "0" disables periodic update checks.
+	UpdateCheckInterval string `yaml:"update_check_interval"`
+	BackupDir           string `yaml:"backup_dir"`
+	// DataDir holds the persistent data of installed arrows,
+	// one directory per arrow.
+	DataDir string `yaml:"data_dir"`
 }
 
 type API struct {
@@ -34,6 +61,15 @@ type Database struct {
 	Path string `yaml:"path"`
 }
 
+type Secrets struct {
+	// KeyFile holds the key sensitive variables are encrypted with.
+	// It is generated on first use unless QUIVER_SECRET_KEY is set.
+	KeyFile string `yaml:"key_file"`
+	// RevealToken lets API callers read sensitive values in plain
+	// text. Revealing is disabled while it is empty.
+	RevealToken string `yaml:"reveal_token"`
+}
+
 type Watcher struct {
 	Enabled  bool   `yaml:"enabled"`
 	Level    string `yaml:"level"`
@@ -48,6 +84,7 @@ type ConfigData struct {
 	Arrows    Arrows    `yaml:"arrows"`
 	API       API       `yaml:"api"`
 	Database  Database  `yaml:"database"`
+	Secrets   Secrets   `yaml:"secrets"`
 	Watcher   Watcher   `yaml:"watcher"`
 }
 
@@ -94,6 +131,10 @@ func GetDatabase() Database {
 	return Get().Config.Database
 }
 
+func GetSecrets() Secrets {
+	return Get().Config.Secrets
+}
+
 func GetWatcher() Watcher {
 	return Get().Config.Watcher
 }
@@ -120,12 +161,18 @@ func getDefaultConfig() *Config {
 			Netbridge: Netbridge{
 				Enabled:      true,
 				AllowedPorts: "40128-40256",
+				Strategies:   []string{"upnp", "pcp", "natpmp"},
+				STUNServers:  []string{"stun.l.google.com:19302", "stun.cloudflare.com:3478"},
+				EchoURLs:     []string{"https://api.ipify.org", "https://checkip.amazonaws.com"},
 			},
 			Arrows: Arrows{
 				Repositories: []string{
 					"./pkgs",
 				},
-				InstallDir: "./arrows",
+				InstallDir:          "./arrows",
+				UpdateCheckInterval: "6h",
+				BackupDir:           "./backups",
+				DataDir:             "./data",
 			},
 			API: API{
 				Host: "0.0.0.0",
@@ -134,6 +181,9 @@ func getDefaultConfig() *Config {
 			Database: Database{
 				Path: "./.db",
 			},
+			Secrets: Secrets{
+				KeyFile: "./secret.key",
+			},
 			Watcher: Watcher{
 				Enabled:  true,
 				Level:    "info",
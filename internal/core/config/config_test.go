@@ -32,6 +32,13 @@ func TestGetNetbridge(t *testing.T) {
 
 	// Test that AllowedPorts field exists
 	_ = netbridge.AllowedPorts
+
+	if len(netbridge.Strategies) == 0 || netbridge.Strategies[0] != "upnp" {
+		t.Errorf("Expected UPnP to be tried first, got %v", netbridge.Strategies)
+	}
+	if len(netbridge.STUNServers) == 0 || len(netbridge.EchoURLs) == 0 {
+		t.Errorf("Expected default public IP sources, got %v and %v", netbridge.STUNServers, netbridge.EchoURLs)
+	}
 }
 
 func TestGetArrows(t *testing.T) {
@@ -68,6 +75,17 @@ func TestGetDatabase(t *testing.T) {
 	}
 }
 
+func TestGetSecrets(t *testing.T) {
+	secrets := GetSecrets()
+
+	if secrets.KeyFile == "" {
+		t.Error("Secrets.KeyFile should not be empty")
+	}
+	if secrets.RevealToken != "" {
+		t.Error("Revealing secrets should be disabled by default")
+	}
+}
+
 func TestGetWatcher(t *testing.T) {
 	watcher := GetWatcher()
 
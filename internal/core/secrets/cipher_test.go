@@ -0,0 +1,61 @@
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func testKey() []byte {
+	return bytes.Repeat([]byte{7}, KeySize)
+}
+
+func TestCipher_RoundTrip(t *testing.T) {
+	c, err := NewCipher(testKey())
+	if err != nil {
+		t.Fatalf("NewCipher() error = %v", err)
+	}
+
+	encrypted, err := c.Encrypt("hunter2")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if encrypted == "hunter2" {
+		t.Fatal("Expected the value to be encrypted")
+	}
+
+	again, _ := c.Encrypt("hunter2")
+	if again == encrypted {
+		t.Error("Expected every encryption to use a new nonce")
+	}
+
+	decrypted, err := c.Decrypt(encrypted)
+	if err != nil || decrypted != "hunter2" {
+		t.Errorf("Decrypt() = %q, %v", decrypted, err)
+	}
+}
+
+func TestCipher_Decrypt_Invalid(t *testing.T) {
+	c, _ := NewCipher(testKey())
+	other, _ := NewCipher(bytes.Repeat([]byte{8}, KeySize))
+
+	encrypted, _ := other.Encrypt("hunter2")
+
+	for name, ciphertext := range map[string]string{
+		"not base64": "%%%",
+		"too short":  "AAAA",
+		"wrong key":  encrypted,
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := c.Decrypt(ciphertext); !errors.Is(err, ErrInvalidCiphertext) {
+				t.Errorf("Expected ErrInvalidCiphertext, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNewCipher_InvalidKey(t *testing.T) {
+	if _, err := NewCipher([]byte("short")); err == nil {
+		t.Error("Expected keys of the wrong size to be rejected")
+	}
+}
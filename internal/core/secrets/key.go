@@ -0,0 +1,86 @@
+package secrets
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rabbytesoftware/quiver/internal/core/config"
+)
+
+// KeyEnv holds a base64 encoded key that takes precedence over the
+// key file, for hosts that keep it in a secret manager.
+const KeyEnv = "QUIVER_SECRET_KEY"
+
+// RevealTokenEnv overrides the configured reveal token.
+const RevealTokenEnv = "QUIVER_REVEAL_TOKEN"
+
+// LoadCipher returns a cipher for the configured key.
+func LoadCipher() (*Cipher, error) {
+	key, err := LoadKey(config.GetSecrets().KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCipher(key)
+}
+
+// LoadKey reads the key from QUIVER_SECRET_KEY or, when it is unset,
+// from path. A missing key file is created with a new random key that
+// only the current user can read.
+func LoadKey(path string) ([]byte, error) {
+	if encoded := os.Getenv(KeyEnv); encoded != "" {
+		return decodeKey(encoded, KeyEnv)
+	}
+
+	data, err := os.ReadFile(path)
+	if err == nil {
+		return decodeKey(string(data), path)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(key) + "\n"
+	if err := os.WriteFile(path, []byte(encoded), 0600); err != nil {
+		return nil, fmt.Errorf("failed to write secret key %s: %w", path, err)
+	}
+
+	return key, nil
+}
+
+// RevealToken returns the token callers have to present to read
+// sensitive values, or "" when revealing is disabled.
+func RevealToken() string {
+	if token := os.Getenv(RevealTokenEnv); token != "" {
+		return token
+	}
+
+	return config.GetSecrets().RevealToken
+}
+
+func decodeKey(encoded, source string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("invalid secret key in %s: %w", source, err)
+	}
+
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("invalid secret key in %s: must be %d bytes", source, KeySize)
+	}
+
+	return key, nil
+}
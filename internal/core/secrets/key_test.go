@@ -0,0 +1,70 @@
+package secrets
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadKey_GeneratesKeyFile(t *testing.T) {
+	t.Setenv(KeyEnv, "")
+	path := filepath.Join(t.TempDir(), "keys", "secret.key")
+
+	key, err := LoadKey(path)
+	if err != nil {
+		t.Fatalf("LoadKey() error = %v", err)
+	}
+	if len(key) != KeySize {
+		t.Fatalf("Expected a %d byte key, got %d", KeySize, len(key))
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Expected the key file to be written: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the key file to be private, got %v", info.Mode().Perm())
+	}
+
+	again, err := LoadKey(path)
+	if err != nil || !bytes.Equal(again, key) {
+		t.Errorf("Expected the same key to be read back, got %v", err)
+	}
+}
+
+func TestLoadKey_Env(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, KeySize)
+	t.Setenv(KeyEnv, base64.StdEncoding.EncodeToString(key))
+	path := filepath.Join(t.TempDir(), "secret.key")
+
+	loaded, err := LoadKey(path)
+	if err != nil || !bytes.Equal(loaded, key) {
+		t.Fatalf("LoadKey() = %v, %v", loaded, err)
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Expected no key file to be written when the key comes from the environment")
+	}
+}
+
+func TestLoadKey_Invalid(t *testing.T) {
+	t.Setenv(KeyEnv, base64.StdEncoding.EncodeToString([]byte("short")))
+
+	if _, err := LoadKey(filepath.Join(t.TempDir(), "secret.key")); err == nil {
+		t.Error("Expected a key of the wrong size to be rejected")
+	}
+}
+
+func TestRevealToken(t *testing.T) {
+	t.Setenv(RevealTokenEnv, "")
+	if RevealToken() != "" {
+		t.Error("Expected revealing to be disabled by default")
+	}
+
+	t.Setenv(RevealTokenEnv, "token")
+	if RevealToken() != "token" {
+		t.Errorf("Expected the environment to set the reveal token, got %q", RevealToken())
+	}
+}
@@ -0,0 +1,84 @@
+package secrets
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Mask replaces secrets wherever they are shown.
+const Mask = "********"
+
+var (
+	mu    sync.RWMutex
+	known = map[string]struct{}{}
+)
+
+// Register makes Redact mask values from now on. Empty values
+// are ignored.
+func Register(values ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, value := range values {
+		if value != "" && value != Mask {
+			known[value] = struct{}{}
+		}
+	}
+}
+
+// Redact masks every registered secret in s.
+func Redact(s string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if len(known) == 0 || s == "" {
+		return s
+	}
+
+	// Longer secrets go first, so a secret containing
+	// another one is masked as a whole.
+	values := make([]string, 0, len(known))
+	for value := range known {
+		if strings.Contains(s, value) {
+			values = append(values, value)
+		}
+	}
+	sort.Slice(values, func(i, j int) bool {
+		return len(values[i]) > len(values[j])
+	})
+
+	for _, value := range values {
+		s = strings.ReplaceAll(s, value, Mask)
+	}
+
+	return s
+}
+
+// RedactError masks registered secrets in the message of err while
+// keeping it inspectable with errors.Is and errors.As.
+func RedactError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	message := Redact(err.Error())
+	if message == err.Error() {
+		return err
+	}
+
+	return &redactedError{message: message, err: err}
+}
+
+type redactedError struct {
+	message string
+	err     error
+}
+
+func (e *redactedError) Error() string {
+	return e.message
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
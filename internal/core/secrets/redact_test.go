@@ -0,0 +1,47 @@
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	Register("redact-rcon", "redact-rcon-long", "")
+
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"rcon_password redact-rcon", "rcon_password " + Mask},
+		{"+sv_password redact-rcon-long", "+sv_password " + Mask},
+		{"nothing to hide", "nothing to hide"},
+		{"", ""},
+	}
+
+	for _, tc := range testCases {
+		if got := Redact(tc.input); got != tc.expected {
+			t.Errorf("Redact(%q) = %q, expected %q", tc.input, got, tc.expected)
+		}
+	}
+}
+
+func TestRedactError(t *testing.T) {
+	Register("redact-error")
+	cause := errors.New("exit status 1")
+
+	err := RedactError(fmt.Errorf("srcds -password redact-error: %w", cause))
+	if err.Error() != "srcds -password "+Mask+": exit status 1" {
+		t.Errorf("Expected the secret to be masked, got %q", err.Error())
+	}
+	if !errors.Is(err, cause) {
+		t.Error("Expected the cause to be kept")
+	}
+
+	if RedactError(nil) != nil {
+		t.Error("Expected nil to stay nil")
+	}
+	if RedactError(cause) != cause {
+		t.Error("Expected errors without secrets to be returned as is")
+	}
+}
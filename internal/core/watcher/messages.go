@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/rabbytesoftware/quiver/internal/core/errors"
+	"github.com/rabbytesoftware/quiver/internal/core/secrets"
 	"github.com/rabbytesoftware/quiver/internal/core/watcher/pool"
 	"github.com/sirupsen/logrus"
 )
@@ -12,7 +13,7 @@ func Debug(message string) {
 	w.logger.Debug(message)
 	w.pool.AddMessage(pool.Message{
 		Level:   logrus.DebugLevel,
-		Message: message,
+		Message: secrets.Redact(message),
 	})
 }
 
@@ -20,7 +21,7 @@ func Info(message string) {
 	w.logger.Info(message)
 	w.pool.AddMessage(pool.Message{
 		Level:   logrus.InfoLevel,
-		Message: message,
+		Message: secrets.Redact(message),
 	})
 }
 
@@ -28,7 +29,7 @@ func Warn(message string) {
 	w.logger.Warning(message)
 	w.pool.AddMessage(pool.Message{
 		Level:   logrus.WarnLevel,
-		Message: message,
+		Message: secrets.Redact(message),
 	})
 }
 
@@ -36,7 +37,7 @@ func Errorf(message string, args ...interface{}) {
 	w.logger.Info(fmt.Sprintf(message, args...))
 	w.pool.AddMessage(pool.Message{
 		Level:   logrus.InfoLevel,
-		Message: fmt.Sprintf(message, args...),
+		Message: secrets.Redact(fmt.Sprintf(message, args...)),
 	})
 }
 
@@ -45,7 +46,7 @@ func Error(message errors.Error) {
 	w.logger.Error(message.Error())
 	w.pool.AddMessage(pool.Message{
 		Level:   logrus.ErrorLevel,
-		Message: message.Error(),
+		Message: secrets.Redact(message.Error()),
 	})
 }
 
@@ -54,6 +55,6 @@ func Unforeseen(message errors.Error) {
 	w.logger.Fatal(message.Error())
 	w.pool.AddMessage(pool.Message{
 		Level:   logrus.FatalLevel,
-		Message: message.Error(),
+		Message: secrets.Redact(message.Error()),
 	})
 }
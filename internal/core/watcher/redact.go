@@ -0,0 +1,26 @@
+package watcher
+
+import (
+	"github.com/rabbytesoftware/quiver/internal/core/secrets"
+	"github.com/sirupsen/logrus"
+)
+
+// redactHook masks secrets in every entry before it is written,
+// including entries logged through WithField and WithFields.
+type redactHook struct{}
+
+func (redactHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (redactHook) Fire(entry *logrus.Entry) error {
+	entry.Message = secrets.Redact(entry.Message)
+
+	for key, value := range entry.Data {
+		if s, ok := value.(string); ok {
+			entry.Data[key] = secrets.Redact(s)
+		}
+	}
+
+	return nil
+}
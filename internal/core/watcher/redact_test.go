@@ -0,0 +1,31 @@
+package watcher
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/rabbytesoftware/quiver/internal/core/secrets"
+	"github.com/sirupsen/logrus"
+)
+
+func TestRedactHook(t *testing.T) {
+	secrets.Register("watcher-rcon")
+
+	var out bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&out)
+	logger.AddHook(redactHook{})
+
+	logger.WithFields(logrus.Fields{
+		"path":   "/api/v1/arrow/cs2/variables?value=watcher-rcon",
+		"status": 200,
+	}).Info("started srcds +rcon_password watcher-rcon")
+
+	if strings.Contains(out.String(), "watcher-rcon") {
+		t.Errorf("Expected the secret to be masked, got %s", out.String())
+	}
+	if !strings.Contains(out.String(), secrets.Mask) || !strings.Contains(out.String(), "status=200") {
+		t.Errorf("Expected the rest of the entry to be kept, got %s", out.String())
+	}
+}
@@ -76,6 +76,7 @@ func IsEnabled() bool {
 
 func initLogger(watcherConfig config.Watcher) *logrus.Logger {
 	logger := logrus.New()
+	logger.AddHook(redactHook{})
 
 	level, err := logrus.ParseLevel(watcherConfig.Level)
 	if err != nil {
@@ -2,30 +2,72 @@ package fetchnshare
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"time"
 )
 
 type FNS struct {
+	client *http.Client
 }
 
 func NewFNS() FNSInterface {
-	return &FNS{}
+	return &FNS{
+		client: &http.Client{},
+	}
 }
 
 // GetInfo retrieves metadata information about a resource (file or directory).
 // It returns ResourceInfo containing size, permissions, modification time, and other attributes.
 // Supports both local filesystem paths and remote URLs (HTTP/HTTPS).
 func (f *FNS) GetInfo(ctx context.Context, path string) (*ResourceInfo, error) {
-	return nil, nil
+	if isRemote(path) {
+		return f.remoteInfo(ctx, path)
+	}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+
+	info := &ResourceInfo{
+		Path:    path,
+		Type:    ResourceTypeFile,
+		Size:    stat.Size(),
+		ModTime: stat.ModTime(),
+	}
+	if stat.IsDir() {
+		info.Type = ResourceTypeDir
+	}
+
+	return info, nil
 }
 
 // Exists checks whether a resource exists at the given path.
 // Returns true if the resource exists, false otherwise.
 // Works with both local filesystem paths and remote URLs.
 func (f *FNS) Exists(ctx context.Context, path string) (bool, error) {
-	return false, nil
+	if isRemote(path) {
+		resp, err := f.request(ctx, "HEAD", path)
+		if err != nil {
+			return false, err
+		}
+		resp.Body.Close()
+
+		return resp.StatusCode >= 200 && resp.StatusCode < 300, nil
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+
+	return true, nil
 }
 
 // IsDir checks whether the resource at the given path is a directory.
@@ -46,7 +88,16 @@ func (f *FNS) IsFile(ctx context.Context, path string) (bool, error) {
 // Returns the complete file content or downloaded data.
 // Use ReadStream for large files to avoid memory issues.
 func (f *FNS) Read(ctx context.Context, path string) ([]byte, error) {
-	return nil, nil
+	if isRemote(path) {
+		return f.Fetch(ctx, path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return data, nil
 }
 
 // ReadStream returns an io.ReadCloser for streaming data from a resource.
@@ -146,13 +197,42 @@ func (f *FNS) Download(ctx context.Context, url, dst string, progress func(int))
 // The progress callback receives the number of bytes downloaded.
 // Caller must close the returned ReadCloser when done.
 func (f *FNS) DownloadStream(ctx context.Context, url string, progress func(int)) (io.ReadCloser, error) {
-	return nil, nil
+	resp, err := f.request(ctx, "GET", url)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to download %s: unexpected status %d", url, resp.StatusCode)
+	}
+
+	if progress == nil {
+		return resp.Body, nil
+	}
+
+	return &progressReader{ReadCloser: resp.Body, progress: progress}, nil
 }
 
 // Fetch downloads content from a URL and returns it as a byte slice.
 // Use for small resources. For large downloads, use DownloadStream.
 func (f *FNS) Fetch(ctx context.Context, url string) ([]byte, error) {
-	return nil, nil
+	resp, err := f.request(ctx, "GET", url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("failed to fetch %s: unexpected status %d", url, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response from %s: %w", url, err)
+	}
+
+	return data, nil
 }
 
 // CacheGet retrieves data from the cache using the specified key.
@@ -2,6 +2,11 @@ package fetchnshare
 
 import (
 	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -17,12 +22,64 @@ func TestFNS_GetInfo(t *testing.T) {
 	fns := NewFNS()
 	ctx := context.Background()
 
-	info, err := fns.GetInfo(ctx, "test-path")
+	if _, err := fns.GetInfo(ctx, "test-path"); err == nil {
+		t.Error("GetInfo() should return an error for a missing path")
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "file.txt")
+	if err := os.WriteFile(path, []byte("quiver"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	info, err := fns.GetInfo(ctx, path)
 	if err != nil {
-		t.Errorf("GetInfo() returned error: %v", err)
+		t.Fatalf("GetInfo() returned error: %v", err)
 	}
-	if info != nil {
-		t.Error("GetInfo() should return nil for unimplemented method")
+	if info.Type != ResourceTypeFile || info.Size != 6 {
+		t.Errorf("Expected a 6 byte file, got %+v", info)
+	}
+
+	info, err = fns.GetInfo(ctx, dir)
+	if err != nil {
+		t.Fatalf("GetInfo() returned error: %v", err)
+	}
+	if info.Type != ResourceTypeDir {
+		t.Errorf("Expected a directory, got %+v", info)
+	}
+}
+
+func TestFNS_GetInfoRemote(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/archive.tar.gz":
+			if r.Method != http.MethodHead {
+				t.Errorf("Expected a HEAD request, got %s", r.Method)
+			}
+			w.Header().Set("Content-Length", "31457280")
+			w.Header().Set("Last-Modified", "Wed, 21 Oct 2015 07:28:00 GMT")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	fns := NewFNS()
+	ctx := context.Background()
+
+	info, err := fns.GetInfo(ctx, server.URL+"/archive.tar.gz")
+	if err != nil {
+		t.Fatalf("GetInfo() returned error: %v", err)
+	}
+	if info.Size != 31457280 {
+		t.Errorf("Expected size 31457280, got %d", info.Size)
+	}
+	if info.ModTime.Year() != 2015 {
+		t.Errorf("Expected modification time from Last-Modified, got %v", info.ModTime)
+	}
+
+	if _, err := fns.GetInfo(ctx, server.URL+"/missing"); err == nil {
+		t.Error("GetInfo() should return an error for a missing URL")
 	}
 }
 
@@ -35,7 +92,48 @@ func TestFNS_Exists(t *testing.T) {
 		t.Errorf("Exists() returned error: %v", err)
 	}
 	if exists {
-		t.Error("Exists() should return false for unimplemented method")
+		t.Error("Exists() should return false for a missing path")
+	}
+
+	path := filepath.Join(t.TempDir(), "manifest.yaml")
+	if err := os.WriteFile(path, []byte("manifest: arrow@v1"), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	exists, err = fns.Exists(ctx, path)
+	if err != nil {
+		t.Errorf("Exists() returned error: %v", err)
+	}
+	if !exists {
+		t.Error("Exists() should return true for an existing file")
+	}
+}
+
+func TestFNS_ExistsRemote(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/present" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	fns := NewFNS()
+	ctx := context.Background()
+
+	exists, err := fns.Exists(ctx, server.URL+"/present")
+	if err != nil {
+		t.Fatalf("Exists() returned error: %v", err)
+	}
+	if !exists {
+		t.Error("Exists() should return true for a reachable URL")
+	}
+
+	exists, err = fns.Exists(ctx, server.URL+"/missing")
+	if err != nil {
+		t.Fatalf("Exists() returned error: %v", err)
+	}
+	if exists {
+		t.Error("Exists() should return false for a 404 URL")
 	}
 }
 
@@ -69,12 +167,38 @@ func TestFNS_Read(t *testing.T) {
 	fns := NewFNS()
 	ctx := context.Background()
 
-	data, err := fns.Read(ctx, "test-path")
+	if _, err := fns.Read(ctx, "test-path"); err == nil {
+		t.Error("Read() should return an error for a missing path")
+	}
+
+	path := filepath.Join(t.TempDir(), "manifest.yaml")
+	if err := os.WriteFile(path, []byte("manifest: arrow@v1"), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	data, err := fns.Read(ctx, path)
 	if err != nil {
-		t.Errorf("Read() returned error: %v", err)
+		t.Fatalf("Read() returned error: %v", err)
 	}
-	if data != nil {
-		t.Error("Read() should return nil for unimplemented method")
+	if string(data) != "manifest: arrow@v1" {
+		t.Errorf("Read() returned %q", string(data))
+	}
+}
+
+func TestFNS_ReadRemote(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("manifest: quiver@v1"))
+	}))
+	defer server.Close()
+
+	fns := NewFNS()
+
+	data, err := fns.Read(context.Background(), server.URL+"/quiver.yaml")
+	if err != nil {
+		t.Fatalf("Read() returned error: %v", err)
+	}
+	if string(data) != "manifest: quiver@v1" {
+		t.Errorf("Read() returned %q", string(data))
 	}
 }
 
@@ -239,13 +363,34 @@ func TestFNS_DownloadStream(t *testing.T) {
 	fns := NewFNS()
 	ctx := context.Background()
 
-	progress := func(bytes int) {}
-	reader, err := fns.DownloadStream(ctx, "http://example.com", progress)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("streamed payload"))
+	}))
+	defer server.Close()
+
+	downloaded := 0
+	reader, err := fns.DownloadStream(ctx, server.URL+"/archive", func(bytes int) {
+		downloaded += bytes
+	})
 	if err != nil {
-		t.Errorf("DownloadStream() returned error: %v", err)
+		t.Fatalf("DownloadStream() returned error: %v", err)
 	}
-	if reader != nil {
-		t.Error("DownloadStream() should return nil for unimplemented method")
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("failed to read stream: %v", err)
+	}
+	if string(data) != "streamed payload" || downloaded != len(data) {
+		t.Errorf("Expected the payload with matching progress, got %q and %d bytes", data, downloaded)
+	}
+
+	if _, err := fns.DownloadStream(ctx, server.URL+"/missing", nil); err == nil {
+		t.Error("DownloadStream() should return an error for a missing URL")
 	}
 }
 
@@ -253,12 +398,25 @@ func TestFNS_Fetch(t *testing.T) {
 	fns := NewFNS()
 	ctx := context.Background()
 
-	data, err := fns.Fetch(ctx, "http://example.com")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("payload"))
+	}))
+	defer server.Close()
+
+	data, err := fns.Fetch(ctx, server.URL+"/file")
 	if err != nil {
-		t.Errorf("Fetch() returned error: %v", err)
+		t.Fatalf("Fetch() returned error: %v", err)
 	}
-	if data != nil {
-		t.Error("Fetch() should return nil for unimplemented method")
+	if string(data) != "payload" {
+		t.Errorf("Fetch() returned %q, expected %q", string(data), "payload")
+	}
+
+	if _, err := fns.Fetch(ctx, server.URL+"/missing"); err == nil {
+		t.Error("Fetch() should return an error for non-2xx responses")
 	}
 }
 
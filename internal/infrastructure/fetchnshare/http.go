@@ -0,0 +1,75 @@
+package fetchnshare
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+func isRemote(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
+
+func (f *FNS) request(
+	ctx context.Context,
+	method string,
+	url string,
+) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request for %s: %w", url, err)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request %s: %w", url, err)
+	}
+
+	return resp, nil
+}
+
+// remoteInfo describes a URL from a HEAD request. Size is -1 when
+// the server does not report a Content-Length.
+func (f *FNS) remoteInfo(
+	ctx context.Context,
+	url string,
+) (*ResourceInfo, error) {
+	resp, err := f.request(ctx, "HEAD", url)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("failed to get info for %s: unexpected status %d", url, resp.StatusCode)
+	}
+
+	info := &ResourceInfo{
+		Path: url,
+		Type: ResourceTypeFile,
+		Size: resp.ContentLength,
+	}
+
+	if modified, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		info.ModTime = modified
+	}
+
+	return info, nil
+}
+
+// progressReader reports the size of every chunk read from a download.
+type progressReader struct {
+	io.ReadCloser
+	progress func(int)
+}
+
+func (p *progressReader) Read(buf []byte) (int, error) {
+	n, err := p.ReadCloser.Read(buf)
+	if n > 0 {
+		p.progress(n)
+	}
+
+	return n, err
+}
@@ -0,0 +1,149 @@
+package netbridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+)
+
+var ErrNoFreePort = errors.New("no free port left in netbridge.allowed_ports")
+
+// AllowPorts sets the ports AllocatePorts hands out, as a list of
+// ports and ranges such as "40128-40256".
+func (n *NetbridgeImpl) AllowPorts(spec string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.allowedPorts = spec
+}
+
+// AllocatePorts gives every rule the ports it runs on. A rule that
+// already has ports keeps them when they all lie in allowed_ports,
+// overlap no taken range or other rule and can be bound on this
+// host; held ranges belong to the instance itself, which may be
+// running, so they are not bind-tested. Any other rule gets the
+// first free run of as many ports as it spans, one when it has none.
+func (n *NetbridgeImpl) AllocatePorts(
+	ctx context.Context,
+	rules []port.PortRule,
+	taken []port.Range,
+	held []port.Range,
+) ([]port.PortRule, error) {
+	n.mu.Lock()
+	spec := n.allowedPorts
+	n.mu.Unlock()
+
+	ranges, err := port.ParseRanges(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse netbridge.allowed_ports: %w", err)
+	}
+
+	used := append([]port.Range{}, taken...)
+	allocated := append([]port.PortRule{}, rules...)
+	kept := make([]bool, len(allocated))
+
+	for i, rule := range allocated {
+		if rule.StartPort <= 0 {
+			continue
+		}
+
+		r := rule.Range()
+		if !usable(r, ranges, used) {
+			continue
+		}
+		if !within(r, held) && !rangeBindable(r, rule.Protocol) {
+			continue
+		}
+
+		used = append(used, r)
+		kept[i] = true
+	}
+
+	for i := range allocated {
+		if kept[i] {
+			continue
+		}
+
+		width := 1
+		if allocated[i].StartPort > 0 {
+			width = allocated[i].Range().Len()
+		}
+
+		r, err := freeRange(ctx, ranges, used, width, allocated[i].Protocol)
+		if err != nil {
+			return nil, fmt.Errorf("failed to allocate %s: %w", allocated[i].Name, err)
+		}
+
+		used = append(used, r)
+		allocated[i].StartPort = r.Start
+		allocated[i].EndPort = r.End
+	}
+
+	return allocated, nil
+}
+
+// usable reports whether every port of r is allowed and none of
+// them is used.
+func usable(r port.Range, allowed []port.Range, used []port.Range) bool {
+	if r.Start < 1 || r.End > 65535 {
+		return false
+	}
+
+	for _, other := range used {
+		if r.Overlaps(other) {
+			return false
+		}
+	}
+
+	for portNum := r.Start; portNum <= r.End; portNum++ {
+		if !slices.ContainsFunc(allowed, func(a port.Range) bool { return a.Contains(portNum) }) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func within(r port.Range, ranges []port.Range) bool {
+	return slices.ContainsFunc(ranges, func(other port.Range) bool {
+		return other.Start <= r.Start && r.End <= other.End
+	})
+}
+
+func rangeBindable(r port.Range, protocol port.Protocol) bool {
+	for portNum := r.Start; portNum <= r.End; portNum++ {
+		if !bindable(portNum, protocol) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// freeRange returns the first run of width consecutive allowed ports
+// that are not used and can be bound.
+func freeRange(
+	ctx context.Context,
+	ranges []port.Range,
+	used []port.Range,
+	width int,
+	protocol port.Protocol,
+) (port.Range, error) {
+	for _, allowed := range ranges {
+		for start := allowed.Start; start+width-1 <= allowed.End; start++ {
+			if err := ctx.Err(); err != nil {
+				return port.Range{}, err
+			}
+
+			candidate := port.Range{Start: start, End: start + width - 1}
+			if usable(candidate, ranges, used) && rangeBindable(candidate, protocol) {
+				return candidate, nil
+			}
+		}
+	}
+
+	return port.Range{}, ErrNoFreePort
+}
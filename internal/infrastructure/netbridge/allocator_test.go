@@ -0,0 +1,156 @@
+package netbridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+)
+
+// busyPort holds a TCP port for the duration of the test.
+func busyPort(t *testing.T) int {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func TestNetbridgeImpl_AllocatePorts(t *testing.T) {
+	busy := busyPort(t)
+
+	nb := NewNetbridgeWith(false)
+	nb.AllowPorts(fmt.Sprintf("%d-%d", busy, busy+4))
+
+	rules := []port.PortRule{
+		{Name: "GAME_PORT", Protocol: port.ProtocolTCP},
+		{Name: "QUERY_PORT", StartPort: busy + 2, EndPort: busy + 2, Protocol: port.ProtocolUDP},
+		{Name: "CHAT_PORT", Protocol: port.ProtocolTCPUDP},
+	}
+
+	allocated, err := nb.AllocatePorts(context.Background(), rules, []port.Range{{Start: busy + 1, End: busy + 1}}, nil)
+	if err != nil {
+		t.Fatalf("AllocatePorts() returned error: %v", err)
+	}
+
+	if allocated[0].StartPort != busy+3 || allocated[0].EndPort != busy+3 {
+		t.Errorf("Expected GAME_PORT to skip the bound, taken and kept ports, got %+v", allocated[0])
+	}
+	if allocated[1].StartPort != busy+2 {
+		t.Errorf("Expected QUERY_PORT to keep its port, got %+v", allocated[1])
+	}
+	if allocated[2].StartPort != busy+4 {
+		t.Errorf("Expected CHAT_PORT to get the next free port, got %+v", allocated[2])
+	}
+	if rules[0].StartPort != 0 {
+		t.Error("AllocatePorts() should not modify the given rules")
+	}
+}
+
+func TestNetbridgeImpl_AllocatePorts_Ranges(t *testing.T) {
+	busy := busyPort(t)
+
+	nb := NewNetbridgeWith(false)
+	nb.AllowPorts(fmt.Sprintf("%d-%d", busy, busy+12))
+
+	taken := []port.Range{{Start: busy + 3, End: busy + 5}}
+	rules := []port.PortRule{
+		// ? Only two ports are free before the taken range, so a
+		// ? run of three starts at busy+6.
+		{Name: "RTP_PORTS", StartPort: 1, EndPort: 3, Protocol: port.ProtocolTCP},
+		{Name: "OVERLAPPING", StartPort: busy + 4, EndPort: busy + 7, Protocol: port.ProtocolTCP},
+		{Name: "GAME_PORT", Protocol: port.ProtocolTCP},
+	}
+
+	allocated, err := nb.AllocatePorts(context.Background(), rules, taken, nil)
+	if err != nil {
+		t.Fatalf("AllocatePorts() returned error: %v", err)
+	}
+
+	if got := allocated[0].Range(); got != (port.Range{Start: busy + 6, End: busy + 8}) {
+		t.Errorf("Expected RTP_PORTS outside allowed_ports to move to a free run of 3, got %v", got)
+	}
+	if got := allocated[1].Range(); got != (port.Range{Start: busy + 9, End: busy + 12}) {
+		t.Errorf("Expected OVERLAPPING to move past the taken range, got %v", got)
+	}
+	if got := allocated[2].Range(); got != (port.Range{Start: busy + 1, End: busy + 1}) {
+		t.Errorf("Expected GAME_PORT to fill the gap before the taken range, got %v", got)
+	}
+}
+
+func TestNetbridgeImpl_AllocatePorts_Kept(t *testing.T) {
+	busy := busyPort(t)
+
+	nb := NewNetbridgeWith(false)
+	nb.AllowPorts(fmt.Sprintf("%d-%d", busy, busy+4))
+
+	held := port.Range{Start: busy, End: busy + 1}
+	rules := []port.PortRule{{Name: "GAME_PORT", StartPort: busy, EndPort: busy + 1, Protocol: port.ProtocolTCP}}
+
+	// ? The instance itself may hold its ports while running.
+	allocated, err := nb.AllocatePorts(context.Background(), rules, nil, []port.Range{held})
+	if err != nil {
+		t.Fatalf("AllocatePorts() returned error: %v", err)
+	}
+	if allocated[0].Range() != held {
+		t.Errorf("Expected the held range to be kept, got %v", allocated[0].Range())
+	}
+
+	// ? Ports from a lockfile are bind-tested.
+	allocated, err = nb.AllocatePorts(context.Background(), rules, nil, nil)
+	if err != nil {
+		t.Fatalf("AllocatePorts() returned error: %v", err)
+	}
+	if got := allocated[0].Range(); got != (port.Range{Start: busy + 1, End: busy + 2}) {
+		t.Errorf("Expected the bound range to move, got %v", got)
+	}
+}
+
+func TestNetbridgeImpl_AllocatePorts_Exhausted(t *testing.T) {
+	busy := busyPort(t)
+
+	testCases := []struct {
+		name    string
+		allowed string
+	}{
+		{"bound", fmt.Sprint(busy)},
+		{"none allowed", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			nb := NewNetbridgeWith(false)
+			nb.AllowPorts(tc.allowed)
+
+			_, err := nb.AllocatePorts(context.Background(), []port.PortRule{{Name: "GAME_PORT"}}, nil, nil)
+			if !errors.Is(err, ErrNoFreePort) {
+				t.Errorf("Expected ErrNoFreePort, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNetbridgeImpl_AllocatePorts_InvalidRange(t *testing.T) {
+	nb := NewNetbridgeWith(false)
+	nb.AllowPorts("40256-40128")
+
+	_, err := nb.AllocatePorts(context.Background(), []port.PortRule{{Name: "GAME_PORT"}}, nil, nil)
+	if !errors.Is(err, port.ErrInvalidRange) {
+		t.Errorf("Expected ErrInvalidRange, got %v", err)
+	}
+}
+
+func TestBindable(t *testing.T) {
+	busy := busyPort(t)
+
+	if bindable(busy, port.ProtocolTCP) || bindable(busy, port.ProtocolTCPUDP) {
+		t.Errorf("Expected port %d to be reported as bound over TCP", busy)
+	}
+}
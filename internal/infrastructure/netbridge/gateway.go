@@ -0,0 +1,143 @@
+package netbridge
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// gatewayPort is where NAT-PMP and PCP servers listen.
+	gatewayPort = 5351
+
+	// initialRetransmit follows RFC 6886: requests are resent after
+	// 250ms, doubling the wait each time.
+	initialRetransmit = 250 * time.Millisecond
+	maxAttempts       = 4
+
+	// routeFlagGateway is RTF_GATEWAY in /proc/net/route.
+	routeFlagGateway = 0x2
+)
+
+// route is the IPv4 default route of this host.
+type route struct {
+	iface   string
+	gateway net.IP
+}
+
+// gatewayAddress returns override when set, otherwise the default
+// gateway on the NAT-PMP/PCP port.
+func gatewayAddress(override string) (string, error) {
+	if override != "" {
+		return override, nil
+	}
+
+	defaultRoute, err := defaultRoute()
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrNoGateway, err)
+	}
+
+	return net.JoinHostPort(defaultRoute.gateway.String(), strconv.Itoa(gatewayPort)), nil
+}
+
+// parseRoutes finds the IPv4 default route in the format of
+// /proc/net/route, where addresses are little endian hex.
+func parseRoutes(r io.Reader) (route, error) {
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || fields[1] != "00000000" {
+			continue
+		}
+
+		flags, err := strconv.ParseUint(fields[3], 16, 32)
+		if err != nil || flags&routeFlagGateway == 0 {
+			continue
+		}
+
+		raw, err := hex.DecodeString(fields[2])
+		if err != nil || len(raw) != net.IPv4len {
+			continue
+		}
+
+		ip := make(net.IP, net.IPv4len)
+		binary.BigEndian.PutUint32(ip, binary.LittleEndian.Uint32(raw))
+
+		return route{iface: fields[0], gateway: ip}, nil
+	}
+
+	if err := scanner.Err(); err != nil {
+		return route{}, err
+	}
+
+	return route{}, fmt.Errorf("no default route")
+}
+
+// exchange sends request to a NAT-PMP or PCP server over UDP and
+// resends it until valid accepts a response, the attempts run out
+// or ctx is done.
+func exchange(
+	ctx context.Context,
+	address string,
+	request []byte,
+	valid func(response []byte) bool,
+) ([]byte, error) {
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach %s: %w", address, err)
+	}
+	defer conn.Close()
+
+	buffer := make([]byte, 1100)
+	wait := initialRetransmit
+
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if _, err := conn.Write(request); err != nil {
+			return nil, fmt.Errorf("failed to send to %s: %w", address, err)
+		}
+
+		deadline := time.Now().Add(wait)
+		if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+			deadline = ctxDeadline
+		}
+		conn.SetReadDeadline(deadline)
+
+		for {
+			n, err := conn.Read(buffer)
+			if err != nil {
+				break
+			}
+
+			if valid(buffer[:n]) {
+				return append([]byte(nil), buffer[:n]...), nil
+			}
+		}
+
+		wait *= 2
+	}
+
+	return nil, fmt.Errorf("%w: no answer from %s", ErrNoGateway, address)
+}
+
+// localAddressFor returns the local IP used to reach a host:port.
+func localAddressFor(address string) (net.IP, error) {
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the local address towards %s: %w", address, err)
+	}
+	defer conn.Close()
+
+	return conn.LocalAddr().(*net.UDPAddr).IP, nil
+}
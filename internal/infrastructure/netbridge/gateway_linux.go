@@ -0,0 +1,20 @@
+//go:build linux
+
+package netbridge
+
+import (
+	"fmt"
+	"os"
+)
+
+const routesPath = "/proc/net/route"
+
+func defaultRoute() (route, error) {
+	file, err := os.Open(routesPath)
+	if err != nil {
+		return route{}, fmt.Errorf("failed to read routes: %w", err)
+	}
+	defer file.Close()
+
+	return parseRoutes(file)
+}
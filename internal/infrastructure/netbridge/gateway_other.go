@@ -0,0 +1,9 @@
+//go:build !linux
+
+package netbridge
+
+import "errors"
+
+func defaultRoute() (route, error) {
+	return route{}, errors.ErrUnsupported
+}
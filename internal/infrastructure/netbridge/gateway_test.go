@@ -0,0 +1,83 @@
+package netbridge
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRoutes(t *testing.T) {
+	routes := "Iface\tDestination\tGateway \tFlags\tRefCnt\tUse\tMetric\tMask\n" +
+		"eth0\t0000A8C0\t00000000\t0001\t0\t0\t0\t00FFFFFF\n" +
+		"eth0\t00000000\t0101A8C0\t0003\t0\t0\t100\t00000000\n"
+
+	defaultRoute, err := parseRoutes(strings.NewReader(routes))
+	if err != nil {
+		t.Fatalf("parseRoutes() returned error: %v", err)
+	}
+	if !defaultRoute.gateway.Equal(net.ParseIP("192.168.1.1")) || defaultRoute.iface != "eth0" {
+		t.Errorf("Expected 192.168.1.1 on eth0, got %s on %s", defaultRoute.gateway, defaultRoute.iface)
+	}
+
+	if _, err := parseRoutes(strings.NewReader("Iface\tDestination\tGateway\tFlags\n")); err == nil {
+		t.Error("parseRoutes() should fail without a default route")
+	}
+}
+
+func TestGatewayAddress(t *testing.T) {
+	address, err := gatewayAddress("127.0.0.1:15351")
+	if err != nil || address != "127.0.0.1:15351" {
+		t.Errorf("gatewayAddress() = %q, %v, expected the override", address, err)
+	}
+}
+
+func TestExchange(t *testing.T) {
+	server, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	// ? Drop the first request and answer with noise before the
+	// ? real response, so retransmission and filtering are exercised.
+	go func() {
+		buffer := make([]byte, 64)
+		for i := 0; ; i++ {
+			n, addr, err := server.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+			if i == 0 {
+				continue
+			}
+			server.WriteTo([]byte("noise"), addr)
+			server.WriteTo(append([]byte("echo:"), buffer[:n]...), addr)
+		}
+	}()
+
+	response, err := exchange(context.Background(), server.LocalAddr().String(), []byte("ping"), func(response []byte) bool {
+		return strings.HasPrefix(string(response), "echo:")
+	})
+	if err != nil || string(response) != "echo:ping" {
+		t.Errorf("exchange() = %q, %v", response, err)
+	}
+}
+
+func TestExchange_NoAnswer(t *testing.T) {
+	silent, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer silent.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	_, err = exchange(ctx, silent.LocalAddr().String(), []byte("ping"), func([]byte) bool { return true })
+	if err == nil || !(errors.Is(err, ErrNoGateway) || errors.Is(err, context.DeadlineExceeded)) {
+		t.Errorf("Expected no answer to fail, got %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+package netbridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"syscall"
+
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+)
+
+// probeAddress is never contacted: dialing UDP towards it only
+// looks up the route this host would use to reach the internet.
+const probeAddress = "192.0.2.1:9"
+
+// IsPortAvailable reports whether portNum can be bound for both
+// TCP and UDP on every interface.
+func (n *NetbridgeImpl) IsPortAvailable(
+	ctx context.Context,
+	portNum int,
+) (bool, error) {
+	return portAvailable(portNum, port.ProtocolTCPUDP)
+}
+
+func (n *NetbridgeImpl) ArePortsAvailable(
+	ctx context.Context,
+	ports []int,
+) (bool, error) {
+	for _, portNum := range ports {
+		available, err := n.IsPortAvailable(ctx, portNum)
+		if err != nil || !available {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// LocalIP returns the IPv4 address of the interface holding the
+// default route.
+func (n *NetbridgeImpl) LocalIP(
+	ctx context.Context,
+) (string, error) {
+	if defaultRoute, err := defaultRoute(); err == nil {
+		if iface, err := net.InterfaceByName(defaultRoute.iface); err == nil {
+			if addrs, err := iface.Addrs(); err == nil {
+				if ip := firstIPv4(addrs); ip != nil {
+					return ip.String(), nil
+				}
+			}
+		}
+	}
+
+	// ? Without a readable routing table, ask the kernel which
+	// ? source address it would pick.
+	ip, err := localAddressFor(probeAddress)
+	if err != nil {
+		return "", err
+	}
+
+	return ip.String(), nil
+}
+
+func firstIPv4(addrs []net.Addr) net.IP {
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.To4() != nil && !ipNet.IP.IsLoopback() {
+			return ipNet.IP.To4()
+		}
+	}
+
+	return nil
+}
+
+// portAvailable binds portNum for TCP and UDP separately, as
+// either can be taken on its own. Only a port in use is reported
+// as unavailable; other bind failures are returned.
+func portAvailable(portNum int, protocol port.Protocol) (bool, error) {
+	if portNum < 1 || portNum > 65535 {
+		return false, fmt.Errorf("port %d is out of range", portNum)
+	}
+
+	if !protocol.IsUDP() {
+		if available, err := tcpAvailable(portNum); err != nil || !available {
+			return false, err
+		}
+	}
+
+	if !protocol.IsTCP() {
+		if available, err := udpAvailable(portNum); err != nil || !available {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func tcpAvailable(portNum int) (bool, error) {
+	listener, err := net.Listen("tcp", net.JoinHostPort("", strconv.Itoa(portNum)))
+	if err != nil {
+		return inUse(err)
+	}
+
+	return true, listener.Close()
+}
+
+func udpAvailable(portNum int) (bool, error) {
+	conn, err := net.ListenPacket("udp", net.JoinHostPort("", strconv.Itoa(portNum)))
+	if err != nil {
+		return inUse(err)
+	}
+
+	return true, conn.Close()
+}
+
+func inUse(err error) (bool, error) {
+	if errors.Is(err, syscall.EADDRINUSE) {
+		return false, nil
+	}
+
+	return false, err
+}
+
+// bindable reports whether portNum can be listened on for protocol,
+// trying both TCP and UDP for tcp/udp and for an unset protocol.
+func bindable(portNum int, protocol port.Protocol) bool {
+	available, err := portAvailable(portNum, protocol)
+	return err == nil && available
+}
@@ -0,0 +1,108 @@
+package netbridge
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+)
+
+// busyUDPPort holds a UDP port for the duration of the test.
+func busyUDPPort(t *testing.T) int {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+func TestNetbridgeImpl_IsPortAvailable(t *testing.T) {
+	nb := NewNetbridgeWith(false)
+	ctx := context.Background()
+
+	for name, busy := range map[string]int{"tcp": busyPort(t), "udp": busyUDPPort(t)} {
+		available, err := nb.IsPortAvailable(ctx, busy)
+		if err != nil || available {
+			t.Errorf("IsPortAvailable() = %v, %v, expected the %s port %d to be taken", available, err, name, busy)
+		}
+	}
+
+	if _, err := nb.IsPortAvailable(ctx, 70000); err == nil {
+		t.Error("IsPortAvailable() should reject ports above 65535")
+	}
+}
+
+func TestPortAvailable_Protocols(t *testing.T) {
+	tcp := busyPort(t)
+	udp := busyUDPPort(t)
+
+	testCases := []struct {
+		name      string
+		port      int
+		protocol  port.Protocol
+		available bool
+	}{
+		{"tcp port taken for tcp", tcp, port.ProtocolTCP, false},
+		{"tcp port taken for tcp/udp", tcp, port.ProtocolTCPUDP, false},
+		{"udp port taken for udp", udp, port.ProtocolUDP, false},
+		{"udp port taken for tcp/udp", udp, port.ProtocolTCPUDP, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			available, err := portAvailable(tc.port, tc.protocol)
+			if err != nil || available != tc.available {
+				t.Errorf("portAvailable(%d, %s) = %v, %v", tc.port, tc.protocol, available, err)
+			}
+		})
+	}
+}
+
+func TestNetbridgeImpl_ArePortsAvailable(t *testing.T) {
+	nb := NewNetbridgeWith(false)
+	busy := busyPort(t)
+
+	available, err := nb.ArePortsAvailable(context.Background(), []int{busy})
+	if err != nil || available {
+		t.Errorf("ArePortsAvailable() = %v, %v, expected a taken port to fail the check", available, err)
+	}
+
+	available, err = nb.ArePortsAvailable(context.Background(), nil)
+	if err != nil || !available {
+		t.Errorf("ArePortsAvailable() = %v, %v, expected no ports to be available", available, err)
+	}
+}
+
+func TestNetbridgeImpl_LocalIP(t *testing.T) {
+	if _, err := localAddressFor(probeAddress); err != nil {
+		t.Skipf("no route to the internet: %v", err)
+	}
+
+	ip, err := NewNetbridgeWith(false).LocalIP(context.Background())
+	if err != nil {
+		t.Fatalf("LocalIP() returned error: %v", err)
+	}
+	if parsed := net.ParseIP(ip); parsed == nil || parsed.To4() == nil || parsed.IsLoopback() {
+		t.Errorf("Expected a non loopback IPv4 address, got %q", ip)
+	}
+}
+
+func TestFirstIPv4(t *testing.T) {
+	addrs := []net.Addr{
+		&net.IPNet{IP: net.ParseIP("127.0.0.1"), Mask: net.CIDRMask(8, 32)},
+		&net.IPNet{IP: net.ParseIP("fe80::1"), Mask: net.CIDRMask(64, 128)},
+		&net.IPNet{IP: net.ParseIP("192.168.1.20"), Mask: net.CIDRMask(24, 32)},
+	}
+
+	if ip := firstIPv4(addrs); !ip.Equal(net.ParseIP("192.168.1.20")) {
+		t.Errorf("Expected 192.168.1.20, got %s", ip)
+	}
+	if ip := firstIPv4(addrs[:2]); ip != nil {
+		t.Errorf("Expected no address, got %s", ip)
+	}
+}
@@ -10,6 +10,17 @@ type NetbridgeInterface interface {
 	IsEnabled() bool
 	IsAvailable() bool
 
+	// Probe tries every configured strategy in order of preference
+	// and activates the first one the gateway supports.
+	Probe(
+		ctx context.Context,
+	) error
+	// Status reports which strategy is active and why the others
+	// are not available.
+	Status(
+		ctx context.Context,
+	) port.NetbridgeStatus
+
 	PublicIP(
 		ctx context.Context,
 	) (string, error)
@@ -17,6 +28,16 @@ type NetbridgeInterface interface {
 		ctx context.Context,
 	) (string, error)
 
+	// AllocatePorts assigns free ports from netbridge.allowed_ports
+	// to every rule without valid ones, skipping the taken ranges.
+	// Held ranges are the instance's own and are not bind-tested.
+	AllocatePorts(
+		ctx context.Context,
+		rules []port.PortRule,
+		taken []port.Range,
+		held []port.Range,
+	) ([]port.PortRule, error)
+
 	IsPortAvailable(
 		ctx context.Context,
 		port int,
@@ -26,30 +47,51 @@ type NetbridgeInterface interface {
 		ports []int,
 	) (bool, error)
 
+	// ForwardPort maps every port of a rule, for both protocols of
+	// a tcp/udp rule, and returns it with its statuses.
 	ForwardPort(
 		ctx context.Context,
-		port int,
+		rule port.PortRule,
 	) (port.PortRule, error)
 	ForwardPorts(
 		ctx context.Context,
-		ports []int,
+		rules []port.PortRule,
 	) ([]port.PortRule, error)
 
 	ReversePort(
 		ctx context.Context,
-		port int,
+		rule port.PortRule,
 	) (port.PortRule, error)
 	ReversePorts(
 		ctx context.Context,
-		ports []int,
+		rules []port.PortRule,
 	) ([]port.PortRule, error)
 
+	// Reconcile renews forwarded ports before their lease ends and
+	// re-creates the ones the gateway lost.
+	Reconcile(
+		ctx context.Context,
+	) error
+	// Close removes every forwarded port from the gateway.
+	Close(
+		ctx context.Context,
+	) error
+
+	// CheckReachability tests whether the forwarded ports of a rule
+	// can be reached from the internet and explains why not.
+	CheckReachability(
+		ctx context.Context,
+		rule port.PortRule,
+	) (port.PortRule, error)
+
 	GetPortForwardingStatus(
 		ctx context.Context,
-		port int,
+		rule port.PortRule,
 	) (port.ForwardingStatus, error)
+	// GetPortForwardingStatuses returns the rules with their
+	// forwarding status per protocol.
 	GetPortForwardingStatuses(
 		ctx context.Context,
-		ports []int,
-	) ([]port.ForwardingStatus, error)
+		rules []port.PortRule,
+	) ([]port.PortRule, error)
 }
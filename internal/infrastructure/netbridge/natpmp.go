@@ -0,0 +1,159 @@
+package netbridge
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+)
+
+const (
+	natpmpVersion = 0
+
+	natpmpOpExternalAddress = 0
+	natpmpOpMapUDP          = 1
+	natpmpOpMapTCP          = 2
+	natpmpOpResponse        = 128
+
+	// natpmpLease is asked for when a mapping does not set one, as
+	// NAT-PMP reads a zero lifetime as a deletion.
+	natpmpLease = 2 * time.Hour
+)
+
+var natpmpResults = map[uint16]string{
+	1: "unsupported version",
+	2: "not authorized",
+	3: "network failure",
+	4: "out of resources",
+	5: "unsupported opcode",
+}
+
+// NATPMP maps ports with the NAT Port Mapping Protocol (RFC 6886)
+// on the default gateway.
+type NATPMP struct {
+	// gateway overrides the server address, so tests can point the
+	// strategy at a fake gateway.
+	gateway string
+
+	mu      sync.Mutex
+	address string
+}
+
+func NewNATPMP() *NATPMP {
+	return &NATPMP{}
+}
+
+func (n *NATPMP) Name() string {
+	return "natpmp"
+}
+
+// Discover asks the default gateway for its external address, which
+// only succeeds when it speaks NAT-PMP.
+func (n *NATPMP) Discover(ctx context.Context) error {
+	address, err := gatewayAddress(n.gateway)
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	n.address = address
+	n.mu.Unlock()
+
+	_, err = n.ExternalIP(ctx)
+	return err
+}
+
+func (n *NATPMP) ExternalIP(ctx context.Context) (string, error) {
+	response, err := n.request(ctx, []byte{natpmpVersion, natpmpOpExternalAddress}, 12)
+	if err != nil {
+		return "", err
+	}
+
+	return net.IP(response[8:12]).String(), nil
+}
+
+func (n *NATPMP) AddMapping(ctx context.Context, mapping Mapping) error {
+	lease := mapping.Lease
+	if lease <= 0 {
+		lease = natpmpLease
+	}
+
+	response, err := n.mapPort(ctx, mapping, mapping.ExternalPort, lease)
+	if err != nil {
+		return err
+	}
+
+	// ? The gateway may hand out another external port when the one
+	// ? asked for is taken; that mapping is useless to the instance.
+	if assigned := int(binary.BigEndian.Uint16(response[10:12])); assigned != mapping.ExternalPort {
+		n.mapPort(ctx, mapping, 0, 0)
+		return fmt.Errorf("gateway assigned port %d instead of %d", assigned, mapping.ExternalPort)
+	}
+
+	return nil
+}
+
+func (n *NATPMP) DeleteMapping(ctx context.Context, mapping Mapping) error {
+	_, err := n.mapPort(ctx, mapping, 0, 0)
+	return err
+}
+
+func (n *NATPMP) mapPort(
+	ctx context.Context,
+	mapping Mapping,
+	externalPort int,
+	lease time.Duration,
+) ([]byte, error) {
+	request := make([]byte, 12)
+	request[0] = natpmpVersion
+	request[1] = natpmpOpcode(mapping.Protocol)
+	binary.BigEndian.PutUint16(request[4:6], uint16(mapping.InternalPort))
+	binary.BigEndian.PutUint16(request[6:8], uint16(externalPort))
+	binary.BigEndian.PutUint32(request[8:12], uint32(lease.Seconds()))
+
+	return n.request(ctx, request, 16)
+}
+
+// request sends a NAT-PMP request and checks the result code of the
+// response matching its opcode.
+func (n *NATPMP) request(ctx context.Context, request []byte, size int) ([]byte, error) {
+	n.mu.Lock()
+	address := n.address
+	n.mu.Unlock()
+
+	if address == "" {
+		return nil, ErrNoGateway
+	}
+
+	response, err := exchange(ctx, address, request, func(response []byte) bool {
+		return len(response) >= 4 &&
+			response[0] == natpmpVersion &&
+			response[1] == natpmpOpResponse+request[1]
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if result := binary.BigEndian.Uint16(response[2:4]); result != 0 {
+		return nil, fmt.Errorf("NAT-PMP error %d %s", result, natpmpResults[result])
+	}
+
+	if len(response) < size {
+		return nil, fmt.Errorf("NAT-PMP response is %d bytes, expected %d", len(response), size)
+	}
+
+	return response, nil
+}
+
+// natpmpOpcode returns the map opcode for a tcp or udp mapping.
+func natpmpOpcode(protocol port.Protocol) byte {
+	if protocol.IsUDP() {
+		return natpmpOpMapUDP
+	}
+
+	return natpmpOpMapTCP
+}
@@ -0,0 +1,180 @@
+package netbridge
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+)
+
+// fakeNATPMP is a NAT-PMP gateway on a local UDP socket. Ports in
+// taken are answered with the next port instead.
+type fakeNATPMP struct {
+	conn net.PacketConn
+
+	mu       sync.Mutex
+	mappings map[string]uint32
+	taken    map[uint16]bool
+	result   uint16
+}
+
+func newFakeNATPMP(t *testing.T) *fakeNATPMP {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	gateway := &fakeNATPMP{conn: conn, mappings: map[string]uint32{}, taken: map[uint16]bool{}}
+	go gateway.serve()
+
+	return gateway
+}
+
+func (f *fakeNATPMP) serve() {
+	buffer := make([]byte, 64)
+
+	for {
+		n, addr, err := f.conn.ReadFrom(buffer)
+		if err != nil {
+			return
+		}
+		request := buffer[:n]
+
+		f.mu.Lock()
+		var response []byte
+		switch {
+		case request[0] != natpmpVersion:
+			response = []byte{natpmpVersion, natpmpOpResponse + request[1], 0, 1, 0, 0, 0, 0}
+
+		case request[1] == natpmpOpExternalAddress:
+			response = make([]byte, 12)
+			copy(response[8:12], net.ParseIP("198.51.100.4").To4())
+
+		default:
+			response = make([]byte, 16)
+			internal := binary.BigEndian.Uint16(request[4:6])
+			external := binary.BigEndian.Uint16(request[6:8])
+			lifetime := binary.BigEndian.Uint32(request[8:12])
+			key := fmt.Sprintf("%d/%d", request[1], internal)
+
+			if lifetime == 0 {
+				delete(f.mappings, key)
+			} else {
+				if f.taken[external] {
+					external++
+				}
+				f.mappings[key] = lifetime
+			}
+
+			copy(response[8:10], request[4:6])
+			binary.BigEndian.PutUint16(response[10:12], external)
+			binary.BigEndian.PutUint32(response[12:16], lifetime)
+		}
+
+		response[0] = natpmpVersion
+		response[1] = natpmpOpResponse + request[1]
+		if f.result != 0 {
+			binary.BigEndian.PutUint16(response[2:4], f.result)
+		}
+		f.mu.Unlock()
+
+		f.conn.WriteTo(response, addr)
+	}
+}
+
+func (f *fakeNATPMP) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.mappings)
+}
+
+func (f *fakeNATPMP) lifetime(key string) uint32 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.mappings[key]
+}
+
+func discoveredNATPMP(t *testing.T, gateway *fakeNATPMP) *NATPMP {
+	t.Helper()
+
+	natpmp := NewNATPMP()
+	natpmp.gateway = gateway.conn.LocalAddr().String()
+
+	if err := natpmp.Discover(context.Background()); err != nil {
+		t.Fatalf("Discover() returned error: %v", err)
+	}
+
+	return natpmp
+}
+
+func TestNATPMP_ExternalIP(t *testing.T) {
+	natpmp := discoveredNATPMP(t, newFakeNATPMP(t))
+
+	ip, err := natpmp.ExternalIP(context.Background())
+	if err != nil || ip != "198.51.100.4" {
+		t.Errorf("ExternalIP() = %q, %v", ip, err)
+	}
+}
+
+func TestNATPMP_Mappings(t *testing.T) {
+	gateway := newFakeNATPMP(t)
+	natpmp := discoveredNATPMP(t, gateway)
+	ctx := context.Background()
+
+	mapping := Mapping{ExternalPort: 40130, InternalPort: 40130, Protocol: port.ProtocolUDP}
+	if err := natpmp.AddMapping(ctx, mapping); err != nil {
+		t.Fatalf("AddMapping() returned error: %v", err)
+	}
+	if gateway.count() != 1 {
+		t.Fatalf("Expected the gateway to hold one mapping, got %d", gateway.count())
+	}
+	if lifetime := gateway.lifetime("1/40130"); lifetime != uint32(natpmpLease.Seconds()) {
+		t.Errorf("Expected the default lease, got %d", lifetime)
+	}
+
+	if err := natpmp.DeleteMapping(ctx, mapping); err != nil {
+		t.Fatalf("DeleteMapping() returned error: %v", err)
+	}
+	if gateway.count() != 0 {
+		t.Error("Expected the mapping to be deleted")
+	}
+
+	gateway.mu.Lock()
+	gateway.taken[40131] = true
+	gateway.mu.Unlock()
+	err := natpmp.AddMapping(ctx, Mapping{ExternalPort: 40131, InternalPort: 40131, Protocol: port.ProtocolTCP})
+	if err == nil || !strings.Contains(err.Error(), "40132") {
+		t.Errorf("Expected a different assigned port to be rejected, got %v", err)
+	}
+	if gateway.count() != 0 {
+		t.Error("Expected the unwanted mapping to be released")
+	}
+}
+
+func TestNATPMP_Errors(t *testing.T) {
+	gateway := newFakeNATPMP(t)
+	natpmp := discoveredNATPMP(t, gateway)
+
+	gateway.mu.Lock()
+	gateway.result = 2
+	gateway.mu.Unlock()
+
+	err := natpmp.AddMapping(context.Background(), Mapping{ExternalPort: 1, InternalPort: 1, Protocol: port.ProtocolTCP})
+	if err == nil || !strings.Contains(err.Error(), "not authorized") {
+		t.Errorf("Expected the result code to be reported, got %v", err)
+	}
+
+	if _, err := NewNATPMP().ExternalIP(context.Background()); err == nil {
+		t.Error("ExternalIP() should fail before discovery")
+	}
+}
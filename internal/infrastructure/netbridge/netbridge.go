@@ -2,103 +2,456 @@ package netbridge
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/rabbytesoftware/quiver/internal/core/config"
 	"github.com/rabbytesoftware/quiver/internal/models/port"
 )
 
-// TODO: We should implement an statregy pattern for the netbridge
-// TODO: so we can easily add new implementations and support multiple protocols
-// TODO: like UPnP, NAT-PMP, hole-punching, etc.
+// rediscoverInterval is how long a failed probe is remembered
+// before the gateway is probed again.
+const rediscoverInterval = time.Minute
 
+// NetbridgeImpl forwards ports through the first Strategy the
+// gateway supports, falling back to the next available one when
+// the active strategy stops working.
 type NetbridgeImpl struct {
+	enabled    bool
+	strategies []Strategy
+
+	mu           sync.Mutex
+	allowedPorts string
+	probed       bool
+	probedAt     time.Time
+	active       Strategy
+	available    map[string]bool
+	errors       map[string]string
+	forwarded    map[mappingKey]*forwarding
+
+	// firewall opens forwarded ports on this host too, alongside
+	// the gateway strategies.
+	firewall         Strategy
+	firewallError    string
+	firewallWarnings []string
+
+	// publicMu serializes public IP lookups, which may take
+	// seconds, apart from mu.
+	publicMu    sync.Mutex
+	stunServers []string
+	echoURLs    []string
+	// reachabilityURL is asked to connect back to forwarded ports.
+	reachabilityURL string
+	client          *http.Client
+	publicIP        string
+	publicIPAt      time.Time
 }
 
 func NewNetbridge() NetbridgeInterface {
-	return &NetbridgeImpl{}
+	netbridge := config.GetNetbridge()
+
+	bridge := NewNetbridgeWith(netbridge.Enabled, strategiesFor(netbridge)...)
+	bridge.AllowPorts(netbridge.AllowedPorts)
+	bridge.UsePublicIPSources(netbridge.STUNServers, netbridge.EchoURLs)
+	bridge.UseReachabilityService(netbridge.ReachabilityURL)
+	if netbridge.Firewall == "nftables" {
+		bridge.UseFirewall(NewNFTables(netbridge.FirewallDryRun))
+	}
+
+	return bridge
+}
+
+// strategiesFor builds the configured strategies, skipping unknown
+// names.
+func strategiesFor(netbridge config.Netbridge) []Strategy {
+	var strategies []Strategy
+
+	for _, name := range netbridge.Strategies {
+		switch name {
+		case "upnp":
+			strategies = append(strategies, NewUPnP())
+		case "pcp":
+			strategies = append(strategies, NewPCP())
+		case "natpmp":
+			strategies = append(strategies, NewNATPMP())
+		case "relay":
+			strategies = append(strategies, NewRelay(netbridge.RelayAddress, netbridge.RelayToken))
+		}
+	}
+
+	return strategies
+}
+
+// NewNetbridgeWith uses the given strategies in order of preference
+// instead of the configured ones.
+func NewNetbridgeWith(enabled bool, strategies ...Strategy) *NetbridgeImpl {
+	return &NetbridgeImpl{
+		enabled:    enabled,
+		strategies: strategies,
+		available:  map[string]bool{},
+		errors:     map[string]string{},
+		forwarded:  map[mappingKey]*forwarding{},
+		client:     &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// UseFirewall opens every forwarded port in firewall as well, such
+// as the host's nftables.
+func (n *NetbridgeImpl) UseFirewall(firewall Strategy) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.firewall = firewall
 }
 
 func (n *NetbridgeImpl) IsEnabled() bool {
-	return true
+	return n.enabled
 }
 
+// IsAvailable reports whether any strategy found a gateway it can
+// open ports on.
 func (n *NetbridgeImpl) IsAvailable() bool {
-	return true
+	return n.discover(context.Background()) == nil
 }
 
-func (n *NetbridgeImpl) PublicIP(
-	ctx context.Context,
-) (string, error) {
-	return "", nil
+// Probe discovers every strategy in order of preference and makes
+// the first one that finds a gateway the active one. The firewall,
+// if any, is set up as well.
+func (n *NetbridgeImpl) Probe(ctx context.Context) error {
+	if !n.enabled {
+		return ErrDisabled
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.firewall != nil {
+		n.firewallError = ""
+		n.firewallWarnings = nil
+		if err := n.firewall.Discover(ctx); err != nil {
+			n.firewallError = err.Error()
+		} else if inspector, ok := n.firewall.(blockerFinder); ok {
+			n.firewallWarnings = inspector.Blockers(ctx)
+		}
+	}
+
+	return n.probe(ctx)
 }
 
-func (n *NetbridgeImpl) LocalIP(
-	ctx context.Context,
-) (string, error) {
-	return "", nil
+func (n *NetbridgeImpl) probe(ctx context.Context) error {
+	n.active = nil
+	n.available = map[string]bool{}
+	n.errors = map[string]string{}
+
+	for _, strategy := range n.strategies {
+		if err := strategy.Discover(ctx); err != nil {
+			n.errors[strategy.Name()] = err.Error()
+			continue
+		}
+
+		n.available[strategy.Name()] = true
+		if n.active == nil {
+			n.active = strategy
+		}
+	}
+	n.probed, n.probedAt = true, time.Now()
+
+	return n.unavailable()
 }
 
-func (n *NetbridgeImpl) IsPortAvailable(
-	ctx context.Context,
-	port int,
-) (bool, error) {
-	return true, nil
+func (n *NetbridgeImpl) unavailable() error {
+	if n.active != nil {
+		return nil
+	}
+
+	var reasons []string
+	for _, strategy := range n.strategies {
+		reasons = append(reasons, fmt.Sprintf("%s: %s", strategy.Name(), n.errors[strategy.Name()]))
+	}
+
+	return fmt.Errorf("%w (%s)", ErrUnavailable, strings.Join(reasons, "; "))
 }
 
-func (n *NetbridgeImpl) ArePortsAvailable(
-	ctx context.Context,
-	ports []int,
-) (bool, error) {
-	return true, nil
+// discover probes once, and again when no strategy was found more
+// than rediscoverInterval ago.
+func (n *NetbridgeImpl) discover(ctx context.Context) error {
+	if !n.enabled {
+		return ErrDisabled
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.probed && (n.active != nil || time.Since(n.probedAt) < rediscoverInterval) {
+		return n.unavailable()
+	}
+
+	return n.probe(ctx)
 }
 
+// use runs operation with the active strategy. When it fails, the
+// other available strategies are tried in order and the first one
+// that succeeds becomes the active one.
+func (n *NetbridgeImpl) use(ctx context.Context, operation func(Strategy) error) error {
+	if err := n.discover(ctx); err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	active := n.active
+	n.mu.Unlock()
+
+	err := operation(active)
+	if err == nil {
+		return nil
+	}
+
+	for _, strategy := range n.strategies {
+		n.mu.Lock()
+		available := n.available[strategy.Name()]
+		n.mu.Unlock()
+
+		if strategy == active || !available {
+			continue
+		}
+
+		if operation(strategy) == nil {
+			n.mu.Lock()
+			n.active = strategy
+			n.mu.Unlock()
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s: %w", active.Name(), err)
+}
+
+// Status reports whether forwarding is possible and which strategy
+// is active, probing the gateway first if that has not happened yet.
+func (n *NetbridgeImpl) Status(ctx context.Context) port.NetbridgeStatus {
+	err := n.discover(ctx)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	status := port.NetbridgeStatus{
+		Enabled:    n.enabled,
+		Available:  err == nil,
+		Strategies: []port.StrategyStatus{},
+	}
+	if n.active != nil {
+		status.Active = n.active.Name()
+	}
+	if n.firewall != nil {
+		status.Firewall = &port.StrategyStatus{
+			Name:      n.firewall.Name(),
+			Available: n.firewallError == "",
+			Error:     n.firewallError,
+			Warnings:  n.firewallWarnings,
+		}
+	}
+
+	for _, strategy := range n.strategies {
+		status.Strategies = append(status.Strategies, port.StrategyStatus{
+			Name:      strategy.Name(),
+			Available: n.available[strategy.Name()],
+			Error:     n.errors[strategy.Name()],
+		})
+	}
+
+	return status
+}
+
+// ForwardPort maps every port of rule on the gateway to the same
+// port on this host, once per protocol for tcp/udp, and keeps them
+// mapped: Reconcile renews the leases and retries failed forwards.
+// The returned rule carries the resulting statuses even when
+// forwarding fails.
 func (n *NetbridgeImpl) ForwardPort(
 	ctx context.Context,
-	portNum int,
+	rule port.PortRule,
 ) (port.PortRule, error) {
-	return port.PortRule{
-		StartPort:        portNum,
-		EndPort:          portNum,
-		Protocol:         port.ProtocolTCP,
-		ForwardingStatus: port.ForwardingStatusEnabled,
-	}, nil
+	if !n.enabled {
+		return n.withStatuses(rule), ErrDisabled
+	}
+
+	ports := rule.Ports()
+	if len(ports) == 0 {
+		return n.withStatuses(rule), fmt.Errorf("%s has no port assigned", ruleName(rule))
+	}
+
+	var errs []error
+	for _, protocol := range rule.Protocol.Protocols() {
+		for _, portNum := range ports {
+			forward := &forwarding{
+				mapping: Mapping{
+					ExternalPort: portNum,
+					InternalPort: portNum,
+					Protocol:     protocol,
+					Description:  "quiver " + ruleName(rule),
+					Lease:        mappingLease,
+				},
+			}
+
+			n.mu.Lock()
+			n.forwarded[keyOf(forward.mapping)] = forward
+			n.mu.Unlock()
+
+			if err := n.renew(ctx, forward); err != nil {
+				errs = append(errs, fmt.Errorf("failed to forward %d/%s: %w", portNum, protocol, err))
+			}
+		}
+	}
+
+	return n.withStatuses(rule), errors.Join(errs...)
 }
 
 func (n *NetbridgeImpl) ForwardPorts(
 	ctx context.Context,
-	ports []int,
+	rules []port.PortRule,
 ) ([]port.PortRule, error) {
-	return []port.PortRule{}, nil
+	forwarded := []port.PortRule{}
+	var errs []error
+
+	for _, rule := range rules {
+		result, err := n.ForwardPort(ctx, rule)
+		forwarded = append(forwarded, result)
+		errs = append(errs, err)
+	}
+
+	return forwarded, errors.Join(errs...)
 }
 
+// ReversePort removes the mappings ForwardPort made for rule from the
+// gateway and stops renewing them.
 func (n *NetbridgeImpl) ReversePort(
 	ctx context.Context,
-	portNum int,
+	rule port.PortRule,
 ) (port.PortRule, error) {
-	return port.PortRule{
-		StartPort:        portNum,
-		EndPort:          portNum,
-		Protocol:         port.ProtocolTCP,
-		ForwardingStatus: port.ForwardingStatusEnabled,
-	}, nil
+	var errs []error
+
+	for _, protocol := range rule.Protocol.Protocols() {
+		for _, portNum := range rule.Ports() {
+			key := mappingKey{port: portNum, protocol: protocol}
+			if err := n.unmap(ctx, key); err != nil {
+				errs = append(errs, fmt.Errorf("failed to reverse %d/%s: %w", portNum, protocol, err))
+			}
+		}
+	}
+
+	reversed := n.withStatuses(rule)
+	reversed.Reachability, reversed.Diagnosis = "", ""
+	if len(errs) > 0 {
+		reversed.ForwardingStatus = port.ForwardingStatusError
+	}
+
+	return reversed, errors.Join(errs...)
 }
 
 func (n *NetbridgeImpl) ReversePorts(
 	ctx context.Context,
-	ports []int,
+	rules []port.PortRule,
 ) ([]port.PortRule, error) {
-	return []port.PortRule{}, nil
+	reversed := []port.PortRule{}
+	var errs []error
+
+	for _, rule := range rules {
+		result, err := n.ReversePort(ctx, rule)
+		reversed = append(reversed, result)
+		errs = append(errs, err)
+	}
+
+	return reversed, errors.Join(errs...)
+}
+
+// unmap stops renewing a tracked mapping and deletes it from the
+// gateway. Ports that were never forwarded are left alone.
+func (n *NetbridgeImpl) unmap(ctx context.Context, key mappingKey) error {
+	n.mu.Lock()
+	forward, ok := n.forwarded[key]
+	delete(n.forwarded, key)
+	n.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	return n.apply(ctx, forward.mapping, Strategy.DeleteMapping)
 }
 
+// GetPortForwardingStatus reports the combined outcome of the last
+// forward or renewal of every mapping of rule, or disabled when it
+// is not forwarded.
 func (n *NetbridgeImpl) GetPortForwardingStatus(
 	ctx context.Context,
-	portNum int,
+	rule port.PortRule,
 ) (port.ForwardingStatus, error) {
-	return port.ForwardingStatusEnabled, nil
+	return n.withStatuses(rule).ForwardingStatus, nil
 }
 
+// GetPortForwardingStatuses returns the rules with their status per
+// protocol and combined.
 func (n *NetbridgeImpl) GetPortForwardingStatuses(
 	ctx context.Context,
-	ports []int,
-) ([]port.ForwardingStatus, error) {
-	return []port.ForwardingStatus{}, nil
+	rules []port.PortRule,
+) ([]port.PortRule, error) {
+	statuses := []port.PortRule{}
+
+	for _, rule := range rules {
+		statuses = append(statuses, n.withStatuses(rule))
+	}
+
+	return statuses, nil
+}
+
+// withStatuses fills in the statuses of rule from the tracked
+// mappings of its ports.
+func (n *NetbridgeImpl) withStatuses(rule port.PortRule) port.PortRule {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	rule.Statuses = []port.ProtocolStatus{}
+	var combined []port.ForwardingStatus
+
+	for _, protocol := range rule.Protocol.Protocols() {
+		var statuses []port.ForwardingStatus
+
+		for _, portNum := range rule.Ports() {
+			status := port.ForwardingStatusDisabled
+			if forward, ok := n.forwarded[mappingKey{port: portNum, protocol: protocol}]; ok {
+				status = forward.current()
+			}
+			statuses = append(statuses, status)
+		}
+
+		status := port.CombineStatuses(statuses...)
+		rule.Statuses = append(rule.Statuses, port.ProtocolStatus{Protocol: protocol, ForwardingStatus: status})
+		combined = append(combined, status)
+	}
+
+	rule.ForwardingStatus = port.CombineStatuses(combined...)
+	return rule
+}
+
+// mappingKey identifies a mapping by port and a single protocol.
+type mappingKey struct {
+	port     int
+	protocol port.Protocol
+}
+
+func keyOf(mapping Mapping) mappingKey {
+	return mappingKey{port: mapping.ExternalPort, protocol: mapping.Protocol}
+}
+
+func ruleName(rule port.PortRule) string {
+	if rule.Name != "" {
+		return rule.Name
+	}
+
+	return fmt.Sprintf("port %d", rule.StartPort)
 }
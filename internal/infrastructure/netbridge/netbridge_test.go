@@ -2,11 +2,65 @@ package netbridge
 
 import (
 	"context"
+	"errors"
+	"slices"
+	"strings"
 	"testing"
 
+	"github.com/rabbytesoftware/quiver/internal/core/config"
 	"github.com/rabbytesoftware/quiver/internal/models/port"
 )
 
+// fakeStrategy records mappings in memory.
+type fakeStrategy struct {
+	name        string
+	discoverErr error
+	addErr      error
+	externalIP  string
+	discoveries int
+	mappings    map[mappingKey]Mapping
+}
+
+func newFakeStrategy() *fakeStrategy {
+	return &fakeStrategy{name: "fake", externalIP: "203.0.113.7", mappings: map[mappingKey]Mapping{}}
+}
+
+func (f *fakeStrategy) Name() string {
+	return f.name
+}
+
+func (f *fakeStrategy) Discover(ctx context.Context) error {
+	f.discoveries++
+	return f.discoverErr
+}
+
+func (f *fakeStrategy) ExternalIP(ctx context.Context) (string, error) {
+	return f.externalIP, nil
+}
+
+func (f *fakeStrategy) AddMapping(ctx context.Context, mapping Mapping) error {
+	if f.addErr != nil {
+		return f.addErr
+	}
+
+	f.mappings[keyOf(mapping)] = mapping
+	return nil
+}
+
+func (f *fakeStrategy) DeleteMapping(ctx context.Context, mapping Mapping) error {
+	delete(f.mappings, keyOf(mapping))
+	return nil
+}
+
+// tcpRule is a single TCP port.
+func tcpRule(portNum int) port.PortRule {
+	return port.PortRule{Name: "GAME_PORT", StartPort: portNum, EndPort: portNum, Protocol: port.ProtocolTCP}
+}
+
+func tcpKey(portNum int) mappingKey {
+	return mappingKey{port: portNum, protocol: port.ProtocolTCP}
+}
+
 func TestNewNetbridge(t *testing.T) {
 	nb := NewNetbridge()
 	if nb == nil {
@@ -15,208 +69,288 @@ func TestNewNetbridge(t *testing.T) {
 }
 
 func TestNetbridgeImpl_IsEnabled(t *testing.T) {
-	nb := NewNetbridge()
-
-	enabled := nb.IsEnabled()
-	if !enabled {
-		t.Error("IsEnabled() should return true")
+	if !NewNetbridgeWith(true, newFakeStrategy()).IsEnabled() {
+		t.Error("IsEnabled() should follow the configuration")
+	}
+	if NewNetbridgeWith(false, newFakeStrategy()).IsEnabled() {
+		t.Error("IsEnabled() should be false when disabled in the configuration")
 	}
 }
 
 func TestNetbridgeImpl_IsAvailable(t *testing.T) {
-	nb := NewNetbridge()
+	strategy := newFakeStrategy()
+	nb := NewNetbridgeWith(true, strategy)
+
+	if !nb.IsAvailable() || !nb.IsAvailable() {
+		t.Error("IsAvailable() should be true once a gateway is found")
+	}
+	if strategy.discoveries != 1 {
+		t.Errorf("Expected discovery to run once, got %d", strategy.discoveries)
+	}
+
+	missing := newFakeStrategy()
+	missing.discoverErr = ErrNoGateway
+	if NewNetbridgeWith(true, missing).IsAvailable() {
+		t.Error("IsAvailable() should be false without a gateway")
+	}
 
-	available := nb.IsAvailable()
-	if !available {
-		t.Error("IsAvailable() should return true")
+	if NewNetbridgeWith(false, newFakeStrategy()).IsAvailable() {
+		t.Error("IsAvailable() should be false when disabled")
 	}
 }
 
 func TestNetbridgeImpl_PublicIP(t *testing.T) {
-	nb := NewNetbridge()
-	ctx := context.Background()
-
-	ip, err := nb.PublicIP(ctx)
-	if err != nil {
-		t.Errorf("PublicIP() returned error: %v", err)
+	ip, err := NewNetbridgeWith(true, newFakeStrategy()).PublicIP(context.Background())
+	if err != nil || ip != "203.0.113.7" {
+		t.Errorf("PublicIP() = %q, %v, expected the gateway's external IP", ip, err)
 	}
-	if ip != "" {
-		t.Error("PublicIP() should return empty string for unimplemented method")
+
+	missing := newFakeStrategy()
+	missing.discoverErr = ErrNoGateway
+	if _, err := NewNetbridgeWith(true, missing).PublicIP(context.Background()); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("PublicIP() should fail without a gateway, got %v", err)
 	}
 }
 
-func TestNetbridgeImpl_LocalIP(t *testing.T) {
-	nb := NewNetbridge()
+func TestNetbridgeImpl_ForwardPort(t *testing.T) {
+	strategy := newFakeStrategy()
+	nb := NewNetbridgeWith(true, strategy)
 	ctx := context.Background()
 
-	ip, err := nb.LocalIP(ctx)
+	rule, err := nb.ForwardPort(ctx, tcpRule(8080))
 	if err != nil {
-		t.Errorf("LocalIP() returned error: %v", err)
+		t.Fatalf("ForwardPort() returned error: %v", err)
 	}
-	if ip != "" {
-		t.Error("LocalIP() should return empty string for unimplemented method")
+
+	if rule.StartPort != 8080 || rule.EndPort != 8080 || rule.Name != "GAME_PORT" {
+		t.Errorf("ForwardPort() should return the given rule, got %+v", rule)
+	}
+	if rule.ForwardingStatus != port.ForwardingStatusEnabled {
+		t.Errorf("ForwardPort() returned wrong ForwardingStatus: got %v", rule.ForwardingStatus)
+	}
+	if len(rule.Statuses) != 1 || rule.Statuses[0].Protocol != port.ProtocolTCP {
+		t.Errorf("Expected a single TCP status, got %+v", rule.Statuses)
+	}
+
+	mapping, ok := strategy.mappings[tcpKey(8080)]
+	if !ok || mapping.InternalPort != 8080 || mapping.Description != "quiver GAME_PORT" {
+		t.Errorf("Expected the gateway to map 8080, got %+v", strategy.mappings)
+	}
+
+	status, _ := nb.GetPortForwardingStatus(ctx, tcpRule(8080))
+	if !status.IsEnabled() {
+		t.Errorf("Expected 8080 to be reported as forwarded, got %s", status)
 	}
 }
 
-func TestNetbridgeImpl_IsPortAvailable(t *testing.T) {
-	nb := NewNetbridge()
+func TestNetbridgeImpl_ForwardPort_RangeAndProtocols(t *testing.T) {
+	strategy := newFakeStrategy()
+	nb := NewNetbridgeWith(true, strategy)
 	ctx := context.Background()
 
-	available, err := nb.IsPortAvailable(ctx, 8080)
+	rule := port.PortRule{Name: "SOURCE_TV", StartPort: 27015, EndPort: 27017, Protocol: port.ProtocolTCPUDP}
+
+	forwarded, err := nb.ForwardPort(ctx, rule)
 	if err != nil {
-		t.Errorf("IsPortAvailable() returned error: %v", err)
+		t.Fatalf("ForwardPort() returned error: %v", err)
 	}
-	if !available {
-		t.Error("IsPortAvailable() should return true for unimplemented method")
+	if len(strategy.mappings) != 6 {
+		t.Errorf("Expected every port of the range over TCP and UDP, got %v", strategy.mappings)
+	}
+	if _, ok := strategy.mappings[mappingKey{port: 27016, protocol: port.ProtocolUDP}]; !ok {
+		t.Error("Expected 27016/udp to be mapped")
+	}
+	if !forwarded.ForwardingStatus.IsEnabled() || len(forwarded.Statuses) != 2 {
+		t.Errorf("Expected both protocols to be enabled, got %+v", forwarded)
 	}
-}
 
-func TestNetbridgeImpl_ArePortsAvailable(t *testing.T) {
-	nb := NewNetbridge()
-	ctx := context.Background()
+	// ? The gateway lost the UDP side of a single port.
+	nb.mu.Lock()
+	nb.forwarded[mappingKey{port: 27017, protocol: port.ProtocolUDP}].status = port.ForwardingStatusError
+	nb.mu.Unlock()
 
-	ports := []int{8080, 8081, 8082}
-	available, err := nb.ArePortsAvailable(ctx, ports)
-	if err != nil {
-		t.Errorf("ArePortsAvailable() returned error: %v", err)
+	statuses, _ := nb.GetPortForwardingStatuses(ctx, []port.PortRule{rule})
+	expected := []port.ProtocolStatus{
+		{Protocol: port.ProtocolTCP, ForwardingStatus: port.ForwardingStatusEnabled},
+		{Protocol: port.ProtocolUDP, ForwardingStatus: port.ForwardingStatusError},
 	}
-	if !available {
-		t.Error("ArePortsAvailable() should return true for unimplemented method")
+	if !statuses[0].ForwardingStatus.IsError() || !slices.Equal(statuses[0].Statuses, expected) {
+		t.Errorf("Expected the UDP side to report the error, got %+v", statuses[0])
+	}
+
+	if _, err := nb.ReversePort(ctx, rule); err != nil {
+		t.Fatalf("ReversePort() returned error: %v", err)
+	}
+	if len(strategy.mappings) != 0 {
+		t.Errorf("Expected every mapping of the rule to be removed, got %v", strategy.mappings)
 	}
 }
 
-func TestNetbridgeImpl_ForwardPort(t *testing.T) {
-	nb := NewNetbridge()
+func TestNetbridgeImpl_ForwardPort_Failures(t *testing.T) {
 	ctx := context.Background()
 
-	rule, err := nb.ForwardPort(ctx, 8080)
-	if err != nil {
-		t.Errorf("ForwardPort() returned error: %v", err)
+	rule, err := NewNetbridgeWith(false, newFakeStrategy()).ForwardPort(ctx, tcpRule(8080))
+	if !errors.Is(err, ErrDisabled) || !rule.ForwardingStatus.IsDisabled() {
+		t.Errorf("Expected a disabled rule, got %s and %v", rule.ForwardingStatus, err)
 	}
 
-	// Check that the returned rule has expected values
-	if rule.StartPort != 8080 {
-		t.Errorf("ForwardPort() returned wrong StartPort: got %d, want %d", rule.StartPort, 8080)
+	if _, err := NewNetbridgeWith(true, newFakeStrategy()).ForwardPort(ctx, port.PortRule{Name: "GAME_PORT"}); err == nil {
+		t.Error("ForwardPort() should fail for a rule without a port")
 	}
-	if rule.EndPort != 8080 {
-		t.Errorf("ForwardPort() returned wrong EndPort: got %d, want %d", rule.EndPort, 8080)
+
+	missing := newFakeStrategy()
+	missing.discoverErr = ErrNoGateway
+	rule, err = NewNetbridgeWith(true, missing).ForwardPort(ctx, tcpRule(8080))
+	if !errors.Is(err, ErrUnavailable) || !rule.ForwardingStatus.IsError() {
+		t.Errorf("Expected an error rule without a gateway, got %s and %v", rule.ForwardingStatus, err)
 	}
-	if rule.Protocol != port.ProtocolTCP {
-		t.Errorf("ForwardPort() returned wrong Protocol: got %v, want %v", rule.Protocol, port.ProtocolTCP)
+
+	refusing := newFakeStrategy()
+	refusing.addErr = errors.New("ConflictInMappingEntry")
+	nb := NewNetbridgeWith(true, refusing)
+	if _, err := nb.ForwardPort(ctx, tcpRule(8080)); err == nil {
+		t.Error("ForwardPort() should fail when the gateway refuses the mapping")
 	}
-	if rule.ForwardingStatus != port.ForwardingStatusEnabled {
-		t.Errorf("ForwardPort() returned wrong ForwardingStatus: got %v, want %v", rule.ForwardingStatus, port.ForwardingStatusEnabled)
+	if status, _ := nb.GetPortForwardingStatus(ctx, tcpRule(8080)); !status.IsError() {
+		t.Errorf("Expected the refused port to be reported as an error, got %s", status)
 	}
 }
 
 func TestNetbridgeImpl_ForwardPorts(t *testing.T) {
-	nb := NewNetbridge()
-	ctx := context.Background()
+	strategy := newFakeStrategy()
+	nb := NewNetbridgeWith(true, strategy)
 
-	ports := []int{8080, 8081, 8082}
-	rules, err := nb.ForwardPorts(ctx, ports)
+	rules, err := nb.ForwardPorts(context.Background(), []port.PortRule{tcpRule(8080), tcpRule(8081), tcpRule(8082)})
 	if err != nil {
 		t.Errorf("ForwardPorts() returned error: %v", err)
 	}
-	if rules == nil {
-		t.Error("ForwardPorts() should return empty slice, not nil")
-	}
-	if len(rules) != 0 {
-		t.Error("ForwardPorts() should return empty slice for unimplemented method")
+	if len(rules) != 3 || len(strategy.mappings) != 3 {
+		t.Errorf("Expected 3 forwarded ports, got %d rules and %d mappings", len(rules), len(strategy.mappings))
 	}
 }
 
 func TestNetbridgeImpl_ReversePort(t *testing.T) {
-	nb := NewNetbridge()
+	strategy := newFakeStrategy()
+	nb := NewNetbridgeWith(true, strategy)
 	ctx := context.Background()
 
-	rule, err := nb.ReversePort(ctx, 8080)
-	if err != nil {
-		t.Errorf("ReversePort() returned error: %v", err)
-	}
+	nb.ForwardPort(ctx, tcpRule(8080))
 
-	// Check that the returned rule has expected values
-	if rule.StartPort != 8080 {
-		t.Errorf("ReversePort() returned wrong StartPort: got %d, want %d", rule.StartPort, 8080)
+	rule, err := nb.ReversePort(ctx, tcpRule(8080))
+	if err != nil {
+		t.Fatalf("ReversePort() returned error: %v", err)
 	}
-	if rule.EndPort != 8080 {
-		t.Errorf("ReversePort() returned wrong EndPort: got %d, want %d", rule.EndPort, 8080)
+	if rule.StartPort != 8080 || !rule.ForwardingStatus.IsDisabled() {
+		t.Errorf("Expected a disabled rule for 8080, got %+v", rule)
 	}
-	if rule.Protocol != port.ProtocolTCP {
-		t.Errorf("ReversePort() returned wrong Protocol: got %v, want %v", rule.Protocol, port.ProtocolTCP)
+	if _, ok := strategy.mappings[tcpKey(8080)]; ok {
+		t.Error("Expected the mapping to be removed from the gateway")
 	}
-	if rule.ForwardingStatus != port.ForwardingStatusEnabled {
-		t.Errorf("ReversePort() returned wrong ForwardingStatus: got %v, want %v", rule.ForwardingStatus, port.ForwardingStatusEnabled)
+	if status, _ := nb.GetPortForwardingStatus(ctx, tcpRule(8080)); !status.IsDisabled() {
+		t.Errorf("Expected 8080 to be reported as disabled, got %s", status)
 	}
 }
 
 func TestNetbridgeImpl_ReversePorts(t *testing.T) {
-	nb := NewNetbridge()
+	// ? Ports that were never forwarded are skipped, so no gateway is needed.
+	nb := NewNetbridgeWith(true)
+
+	rules, err := nb.ReversePorts(context.Background(), []port.PortRule{tcpRule(8080), tcpRule(8081)})
+	if err != nil || len(rules) != 2 {
+		t.Errorf("ReversePorts() = %v, %v", rules, err)
+	}
+}
+
+func TestNetbridgeImpl_GetPortForwardingStatuses(t *testing.T) {
+	nb := NewNetbridgeWith(true, newFakeStrategy())
 	ctx := context.Background()
 
-	ports := []int{8080, 8081, 8082}
-	rules, err := nb.ReversePorts(ctx, ports)
+	nb.ForwardPort(ctx, tcpRule(8080))
+
+	statuses, err := nb.GetPortForwardingStatuses(ctx, []port.PortRule{tcpRule(8080), tcpRule(8081)})
 	if err != nil {
-		t.Errorf("ReversePorts() returned error: %v", err)
-	}
-	if rules == nil {
-		t.Error("ReversePorts() should return empty slice, not nil")
+		t.Fatalf("GetPortForwardingStatuses() returned error: %v", err)
 	}
-	if len(rules) != 0 {
-		t.Error("ReversePorts() should return empty slice for unimplemented method")
+	if len(statuses) != 2 || !statuses[0].ForwardingStatus.IsEnabled() || !statuses[1].ForwardingStatus.IsDisabled() {
+		t.Errorf("Expected 8080 enabled and 8081 disabled, got %v", statuses)
 	}
 }
 
-func TestNetbridgeImpl_GetPortForwardingStatus(t *testing.T) {
-	nb := NewNetbridge()
-	ctx := context.Background()
+func TestNetbridgeImpl_Probe(t *testing.T) {
+	upnp, pcp, natpmp := newFakeStrategy(), newFakeStrategy(), newFakeStrategy()
+	upnp.name, pcp.name, natpmp.name = "upnp", "pcp", "natpmp"
+	upnp.discoverErr = ErrNoGateway
 
-	status, err := nb.GetPortForwardingStatus(ctx, 8080)
-	if err != nil {
-		t.Errorf("GetPortForwardingStatus() returned error: %v", err)
+	nb := NewNetbridgeWith(true, upnp, pcp, natpmp)
+	if err := nb.Probe(context.Background()); err != nil {
+		t.Fatalf("Probe() returned error: %v", err)
+	}
+
+	status := nb.Status(context.Background())
+	if !status.Enabled || !status.Available || status.Active != "pcp" {
+		t.Errorf("Expected PCP to be active after UPnP failed, got %+v", status)
+	}
+	if len(status.Strategies) != 3 {
+		t.Fatalf("Expected every strategy to be reported, got %+v", status.Strategies)
+	}
+	if s, _ := status.Strategy("upnp"); s.Available || s.Error == "" {
+		t.Errorf("Expected UPnP to report why it is unavailable, got %+v", s)
 	}
-	if status != port.ForwardingStatusEnabled {
-		t.Errorf("GetPortForwardingStatus() returned wrong status: got %v, want %v", status, port.ForwardingStatusEnabled)
+	if s, _ := status.Strategy("natpmp"); !s.Available {
+		t.Errorf("Expected NAT-PMP to be available as a fallback, got %+v", s)
+	}
+	if upnp.discoveries != 1 || natpmp.discoveries != 1 {
+		t.Error("Expected Status() to reuse the probe")
+	}
+
+	if err := NewNetbridgeWith(false, pcp).Probe(context.Background()); !errors.Is(err, ErrDisabled) {
+		t.Errorf("Expected a disabled netbridge not to probe, got %v", err)
 	}
 }
 
-func TestNetbridgeImpl_GetPortForwardingStatuses(t *testing.T) {
-	nb := NewNetbridge()
+func TestNetbridgeImpl_Fallback(t *testing.T) {
+	pcp, natpmp := newFakeStrategy(), newFakeStrategy()
+	pcp.name, natpmp.name = "pcp", "natpmp"
+	pcp.addErr = errors.New("PCP error 8 no resources")
+
+	nb := NewNetbridgeWith(true, pcp, natpmp)
 	ctx := context.Background()
 
-	ports := []int{8080, 8081, 8082}
-	statuses, err := nb.GetPortForwardingStatuses(ctx, ports)
-	if err != nil {
-		t.Errorf("GetPortForwardingStatuses() returned error: %v", err)
+	rule, err := nb.ForwardPort(ctx, tcpRule(8080))
+	if err != nil || !rule.ForwardingStatus.IsEnabled() {
+		t.Fatalf("Expected the port to be forwarded through NAT-PMP, got %s and %v", rule.ForwardingStatus, err)
 	}
-	if statuses == nil {
-		t.Error("GetPortForwardingStatuses() should return empty slice, not nil")
+	if _, ok := natpmp.mappings[tcpKey(8080)]; !ok {
+		t.Error("Expected NAT-PMP to hold the mapping")
 	}
-	if len(statuses) != 0 {
-		t.Error("GetPortForwardingStatuses() should return empty slice for unimplemented method")
+	if nb.Status(ctx).Active != "natpmp" {
+		t.Errorf("Expected NAT-PMP to become the active strategy, got %q", nb.Status(ctx).Active)
 	}
-}
 
-func TestNetbridgeImpl_InterfaceCompliance(t *testing.T) {
-	// Test that NetbridgeImpl implements NetbridgeInterface
-	var _ NetbridgeInterface = &NetbridgeImpl{}
+	natpmp.addErr = errors.New("NAT-PMP error 3 network failure")
+	if _, err := nb.ForwardPort(ctx, tcpRule(8081)); err == nil || !strings.Contains(err.Error(), "natpmp") {
+		t.Errorf("Expected the error of the active strategy when all fail, got %v", err)
+	}
 }
 
-func TestNetbridgeImpl_MultipleInstances(t *testing.T) {
-	nb1 := NewNetbridge()
-	nb2 := NewNetbridge()
+func TestNetbridgeImpl_Status_Unavailable(t *testing.T) {
+	missing := newFakeStrategy()
+	missing.discoverErr = ErrNoGateway
 
-	// Both should be valid
-	if nb1 == nil || nb2 == nil {
-		t.Error("NewNetbridge() returned nil instance")
+	status := NewNetbridgeWith(true, missing).Status(context.Background())
+	if status.Available || status.Active != "" || status.Strategies[0].Error == "" {
+		t.Errorf("Expected no active strategy, got %+v", status)
 	}
 
-	// Test that both instances work correctly
-	if nb1.IsEnabled() != nb2.IsEnabled() {
-		t.Error("Both instances should have same IsEnabled behavior")
+	if strategies := strategiesFor(config.Netbridge{Strategies: []string{"natpmp", "unknown", "upnp"}}); len(strategies) != 2 ||
+		strategies[0].Name() != "natpmp" || strategies[1].Name() != "upnp" {
+		t.Errorf("Expected known strategies in the configured order, got %v", strategies)
 	}
+}
 
-	if nb1.IsAvailable() != nb2.IsAvailable() {
-		t.Error("Both instances should have same IsAvailable behavior")
-	}
+func TestNetbridgeImpl_InterfaceCompliance(t *testing.T) {
+	var _ NetbridgeInterface = &NetbridgeImpl{}
+	var _ Strategy = &UPnP{}
+	var _ Strategy = &NATPMP{}
+	var _ Strategy = &PCP{}
 }
@@ -0,0 +1,272 @@
+package netbridge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/rabbytesoftware/quiver/internal/core/watcher"
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+)
+
+// nftTable is the table Quiver owns; nothing outside it is touched.
+const nftTable = "quiver"
+
+// nftSetup replaces the table with an empty one in a single
+// transaction, so ports left open by a previous run are closed.
+// Ports are accepted through the tcp_ports and udp_ports sets.
+var nftSetup = `add table inet quiver
+delete table inet quiver
+table inet quiver {
+	set tcp_ports {
+		type inet_service
+	}
+	set udp_ports {
+		type inet_service
+	}
+	chain input {
+		type filter hook input priority -10; policy accept;
+		tcp dport @tcp_ports accept
+		udp dport @udp_ports accept
+	}
+}
+`
+
+// NFTables accepts forwarded ports in a dedicated nftables table.
+// An accept there does not override a drop in another table on the
+// same hook, so ports stay closed on hosts whose firewall (ufw,
+// firewalld, iptables-nft) drops them elsewhere; Blockers reports
+// those chains. In dry-run mode the rules are printed instead of
+// applied.
+type NFTables struct {
+	dryRun bool
+	output io.Writer
+	run    func(ctx context.Context, script string) error
+	list   func(ctx context.Context) ([]byte, error)
+
+	mu    sync.Mutex
+	ready bool
+}
+
+func NewNFTables(dryRun bool) *NFTables {
+	return &NFTables{dryRun: dryRun, output: os.Stdout, run: runNFT, list: listNFT}
+}
+
+func (n *NFTables) Name() string {
+	return "nftables"
+}
+
+// Discover sets up the quiver table, dropping every port it opened
+// before.
+func (n *NFTables) Discover(ctx context.Context) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if err := n.apply(ctx, nftSetup); err != nil {
+		return fmt.Errorf("failed to set up the %s table: %w", nftTable, err)
+	}
+	n.ready = true
+
+	return nil
+}
+
+// ExternalIP is not known to the host firewall.
+func (n *NFTables) ExternalIP(ctx context.Context) (string, error) {
+	return "", errors.New("nftables does not know the public address")
+}
+
+func (n *NFTables) AddMapping(ctx context.Context, mapping Mapping) error {
+	n.mu.Lock()
+	ready := n.ready
+	n.mu.Unlock()
+
+	if !ready {
+		if err := n.Discover(ctx); err != nil {
+			return err
+		}
+	}
+
+	return n.apply(ctx, nftElement("add", mapping))
+}
+
+func (n *NFTables) DeleteMapping(ctx context.Context, mapping Mapping) error {
+	n.mu.Lock()
+	ready := n.ready
+	n.mu.Unlock()
+
+	// ? Nothing was opened before the table was set up.
+	if !ready {
+		return nil
+	}
+
+	err := n.apply(ctx, nftElement("delete", mapping))
+	if err != nil && strings.Contains(err.Error(), "No such file or directory") {
+		return nil
+	}
+
+	return err
+}
+
+func (n *NFTables) apply(ctx context.Context, script string) error {
+	if !n.dryRun {
+		return n.run(ctx, script)
+	}
+
+	if watcher.GetWatcher() != nil {
+		watcher.Info(fmt.Sprintf("nftables dry run:\n%s", script))
+		return nil
+	}
+
+	_, err := fmt.Fprint(n.output, script)
+	return err
+}
+
+// Blockers lists the input chains of other tables that drop or
+// reject packets, which the quiver table cannot override. A dry run
+// does not inspect the host.
+func (n *NFTables) Blockers(ctx context.Context) []string {
+	if n.dryRun {
+		return nil
+	}
+
+	ruleset, err := n.list(ctx)
+	if err != nil {
+		return []string{fmt.Sprintf("failed to inspect the other nftables tables: %s", err)}
+	}
+
+	blockers, err := nftBlockers(ruleset)
+	if err != nil {
+		return []string{fmt.Sprintf("failed to inspect the other nftables tables: %s", err)}
+	}
+
+	return blockers
+}
+
+// nftRuleset is the part of `nft -j list ruleset` Blockers reads.
+type nftRuleset struct {
+	Nftables []struct {
+		Chain *struct {
+			Family string `json:"family"`
+			Table  string `json:"table"`
+			Name   string `json:"name"`
+			Hook   string `json:"hook"`
+			Policy string `json:"policy"`
+		} `json:"chain"`
+		Rule *struct {
+			Family string                       `json:"family"`
+			Table  string                       `json:"table"`
+			Chain  string                       `json:"chain"`
+			Expr   []map[string]json.RawMessage `json:"expr"`
+		} `json:"rule"`
+	} `json:"nftables"`
+}
+
+func nftBlockers(data []byte) ([]string, error) {
+	var ruleset nftRuleset
+	if err := json.Unmarshal(data, &ruleset); err != nil {
+		return nil, err
+	}
+
+	// ? Only base chains on the input hook see the forwarded
+	// ? traffic; regular chains are reached through them.
+	input := map[string]bool{}
+	var blockers []string
+
+	for _, object := range ruleset.Nftables {
+		chain := object.Chain
+		if chain == nil || chain.Hook != "input" || isQuiverTable(chain.Family, chain.Table) {
+			continue
+		}
+
+		name := fmt.Sprintf("%s %s %s", chain.Family, chain.Table, chain.Name)
+		input[name] = true
+
+		if chain.Policy == "drop" {
+			blockers = append(blockers, fmt.Sprintf(
+				"chain %s drops by default, so ports opened in the %s table are still dropped there",
+				name, nftTable,
+			))
+		}
+	}
+
+	reported := map[string]bool{}
+	for _, object := range ruleset.Nftables {
+		rule := object.Rule
+		if rule == nil {
+			continue
+		}
+
+		name := fmt.Sprintf("%s %s %s", rule.Family, rule.Table, rule.Chain)
+		if !input[name] || reported[name] || !dropsPackets(rule.Expr) {
+			continue
+		}
+
+		reported[name] = true
+		blockers = append(blockers, fmt.Sprintf(
+			"chain %s drops or rejects packets, which the %s table cannot override",
+			name, nftTable,
+		))
+	}
+
+	return blockers, nil
+}
+
+func isQuiverTable(family, table string) bool {
+	return family == "inet" && table == nftTable
+}
+
+func dropsPackets(expr []map[string]json.RawMessage) bool {
+	for _, statement := range expr {
+		if _, ok := statement["drop"]; ok {
+			return true
+		}
+		if _, ok := statement["reject"]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nftElement adds or deletes the internal port of mapping in the
+// set of its protocol.
+func nftElement(verb string, mapping Mapping) string {
+	set := "tcp_ports"
+	if mapping.Protocol == port.ProtocolUDP {
+		set = "udp_ports"
+	}
+
+	return fmt.Sprintf("%s element inet %s %s { %d }\n", verb, nftTable, set, mapping.InternalPort)
+}
+
+// listNFT returns the ruleset of the host as JSON.
+func listNFT(ctx context.Context) ([]byte, error) {
+	output, err := exec.CommandContext(ctx, "nft", "-j", "list", "ruleset").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+// runNFT feeds script to nft, which applies it atomically.
+func runNFT(ctx context.Context, script string) error {
+	cmd := exec.CommandContext(ctx, "nft", "-f", "-")
+	cmd.Stdin = strings.NewReader(script)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if message := strings.TrimSpace(string(output)); message != "" {
+			return fmt.Errorf("%w: %s", err, message)
+		}
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,223 @@
+package netbridge
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+)
+
+// recordedNFT returns nftables that records the scripts it would
+// feed to nft, failing with err.
+func recordedNFT(err error) (*NFTables, *[]string) {
+	var scripts []string
+
+	nft := NewNFTables(false)
+	nft.run = func(ctx context.Context, script string) error {
+		scripts = append(scripts, script)
+		return err
+	}
+
+	return nft, &scripts
+}
+
+func TestNFTables_Mappings(t *testing.T) {
+	nft, scripts := recordedNFT(nil)
+	ctx := context.Background()
+	mapping := Mapping{ExternalPort: 27015, InternalPort: 27015, Protocol: port.ProtocolUDP}
+
+	if err := nft.DeleteMapping(ctx, mapping); err != nil || len(*scripts) != 0 {
+		t.Errorf("Expected nothing to delete before the table is set up, got %v and %v", *scripts, err)
+	}
+
+	if err := nft.AddMapping(ctx, mapping); err != nil {
+		t.Fatalf("AddMapping() returned error: %v", err)
+	}
+	if len(*scripts) != 2 || (*scripts)[0] != nftSetup {
+		t.Fatalf("Expected the table to be set up before the first port, got %v", *scripts)
+	}
+	if (*scripts)[1] != "add element inet quiver udp_ports { 27015 }\n" {
+		t.Errorf("Expected the port to be added to the UDP set, got %q", (*scripts)[1])
+	}
+
+	mapping.Protocol = port.ProtocolTCP
+	if err := nft.DeleteMapping(ctx, mapping); err != nil {
+		t.Fatalf("DeleteMapping() returned error: %v", err)
+	}
+	if (*scripts)[2] != "delete element inet quiver tcp_ports { 27015 }\n" {
+		t.Errorf("Expected the port to be removed from the TCP set, got %q", (*scripts)[2])
+	}
+
+	if _, err := nft.ExternalIP(ctx); err == nil {
+		t.Error("ExternalIP() should fail, the firewall does not know the public address")
+	}
+}
+
+func TestNFTables_Errors(t *testing.T) {
+	ctx := context.Background()
+	mapping := Mapping{ExternalPort: 27015, InternalPort: 27015, Protocol: port.ProtocolTCP}
+
+	missing, _ := recordedNFT(errors.New("exit status 1: Error: Could not process rule: No such file or directory"))
+	missing.ready = true
+	if err := missing.DeleteMapping(ctx, mapping); err != nil {
+		t.Errorf("Expected a port that is already closed to be ignored, got %v", err)
+	}
+
+	denied, _ := recordedNFT(errors.New("exit status 1: Error: Operation not permitted"))
+	if err := denied.AddMapping(ctx, mapping); err == nil || !strings.Contains(err.Error(), "Operation not permitted") {
+		t.Errorf("Expected the nft error to be returned, got %v", err)
+	}
+}
+
+func TestNFTables_DryRun(t *testing.T) {
+	var output bytes.Buffer
+
+	nft := NewNFTables(true)
+	nft.output = &output
+	nft.run = func(ctx context.Context, script string) error {
+		t.Errorf("Expected a dry run not to apply %q", script)
+		return nil
+	}
+
+	mapping := Mapping{ExternalPort: 40128, InternalPort: 40128, Protocol: port.ProtocolTCP}
+	if err := nft.AddMapping(context.Background(), mapping); err != nil {
+		t.Fatalf("AddMapping() returned error: %v", err)
+	}
+
+	printed := output.String()
+	if !strings.Contains(printed, "table inet quiver {") ||
+		!strings.Contains(printed, "add element inet quiver tcp_ports { 40128 }") {
+		t.Errorf("Expected the rules to be printed, got %q", printed)
+	}
+}
+
+func TestNetbridgeImpl_Firewall(t *testing.T) {
+	ctx := context.Background()
+
+	// ? A server with a public address has no gateway to map on,
+	// ? so only the firewall is used.
+	firewall := newFakeStrategy()
+	firewall.name = "nftables"
+	nb := NewNetbridgeWith(true)
+	nb.UseFirewall(firewall)
+
+	rule, err := nb.ForwardPort(ctx, tcpRule(40128))
+	if err != nil || !rule.ForwardingStatus.IsEnabled() {
+		t.Fatalf("Expected the firewall alone to open the port, got %s and %v", rule.ForwardingStatus, err)
+	}
+	if _, ok := firewall.mappings[tcpKey(40128)]; !ok {
+		t.Errorf("Expected the firewall to hold the port, got %v", firewall.mappings)
+	}
+
+	nb.ReversePort(ctx, tcpRule(40128))
+	if len(firewall.mappings) != 0 {
+		t.Errorf("Expected the firewall to close the port, got %v", firewall.mappings)
+	}
+
+	// ? With a gateway, a firewall failure still fails the forward.
+	gateway := newFakeStrategy()
+	blocked := newFakeStrategy()
+	blocked.name = "nftables"
+	blocked.addErr = errors.New("Operation not permitted")
+	nb = NewNetbridgeWith(true, gateway)
+	nb.UseFirewall(blocked)
+
+	rule, err = nb.ForwardPort(ctx, tcpRule(40128))
+	if err == nil || !strings.Contains(err.Error(), "nftables") || !rule.ForwardingStatus.IsError() {
+		t.Errorf("Expected the firewall error, got %s and %v", rule.ForwardingStatus, err)
+	}
+	if _, ok := gateway.mappings[tcpKey(40128)]; !ok {
+		t.Error("Expected the gateway mapping to be made regardless")
+	}
+
+	status := nb.Status(ctx)
+	if status.Firewall == nil || status.Firewall.Name != "nftables" || status.Firewall.Available || status.Firewall.Error == "" {
+		t.Errorf("Expected the firewall failure to be reported, got %+v", status.Firewall)
+	}
+	if status := NewNetbridgeWith(true, gateway).Status(ctx); status.Firewall != nil {
+		t.Errorf("Expected no firewall status without a firewall, got %+v", status.Firewall)
+	}
+}
+
+// hostRuleset is what ufw and firewalld leave in nftables, next to
+// the quiver table.
+const hostRuleset = `{"nftables": [
+	{"metainfo": {"version": "1.0.9", "json_schema_version": 1}},
+	{"table": {"family": "ip", "name": "filter", "handle": 1}},
+	{"chain": {"family": "ip", "table": "filter", "name": "INPUT", "handle": 1, "type": "filter", "hook": "input", "prio": 0, "policy": "drop"}},
+	{"chain": {"family": "ip", "table": "filter", "name": "ufw-user-input", "handle": 2}},
+	{"rule": {"family": "ip", "table": "filter", "chain": "ufw-user-input", "handle": 3, "expr": [{"drop": null}]}},
+	{"chain": {"family": "inet", "table": "firewalld", "name": "filter_INPUT", "handle": 4, "type": "filter", "hook": "input", "prio": 10, "policy": "accept"}},
+	{"rule": {"family": "inet", "table": "firewalld", "chain": "filter_INPUT", "handle": 5, "expr": [{"accept": null}]}},
+	{"rule": {"family": "inet", "table": "firewalld", "chain": "filter_INPUT", "handle": 6, "expr": [{"reject": {"type": "icmpx", "expr": "admin-prohibited"}}]}},
+	{"rule": {"family": "inet", "table": "firewalld", "chain": "filter_INPUT", "handle": 7, "expr": [{"drop": null}]}},
+	{"chain": {"family": "inet", "table": "firewalld", "name": "filter_OUTPUT", "handle": 8, "type": "filter", "hook": "output", "prio": 10, "policy": "drop"}},
+	{"chain": {"family": "inet", "table": "quiver", "name": "input", "handle": 9, "type": "filter", "hook": "input", "prio": -10, "policy": "accept"}},
+	{"rule": {"family": "inet", "table": "quiver", "chain": "input", "handle": 10, "expr": [{"drop": null}]}}
+]}`
+
+func TestNFTables_Blockers(t *testing.T) {
+	ctx := context.Background()
+
+	nft, _ := recordedNFT(nil)
+	nft.list = func(ctx context.Context) ([]byte, error) {
+		return []byte(hostRuleset), nil
+	}
+
+	blockers := nft.Blockers(ctx)
+	if len(blockers) != 2 {
+		t.Fatalf("Expected the ufw and firewalld input chains, got %v", blockers)
+	}
+	if !strings.Contains(blockers[0], "ip filter INPUT drops by default") {
+		t.Errorf("Expected the drop policy to be reported, got %q", blockers[0])
+	}
+	if !strings.Contains(blockers[1], "inet firewalld filter_INPUT drops or rejects") {
+		t.Errorf("Expected the reject rule to be reported once, got %q", blockers[1])
+	}
+
+	nft.list = func(ctx context.Context) ([]byte, error) {
+		return []byte(`{"nftables": []}`), nil
+	}
+	if blockers := nft.Blockers(ctx); len(blockers) != 0 {
+		t.Errorf("Expected nothing to block an empty ruleset, got %v", blockers)
+	}
+
+	nft.list = func(ctx context.Context) ([]byte, error) {
+		return nil, errors.New("Operation not permitted")
+	}
+	if blockers := nft.Blockers(ctx); len(blockers) != 1 || !strings.Contains(blockers[0], "Operation not permitted") {
+		t.Errorf("Expected the failed inspection to be reported, got %v", blockers)
+	}
+
+	dry := NewNFTables(true)
+	dry.list = func(ctx context.Context) ([]byte, error) {
+		t.Error("Expected a dry run not to inspect the host")
+		return nil, nil
+	}
+	if blockers := dry.Blockers(ctx); blockers != nil {
+		t.Errorf("Expected no blockers in a dry run, got %v", blockers)
+	}
+}
+
+func TestNetbridgeImpl_FirewallWarnings(t *testing.T) {
+	ctx := context.Background()
+
+	nft, _ := recordedNFT(nil)
+	nft.list = func(ctx context.Context) ([]byte, error) {
+		return []byte(hostRuleset), nil
+	}
+
+	nb := NewNetbridgeWith(true, newFakeStrategy())
+	nb.UseFirewall(nft)
+	if err := nb.Probe(ctx); err != nil {
+		t.Fatalf("Probe() returned error: %v", err)
+	}
+
+	status := nb.Status(ctx)
+	if status.Firewall == nil || !status.Firewall.Available || len(status.Firewall.Warnings) != 2 {
+		t.Errorf("Expected the firewall to be available with warnings, got %+v", status.Firewall)
+	}
+}
@@ -0,0 +1,245 @@
+package netbridge
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+)
+
+const (
+	pcpVersion = 2
+
+	pcpOpAnnounce = 0
+	pcpOpMap      = 1
+	pcpResponse   = 0x80
+
+	pcpHeaderSize = 24
+	pcpMapSize    = 36
+	pcpNonceSize  = 12
+
+	pcpProtocolTCP = 6
+	pcpProtocolUDP = 17
+
+	// pcpLease is asked for when a mapping does not set one, as a
+	// zero lifetime deletes the mapping.
+	pcpLease = 2 * time.Hour
+)
+
+var errNoExternalIP = errors.New("PCP reports the external IP only once a port is mapped")
+
+var pcpResults = map[byte]string{
+	1:  "unsupported version",
+	2:  "not authorized",
+	3:  "malformed request",
+	4:  "unsupported opcode",
+	5:  "unsupported option",
+	6:  "malformed option",
+	7:  "network failure",
+	8:  "no resources",
+	9:  "unsupported protocol",
+	10: "user exceeded quota",
+	11: "cannot provide external",
+	12: "address mismatch",
+	13: "excessive remote peers",
+}
+
+// PCP maps ports with the Port Control Protocol (RFC 6887) on the
+// default gateway. A mapping is renewed and deleted with the nonce
+// it was created with, so nonces are kept per internal port.
+type PCP struct {
+	// gateway overrides the server address, so tests can point the
+	// strategy at a fake gateway.
+	gateway string
+
+	mu         sync.Mutex
+	address    string
+	clientIP   net.IP
+	externalIP string
+	nonces     map[string][]byte
+}
+
+func NewPCP() *PCP {
+	return &PCP{nonces: map[string][]byte{}}
+}
+
+func (p *PCP) Name() string {
+	return "pcp"
+}
+
+// Discover sends an ANNOUNCE to the default gateway, which only a
+// PCP server answers with a PCP response.
+func (p *PCP) Discover(ctx context.Context) error {
+	address, err := gatewayAddress(p.gateway)
+	if err != nil {
+		return err
+	}
+
+	clientIP, err := localAddressFor(address)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	p.address, p.clientIP = address, clientIP
+	p.mu.Unlock()
+
+	_, err = p.request(ctx, pcpOpAnnounce, 0, nil)
+	return err
+}
+
+// ExternalIP returns the address the gateway reported for the last
+// mapping, as PCP has no request for it alone.
+func (p *PCP) ExternalIP(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.externalIP == "" {
+		return "", errNoExternalIP
+	}
+
+	return p.externalIP, nil
+}
+
+func (p *PCP) AddMapping(ctx context.Context, mapping Mapping) error {
+	lease := mapping.Lease
+	if lease <= 0 {
+		lease = pcpLease
+	}
+
+	response, err := p.mapPort(ctx, mapping, lease)
+	if err != nil {
+		return err
+	}
+
+	payload := response[pcpHeaderSize:]
+	if assigned := int(binary.BigEndian.Uint16(payload[18:20])); assigned != mapping.ExternalPort {
+		p.mapPort(ctx, mapping, 0)
+		return fmt.Errorf("gateway assigned port %d instead of %d", assigned, mapping.ExternalPort)
+	}
+
+	p.mu.Lock()
+	p.externalIP = net.IP(payload[20:36]).String()
+	p.mu.Unlock()
+
+	return nil
+}
+
+func (p *PCP) DeleteMapping(ctx context.Context, mapping Mapping) error {
+	_, err := p.mapPort(ctx, mapping, 0)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	delete(p.nonces, pcpKey(mapping))
+	p.mu.Unlock()
+
+	return nil
+}
+
+func (p *PCP) mapPort(ctx context.Context, mapping Mapping, lease time.Duration) ([]byte, error) {
+	nonce, err := p.nonce(mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := make([]byte, pcpMapSize)
+	copy(payload[0:12], nonce)
+	payload[12] = pcpProtocol(mapping.Protocol)
+	binary.BigEndian.PutUint16(payload[16:18], uint16(mapping.InternalPort))
+	binary.BigEndian.PutUint16(payload[18:20], uint16(mapping.ExternalPort))
+	copy(payload[20:36], net.IPv4zero.To16())
+
+	return p.request(ctx, pcpOpMap, lease, payload)
+}
+
+func (p *PCP) nonce(mapping Mapping) ([]byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := pcpKey(mapping)
+	if nonce, ok := p.nonces[key]; ok {
+		return nonce, nil
+	}
+
+	nonce := make([]byte, pcpNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate a PCP nonce: %w", err)
+	}
+	p.nonces[key] = nonce
+
+	return nonce, nil
+}
+
+// request sends a PCP request and checks the result code of the
+// response matching its opcode and, for MAP, its nonce.
+func (p *PCP) request(
+	ctx context.Context,
+	opcode byte,
+	lease time.Duration,
+	payload []byte,
+) ([]byte, error) {
+	p.mu.Lock()
+	address, clientIP := p.address, p.clientIP
+	p.mu.Unlock()
+
+	if address == "" {
+		return nil, ErrNoGateway
+	}
+
+	request := make([]byte, pcpHeaderSize, pcpHeaderSize+len(payload))
+	request[0] = pcpVersion
+	request[1] = opcode
+	binary.BigEndian.PutUint32(request[4:8], uint32(lease.Seconds()))
+	copy(request[8:24], clientIP.To16())
+	request = append(request, payload...)
+
+	response, err := exchange(ctx, address, request, func(response []byte) bool {
+		// ? A NAT-PMP only gateway answers with its own, shorter
+		// ? unsupported version response.
+		if len(response) >= 4 && response[0] != pcpVersion {
+			return true
+		}
+		if len(response) < pcpHeaderSize || response[1] != pcpResponse|opcode {
+			return false
+		}
+
+		return opcode != pcpOpMap ||
+			len(response) >= pcpHeaderSize+pcpMapSize &&
+				bytes.Equal(response[pcpHeaderSize:pcpHeaderSize+pcpNonceSize], payload[:pcpNonceSize])
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if response[0] != pcpVersion {
+		return nil, fmt.Errorf("gateway speaks PCP version %d", response[0])
+	}
+
+	if result := response[3]; result != 0 {
+		return nil, fmt.Errorf("PCP error %d %s", result, pcpResults[result])
+	}
+
+	return response, nil
+}
+
+func pcpKey(mapping Mapping) string {
+	return fmt.Sprintf("%s/%d", mapping.Protocol, mapping.InternalPort)
+}
+
+// pcpProtocol returns the IANA protocol number of a tcp or udp mapping.
+func pcpProtocol(protocol port.Protocol) byte {
+	if protocol.IsUDP() {
+		return pcpProtocolUDP
+	}
+
+	return pcpProtocolTCP
+}
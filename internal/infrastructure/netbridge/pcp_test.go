@@ -0,0 +1,175 @@
+package netbridge
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+)
+
+// fakePCP is a PCP server on a local UDP socket. Mappings are keyed
+// by protocol and internal port and remember the nonce that made
+// them, as a real server refuses changes with another nonce.
+type fakePCP struct {
+	conn net.PacketConn
+
+	mu       sync.Mutex
+	mappings map[string][]byte
+	clients  []net.IP
+}
+
+func newFakePCP(t *testing.T) *fakePCP {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	server := &fakePCP{conn: conn, mappings: map[string][]byte{}}
+	go server.serve()
+
+	return server
+}
+
+func (f *fakePCP) serve() {
+	buffer := make([]byte, 1100)
+
+	for {
+		n, addr, err := f.conn.ReadFrom(buffer)
+		if err != nil {
+			return
+		}
+		request := append([]byte(nil), buffer[:n]...)
+
+		response := make([]byte, pcpHeaderSize, pcpHeaderSize+pcpMapSize)
+		response[0] = pcpVersion
+		response[1] = pcpResponse | request[1]
+		copy(response[4:8], request[4:8])
+
+		f.mu.Lock()
+		f.clients = append(f.clients, net.IP(request[8:24]))
+
+		if request[1] == pcpOpMap {
+			payload := append([]byte(nil), request[pcpHeaderSize:pcpHeaderSize+pcpMapSize]...)
+			key := fmt.Sprintf("%d/%d", payload[12], binary.BigEndian.Uint16(payload[16:18]))
+			lifetime := binary.BigEndian.Uint32(request[4:8])
+
+			if nonce, ok := f.mappings[key]; ok && !bytes.Equal(nonce, payload[:pcpNonceSize]) {
+				response[3] = 2
+			} else if lifetime == 0 {
+				delete(f.mappings, key)
+			} else {
+				f.mappings[key] = payload[:pcpNonceSize]
+			}
+
+			copy(payload[20:36], net.ParseIP("198.51.100.9").To16())
+			response = append(response, payload...)
+		}
+		f.mu.Unlock()
+
+		f.conn.WriteTo(response, addr)
+	}
+}
+
+func (f *fakePCP) has(key string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, ok := f.mappings[key]
+	return ok
+}
+
+func discoveredPCP(t *testing.T, server *fakePCP) *PCP {
+	t.Helper()
+
+	pcp := NewPCP()
+	pcp.gateway = server.conn.LocalAddr().String()
+
+	if err := pcp.Discover(context.Background()); err != nil {
+		t.Fatalf("Discover() returned error: %v", err)
+	}
+
+	return pcp
+}
+
+func TestPCP_Discover(t *testing.T) {
+	server := newFakePCP(t)
+	discoveredPCP(t, server)
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	if len(server.clients) != 1 || !server.clients[0].Equal(net.ParseIP("127.0.0.1")) {
+		t.Errorf("Expected the announce to carry the client address, got %v", server.clients)
+	}
+}
+
+func TestPCP_Discover_NATPMPOnly(t *testing.T) {
+	gateway := newFakeNATPMP(t)
+
+	pcp := NewPCP()
+	pcp.gateway = gateway.conn.LocalAddr().String()
+
+	err := pcp.Discover(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "version 0") {
+		t.Errorf("Expected a NAT-PMP only gateway to be rejected quickly, got %v", err)
+	}
+}
+
+func TestPCP_Mappings(t *testing.T) {
+	server := newFakePCP(t)
+	pcp := discoveredPCP(t, server)
+	ctx := context.Background()
+
+	if _, err := pcp.ExternalIP(ctx); !errors.Is(err, errNoExternalIP) {
+		t.Errorf("Expected no external IP before a mapping, got %v", err)
+	}
+
+	mapping := Mapping{ExternalPort: 40130, InternalPort: 40130, Protocol: port.ProtocolUDP}
+	if err := pcp.AddMapping(ctx, mapping); err != nil {
+		t.Fatalf("AddMapping() returned error: %v", err)
+	}
+	if !server.has("17/40130") {
+		t.Fatal("Expected a UDP mapping for 40130")
+	}
+
+	ip, err := pcp.ExternalIP(ctx)
+	if err != nil || ip != "198.51.100.9" {
+		t.Errorf("ExternalIP() = %q, %v, expected the address of the mapping", ip, err)
+	}
+
+	// ? Renewing must reuse the nonce or the server refuses it.
+	if err := pcp.AddMapping(ctx, mapping); err != nil {
+		t.Errorf("Expected the mapping to be renewed, got %v", err)
+	}
+
+	if err := pcp.DeleteMapping(ctx, mapping); err != nil {
+		t.Fatalf("DeleteMapping() returned error: %v", err)
+	}
+	if server.has("17/40130") {
+		t.Error("Expected the mapping to be deleted")
+	}
+}
+
+func TestPCP_Errors(t *testing.T) {
+	server := newFakePCP(t)
+	pcp := discoveredPCP(t, server)
+
+	server.mu.Lock()
+	server.mappings["6/40130"] = make([]byte, pcpNonceSize)
+	server.mu.Unlock()
+
+	err := pcp.AddMapping(context.Background(), Mapping{ExternalPort: 40130, InternalPort: 40130, Protocol: port.ProtocolTCP})
+	if err == nil || !strings.Contains(err.Error(), "not authorized") {
+		t.Errorf("Expected another client's mapping to be refused, got %v", err)
+	}
+}
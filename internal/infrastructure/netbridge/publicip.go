@@ -0,0 +1,119 @@
+package netbridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// publicIPTTL is how long a discovered public IP is reused before
+// it is looked up again.
+const publicIPTTL = 5 * time.Minute
+
+// cgnat is the shared address space of RFC 6598, which carriers
+// put between their customers and the internet.
+var cgnat = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// UsePublicIPSources sets the STUN servers (host:port) and HTTP
+// echo endpoints PublicIP asks when the gateway cannot tell.
+func (n *NetbridgeImpl) UsePublicIPSources(stunServers []string, echoURLs []string) {
+	n.publicMu.Lock()
+	defer n.publicMu.Unlock()
+
+	n.stunServers = stunServers
+	n.echoURLs = echoURLs
+}
+
+// PublicIP returns the address this host is reached at from the
+// internet. The active strategy is asked first, then the STUN
+// servers and the echo endpoints in order. The result is cached
+// for publicIPTTL.
+func (n *NetbridgeImpl) PublicIP(
+	ctx context.Context,
+) (string, error) {
+	n.publicMu.Lock()
+	defer n.publicMu.Unlock()
+
+	if n.publicIP != "" && time.Since(n.publicIPAt) < publicIPTTL {
+		return n.publicIP, nil
+	}
+
+	var errs []error
+	found := func(ip string, err error, source string) bool {
+		if err == nil && !isPublicIP(net.ParseIP(ip)) {
+			err = fmt.Errorf("not a public address: %q", ip)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", source, err))
+			return false
+		}
+
+		n.publicIP, n.publicIPAt = ip, time.Now()
+		return true
+	}
+
+	if !n.enabled {
+		errs = append(errs, ErrDisabled)
+	} else {
+		ip, err := n.gatewayIP(ctx)
+		if found(ip, err, "gateway") {
+			return ip, nil
+		}
+	}
+
+	for _, server := range n.stunServers {
+		ip, err := stunPublicIP(ctx, server)
+		if found(ip, err, server) {
+			return ip, nil
+		}
+	}
+
+	for _, url := range n.echoURLs {
+		ip, err := n.echoPublicIP(ctx, url)
+		if found(ip, err, url) {
+			return ip, nil
+		}
+	}
+
+	return "", fmt.Errorf("failed to find the public IP: %w", errors.Join(errs...))
+}
+
+// echoPublicIP reads the address an HTTP endpoint answers with in
+// plain text, such as https://api.ipify.org.
+func (n *NetbridgeImpl) echoPublicIP(ctx context.Context, url string) (string, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := n.client.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %s", response.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, 64))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(body)), nil
+}
+
+// isPublicIP reports whether ip is routable on the internet, so
+// neither private, loopback, link local nor behind a carrier NAT.
+func isPublicIP(ip net.IP) bool {
+	return ip != nil &&
+		ip.IsGlobalUnicast() &&
+		!ip.IsPrivate() &&
+		!cgnat.Contains(ip)
+}
@@ -0,0 +1,123 @@
+package netbridge
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeEcho answers every request with body and counts them.
+func fakeEcho(t *testing.T, body string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(body + "\n"))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+func TestNetbridgeImpl_PublicIP_Fallbacks(t *testing.T) {
+	// ? A STUN server that is not listening makes the lookup move
+	// ? on to the echo endpoints.
+	closed, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed.Close()
+
+	echo, _ := fakeEcho(t, "198.51.100.4")
+	private := newFakeStrategy()
+	private.externalIP = "192.168.0.2"
+
+	testCases := []struct {
+		name        string
+		nb          *NetbridgeImpl
+		stunServers []string
+		echoURLs    []string
+		expected    string
+	}{
+		{"gateway", NewNetbridgeWith(true, newFakeStrategy()), []string{closed.LocalAddr().String()}, nil, "203.0.113.7"},
+		{"stun when disabled", NewNetbridgeWith(false), []string{fakeSTUN(t, net.ParseIP("203.0.113.9"), false)}, nil, "203.0.113.9"},
+		{"stun behind a private gateway address", NewNetbridgeWith(true, private), []string{fakeSTUN(t, net.ParseIP("203.0.113.9"), false)}, nil, "203.0.113.9"},
+		{"echo", NewNetbridgeWith(false), []string{closed.LocalAddr().String()}, []string{echo.URL}, "198.51.100.4"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.nb.UsePublicIPSources(tc.stunServers, tc.echoURLs)
+
+			ip, err := tc.nb.PublicIP(context.Background())
+			if err != nil || ip != tc.expected {
+				t.Errorf("PublicIP() = %q, %v, expected %s", ip, err, tc.expected)
+			}
+		})
+	}
+}
+
+func TestNetbridgeImpl_PublicIP_Cache(t *testing.T) {
+	echo, requests := fakeEcho(t, "198.51.100.4")
+
+	nb := NewNetbridgeWith(false)
+	nb.UsePublicIPSources(nil, []string{echo.URL})
+
+	for range 3 {
+		if ip, err := nb.PublicIP(context.Background()); err != nil || ip != "198.51.100.4" {
+			t.Fatalf("PublicIP() = %q, %v", ip, err)
+		}
+	}
+	if requests.Load() != 1 {
+		t.Errorf("Expected the public IP to be cached, got %d requests", requests.Load())
+	}
+
+	nb.publicIPAt = time.Now().Add(-publicIPTTL)
+	nb.PublicIP(context.Background())
+	if requests.Load() != 2 {
+		t.Errorf("Expected an expired public IP to be looked up again, got %d requests", requests.Load())
+	}
+}
+
+func TestNetbridgeImpl_PublicIP_NoSource(t *testing.T) {
+	echo, _ := fakeEcho(t, "not an address")
+
+	nb := NewNetbridgeWith(false)
+	nb.UsePublicIPSources(nil, []string{echo.URL})
+
+	_, err := nb.PublicIP(context.Background())
+	if !errors.Is(err, ErrDisabled) {
+		t.Errorf("Expected the reasons of every source, got %v", err)
+	}
+	if nb.publicIP != "" {
+		t.Error("A failed lookup should not be cached")
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	testCases := map[string]bool{
+		"203.0.113.7":  true,
+		"2001:db8::1":  true,
+		"192.168.1.20": false,
+		"10.0.0.1":     false,
+		"100.64.12.1":  false,
+		"127.0.0.1":    false,
+		"169.254.0.1":  false,
+		"0.0.0.0":      false,
+	}
+
+	for address, expected := range testCases {
+		if isPublicIP(net.ParseIP(address)) != expected {
+			t.Errorf("isPublicIP(%s) should be %v", address, expected)
+		}
+	}
+	if isPublicIP(nil) {
+		t.Error("isPublicIP(nil) should be false")
+	}
+}
@@ -0,0 +1,259 @@
+package netbridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+)
+
+var ErrUnreachable = errors.New("port is not reachable from the internet")
+
+const (
+	// reachabilityTTL is how long the outcome of a reachability
+	// check of a mapping is reused.
+	reachabilityTTL = 5 * time.Minute
+
+	// loopbackTimeout bounds the connection made to the public IP
+	// when no reachability service is configured.
+	loopbackTimeout = 3 * time.Second
+)
+
+// reachability is the outcome of checking a single mapping.
+type reachability struct {
+	status    port.Reachability
+	diagnosis string
+}
+
+// checked returns the last reachability of the mapping while it is
+// younger than reachabilityTTL.
+func (f *forwarding) checked(now time.Time) (reachability, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.checkedAt.IsZero() || now.Sub(f.checkedAt) >= reachabilityTTL {
+		return reachability{}, false
+	}
+
+	return f.reached, true
+}
+
+func (f *forwarding) remember(reached reachability) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.reached, f.checkedAt = reached, time.Now()
+}
+
+// UseReachabilityService sets the service CheckReachability asks to
+// connect back to a forwarded port. Without one, ports are tested
+// by connecting to the public IP from this host.
+func (n *NetbridgeImpl) UseReachabilityService(serviceURL string) {
+	n.publicMu.Lock()
+	defer n.publicMu.Unlock()
+
+	n.reachabilityURL = serviceURL
+}
+
+// CheckReachability tests whether the forwarded ports of rule can
+// be reached from the internet and returns it with the outcome. A
+// gateway whose external address is behind carrier-grade NAT or
+// private makes every port unreachable, whatever it maps; otherwise
+// each mapping is tested through the reachability service. Results
+// are reused for reachabilityTTL.
+func (n *NetbridgeImpl) CheckReachability(
+	ctx context.Context,
+	rule port.PortRule,
+) (port.PortRule, error) {
+	rule.Reachability, rule.Diagnosis = port.ReachabilityUnknown, ""
+
+	if !n.enabled {
+		return rule, ErrDisabled
+	}
+
+	var (
+		gateway string
+		results []reachability
+	)
+
+	for _, protocol := range rule.Protocol.Protocols() {
+		for _, portNum := range rule.Ports() {
+			n.mu.Lock()
+			forward, ok := n.forwarded[mappingKey{port: portNum, protocol: protocol}]
+			n.mu.Unlock()
+
+			if !ok {
+				continue
+			}
+
+			result, ok := forward.checked(time.Now())
+			if !ok {
+				if gateway == "" {
+					ip, err := n.gatewayIP(ctx)
+					if err != nil {
+						rule.Diagnosis = fmt.Sprintf("the router did not report its public address: %s", err)
+						return rule, err
+					}
+					gateway = ip
+				}
+
+				result = n.reach(ctx, gateway, forward.mapping)
+				forward.remember(result)
+			}
+
+			results = append(results, result)
+		}
+	}
+
+	combined := combineReachability(results)
+	rule.Reachability, rule.Diagnosis = combined.status, combined.diagnosis
+
+	if combined.status.IsUnreachable() {
+		return rule, fmt.Errorf("%w: %s", ErrUnreachable, combined.diagnosis)
+	}
+
+	return rule, nil
+}
+
+// reach tests a single mapping through the gateway's external
+// address.
+func (n *NetbridgeImpl) reach(ctx context.Context, gateway string, mapping Mapping) reachability {
+	unreachable := func(reason string) reachability {
+		return reachability{
+			status:    port.ReachabilityUnreachable,
+			diagnosis: fmt.Sprintf("port %d/%s is not reachable from the internet: %s", mapping.ExternalPort, mapping.Protocol, reason),
+		}
+	}
+	unknown := func(reason string) reachability {
+		return reachability{
+			status:    port.ReachabilityUnknown,
+			diagnosis: fmt.Sprintf("could not test port %d/%s: %s", mapping.ExternalPort, mapping.Protocol, reason),
+		}
+	}
+
+	ip := net.ParseIP(gateway)
+	switch {
+	case cgnat.Contains(ip):
+		return unreachable(fmt.Sprintf("the router's public address %s is behind carrier-grade NAT, so the ISP drops incoming traffic", gateway))
+	case !isPublicIP(ip):
+		return unreachable(fmt.Sprintf("the router's public address %s is private, so another router in front of it must forward the port too", gateway))
+	}
+
+	n.publicMu.Lock()
+	serviceURL := n.reachabilityURL
+	n.publicMu.Unlock()
+
+	if serviceURL != "" {
+		open, err := n.askReachability(ctx, serviceURL, mapping)
+		switch {
+		case err != nil:
+			return unknown(fmt.Sprintf("the reachability service failed: %s", err))
+		case !open:
+			return unreachable(fmt.Sprintf("the reachability service could not connect to %s", gateway))
+		}
+
+		return reachability{status: port.ReachabilityReachable}
+	}
+
+	// ? Without a service, only TCP can be tested, by connecting to
+	// ? the public IP from here. Routers that do not loop such
+	// ? connections back make this inconclusive rather than failed.
+	if mapping.Protocol != port.ProtocolTCP {
+		return unknown("only TCP is tested without netbridge.reachability_url")
+	}
+
+	dialer := net.Dialer{Timeout: loopbackTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(gateway, strconv.Itoa(mapping.ExternalPort)))
+	if err != nil {
+		return unknown("the router does not loop connections to its public address back, set netbridge.reachability_url to test from outside")
+	}
+	conn.Close()
+
+	return reachability{status: port.ReachabilityReachable}
+}
+
+// askReachability asks the service to connect back to the caller
+// on a port, as GET <url>?port=<port>&protocol=<protocol>. It
+// answers "open" or "closed" in plain text.
+func (n *NetbridgeImpl) askReachability(ctx context.Context, serviceURL string, mapping Mapping) (bool, error) {
+	endpoint, err := url.Parse(serviceURL)
+	if err != nil {
+		return false, err
+	}
+
+	query := endpoint.Query()
+	query.Set("port", strconv.Itoa(mapping.ExternalPort))
+	query.Set("protocol", mapping.Protocol.String())
+	endpoint.RawQuery = query.Encode()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return false, err
+	}
+
+	response, err := n.client.Do(request)
+	if err != nil {
+		return false, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected status %s", response.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, 64))
+	if err != nil {
+		return false, err
+	}
+
+	switch answer := strings.TrimSpace(string(body)); answer {
+	case "open":
+		return true, nil
+	case "closed":
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected answer %q", answer)
+	}
+}
+
+// gatewayIP asks the active strategy for the gateway's external
+// address.
+func (n *NetbridgeImpl) gatewayIP(ctx context.Context) (string, error) {
+	var ip string
+	err := n.use(ctx, func(strategy Strategy) error {
+		var err error
+		ip, err = strategy.ExternalIP(ctx)
+		return err
+	})
+
+	return ip, err
+}
+
+// combineReachability sums up the mappings of a rule: it is
+// unreachable when any of them is, and reachable only when all of
+// them are.
+func combineReachability(results []reachability) reachability {
+	if len(results) == 0 {
+		return reachability{status: port.ReachabilityUnknown}
+	}
+
+	combined := reachability{status: port.ReachabilityReachable}
+	for _, result := range results {
+		switch {
+		case result.status.IsUnreachable():
+			return result
+		case !result.status.IsReachable() && combined.status.IsReachable():
+			combined = result
+		}
+	}
+
+	return combined
+}
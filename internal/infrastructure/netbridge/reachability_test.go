@@ -0,0 +1,151 @@
+package netbridge
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+)
+
+func TestNetbridgeImpl_CheckReachability_Gateway(t *testing.T) {
+	testCases := []struct {
+		name      string
+		gateway   string
+		diagnosis string
+	}{
+		{"carrier-grade NAT", "100.72.14.3", "carrier-grade NAT"},
+		{"double NAT", "192.168.0.2", "another router"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			strategy := newFakeStrategy()
+			strategy.externalIP = tc.gateway
+			nb := NewNetbridgeWith(true, strategy)
+			ctx := context.Background()
+
+			nb.ForwardPort(ctx, tcpRule(40130))
+
+			rule, err := nb.CheckReachability(ctx, tcpRule(40130))
+			if !errors.Is(err, ErrUnreachable) {
+				t.Errorf("Expected ErrUnreachable, got %v", err)
+			}
+			if !rule.Reachability.IsUnreachable() {
+				t.Errorf("Expected the port to be unreachable, got %s", rule.Reachability)
+			}
+			if !strings.Contains(rule.Diagnosis, "port 40130/tcp is not reachable from the internet") ||
+				!strings.Contains(rule.Diagnosis, tc.diagnosis) {
+				t.Errorf("Expected a diagnosis about %s, got %q", tc.diagnosis, rule.Diagnosis)
+			}
+		})
+	}
+}
+
+func TestNetbridgeImpl_CheckReachability_Service(t *testing.T) {
+	answers := map[string]string{"40130/tcp": "open", "40130/udp": "closed"}
+
+	var requests []string
+	service := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		asked := r.URL.Query().Get("port") + "/" + r.URL.Query().Get("protocol")
+		requests = append(requests, asked)
+		w.Write([]byte(answers[asked] + "\n"))
+	}))
+	t.Cleanup(service.Close)
+
+	nb := NewNetbridgeWith(true, newFakeStrategy())
+	nb.UseReachabilityService(service.URL)
+	ctx := context.Background()
+
+	tcp := tcpRule(40130)
+	both := port.PortRule{Name: "GAME_PORT", StartPort: 40130, EndPort: 40130, Protocol: port.ProtocolTCPUDP}
+	nb.ForwardPort(ctx, both)
+
+	rule, err := nb.CheckReachability(ctx, tcp)
+	if err != nil || !rule.Reachability.IsReachable() || rule.Diagnosis != "" {
+		t.Errorf("Expected the TCP side to be reachable, got %s %q and %v", rule.Reachability, rule.Diagnosis, err)
+	}
+
+	rule, err = nb.CheckReachability(ctx, both)
+	if !errors.Is(err, ErrUnreachable) || !rule.Reachability.IsUnreachable() {
+		t.Errorf("Expected the UDP side to make the rule unreachable, got %s and %v", rule.Reachability, err)
+	}
+	if !strings.Contains(rule.Diagnosis, "40130/udp") {
+		t.Errorf("Expected the diagnosis to name the closed port, got %q", rule.Diagnosis)
+	}
+
+	if len(requests) != 2 {
+		t.Errorf("Expected each mapping to be tested once and then reused, got %v", requests)
+	}
+
+	nb.ReversePort(ctx, both)
+	if rule, _ := nb.CheckReachability(ctx, both); !rule.Reachability.IsUnknown() {
+		t.Errorf("Expected nothing to test once the rule is reversed, got %s", rule.Reachability)
+	}
+}
+
+func TestNetbridgeImpl_CheckReachability_Unknown(t *testing.T) {
+	ctx := context.Background()
+
+	rule, err := NewNetbridgeWith(false).CheckReachability(ctx, tcpRule(40130))
+	if !errors.Is(err, ErrDisabled) || !rule.Reachability.IsUnknown() {
+		t.Errorf("Expected a disabled netbridge not to test, got %s and %v", rule.Reachability, err)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(failing.Close)
+
+	testCases := []struct {
+		name      string
+		service   string
+		diagnosis string
+	}{
+		{"udp without service", "", "only TCP is tested"},
+		{"service failing", failing.URL, "the reachability service failed"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			nb := NewNetbridgeWith(true, newFakeStrategy())
+			nb.UseReachabilityService(tc.service)
+
+			udp := port.PortRule{Name: "QUERY_PORT", StartPort: 40131, EndPort: 40131, Protocol: port.ProtocolUDP}
+			nb.ForwardPort(ctx, udp)
+
+			rule, err := nb.CheckReachability(ctx, udp)
+			if err != nil || !rule.Reachability.IsUnknown() || !strings.Contains(rule.Diagnosis, tc.diagnosis) {
+				t.Errorf("Expected an inconclusive test, got %s %q and %v", rule.Reachability, rule.Diagnosis, err)
+			}
+		})
+	}
+}
+
+func TestCombineReachability(t *testing.T) {
+	reachable := reachability{status: port.ReachabilityReachable}
+	unknown := reachability{status: port.ReachabilityUnknown, diagnosis: "could not test"}
+	unreachable := reachability{status: port.ReachabilityUnreachable, diagnosis: "not reachable"}
+
+	testCases := []struct {
+		name     string
+		results  []reachability
+		expected reachability
+	}{
+		{"none", nil, reachability{status: port.ReachabilityUnknown}},
+		{"all reachable", []reachability{reachable, reachable}, reachable},
+		{"partly unknown", []reachability{reachable, unknown}, unknown},
+		{"any unreachable", []reachability{unknown, unreachable, reachable}, unreachable},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := combineReachability(tc.results); result != tc.expected {
+				t.Errorf("combineReachability() = %+v, expected %+v", result, tc.expected)
+			}
+		})
+	}
+}
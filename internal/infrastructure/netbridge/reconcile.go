@@ -0,0 +1,156 @@
+package netbridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+)
+
+const (
+	// mappingLease is asked for every mapping, so one the gateway
+	// lost track of, such as after a crash, expires on its own.
+	mappingLease = time.Hour
+
+	// refreshInterval bounds how long a mapping lost to a gateway
+	// restart stays missing, as mappings are re-added this often
+	// even when their lease runs longer.
+	refreshInterval = 5 * time.Minute
+)
+
+// forwarding is a mapping the gateway should hold until the port
+// is reversed.
+type forwarding struct {
+	mapping Mapping
+
+	mu        sync.Mutex
+	status    port.ForwardingStatus
+	renewedAt time.Time
+	reached   reachability
+	checkedAt time.Time
+}
+
+func (f *forwarding) current() port.ForwardingStatus {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.status
+}
+
+// due reports whether the mapping failed or is halfway through its
+// lease or refreshInterval, whichever is shorter.
+func (f *forwarding) due(now time.Time) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	every := min(f.mapping.Lease/2, refreshInterval)
+	if f.mapping.Lease <= 0 {
+		every = refreshInterval
+	}
+
+	return !f.status.IsEnabled() || now.Sub(f.renewedAt) >= every
+}
+
+func (n *NetbridgeImpl) renew(ctx context.Context, forward *forwarding) error {
+	err := n.apply(ctx, forward.mapping, Strategy.AddMapping)
+
+	forward.mu.Lock()
+	defer forward.mu.Unlock()
+
+	if err != nil {
+		forward.status = port.ForwardingStatusError
+		return err
+	}
+
+	forward.status = port.ForwardingStatusEnabled
+	forward.renewedAt = time.Now()
+
+	return nil
+}
+
+// apply adds or deletes mapping through the gateway strategies and
+// the firewall. Without any gateway strategy, as on a server with a
+// public address, the firewall alone is used.
+func (n *NetbridgeImpl) apply(
+	ctx context.Context,
+	mapping Mapping,
+	operation func(Strategy, context.Context, Mapping) error,
+) error {
+	n.mu.Lock()
+	firewall := n.firewall
+	n.mu.Unlock()
+
+	var errs []error
+	if len(n.strategies) > 0 || firewall == nil {
+		errs = append(errs, n.use(ctx, func(strategy Strategy) error {
+			return operation(strategy, ctx, mapping)
+		}))
+	}
+
+	if firewall != nil {
+		err := operation(firewall, ctx, mapping)
+
+		n.mu.Lock()
+		n.firewallError = ""
+		if err != nil {
+			n.firewallError = err.Error()
+		}
+		n.mu.Unlock()
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", firewall.Name(), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Reconcile renews the mappings that are due and re-creates the ones
+// that failed, such as after the gateway restarted. Forwarding
+// statuses are updated with the outcome.
+func (n *NetbridgeImpl) Reconcile(ctx context.Context) error {
+	n.mu.Lock()
+	forwards := make([]*forwarding, 0, len(n.forwarded))
+	for _, forward := range n.forwarded {
+		forwards = append(forwards, forward)
+	}
+	n.mu.Unlock()
+
+	now := time.Now()
+	var errs []error
+
+	for _, forward := range forwards {
+		if !forward.due(now) {
+			continue
+		}
+
+		if err := n.renew(ctx, forward); err != nil {
+			errs = append(errs, fmt.Errorf("failed to renew port %d: %w", forward.mapping.ExternalPort, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Close removes every mapping still forwarded from the gateway, so
+// none is left behind when Quiver stops.
+func (n *NetbridgeImpl) Close(ctx context.Context) error {
+	n.mu.Lock()
+	keys := make([]mappingKey, 0, len(n.forwarded))
+	for key := range n.forwarded {
+		keys = append(keys, key)
+	}
+	n.mu.Unlock()
+
+	var errs []error
+	for _, key := range keys {
+		if err := n.unmap(ctx, key); err != nil {
+			errs = append(errs, fmt.Errorf("failed to reverse %d/%s: %w", key.port, key.protocol, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,113 @@
+package netbridge
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+)
+
+func TestNetbridgeImpl_Reconcile(t *testing.T) {
+	strategy := newFakeStrategy()
+	nb := NewNetbridgeWith(true, strategy)
+	ctx := context.Background()
+
+	if _, err := nb.ForwardPort(ctx, tcpRule(40130)); err != nil {
+		t.Fatalf("ForwardPort() returned error: %v", err)
+	}
+	if strategy.mappings[tcpKey(40130)].Lease != mappingLease {
+		t.Errorf("Expected a %s lease, got %+v", mappingLease, strategy.mappings[tcpKey(40130)])
+	}
+
+	// ? The gateway restarted and forgot the mapping, which is not
+	// ? due yet.
+	delete(strategy.mappings, tcpKey(40130))
+	if err := nb.Reconcile(ctx); err != nil {
+		t.Fatalf("Reconcile() returned error: %v", err)
+	}
+	if _, ok := strategy.mappings[tcpKey(40130)]; ok {
+		t.Error("Expected a mapping to be left alone before it is due")
+	}
+
+	nb.forwarded[tcpKey(40130)].renewedAt = time.Now().Add(-refreshInterval)
+	if err := nb.Reconcile(ctx); err != nil {
+		t.Fatalf("Reconcile() returned error: %v", err)
+	}
+	if _, ok := strategy.mappings[tcpKey(40130)]; !ok {
+		t.Error("Expected the lost mapping to be re-created")
+	}
+}
+
+func TestNetbridgeImpl_Reconcile_Failures(t *testing.T) {
+	strategy := newFakeStrategy()
+	nb := NewNetbridgeWith(true, strategy)
+	ctx := context.Background()
+
+	strategy.addErr = errors.New("gateway busy")
+	if _, err := nb.ForwardPort(ctx, tcpRule(40130)); err == nil {
+		t.Fatal("Expected ForwardPort() to fail")
+	}
+
+	if err := nb.Reconcile(ctx); err == nil {
+		t.Error("Expected Reconcile() to report the port it could not renew")
+	}
+	if status, _ := nb.GetPortForwardingStatus(ctx, tcpRule(40130)); !status.IsError() {
+		t.Errorf("Expected an error status, got %s", status)
+	}
+
+	strategy.addErr = nil
+	if err := nb.Reconcile(ctx); err != nil {
+		t.Fatalf("Reconcile() returned error: %v", err)
+	}
+	if status, _ := nb.GetPortForwardingStatus(ctx, tcpRule(40130)); !status.IsEnabled() {
+		t.Errorf("Expected a failed forward to be retried at once, got %s", status)
+	}
+}
+
+func TestNetbridgeImpl_Close(t *testing.T) {
+	strategy := newFakeStrategy()
+	nb := NewNetbridgeWith(true, strategy)
+	ctx := context.Background()
+
+	nb.ForwardPorts(ctx, []port.PortRule{tcpRule(40130), tcpRule(40131)})
+
+	if err := nb.Close(ctx); err != nil {
+		t.Fatalf("Close() returned error: %v", err)
+	}
+	if len(strategy.mappings) != 0 {
+		t.Errorf("Expected every mapping to be removed, got %v", strategy.mappings)
+	}
+	if status, _ := nb.GetPortForwardingStatus(ctx, tcpRule(40130)); !status.IsDisabled() {
+		t.Errorf("Expected closed ports to be disabled, got %s", status)
+	}
+
+	if err := NewNetbridgeWith(false).Close(ctx); err != nil {
+		t.Errorf("Close() without forwarded ports returned error: %v", err)
+	}
+}
+
+func TestForwarding_Due(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		name     string
+		forward  *forwarding
+		expected bool
+	}{
+		{"fresh", &forwarding{mapping: Mapping{Lease: time.Hour}, status: port.ForwardingStatusEnabled, renewedAt: now}, false},
+		{"refresh", &forwarding{mapping: Mapping{Lease: time.Hour}, status: port.ForwardingStatusEnabled, renewedAt: now.Add(-refreshInterval)}, true},
+		{"half lease", &forwarding{mapping: Mapping{Lease: 2 * time.Minute}, status: port.ForwardingStatusEnabled, renewedAt: now.Add(-time.Minute)}, true},
+		{"permanent", &forwarding{status: port.ForwardingStatusEnabled, renewedAt: now.Add(-time.Minute)}, false},
+		{"failed", &forwarding{mapping: Mapping{Lease: time.Hour}, status: port.ForwardingStatusError, renewedAt: now}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.forward.due(now) != tc.expected {
+				t.Errorf("due() should be %v", tc.expected)
+			}
+		})
+	}
+}
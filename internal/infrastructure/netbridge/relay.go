@@ -0,0 +1,67 @@
+package netbridge
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/rabbytesoftware/quiver/internal/infrastructure/netbridge/relay"
+)
+
+// Relay exposes ports through a relay server Quiver connects out
+// to, for hosts behind carrier-grade NAT where no gateway mapping
+// reaches them. Players connect to the relay's address instead.
+type Relay struct {
+	address string
+	client  *relay.Client
+}
+
+func NewRelay(address string, token string) *Relay {
+	return &Relay{address: address, client: relay.NewClient(address, token)}
+}
+
+func (r *Relay) Name() string {
+	return "relay"
+}
+
+// Discover connects to the relay and authenticates.
+func (r *Relay) Discover(ctx context.Context) error {
+	if r.address == "" {
+		return fmt.Errorf("%w: netbridge.relay_address is not set", ErrNoGateway)
+	}
+
+	if err := r.client.Connect(ctx); err != nil {
+		return fmt.Errorf("%w: %s", ErrNoGateway, err)
+	}
+
+	return nil
+}
+
+// ExternalIP is the address of the relay, which players connect to.
+func (r *Relay) ExternalIP(ctx context.Context) (string, error) {
+	host, _, err := net.SplitHostPort(r.address)
+	if err != nil {
+		return "", err
+	}
+
+	addresses, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return "", err
+	}
+
+	for _, address := range addresses {
+		if ip := address.IP.To4(); ip != nil {
+			return ip.String(), nil
+		}
+	}
+
+	return "", fmt.Errorf("relay %s has no IPv4 address", host)
+}
+
+func (r *Relay) AddMapping(ctx context.Context, mapping Mapping) error {
+	return r.client.Open(ctx, mapping.Protocol, mapping.ExternalPort, mapping.InternalPort)
+}
+
+func (r *Relay) DeleteMapping(ctx context.Context, mapping Mapping) error {
+	return r.client.Close(ctx, mapping.Protocol, mapping.ExternalPort)
+}
@@ -0,0 +1,289 @@
+package relay
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+)
+
+// Client keeps the control connection of a host to a relay and
+// connects every player the relay hands over to the local port.
+type Client struct {
+	address string
+	token   string
+	// local is the host players are connected to on this machine.
+	local  string
+	dialer net.Dialer
+
+	// requests serializes commands, as answers carry no id.
+	requests sync.Mutex
+
+	mu      sync.Mutex
+	control net.Conn
+	answers chan []string
+	done    chan struct{}
+	exposed map[exposure]int
+}
+
+func NewClient(address string, token string) *Client {
+	return &Client{
+		address: address,
+		token:   token,
+		local:   "127.0.0.1",
+		dialer:  net.Dialer{Timeout: handshakeTimeout},
+		exposed: map[exposure]int{},
+	}
+}
+
+// Connect opens the control connection unless it is open already.
+// Ports exposed over a previous connection are exposed again.
+func (c *Client) Connect(ctx context.Context) error {
+	c.requests.Lock()
+	defer c.requests.Unlock()
+
+	return c.connect(ctx)
+}
+
+func (c *Client) connect(ctx context.Context) error {
+	c.mu.Lock()
+	connected := c.control != nil
+	c.mu.Unlock()
+
+	if connected {
+		return nil
+	}
+
+	conn, err := c.dialer.DialContext(ctx, "tcp", c.address)
+	if err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(conn)
+	conn.SetDeadline(time.Now().Add(handshakeTimeout))
+
+	if err := writeLine(conn, "HELLO", c.token); err != nil {
+		conn.Close()
+		return err
+	}
+
+	fields, err := readLine(reader)
+	if err == nil {
+		err = answer(fields)
+	}
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	conn.SetDeadline(time.Time{})
+
+	answers, done := make(chan []string, 1), make(chan struct{})
+
+	c.mu.Lock()
+	c.control, c.answers, c.done = conn, answers, done
+	exposed := make(map[exposure]int, len(c.exposed))
+	for stream, local := range c.exposed {
+		exposed[stream] = local
+	}
+	c.mu.Unlock()
+
+	go c.listen(conn, reader, answers, done)
+
+	// ? A port that cannot be exposed again yet is retried when the
+	// ? host renews it with Open.
+	for stream := range exposed {
+		c.request(ctx, "OPEN", stream.protocol.String(), strconv.Itoa(stream.port))
+	}
+
+	return nil
+}
+
+// Open asks the relay to listen on publicPort and connects the
+// players that arrive there to localPort.
+func (c *Client) Open(ctx context.Context, protocol port.Protocol, publicPort int, localPort int) error {
+	c.requests.Lock()
+	defer c.requests.Unlock()
+
+	if err := c.connect(ctx); err != nil {
+		return err
+	}
+
+	if err := c.request(ctx, "OPEN", protocol.String(), strconv.Itoa(publicPort)); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.exposed[exposure{protocol: protocol, port: publicPort}] = localPort
+	c.mu.Unlock()
+
+	return nil
+}
+
+// Close asks the relay to stop listening on publicPort.
+func (c *Client) Close(ctx context.Context, protocol port.Protocol, publicPort int) error {
+	c.requests.Lock()
+	defer c.requests.Unlock()
+
+	c.mu.Lock()
+	delete(c.exposed, exposure{protocol: protocol, port: publicPort})
+	connected := c.control != nil
+	c.mu.Unlock()
+
+	// ? Ports exposed over a lost connection are closed already.
+	if !connected {
+		return nil
+	}
+
+	return c.request(ctx, "CLOSE", protocol.String(), strconv.Itoa(publicPort))
+}
+
+// Shutdown closes the control connection, which makes the relay
+// close every port of this host.
+func (c *Client) Shutdown() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.exposed = map[exposure]int{}
+	if c.control == nil {
+		return nil
+	}
+
+	return c.control.Close()
+}
+
+func (c *Client) request(ctx context.Context, fields ...string) error {
+	c.mu.Lock()
+	conn, answers, done := c.control, c.answers, c.done
+	c.mu.Unlock()
+
+	if conn == nil {
+		return ErrClosed
+	}
+
+	if err := writeLine(conn, fields...); err != nil {
+		conn.Close()
+		return err
+	}
+
+	select {
+	case fields := <-answers:
+		return answer(fields)
+	case <-done:
+		return ErrClosed
+	case <-ctx.Done():
+		// ? A late answer would be taken for the next request's.
+		conn.Close()
+		return ctx.Err()
+	}
+}
+
+// listen reads the control connection, answering requests and
+// joining the streams the relay announces, until it is closed.
+func (c *Client) listen(conn net.Conn, reader *bufio.Reader, answers chan []string, done chan struct{}) {
+	defer func() {
+		conn.Close()
+		close(done)
+
+		c.mu.Lock()
+		if c.control == conn {
+			c.control = nil
+		}
+		c.mu.Unlock()
+	}()
+
+	for {
+		fields, err := readLine(reader)
+		if err != nil {
+			return
+		}
+
+		if fields[0] != "CONNECT" {
+			// ? An answer nobody waits for is dropped.
+			select {
+			case answers <- fields:
+			default:
+			}
+			continue
+		}
+
+		if len(fields) != 4 {
+			continue
+		}
+
+		publicPort, err := strconv.Atoi(fields[3])
+		if err != nil {
+			continue
+		}
+
+		c.mu.Lock()
+		localPort, ok := c.exposed[exposure{protocol: port.Protocol(fields[2]), port: publicPort}]
+		c.mu.Unlock()
+
+		if ok {
+			go c.join(fields[1], port.Protocol(fields[2]), localPort)
+		}
+	}
+}
+
+// join connects stream id of the relay to the local port.
+func (c *Client) join(id string, protocol port.Protocol, localPort int) {
+	local, err := net.DialTimeout(protocol.String(), net.JoinHostPort(c.local, strconv.Itoa(localPort)), handshakeTimeout)
+	if err != nil {
+		return
+	}
+
+	relayed, err := c.dialer.Dial("tcp", c.address)
+	if err != nil {
+		local.Close()
+		return
+	}
+
+	if err := writeLine(relayed, "JOIN", c.token, id); err != nil {
+		local.Close()
+		relayed.Close()
+		return
+	}
+
+	if protocol == port.ProtocolTCP {
+		pipe(relayed, local)
+		return
+	}
+
+	pipeDatagrams(relayed, local)
+}
+
+// pipeDatagrams carries datagrams between the relay stream and the
+// local UDP socket until either side is done or idle.
+func pipeDatagrams(relayed net.Conn, local net.Conn) {
+	defer relayed.Close()
+	defer local.Close()
+
+	go func() {
+		buf := make([]byte, maxDatagram)
+		for {
+			n, err := readDatagram(relayed, buf)
+			if err != nil {
+				local.Close()
+				return
+			}
+			local.Write(buf[:n])
+		}
+	}()
+
+	buf := make([]byte, maxDatagram)
+	for {
+		local.SetReadDeadline(time.Now().Add(udpIdleTimeout))
+
+		n, err := local.Read(buf)
+		if err != nil {
+			return
+		}
+		if writeDatagram(relayed, buf[:n]) != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// Package relay exposes ports of a host that cannot forward them,
+// such as one behind carrier-grade NAT. The host keeps a control
+// connection open to a relay server, which listens on its behalf
+// and hands every player over a new connection the host dials out.
+//
+// Every message is a line of space separated fields:
+//
+//	HELLO <token>                   host → relay, opens the control connection
+//	OPEN <protocol> <port>          host → relay, listens on port
+//	CLOSE <protocol> <port>         host → relay, stops listening
+//	CONNECT <id> <protocol> <port>  relay → host, a player arrived on port
+//	JOIN <token> <id>               host → relay, on a new connection for stream id
+//
+// The relay answers HELLO, OPEN and CLOSE with "OK" or "ERR
+// <message>". After JOIN the connection carries the stream: TCP
+// as is, and UDP as datagrams prefixed with their length on two
+// bytes.
+package relay
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrUnauthorized = errors.New("invalid relay token")
+	ErrClosed       = errors.New("relay connection closed")
+)
+
+const (
+	// handshakeTimeout bounds the first line of a connection and the
+	// wait for the host to join a stream.
+	handshakeTimeout = 10 * time.Second
+
+	// udpIdleTimeout ends a UDP stream nothing was sent over.
+	udpIdleTimeout = 2 * time.Minute
+
+	// maxLine bounds a control message.
+	maxLine = 256
+
+	// maxDatagram is the largest UDP payload.
+	maxDatagram = 65535
+)
+
+func writeLine(w io.Writer, fields ...string) error {
+	_, err := io.WriteString(w, strings.Join(fields, " ")+"\n")
+	return err
+}
+
+func readLine(r *bufio.Reader) ([]string, error) {
+	var line []byte
+
+	for {
+		chunk, prefix, err := r.ReadLine()
+		if err != nil {
+			return nil, err
+		}
+
+		line = append(line, chunk...)
+		if len(line) > maxLine {
+			return nil, errors.New("relay message too long")
+		}
+		if !prefix {
+			break
+		}
+	}
+
+	fields := strings.Fields(string(line))
+	if len(fields) == 0 {
+		return nil, errors.New("empty relay message")
+	}
+
+	return fields, nil
+}
+
+// answer turns an "OK" or "ERR <message>" line into an error.
+func answer(fields []string) error {
+	switch fields[0] {
+	case "OK":
+		return nil
+	case "ERR":
+		message := strings.Join(fields[1:], " ")
+		if message == ErrUnauthorized.Error() {
+			return ErrUnauthorized
+		}
+		return errors.New(message)
+	default:
+		return fmt.Errorf("unexpected relay answer %q", strings.Join(fields, " "))
+	}
+}
+
+func writeDatagram(w io.Writer, payload []byte) error {
+	frame := make([]byte, 2+len(payload))
+	binary.BigEndian.PutUint16(frame, uint16(len(payload)))
+	copy(frame[2:], payload)
+
+	_, err := w.Write(frame)
+	return err
+}
+
+func readDatagram(r io.Reader, buf []byte) (int, error) {
+	var size [2]byte
+	if _, err := io.ReadFull(r, size[:]); err != nil {
+		return 0, err
+	}
+
+	length := int(binary.BigEndian.Uint16(size[:]))
+	if length > len(buf) {
+		return 0, errors.New("datagram too large")
+	}
+
+	return io.ReadFull(r, buf[:length])
+}
+
+// newID returns an unguessable stream id.
+func newID() string {
+	id := make([]byte, 16)
+	rand.Read(id)
+
+	return hex.EncodeToString(id)
+}
+
+// pipe copies between a and b until either side is done, then
+// closes both.
+func pipe(a, b net.Conn) {
+	var once sync.Once
+	closeBoth := func() {
+		a.Close()
+		b.Close()
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		io.Copy(a, b)
+		once.Do(closeBoth)
+	}()
+	go func() {
+		defer wg.Done()
+		io.Copy(b, a)
+		once.Do(closeBoth)
+	}()
+
+	wg.Wait()
+}
@@ -0,0 +1,291 @@
+package relay
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+)
+
+// startServer runs a relay on a free local port.
+func startServer(t *testing.T, allowed ...port.Range) (*Server, string) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := NewServer([]string{"secret"}, allowed)
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Close() })
+
+	return server, listener.Addr().String()
+}
+
+// freePort returns a port that was free a moment ago.
+func freePort(t *testing.T, network string) int {
+	t.Helper()
+
+	if network == "udp" {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		return conn.LocalAddr().(*net.UDPAddr).Port
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+// echoTCP answers every line with itself, like a game server.
+func echoTCP(t *testing.T) int {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					conn.Write([]byte(line))
+				}
+			}()
+		}
+	}()
+
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func echoUDP(t *testing.T) int {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 1500)
+		for {
+			n, address, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			conn.WriteTo(buf[:n], address)
+		}
+	}()
+
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+func connect(t *testing.T, address string, token string) *Client {
+	t.Helper()
+
+	client := NewClient(address, token)
+	t.Cleanup(func() { client.Shutdown() })
+
+	return client
+}
+
+func TestRelay_TCP(t *testing.T) {
+	_, address := startServer(t)
+	ctx := context.Background()
+
+	client := connect(t, address, "secret")
+	public := freePort(t, "tcp")
+	if err := client.Open(ctx, port.ProtocolTCP, public, echoTCP(t)); err != nil {
+		t.Fatalf("Open() returned error: %v", err)
+	}
+
+	player, err := net.DialTimeout("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(public)), time.Second)
+	if err != nil {
+		t.Fatalf("Expected the relay to listen on %d: %v", public, err)
+	}
+	defer player.Close()
+
+	player.SetDeadline(time.Now().Add(5 * time.Second))
+	player.Write([]byte("status\n"))
+	if line, err := bufio.NewReader(player).ReadString('\n'); err != nil || line != "status\n" {
+		t.Errorf("Expected the game server to answer through the relay, got %q and %v", line, err)
+	}
+
+	if err := client.Open(ctx, port.ProtocolTCP, public, echoTCP(t)); err != nil {
+		t.Errorf("Expected a port to be opened again, as hosts renew them, got %v", err)
+	}
+
+	if err := client.Close(ctx, port.ProtocolTCP, public); err != nil {
+		t.Fatalf("Close() returned error: %v", err)
+	}
+	if conn, err := net.DialTimeout("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(public)), time.Second); err == nil {
+		conn.Close()
+		t.Error("Expected the relay to stop listening once the port is closed")
+	}
+}
+
+func TestRelay_UDP(t *testing.T) {
+	_, address := startServer(t)
+	ctx := context.Background()
+
+	client := connect(t, address, "secret")
+	public := freePort(t, "udp")
+	if err := client.Open(ctx, port.ProtocolUDP, public, echoUDP(t)); err != nil {
+		t.Fatalf("Open() returned error: %v", err)
+	}
+
+	player, err := net.Dial("udp", net.JoinHostPort("127.0.0.1", strconv.Itoa(public)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer player.Close()
+
+	buf := make([]byte, 64)
+	for _, query := range []string{"A2S_INFO", "A2S_PLAYER"} {
+		player.SetReadDeadline(time.Now().Add(5 * time.Second))
+		player.Write([]byte(query))
+
+		n, err := player.Read(buf)
+		if err != nil || string(buf[:n]) != query {
+			t.Errorf("Expected %q to be echoed through the relay, got %q and %v", query, buf[:n], err)
+		}
+	}
+}
+
+func TestRelay_Errors(t *testing.T) {
+	allowed := port.Range{Start: freePort(t, "tcp"), End: 0}
+	allowed.End = allowed.Start
+	_, address := startServer(t, allowed)
+	ctx := context.Background()
+
+	if err := connect(t, address, "guess").Connect(ctx); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized, got %v", err)
+	}
+
+	client := connect(t, address, "secret")
+	if err := client.Open(ctx, port.ProtocolTCP, allowed.Start+1, 27015); err == nil || !strings.Contains(err.Error(), "not allowed") {
+		t.Errorf("Expected a port outside the allowed ranges to be refused, got %v", err)
+	}
+
+	if err := client.Open(ctx, port.ProtocolTCP, allowed.Start, 27015); err != nil {
+		t.Fatalf("Open() returned error: %v", err)
+	}
+	other := connect(t, address, "secret")
+	if err := other.Open(ctx, port.ProtocolTCP, allowed.Start, 27015); err == nil || !strings.Contains(err.Error(), "another host") {
+		t.Errorf("Expected a port exposed by another host to be refused, got %v", err)
+	}
+
+	// ? Once the first host is gone, its ports are free again.
+	client.Shutdown()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		err := other.Open(ctx, port.ProtocolTCP, allowed.Start, 27015)
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the port to be released with the session, got %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRelay_Reconnect(t *testing.T) {
+	server, address := startServer(t)
+	ctx := context.Background()
+
+	client := connect(t, address, "secret")
+	public := freePort(t, "tcp")
+	if err := client.Open(ctx, port.ProtocolTCP, public, echoTCP(t)); err != nil {
+		t.Fatalf("Open() returned error: %v", err)
+	}
+
+	// ? The relay drops the host, as on a restart.
+	server.Close()
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		t.Skipf("relay address %s was taken meanwhile: %v", address, err)
+	}
+	restarted := NewServer([]string{"secret"}, nil)
+	go restarted.Serve(listener)
+	t.Cleanup(func() { restarted.Close() })
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		client.Connect(ctx)
+
+		conn, err := net.DialTimeout("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(public)), time.Second)
+		if err == nil {
+			conn.Close()
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the port to be exposed again after reconnecting: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestReadLine(t *testing.T) {
+	fields, err := readLine(bufio.NewReader(strings.NewReader("CONNECT 01ab tcp 27015\n")))
+	if err != nil || len(fields) != 4 || fields[3] != "27015" {
+		t.Errorf("readLine() = %v, %v", fields, err)
+	}
+
+	if _, err := readLine(bufio.NewReader(strings.NewReader(strings.Repeat("A", maxLine+1) + "\n"))); err == nil {
+		t.Error("Expected a message longer than maxLine to be refused")
+	}
+	if _, err := readLine(bufio.NewReader(strings.NewReader("\n"))); err == nil {
+		t.Error("Expected an empty message to be refused")
+	}
+}
+
+func TestDatagrams(t *testing.T) {
+	var frames strings.Builder
+	writeDatagram(&frames, []byte("A2S_INFO"))
+	writeDatagram(&frames, nil)
+
+	reader := strings.NewReader(frames.String())
+	buf := make([]byte, 16)
+
+	if n, err := readDatagram(reader, buf); err != nil || string(buf[:n]) != "A2S_INFO" {
+		t.Errorf("readDatagram() = %q, %v", buf[:n], err)
+	}
+	if n, err := readDatagram(reader, buf); err != nil || n != 0 {
+		t.Errorf("Expected an empty datagram, got %d bytes and %v", n, err)
+	}
+
+	writeDatagram(&frames, make([]byte, 32))
+	reader = strings.NewReader(frames.String()[frames.Len()-34:])
+	if _, err := readDatagram(reader, buf); err == nil {
+		t.Error("Expected a datagram larger than the buffer to be refused")
+	}
+}
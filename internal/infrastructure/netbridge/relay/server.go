@@ -0,0 +1,476 @@
+package relay
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+)
+
+// Server is the reference relay. Hosts authenticate with one of its
+// tokens and may expose any port within its allowed ranges, or any
+// port at all when none are set.
+type Server struct {
+	tokens  map[string]bool
+	allowed []port.Range
+
+	mu       sync.Mutex
+	listener net.Listener
+	pending  map[string]chan net.Conn
+	exposed  map[exposure]*session
+	sessions map[*session]bool
+}
+
+// exposure is a public port of a single protocol.
+type exposure struct {
+	protocol port.Protocol
+	port     int
+}
+
+func (e exposure) String() string {
+	return fmt.Sprintf("%d/%s", e.port, e.protocol)
+}
+
+func NewServer(tokens []string, allowed []port.Range) *Server {
+	s := &Server{
+		tokens:   map[string]bool{},
+		allowed:  allowed,
+		pending:  map[string]chan net.Conn{},
+		exposed:  map[exposure]*session{},
+		sessions: map[*session]bool{},
+	}
+
+	for _, token := range tokens {
+		if token != "" {
+			s.tokens[token] = true
+		}
+	}
+
+	return s
+}
+
+func (s *Server) ListenAndServe(address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+
+	return s.Serve(listener)
+}
+
+// Serve accepts hosts on listener until Close is called.
+func (s *Server) Serve(listener net.Listener) error {
+	s.mu.Lock()
+	s.listener = listener
+	s.mu.Unlock()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+
+		go s.handle(conn)
+	}
+}
+
+// Close stops accepting hosts and ends every session along with
+// the ports it exposed.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	listener := s.listener
+	sessions := make([]*session, 0, len(s.sessions))
+	for session := range s.sessions {
+		sessions = append(sessions, session)
+	}
+	s.mu.Unlock()
+
+	for _, session := range sessions {
+		session.conn.Close()
+	}
+
+	if listener == nil {
+		return nil
+	}
+
+	return listener.Close()
+}
+
+func (s *Server) handle(conn net.Conn) {
+	reader := bufio.NewReader(conn)
+
+	conn.SetReadDeadline(time.Now().Add(handshakeTimeout))
+	fields, err := readLine(reader)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	conn.SetReadDeadline(time.Time{})
+
+	switch {
+	case len(fields) == 2 && fields[0] == "HELLO":
+		if !s.tokens[fields[1]] {
+			writeLine(conn, "ERR", ErrUnauthorized.Error())
+			conn.Close()
+			return
+		}
+
+		session := &session{server: s, conn: conn, reader: reader, exposed: map[exposure]func(){}}
+		s.mu.Lock()
+		s.sessions[session] = true
+		s.mu.Unlock()
+
+		session.run()
+	case len(fields) == 3 && fields[0] == "JOIN" && s.tokens[fields[1]]:
+		if !s.join(fields[2], &bufferedConn{Conn: conn, reader: reader}) {
+			conn.Close()
+		}
+	default:
+		writeLine(conn, "ERR", "unexpected message")
+		conn.Close()
+	}
+}
+
+// await asks the host of session for a connection to a new stream
+// and waits for it to join.
+func (s *Server) await(session *session, stream exposure) (net.Conn, error) {
+	id := newID()
+	joined := make(chan net.Conn, 1)
+
+	s.mu.Lock()
+	s.pending[id] = joined
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.pending, id)
+		s.mu.Unlock()
+	}()
+
+	if err := session.send("CONNECT", id, stream.protocol.String(), strconv.Itoa(stream.port)); err != nil {
+		return nil, err
+	}
+
+	select {
+	case conn := <-joined:
+		return conn, nil
+	case <-time.After(handshakeTimeout):
+		return nil, fmt.Errorf("host did not join stream %s", id)
+	}
+}
+
+func (s *Server) join(id string, conn net.Conn) bool {
+	s.mu.Lock()
+	joined, ok := s.pending[id]
+	delete(s.pending, id)
+	s.mu.Unlock()
+
+	if ok {
+		joined <- conn
+	}
+
+	return ok
+}
+
+// permitted reports whether hosts may expose portNum.
+func (s *Server) permitted(portNum int) bool {
+	if len(s.allowed) == 0 {
+		return portNum > 0 && portNum <= 65535
+	}
+
+	for _, r := range s.allowed {
+		if r.Contains(portNum) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// session is the control connection of a host.
+type session struct {
+	server *Server
+	conn   net.Conn
+	reader *bufio.Reader
+
+	writeMu sync.Mutex
+	mu      sync.Mutex
+	exposed map[exposure]func()
+}
+
+func (s *session) send(fields ...string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	return writeLine(s.conn, fields...)
+}
+
+func (s *session) run() {
+	defer s.close()
+
+	if s.send("OK") != nil {
+		return
+	}
+
+	for {
+		fields, err := readLine(s.reader)
+		if err != nil {
+			return
+		}
+
+		if err := s.command(fields); err != nil {
+			s.send("ERR", err.Error())
+			continue
+		}
+		s.send("OK")
+	}
+}
+
+func (s *session) command(fields []string) error {
+	if len(fields) != 3 || (fields[0] != "OPEN" && fields[0] != "CLOSE") {
+		return errors.New("unexpected message")
+	}
+
+	portNum, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return fmt.Errorf("invalid port %q", fields[2])
+	}
+
+	protocol := port.Protocol(fields[1])
+	if protocol != port.ProtocolTCP && protocol != port.ProtocolUDP {
+		return fmt.Errorf("invalid protocol %q", fields[1])
+	}
+
+	stream := exposure{protocol: protocol, port: portNum}
+	if fields[0] == "CLOSE" {
+		s.unexpose(stream)
+		return nil
+	}
+
+	return s.expose(stream)
+}
+
+// expose listens on the public port for players. Exposing a port
+// twice is allowed, so hosts can renew what they opened.
+func (s *session) expose(stream exposure) error {
+	if !s.server.permitted(stream.port) {
+		return fmt.Errorf("port %s is not allowed on this relay", stream)
+	}
+
+	s.server.mu.Lock()
+	owner, taken := s.server.exposed[stream]
+	if !taken {
+		s.server.exposed[stream] = s
+	}
+	s.server.mu.Unlock()
+
+	if taken {
+		if owner == s {
+			return nil
+		}
+		return fmt.Errorf("port %s is used by another host", stream)
+	}
+
+	address := net.JoinHostPort("", strconv.Itoa(stream.port))
+
+	var stop func()
+	if stream.protocol == port.ProtocolTCP {
+		listener, err := net.Listen("tcp", address)
+		if err == nil {
+			go s.acceptTCP(listener, stream)
+			stop = func() { listener.Close() }
+		}
+		return s.listened(stream, stop, err)
+	}
+
+	conn, err := net.ListenPacket("udp", address)
+	if err == nil {
+		relay := &udpRelay{session: s, stream: stream, conn: conn, done: make(chan struct{}), players: map[string]*udpPlayer{}}
+		go relay.serve()
+		stop = relay.close
+	}
+
+	return s.listened(stream, stop, err)
+}
+
+func (s *session) listened(stream exposure, stop func(), err error) error {
+	if err != nil {
+		s.server.mu.Lock()
+		delete(s.server.exposed, stream)
+		s.server.mu.Unlock()
+
+		return fmt.Errorf("failed to listen on %s: %w", stream, err)
+	}
+
+	s.mu.Lock()
+	s.exposed[stream] = stop
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *session) unexpose(stream exposure) {
+	s.mu.Lock()
+	stop, ok := s.exposed[stream]
+	delete(s.exposed, stream)
+	s.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	stop()
+
+	s.server.mu.Lock()
+	delete(s.server.exposed, stream)
+	s.server.mu.Unlock()
+}
+
+// close ends the session along with every port it exposed.
+func (s *session) close() {
+	s.conn.Close()
+
+	s.mu.Lock()
+	streams := make([]exposure, 0, len(s.exposed))
+	for stream := range s.exposed {
+		streams = append(streams, stream)
+	}
+	s.mu.Unlock()
+
+	for _, stream := range streams {
+		s.unexpose(stream)
+	}
+
+	s.server.mu.Lock()
+	delete(s.server.sessions, s)
+	s.server.mu.Unlock()
+}
+
+func (s *session) acceptTCP(listener net.Listener, stream exposure) {
+	for {
+		player, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go func() {
+			host, err := s.server.await(s, stream)
+			if err != nil {
+				player.Close()
+				return
+			}
+
+			pipe(player, host)
+		}()
+	}
+}
+
+// udpRelay forwards the datagrams of every player on a UDP port
+// over a stream of its own.
+type udpRelay struct {
+	session *session
+	stream  exposure
+	conn    net.PacketConn
+	done    chan struct{}
+
+	mu      sync.Mutex
+	players map[string]*udpPlayer
+}
+
+type udpPlayer struct {
+	address  net.Addr
+	incoming chan []byte
+}
+
+func (u *udpRelay) serve() {
+	buf := make([]byte, maxDatagram)
+
+	for {
+		n, address, err := u.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+
+		u.mu.Lock()
+		player, ok := u.players[address.String()]
+		if !ok {
+			player = &udpPlayer{address: address, incoming: make(chan []byte, 64)}
+			u.players[address.String()] = player
+			go u.forward(player)
+		}
+		u.mu.Unlock()
+
+		// ? UDP may drop datagrams, so does a player that is sending
+		// ? faster than the host can take.
+		select {
+		case player.incoming <- append([]byte{}, buf[:n]...):
+		default:
+		}
+	}
+}
+
+func (u *udpRelay) forward(player *udpPlayer) {
+	defer func() {
+		u.mu.Lock()
+		delete(u.players, player.address.String())
+		u.mu.Unlock()
+	}()
+
+	host, err := u.session.server.await(u.session, u.stream)
+	if err != nil {
+		return
+	}
+	defer host.Close()
+
+	go func() {
+		buf := make([]byte, maxDatagram)
+		for {
+			n, err := readDatagram(host, buf)
+			if err != nil {
+				host.Close()
+				return
+			}
+			u.conn.WriteTo(buf[:n], player.address)
+		}
+	}()
+
+	idle := time.NewTimer(udpIdleTimeout)
+	defer idle.Stop()
+
+	for {
+		select {
+		case payload := <-player.incoming:
+			if writeDatagram(host, payload) != nil {
+				return
+			}
+			idle.Reset(udpIdleTimeout)
+		case <-idle.C:
+			return
+		case <-u.done:
+			return
+		}
+	}
+}
+
+func (u *udpRelay) close() {
+	close(u.done)
+	u.conn.Close()
+}
+
+// bufferedConn reads what the handshake reader buffered first.
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (b *bufferedConn) Read(p []byte) (int, error) {
+	return b.reader.Read(p)
+}
@@ -0,0 +1,85 @@
+package netbridge
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/rabbytesoftware/quiver/internal/infrastructure/netbridge/relay"
+)
+
+func TestRelay(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := relay.NewServer([]string{"secret"}, nil)
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Close() })
+
+	ctx := context.Background()
+	strategy := NewRelay(listener.Addr().String(), "secret")
+	nb := NewNetbridgeWith(true, strategy)
+
+	// ? The game server, which players reach through the relay on
+	// ? a port that was free a moment ago.
+	game := busyPort(t)
+	freed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	public := freed.Addr().(*net.TCPAddr).Port
+	freed.Close()
+
+	mapping := Mapping{ExternalPort: public, InternalPort: game, Protocol: "tcp"}
+	if err := strategy.AddMapping(ctx, mapping); err != nil {
+		t.Fatalf("AddMapping() returned error: %v", err)
+	}
+
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(public)), time.Second)
+	if err != nil {
+		t.Fatalf("Expected the relay to listen on %d: %v", public, err)
+	}
+	conn.Close()
+
+	if ip, err := nb.gatewayIP(ctx); err != nil || ip != "127.0.0.1" {
+		t.Errorf("Expected the relay's address as the external IP, got %q and %v", ip, err)
+	}
+	if nb.Status(ctx).Active != "relay" {
+		t.Errorf("Expected the relay to be the active strategy, got %+v", nb.Status(ctx))
+	}
+
+	if err := strategy.DeleteMapping(ctx, mapping); err != nil {
+		t.Fatalf("DeleteMapping() returned error: %v", err)
+	}
+}
+
+func TestRelay_Discover(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := relay.NewServer([]string{"secret"}, nil)
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Close() })
+
+	testCases := []struct {
+		name    string
+		address string
+		token   string
+	}{
+		{"no address", "", "secret"},
+		{"wrong token", listener.Addr().String(), "guess"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := NewRelay(tc.address, tc.token).Discover(context.Background()); !errors.Is(err, ErrNoGateway) {
+				t.Errorf("Expected ErrNoGateway, got %v", err)
+			}
+		})
+	}
+}
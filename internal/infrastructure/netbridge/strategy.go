@@ -0,0 +1,46 @@
+package netbridge
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+)
+
+var (
+	ErrDisabled    = errors.New("netbridge is disabled")
+	ErrUnavailable = errors.New("no port forwarding mechanism is available")
+	ErrNoGateway   = errors.New("no gateway found")
+)
+
+// Mapping is a single port opened on the gateway. Protocol is
+// either tcp or udp; a zero Lease asks for a permanent mapping.
+type Mapping struct {
+	ExternalPort int
+	InternalPort int
+	Protocol     port.Protocol
+	Description  string
+	Lease        time.Duration
+}
+
+// Strategy is one way of opening ports to the outside world, such
+// as UPnP IGD. Discover must succeed before any other call is made.
+type Strategy interface {
+	// Name identifies the strategy in logs and status reports.
+	Name() string
+
+	// Discover looks for a gateway speaking this protocol and
+	// fails when there is none.
+	Discover(ctx context.Context) error
+
+	ExternalIP(ctx context.Context) (string, error)
+	AddMapping(ctx context.Context, mapping Mapping) error
+	DeleteMapping(ctx context.Context, mapping Mapping) error
+}
+
+// blockerFinder is implemented by firewalls that can tell what
+// else on the host may still block the ports they open.
+type blockerFinder interface {
+	Blockers(ctx context.Context) []string
+}
@@ -0,0 +1,116 @@
+package netbridge
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+const (
+	stunBindingRequest = 0x0001
+	stunBindingSuccess = 0x0101
+	stunMagicCookie    = 0x2112A442
+	stunHeaderSize     = 20
+
+	stunMappedAddress    = 0x0001
+	stunXorMappedAddress = 0x0020
+
+	stunFamilyIPv4 = 0x01
+	stunFamilyIPv6 = 0x02
+)
+
+// stunPublicIP asks a STUN server (RFC 5389) which address the
+// binding request came from.
+func stunPublicIP(ctx context.Context, server string) (string, error) {
+	request := make([]byte, stunHeaderSize)
+	binary.BigEndian.PutUint16(request[0:2], stunBindingRequest)
+	binary.BigEndian.PutUint32(request[4:8], stunMagicCookie)
+	if _, err := rand.Read(request[8:20]); err != nil {
+		return "", fmt.Errorf("failed to generate a STUN transaction ID: %w", err)
+	}
+
+	response, err := exchange(ctx, server, request, func(response []byte) bool {
+		return len(response) >= stunHeaderSize &&
+			binary.BigEndian.Uint16(response[0:2]) == stunBindingSuccess &&
+			bytes.Equal(response[4:20], request[4:20])
+	})
+	if err != nil {
+		return "", err
+	}
+
+	ip := stunMappedIP(response)
+	if ip == nil {
+		return "", fmt.Errorf("STUN server %s did not report a mapped address", server)
+	}
+
+	return ip.String(), nil
+}
+
+// stunMappedIP reads the XOR-MAPPED-ADDRESS of a binding response,
+// or the MAPPED-ADDRESS older servers send instead.
+func stunMappedIP(response []byte) net.IP {
+	var mapped net.IP
+	attributes := response[stunHeaderSize:]
+
+	for len(attributes) >= 4 {
+		kind := binary.BigEndian.Uint16(attributes[0:2])
+		length := int(binary.BigEndian.Uint16(attributes[2:4]))
+		if len(attributes) < 4+length {
+			break
+		}
+		value := attributes[4 : 4+length]
+
+		switch kind {
+		case stunXorMappedAddress:
+			// ? Addresses are XORed with the cookie and, for IPv6,
+			// ? the transaction ID that follows it in the header.
+			if ip := stunAddress(value, response[4:20]); ip != nil {
+				return ip
+			}
+		case stunMappedAddress:
+			mapped = stunAddress(value, nil)
+		}
+
+		// ? Attributes are padded to a multiple of four bytes.
+		padded := 4 + (length+3)/4*4
+		if padded > len(attributes) {
+			break
+		}
+		attributes = attributes[padded:]
+	}
+
+	return mapped
+}
+
+func stunAddress(value []byte, mask []byte) net.IP {
+	if len(value) < 4 {
+		return nil
+	}
+
+	var size int
+	switch value[1] {
+	case stunFamilyIPv4:
+		size = net.IPv4len
+	case stunFamilyIPv6:
+		size = net.IPv6len
+	default:
+		return nil
+	}
+
+	if len(value) < 4+size {
+		return nil
+	}
+
+	ip := make(net.IP, size)
+	for i := range ip {
+		ip[i] = value[4+i]
+		if mask != nil {
+			ip[i] ^= mask[i]
+		}
+	}
+
+	return ip
+}
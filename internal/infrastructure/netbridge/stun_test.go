@@ -0,0 +1,105 @@
+package netbridge
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"testing"
+)
+
+// fakeSTUN answers binding requests with the given mapped address,
+// XORed unless plain is set.
+func fakeSTUN(t *testing.T, mapped net.IP, plain bool) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buffer := make([]byte, 1500)
+
+		for {
+			n, addr, err := conn.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+			if n < stunHeaderSize || binary.BigEndian.Uint16(buffer[0:2]) != stunBindingRequest {
+				continue
+			}
+
+			conn.WriteTo(stunResponse(buffer[4:20], mapped, plain), addr)
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func stunResponse(cookieAndID []byte, mapped net.IP, plain bool) []byte {
+	kind := uint16(stunXorMappedAddress)
+	ip := append(net.IP(nil), mapped.To4()...)
+	if plain {
+		kind = stunMappedAddress
+	} else {
+		for i := range ip {
+			ip[i] ^= cookieAndID[i]
+		}
+	}
+
+	// ? An unknown attribute with padding comes first, as real
+	// ? servers send SOFTWARE before the address.
+	software := []byte{0x80, 0x22, 0x00, 0x05, 'q', 'u', 'i', 'v', 'r', 0, 0, 0}
+	address := []byte{0x00, 0x00, 0x00, 0x08, 0x00, stunFamilyIPv4, 0x9c, 0x40}
+	binary.BigEndian.PutUint16(address[0:2], kind)
+	address = append(address, ip...)
+
+	response := make([]byte, stunHeaderSize)
+	binary.BigEndian.PutUint16(response[0:2], stunBindingSuccess)
+	binary.BigEndian.PutUint16(response[2:4], uint16(len(software)+len(address)))
+	copy(response[4:20], cookieAndID)
+
+	return append(append(response, software...), address...)
+}
+
+func TestStunPublicIP(t *testing.T) {
+	for name, plain := range map[string]bool{"xor mapped": false, "mapped": true} {
+		t.Run(name, func(t *testing.T) {
+			server := fakeSTUN(t, net.ParseIP("203.0.113.9"), plain)
+
+			ip, err := stunPublicIP(context.Background(), server)
+			if err != nil || ip != "203.0.113.9" {
+				t.Errorf("stunPublicIP() = %q, %v", ip, err)
+			}
+		})
+	}
+}
+
+func TestStunMappedIP_IPv6(t *testing.T) {
+	header := make([]byte, stunHeaderSize)
+	binary.BigEndian.PutUint32(header[4:8], stunMagicCookie)
+	copy(header[8:20], "transaction!")
+
+	expected := net.ParseIP("2001:db8::7")
+	value := []byte{0x00, stunFamilyIPv6, 0x9c, 0x40}
+	for i, b := range expected {
+		value = append(value, b^header[4+i])
+	}
+
+	attribute := []byte{0x00, 0x20, 0x00, byte(len(value))}
+	response := append(append(header, attribute...), value...)
+
+	if ip := stunMappedIP(response); !ip.Equal(expected) {
+		t.Errorf("Expected %s, got %s", expected, ip)
+	}
+}
+
+func TestStunMappedIP_Truncated(t *testing.T) {
+	response := make([]byte, stunHeaderSize)
+	response = append(response, 0x00, 0x20, 0x00, 0x08, 0x00, stunFamilyIPv4)
+
+	if ip := stunMappedIP(response); ip != nil {
+		t.Errorf("Expected no address from a truncated attribute, got %s", ip)
+	}
+}
@@ -0,0 +1,417 @@
+package netbridge
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ssdpAddress      = "239.255.255.250:1900"
+	discoveryTimeout = 2 * time.Second
+	soapTimeout      = 5 * time.Second
+)
+
+// igdDeviceTypes are searched for over SSDP, newest first.
+var igdDeviceTypes = []string{
+	"urn:schemas-upnp-org:device:InternetGatewayDevice:2",
+	"urn:schemas-upnp-org:device:InternetGatewayDevice:1",
+}
+
+// wanServiceTypes are the services that can map ports, in the
+// order they are preferred when a gateway offers several.
+var wanServiceTypes = []string{
+	"urn:schemas-upnp-org:service:WANIPConnection:2",
+	"urn:schemas-upnp-org:service:WANIPConnection:1",
+	"urn:schemas-upnp-org:service:WANPPPConnection:1",
+}
+
+// UPnP maps ports through an Internet Gateway Device found with
+// SSDP, using the SOAP actions of its WAN connection service.
+type UPnP struct {
+	// ssdpAddress is where M-SEARCH requests are sent, so tests can
+	// point discovery at a fake gateway.
+	ssdpAddress string
+	client      *http.Client
+
+	mu          sync.Mutex
+	controlURL  string
+	serviceType string
+	localIP     string
+}
+
+func NewUPnP() *UPnP {
+	return &UPnP{
+		ssdpAddress: ssdpAddress,
+		client:      &http.Client{Timeout: soapTimeout},
+	}
+}
+
+func (u *UPnP) Name() string {
+	return "upnp"
+}
+
+// Discover sends an SSDP search and keeps the first gateway whose
+// description offers a WAN connection service.
+func (u *UPnP) Discover(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, discoveryTimeout)
+	defer cancel()
+
+	locations, err := u.search(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, location := range locations {
+		controlURL, serviceType, err := u.describe(ctx, location)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		localIP, err := localAddressTo(location)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		u.mu.Lock()
+		u.controlURL, u.serviceType, u.localIP = controlURL, serviceType, localIP
+		u.mu.Unlock()
+
+		return nil
+	}
+
+	return fmt.Errorf("%w: %w", ErrNoGateway, errors.Join(errs...))
+}
+
+func (u *UPnP) ExternalIP(ctx context.Context) (string, error) {
+	values, err := u.call(ctx, "GetExternalIPAddress", nil)
+	if err != nil {
+		return "", err
+	}
+
+	ip := values["NewExternalIPAddress"]
+	if net.ParseIP(ip) == nil {
+		return "", fmt.Errorf("gateway returned an invalid external IP %q", ip)
+	}
+
+	return ip, nil
+}
+
+func (u *UPnP) AddMapping(ctx context.Context, mapping Mapping) error {
+	u.mu.Lock()
+	localIP := u.localIP
+	u.mu.Unlock()
+
+	add := func(lease time.Duration) error {
+		_, err := u.call(ctx, "AddPortMapping", [][2]string{
+			{"NewRemoteHost", ""},
+			{"NewExternalPort", strconv.Itoa(mapping.ExternalPort)},
+			{"NewProtocol", strings.ToUpper(mapping.Protocol.String())},
+			{"NewInternalPort", strconv.Itoa(mapping.InternalPort)},
+			{"NewInternalClient", localIP},
+			{"NewEnabled", "1"},
+			{"NewPortMappingDescription", mapping.Description},
+			{"NewLeaseDuration", strconv.Itoa(int(lease.Seconds()))},
+		})
+		return err
+	}
+
+	err := add(mapping.Lease)
+
+	// ? Some gateways only accept permanent mappings; those are
+	// ? still re-added on every renewal.
+	var upnpErr *upnpError
+	if errors.As(err, &upnpErr) && upnpErr.code == upnpOnlyPermanentLeases {
+		err = add(0)
+	}
+
+	return err
+}
+
+func (u *UPnP) DeleteMapping(ctx context.Context, mapping Mapping) error {
+	_, err := u.call(ctx, "DeletePortMapping", [][2]string{
+		{"NewRemoteHost", ""},
+		{"NewExternalPort", strconv.Itoa(mapping.ExternalPort)},
+		{"NewProtocol", strings.ToUpper(mapping.Protocol.String())},
+	})
+
+	return err
+}
+
+// search multicasts an M-SEARCH for every gateway device type and
+// collects the description URLs of the devices that answer before
+// ctx expires.
+func (u *UPnP) search(ctx context.Context) ([]string, error) {
+	conn, err := net.ListenPacket("udp4", ":0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SSDP socket: %w", err)
+	}
+	defer conn.Close()
+
+	target, err := net.ResolveUDPAddr("udp4", u.ssdpAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", u.ssdpAddress, err)
+	}
+
+	for _, deviceType := range igdDeviceTypes {
+		request := "M-SEARCH * HTTP/1.1\r\n" +
+			"HOST: " + ssdpAddress + "\r\n" +
+			"ST: " + deviceType + "\r\n" +
+			"MAN: \"ssdp:discover\"\r\n" +
+			"MX: 2\r\n\r\n"
+
+		if _, err := conn.WriteTo([]byte(request), target); err != nil {
+			return nil, fmt.Errorf("failed to send SSDP search: %w", err)
+		}
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetReadDeadline(deadline)
+	}
+
+	var locations []string
+	seen := map[string]bool{}
+	buffer := make([]byte, 2048)
+
+	for {
+		n, _, err := conn.ReadFrom(buffer)
+		if err != nil {
+			break
+		}
+
+		response, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(buffer[:n])), nil)
+		if err != nil {
+			continue
+		}
+		response.Body.Close()
+
+		location := response.Header.Get("Location")
+		if location != "" && !seen[location] {
+			seen[location] = true
+			locations = append(locations, location)
+
+			// ? One gateway is enough, but a short grace period
+			// ? lets a second device answer when there are several.
+			conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+		}
+	}
+
+	if len(locations) == 0 {
+		return nil, fmt.Errorf("%w: no answer to SSDP search", ErrNoGateway)
+	}
+
+	return locations, nil
+}
+
+type igdRoot struct {
+	URLBase string    `xml:"URLBase"`
+	Device  igdDevice `xml:"device"`
+}
+
+type igdDevice struct {
+	DeviceType string       `xml:"deviceType"`
+	Services   []igdService `xml:"serviceList>service"`
+	Devices    []igdDevice  `xml:"deviceList>device"`
+}
+
+type igdService struct {
+	ServiceType string `xml:"serviceType"`
+	ControlURL  string `xml:"controlURL"`
+}
+
+// describe fetches a device description and returns the absolute
+// control URL of its preferred WAN connection service.
+func (u *UPnP) describe(ctx context.Context, location string) (string, string, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return "", "", err
+	}
+
+	response, err := u.client.Do(request)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to fetch %s: %w", location, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("failed to fetch %s: %s", location, response.Status)
+	}
+
+	var root igdRoot
+	if err := xml.NewDecoder(response.Body).Decode(&root); err != nil {
+		return "", "", fmt.Errorf("failed to parse %s: %w", location, err)
+	}
+
+	base, err := url.Parse(location)
+	if err != nil {
+		return "", "", err
+	}
+	if root.URLBase != "" {
+		if base, err = url.Parse(root.URLBase); err != nil {
+			return "", "", err
+		}
+	}
+
+	services := map[string]string{}
+	collectServices(root.Device, services)
+
+	for _, serviceType := range wanServiceTypes {
+		if controlURL, ok := services[serviceType]; ok {
+			resolved, err := base.Parse(controlURL)
+			if err != nil {
+				return "", "", err
+			}
+
+			return resolved.String(), serviceType, nil
+		}
+	}
+
+	return "", "", fmt.Errorf("%s offers no WAN connection service", location)
+}
+
+func collectServices(device igdDevice, services map[string]string) {
+	for _, service := range device.Services {
+		if _, ok := services[service.ServiceType]; !ok {
+			services[service.ServiceType] = service.ControlURL
+		}
+	}
+
+	for _, child := range device.Devices {
+		collectServices(child, services)
+	}
+}
+
+// call invokes a SOAP action on the discovered WAN connection
+// service. Arguments are sent in order, as some gateways insist.
+func (u *UPnP) call(ctx context.Context, action string, arguments [][2]string) (map[string]string, error) {
+	u.mu.Lock()
+	controlURL, serviceType := u.controlURL, u.serviceType
+	u.mu.Unlock()
+
+	if controlURL == "" {
+		return nil, ErrNoGateway
+	}
+
+	var body bytes.Buffer
+	body.WriteString(`<?xml version="1.0"?>` +
+		`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" ` +
+		`s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/"><s:Body>`)
+	fmt.Fprintf(&body, `<u:%s xmlns:u="%s">`, action, serviceType)
+	for _, argument := range arguments {
+		fmt.Fprintf(&body, "<%s>", argument[0])
+		xml.EscapeText(&body, []byte(argument[1]))
+		fmt.Fprintf(&body, "</%s>", argument[0])
+	}
+	fmt.Fprintf(&body, `</u:%s></s:Body></s:Envelope>`, action)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, controlURL, &body)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", `text/xml; charset="utf-8"`)
+	request.Header.Set("SOAPAction", fmt.Sprintf(`"%s#%s"`, serviceType, action))
+
+	response, err := u.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("%s failed: %w", action, err)
+	}
+	defer response.Body.Close()
+
+	values, err := soapValues(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%s returned an invalid response: %w", action, err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		if code, ok := values["errorCode"]; ok {
+			return nil, &upnpError{action: action, code: code, description: values["errorDescription"]}
+		}
+
+		return nil, fmt.Errorf("%s failed: %s", action, response.Status)
+	}
+
+	return values, nil
+}
+
+// soapValues flattens a SOAP envelope into the text of its leaf
+// elements, which is all the IGD actions and faults return.
+func soapValues(body io.Reader) (map[string]string, error) {
+	decoder := xml.NewDecoder(body)
+	values := map[string]string{}
+
+	var name string
+	var text strings.Builder
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return values, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			name = t.Name.Local
+			text.Reset()
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			if t.Name.Local == name {
+				values[name] = strings.TrimSpace(text.String())
+			}
+			name = ""
+		}
+	}
+}
+
+// localAddressTo returns the local IP the host uses to reach the
+// gateway at rawURL, which is the address ports are mapped to.
+func localAddressTo(rawURL string) (string, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	port := parsed.Port()
+	if port == "" {
+		port = "80"
+	}
+
+	ip, err := localAddressFor(net.JoinHostPort(parsed.Hostname(), port))
+	if err != nil {
+		return "", err
+	}
+
+	return ip.String(), nil
+}
+
+// upnpOnlyPermanentLeases is the error code of gateways that reject
+// mappings with a lease duration.
+const upnpOnlyPermanentLeases = "725"
+
+// upnpError is a fault returned by the gateway for a SOAP action.
+type upnpError struct {
+	action      string
+	code        string
+	description string
+}
+
+func (e *upnpError) Error() string {
+	return fmt.Sprintf("%s failed: UPnP error %s %s", e.action, e.code, e.description)
+}
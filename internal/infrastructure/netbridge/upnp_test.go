@@ -0,0 +1,320 @@
+package netbridge
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+)
+
+const fakeDescription = `<?xml version="1.0"?>
+<root xmlns="urn:schemas-upnp-org:device-1-0">
+  <device>
+    <deviceType>urn:schemas-upnp-org:device:InternetGatewayDevice:1</deviceType>
+    <deviceList>
+      <device>
+        <deviceType>urn:schemas-upnp-org:device:WANDevice:1</deviceType>
+        <deviceList>
+          <device>
+            <deviceType>urn:schemas-upnp-org:device:WANConnectionDevice:1</deviceType>
+            <serviceList>
+              <service>
+                <serviceType>urn:schemas-upnp-org:service:WANPPPConnection:1</serviceType>
+                <controlURL>/ctl/PPPConn</controlURL>
+              </service>
+              <service>
+                <serviceType>urn:schemas-upnp-org:service:WANIPConnection:1</serviceType>
+                <controlURL>/ctl/IPConn</controlURL>
+              </service>
+            </serviceList>
+          </device>
+        </deviceList>
+      </device>
+    </deviceList>
+  </device>
+</root>`
+
+// fakeIGD answers SSDP searches on a local UDP socket and serves a
+// gateway description and WANIPConnection control endpoint.
+type fakeIGD struct {
+	ssdp   net.PacketConn
+	server *httptest.Server
+
+	mu       sync.Mutex
+	mappings map[string]map[string]string
+	actions  []string
+
+	// permanentOnly rejects leases like gateways answering 725.
+	permanentOnly bool
+}
+
+func newFakeIGD(t *testing.T) *fakeIGD {
+	t.Helper()
+
+	igd := &fakeIGD{mappings: map[string]map[string]string{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rootDesc.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(fakeDescription))
+	})
+	mux.HandleFunc("/ctl/IPConn", igd.control)
+	igd.server = httptest.NewServer(mux)
+	t.Cleanup(igd.server.Close)
+
+	ssdp, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	igd.ssdp = ssdp
+	t.Cleanup(func() { ssdp.Close() })
+
+	go igd.answer()
+
+	return igd
+}
+
+func (f *fakeIGD) answer() {
+	buffer := make([]byte, 2048)
+
+	for {
+		n, addr, err := f.ssdp.ReadFrom(buffer)
+		if err != nil {
+			return
+		}
+
+		request := string(buffer[:n])
+		if !strings.HasPrefix(request, "M-SEARCH") || !strings.Contains(request, "InternetGatewayDevice:1") {
+			continue
+		}
+
+		response := "HTTP/1.1 200 OK\r\n" +
+			"CACHE-CONTROL: max-age=120\r\n" +
+			"ST: urn:schemas-upnp-org:device:InternetGatewayDevice:1\r\n" +
+			"LOCATION: " + f.server.URL + "/rootDesc.xml\r\n\r\n"
+		f.ssdp.WriteTo([]byte(response), addr)
+	}
+}
+
+func (f *fakeIGD) control(w http.ResponseWriter, r *http.Request) {
+	action := r.Header.Get("SOAPAction")
+	body, _ := io.ReadAll(r.Body)
+	values, _ := soapValues(strings.NewReader(string(body)))
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.actions = append(f.actions, action)
+	key := values["NewExternalPort"] + "/" + values["NewProtocol"]
+
+	switch {
+	case strings.HasSuffix(action, `#GetExternalIPAddress"`):
+		soapResponse(w, "GetExternalIPAddress", "<NewExternalIPAddress>203.0.113.7</NewExternalIPAddress>")
+
+	case strings.HasSuffix(action, `#AddPortMapping"`):
+		if existing, ok := f.mappings[key]; ok && existing["NewInternalClient"] != values["NewInternalClient"] {
+			soapFault(w, 718, "ConflictInMappingEntry")
+			return
+		}
+		if f.permanentOnly && values["NewLeaseDuration"] != "0" {
+			soapFault(w, 725, "OnlyPermanentLeasesSupported")
+			return
+		}
+		f.mappings[key] = values
+		soapResponse(w, "AddPortMapping", "")
+
+	case strings.HasSuffix(action, `#DeletePortMapping"`):
+		if _, ok := f.mappings[key]; !ok {
+			soapFault(w, 714, "NoSuchEntryInArray")
+			return
+		}
+		delete(f.mappings, key)
+		soapResponse(w, "DeletePortMapping", "")
+
+	default:
+		soapFault(w, 401, "Invalid Action")
+	}
+}
+
+func soapResponse(w http.ResponseWriter, action, body string) {
+	fmt.Fprintf(w, `<?xml version="1.0"?><s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body>`+
+		`<u:%sResponse xmlns:u="urn:schemas-upnp-org:service:WANIPConnection:1">%s</u:%sResponse>`+
+		`</s:Body></s:Envelope>`, action, body, action)
+}
+
+func soapFault(w http.ResponseWriter, code int, description string) {
+	w.WriteHeader(http.StatusInternalServerError)
+	fmt.Fprintf(w, `<?xml version="1.0"?><s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body>`+
+		`<s:Fault><faultcode>s:Client</faultcode><faultstring>UPnPError</faultstring><detail>`+
+		`<UPnPError xmlns="urn:schemas-upnp-org:control-1-0"><errorCode>%d</errorCode>`+
+		`<errorDescription>%s</errorDescription></UPnPError></detail></s:Fault></s:Body></s:Envelope>`,
+		code, description)
+}
+
+func discoveredUPnP(t *testing.T, igd *fakeIGD) *UPnP {
+	t.Helper()
+
+	upnp := NewUPnP()
+	upnp.ssdpAddress = igd.ssdp.LocalAddr().String()
+
+	if err := upnp.Discover(context.Background()); err != nil {
+		t.Fatalf("Discover() returned error: %v", err)
+	}
+
+	return upnp
+}
+
+func TestUPnP_Discover(t *testing.T) {
+	igd := newFakeIGD(t)
+	upnp := discoveredUPnP(t, igd)
+
+	if upnp.controlURL != igd.server.URL+"/ctl/IPConn" {
+		t.Errorf("Expected the WANIPConnection control URL, got %q", upnp.controlURL)
+	}
+	if upnp.serviceType != "urn:schemas-upnp-org:service:WANIPConnection:1" {
+		t.Errorf("Expected WANIPConnection to be preferred over WANPPPConnection, got %q", upnp.serviceType)
+	}
+	if upnp.localIP != "127.0.0.1" {
+		t.Errorf("Expected the local address towards the gateway, got %q", upnp.localIP)
+	}
+}
+
+func TestUPnP_Discover_NoGateway(t *testing.T) {
+	silent, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer silent.Close()
+
+	upnp := NewUPnP()
+	upnp.ssdpAddress = silent.LocalAddr().String()
+
+	if err := upnp.Discover(context.Background()); !errors.Is(err, ErrNoGateway) {
+		t.Errorf("Expected ErrNoGateway, got %v", err)
+	}
+	if _, err := upnp.ExternalIP(context.Background()); !errors.Is(err, ErrNoGateway) {
+		t.Errorf("Expected calls before discovery to fail, got %v", err)
+	}
+}
+
+func TestUPnP_ExternalIP(t *testing.T) {
+	upnp := discoveredUPnP(t, newFakeIGD(t))
+
+	ip, err := upnp.ExternalIP(context.Background())
+	if err != nil || ip != "203.0.113.7" {
+		t.Errorf("ExternalIP() = %q, %v", ip, err)
+	}
+}
+
+func TestUPnP_Mappings(t *testing.T) {
+	igd := newFakeIGD(t)
+	upnp := discoveredUPnP(t, igd)
+	ctx := context.Background()
+
+	mapping := Mapping{
+		ExternalPort: 40130,
+		InternalPort: 40130,
+		Protocol:     port.ProtocolUDP,
+		Description:  "quiver <cs2>",
+	}
+
+	if err := upnp.AddMapping(ctx, mapping); err != nil {
+		t.Fatalf("AddMapping() returned error: %v", err)
+	}
+
+	added, ok := igd.mappings["40130/UDP"]
+	if !ok {
+		t.Fatalf("Expected the gateway to hold 40130/UDP, got %v", igd.mappings)
+	}
+	if added["NewInternalClient"] != "127.0.0.1" || added["NewInternalPort"] != "40130" ||
+		added["NewPortMappingDescription"] != "quiver <cs2>" || added["NewLeaseDuration"] != "0" {
+		t.Errorf("Unexpected mapping arguments: %v", added)
+	}
+
+	igd.mappings["40131/TCP"] = map[string]string{"NewInternalClient": "192.168.1.20"}
+	err := upnp.AddMapping(ctx, Mapping{ExternalPort: 40131, InternalPort: 40131, Protocol: port.ProtocolTCP})
+	if err == nil || !strings.Contains(err.Error(), "718") {
+		t.Errorf("Expected the UPnP error code of a conflicting mapping, got %v", err)
+	}
+
+	if err := upnp.DeleteMapping(ctx, mapping); err != nil {
+		t.Fatalf("DeleteMapping() returned error: %v", err)
+	}
+	if _, ok := igd.mappings["40130/UDP"]; ok {
+		t.Error("Expected the mapping to be deleted")
+	}
+	if err := upnp.DeleteMapping(ctx, mapping); err == nil {
+		t.Error("DeleteMapping() should report a mapping the gateway does not hold")
+	}
+}
+
+func TestNetbridgeImpl_UPnP(t *testing.T) {
+	igd := newFakeIGD(t)
+
+	upnp := NewUPnP()
+	upnp.ssdpAddress = igd.ssdp.LocalAddr().String()
+	nb := NewNetbridgeWith(true, upnp)
+	ctx := context.Background()
+
+	if !nb.IsAvailable() {
+		t.Fatal("Expected the fake gateway to be found")
+	}
+
+	if _, err := nb.ForwardPort(ctx, tcpRule(40130)); err != nil {
+		t.Fatalf("ForwardPort() returned error: %v", err)
+	}
+	if _, ok := igd.mappings["40130/TCP"]; !ok {
+		t.Errorf("Expected ForwardPort to open the port on the gateway, got %v", igd.mappings)
+	}
+
+	if _, err := nb.ReversePort(ctx, tcpRule(40130)); err != nil {
+		t.Fatalf("ReversePort() returned error: %v", err)
+	}
+	if len(igd.mappings) != 0 {
+		t.Errorf("Expected ReversePort to close the port, got %v", igd.mappings)
+	}
+}
+
+func TestSoapValues(t *testing.T) {
+	values, err := soapValues(strings.NewReader(`<Envelope><Body><Response>` +
+		`<NewExternalIPAddress> 203.0.113.7 </NewExternalIPAddress><Empty></Empty>` +
+		`</Response></Body></Envelope>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if values["NewExternalIPAddress"] != "203.0.113.7" {
+		t.Errorf("Expected the trimmed IP, got %q", values["NewExternalIPAddress"])
+	}
+	if _, ok := values["Empty"]; !ok {
+		t.Error("Expected empty elements to be kept")
+	}
+
+	var syntax *xml.SyntaxError
+	if _, err := soapValues(strings.NewReader("<Envelope>")); !errors.As(err, &syntax) {
+		t.Errorf("Expected a syntax error for a truncated envelope, got %v", err)
+	}
+}
+
+func TestUPnP_AddMapping_PermanentOnly(t *testing.T) {
+	igd := newFakeIGD(t)
+	igd.permanentOnly = true
+	upnp := discoveredUPnP(t, igd)
+
+	mapping := Mapping{ExternalPort: 40130, InternalPort: 40130, Protocol: port.ProtocolTCP, Lease: mappingLease}
+	if err := upnp.AddMapping(context.Background(), mapping); err != nil {
+		t.Fatalf("AddMapping() returned error: %v", err)
+	}
+
+	if lease := igd.mappings["40130/TCP"]["NewLeaseDuration"]; lease != "0" {
+		t.Errorf("Expected a permanent mapping after error 725, got lease %q", lease)
+	}
+}
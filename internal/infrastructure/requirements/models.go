@@ -15,6 +15,18 @@ type SRVInterface interface {
 		requirements *requirement.Requirement,
 	) (bool, error)
 
+	// Probe detects what the host offers.
+	Probe(
+		ctx context.Context,
+	) *requirement.Host
+	// Check compares requirements with what the host has left
+	// once committed resources are taken off, and explains every
+	// requirement that is not met.
+	Check(
+		ctx context.Context,
+		requirements *requirement.Requirement,
+		committed requirement.Tier,
+	) *requirement.Report
 	ValidateOS(
 		ctx context.Context,
 		recommendedOS system.OS,
@@ -44,4 +56,11 @@ type SRVInterface interface {
 		ctx context.Context,
 		recommendedNetwork int,
 	) (bool, error)
+
+	// AvailableDisk returns the free space in MB on the filesystem
+	// holding path, or its nearest existing parent.
+	AvailableDisk(
+		ctx context.Context,
+		path string,
+	) (int, error)
 }
@@ -0,0 +1,52 @@
+//go:build linux
+
+package requirements
+
+import (
+	"path/filepath"
+	goruntime "runtime"
+
+	"github.com/rabbytesoftware/quiver/internal/models/requirement"
+)
+
+// probeSystem fills in what procfs and sysfs tell about the host.
+func (r *Requirements) probeSystem(host *requirement.Host) {
+	if cores, model, err := cpuInfo(r.path(cpuinfoPath)); err != nil {
+		host.Errors["cpu"] = err.Error()
+		host.CpuCores = goruntime.NumCPU()
+	} else {
+		// cpuinfo lists every processor of the machine, while the
+		// affinity mask limits how many Quiver may actually use.
+		host.CpuCores = min(cores, goruntime.NumCPU())
+		host.CpuModel = model
+	}
+
+	if fields, err := readMeminfo(r.path(meminfoPath)); err != nil {
+		host.Errors["memory"] = err.Error()
+	} else {
+		host.MemoryTotal = fields["MemTotal"]
+		host.MemoryAvailable = fields["MemAvailable"]
+	}
+
+	if version, err := kernelVersion(r.path(osreleasePath)); err != nil {
+		host.Errors["kernel"] = err.Error()
+	} else {
+		host.KernelVersion = version
+	}
+
+	if name, err := distribution(r.path(osReleasePath)); err != nil {
+		host.Errors["distribution"] = err.Error()
+	} else {
+		host.Distribution = name
+	}
+
+	if speed, err := linkSpeed(r.path(netClassPath)); err != nil {
+		host.Errors["network"] = err.Error()
+	} else {
+		host.NetworkSpeed = speed
+	}
+}
+
+func (r *Requirements) path(path string) string {
+	return filepath.Join(r.root, path)
+}
@@ -0,0 +1,159 @@
+//go:build linux
+
+package requirements
+
+import (
+	"context"
+	"path/filepath"
+	goruntime "runtime"
+	"testing"
+
+	"github.com/rabbytesoftware/quiver/internal/models/requirement"
+	"github.com/rabbytesoftware/quiver/internal/models/system"
+)
+
+// fakeHost writes the procfs and sysfs files of a four core host
+// with 16 GB of memory and a gigabit link.
+func fakeHost(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+
+	files := map[string]string{
+		cpuinfoPath: "processor\t: 0\nmodel name\t: AMD EPYC 7763\n\nprocessor\t: 1\n\n" +
+			"processor\t: 2\n\nprocessor\t: 3\n",
+		meminfoPath:   "MemTotal:       16384000 kB\nMemAvailable:    8192000 kB\n",
+		osreleasePath: "6.8.0-45-generic\n",
+		osReleasePath: "NAME=\"Ubuntu\"\nPRETTY_NAME=\"Ubuntu 24.04.1 LTS\"\n",
+		filepath.Join(netClassPath, "lo", "operstate"):   "unknown\n",
+		filepath.Join(netClassPath, "eth0", "operstate"): "up\n",
+		filepath.Join(netClassPath, "eth0", "speed"):     "1000\n",
+		filepath.Join(netClassPath, "eth1", "operstate"): "down\n",
+		filepath.Join(netClassPath, "eth1", "speed"):     "10000\n",
+	}
+
+	for path, content := range files {
+		writeFileAt(t, filepath.Join(root, path), content)
+	}
+
+	return root
+}
+
+func TestRequirements_Probe(t *testing.T) {
+	host := (&Requirements{root: fakeHost(t)}).Probe(context.Background())
+
+	if host.OS != system.CurrentOS() || host.Arch != goruntime.GOARCH {
+		t.Errorf("Expected the host platform, got %s %s", host.OS, host.Arch)
+	}
+	if host.CpuCores != min(4, goruntime.NumCPU()) || host.CpuModel != "AMD EPYC 7763" {
+		t.Errorf("Expected four EPYC cores, got %d %q", host.CpuCores, host.CpuModel)
+	}
+	if host.MemoryTotal != 16000 || host.MemoryAvailable != 8000 {
+		t.Errorf("Expected 16000 MB with 8000 MB available, got %d/%d", host.MemoryTotal, host.MemoryAvailable)
+	}
+	if host.KernelVersion != "6.8.0-45-generic" || host.Distribution != "Ubuntu 24.04.1 LTS" {
+		t.Errorf("Expected Ubuntu on 6.8, got %q on %q", host.Distribution, host.KernelVersion)
+	}
+	if host.NetworkSpeed != 1000 {
+		t.Errorf("Expected the link that is up to be used, got %d", host.NetworkSpeed)
+	}
+	if len(host.Errors) != 0 {
+		t.Errorf("Expected no errors, got %v", host.Errors)
+	}
+}
+
+func TestRequirements_Probe_Unreadable(t *testing.T) {
+	host := (&Requirements{root: t.TempDir()}).Probe(context.Background())
+
+	if host.CpuCores != goruntime.NumCPU() {
+		t.Errorf("Expected the core count to fall back to the runtime, got %d", host.CpuCores)
+	}
+
+	for _, key := range []string{"cpu", "memory", "kernel", "distribution", "network"} {
+		if host.Errors[key] == "" {
+			t.Errorf("Expected an error for %s, got %v", key, host.Errors)
+		}
+	}
+}
+
+func TestRequirements_Check(t *testing.T) {
+	req := &Requirements{root: fakeHost(t)}
+
+	report := req.Check(context.Background(), &requirement.Requirement{
+		CpuCores: 1,
+		Memory:   12000,
+		Disk:     1,
+		OS:       system.CurrentOS(),
+	}, requirement.Tier{})
+
+	if report.Passed {
+		t.Fatal("Expected the report to fail on memory")
+	}
+
+	failed := report.Failed()
+	if len(failed) != 1 || failed[0].Name != "memory_mb" {
+		t.Fatalf("Expected only memory to fail, got %+v", failed)
+	}
+	if failed[0].Required != "12000" || failed[0].Detected != "8000" {
+		t.Errorf("Expected the required and detected memory, got %+v", failed[0])
+	}
+
+	committed := req.Check(context.Background(), &requirement.Requirement{
+		CpuCores: 1,
+		Memory:   6000,
+		Disk:     1,
+		OS:       system.CurrentOS(),
+	}, requirement.Tier{Memory: 12000})
+	if committed.Passed {
+		t.Error("Expected memory committed to other instances not to count as free")
+	}
+
+	broken := (&Requirements{root: t.TempDir()}).Check(context.Background(), &requirement.Requirement{
+		CpuCores: 1,
+		Memory:   1,
+		Disk:     1,
+		OS:       system.CurrentOS(),
+	}, requirement.Tier{})
+	for _, check := range broken.Checks {
+		if check.Name == "memory_mb" && (check.Passed || !check.Unknown() || check.Detected != "") {
+			t.Errorf("Expected an undetectable value to be unknown with its error, got %+v", check)
+		}
+	}
+	if len(broken.Warnings) == 0 {
+		t.Error("Expected undetectable values to be reported as warnings")
+	}
+}
+
+func TestRequirements_ValidateOSVersion(t *testing.T) {
+	req := &Requirements{root: fakeHost(t)}
+	ctx := context.Background()
+
+	valid, err := req.ValidateOSVersion(ctx, "6.8")
+	if err != nil || !valid {
+		t.Errorf("ValidateOSVersion(6.8) = %v, %v, expected the kernel to be recent enough", valid, err)
+	}
+
+	valid, _ = req.ValidateOSVersion(ctx, "6.9")
+	if valid {
+		t.Error("ValidateOSVersion() should reject a newer kernel than the host runs")
+	}
+}
+
+func TestRequirements_ValidateNetwork(t *testing.T) {
+	req := &Requirements{root: fakeHost(t)}
+	ctx := context.Background()
+
+	valid, err := req.ValidateNetwork(ctx, 1000)
+	if err != nil || !valid {
+		t.Errorf("ValidateNetwork(1000) = %v, %v, expected the gigabit link to be enough", valid, err)
+	}
+
+	valid, _ = req.ValidateNetwork(ctx, 10000)
+	if valid {
+		t.Error("ValidateNetwork() should reject a faster link than the host has")
+	}
+
+	_, err = (&Requirements{root: t.TempDir()}).ValidateNetwork(ctx, 1000)
+	if err == nil {
+		t.Error("ValidateNetwork() should fail when no link speed is known")
+	}
+}
@@ -0,0 +1,19 @@
+//go:build !linux
+
+package requirements
+
+import (
+	"errors"
+	goruntime "runtime"
+
+	"github.com/rabbytesoftware/quiver/internal/models/requirement"
+)
+
+// probeSystem only knows the CPU count outside of Linux.
+func (r *Requirements) probeSystem(host *requirement.Host) {
+	host.CpuCores = goruntime.NumCPU()
+
+	for _, key := range []string{"memory", "kernel", "distribution", "network"} {
+		host.Errors[key] = errors.ErrUnsupported.Error()
+	}
+}
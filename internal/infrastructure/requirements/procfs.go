@@ -0,0 +1,223 @@
+package requirements
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	cpuinfoPath   = "/proc/cpuinfo"
+	meminfoPath   = "/proc/meminfo"
+	osreleasePath = "/proc/sys/kernel/osrelease"
+	osReleasePath = "/etc/os-release"
+	netClassPath  = "/sys/class/net"
+)
+
+var errNoLinkSpeed = errors.New("no network interface reports its link speed")
+
+// cpuInfo counts the processors listed in a cpuinfo file and returns
+// the model name of the first one.
+func cpuInfo(path string) (int, string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to read cpu information: %w", err)
+	}
+	defer file.Close()
+
+	cores, model := 0, ""
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+
+		switch strings.TrimSpace(key) {
+		case "processor":
+			cores++
+		case "model name":
+			if model == "" {
+				model = strings.TrimSpace(value)
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return 0, "", fmt.Errorf("failed to read cpu information: %w", err)
+	}
+
+	if cores == 0 {
+		return 0, "", fmt.Errorf("no processors found in %s", path)
+	}
+
+	return cores, model, nil
+}
+
+// readMeminfo returns the fields of a meminfo file in MB.
+func readMeminfo(path string) (map[string]int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read memory information: %w", err)
+	}
+	defer file.Close()
+
+	fields := map[string]int{}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		parts := strings.Fields(scanner.Text())
+		if len(parts) < 2 {
+			continue
+		}
+
+		kb, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value %q", parts[0], parts[1])
+		}
+
+		fields[strings.TrimSuffix(parts[0], ":")] = kb / 1024
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read memory information: %w", err)
+	}
+
+	return fields, nil
+}
+
+// availableMemory reads MemAvailable from a meminfo file in MB.
+func availableMemory(path string) (int, error) {
+	fields, err := readMeminfo(path)
+	if err != nil {
+		return 0, err
+	}
+
+	available, ok := fields["MemAvailable"]
+	if !ok {
+		return 0, fmt.Errorf("MemAvailable not found in %s", path)
+	}
+
+	return available, nil
+}
+
+// kernelVersion reads the running kernel release, e.g. 6.8.0-45-generic.
+func kernelVersion(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read kernel version: %w", err)
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// distribution reads the PRETTY_NAME of an os-release file.
+func distribution(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read os release: %w", err)
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if value, ok := strings.CutPrefix(line, "PRETTY_NAME="); ok {
+			return strings.Trim(value, `"'`), nil
+		}
+	}
+
+	return "", fmt.Errorf("PRETTY_NAME not found in %s", path)
+}
+
+// linkSpeed returns the fastest link speed in Mbit/s of the network
+// interfaces under a sysfs net class directory. Loopback, interfaces
+// that are down and virtual ones, which report no speed, are skipped.
+func linkSpeed(dir string) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read network interfaces: %w", err)
+	}
+
+	fastest := 0
+	for _, entry := range entries {
+		if entry.Name() == "lo" {
+			continue
+		}
+
+		state, err := os.ReadFile(filepath.Join(dir, entry.Name(), "operstate"))
+		if err != nil || strings.TrimSpace(string(state)) != "up" {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name(), "speed"))
+		if err != nil {
+			continue
+		}
+
+		speed, err := strconv.Atoi(strings.TrimSpace(string(data)))
+		if err != nil || speed <= 0 {
+			continue
+		}
+
+		fastest = max(fastest, speed)
+	}
+
+	if fastest == 0 {
+		return 0, errNoLinkSpeed
+	}
+
+	return fastest, nil
+}
+
+// versionAtLeast compares the leading dotted numbers of two versions,
+// so 6.8.0-45-generic is at least 6.8 and 5.15 is not.
+func versionAtLeast(version, minimum string) (bool, error) {
+	have, err := versionNumbers(version)
+	if err != nil {
+		return false, err
+	}
+
+	want, err := versionNumbers(minimum)
+	if err != nil {
+		return false, err
+	}
+
+	for i := 0; i < max(len(have), len(want)); i++ {
+		h, w := 0, 0
+		if i < len(have) {
+			h = have[i]
+		}
+		if i < len(want) {
+			w = want[i]
+		}
+
+		if h != w {
+			return h > w, nil
+		}
+	}
+
+	return true, nil
+}
+
+func versionNumbers(version string) ([]int, error) {
+	end := strings.IndexFunc(version, func(r rune) bool {
+		return r != '.' && (r < '0' || r > '9')
+	})
+	if end >= 0 {
+		version = version[:end]
+	}
+
+	var numbers []int
+	for _, part := range strings.Split(strings.Trim(version, "."), ".") {
+		number, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version %q", version)
+		}
+		numbers = append(numbers, number)
+	}
+
+	return numbers, nil
+}
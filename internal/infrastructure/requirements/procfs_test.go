@@ -0,0 +1,105 @@
+package requirements
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeProcFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+
+	return path
+}
+
+func TestCpuInfo(t *testing.T) {
+	cores, model, err := cpuInfo(writeProcFile(t,
+		"processor\t: 0\nmodel name\t: Intel(R) Xeon(R)\n\nprocessor\t: 1\nmodel name\t: Intel(R) Xeon(R)\n",
+	))
+	if err != nil || cores != 2 || model != "Intel(R) Xeon(R)" {
+		t.Errorf("cpuInfo() = %d, %q, %v", cores, model, err)
+	}
+
+	// ARM cpuinfo has no model name per processor.
+	cores, model, err = cpuInfo(writeProcFile(t, "processor\t: 0\nBogoMIPS\t: 50.00\n"))
+	if err != nil || cores != 1 || model != "" {
+		t.Errorf("cpuInfo() = %d, %q, %v", cores, model, err)
+	}
+
+	if _, _, err := cpuInfo(writeProcFile(t, "")); err == nil {
+		t.Error("cpuInfo() should fail without processors")
+	}
+}
+
+func TestReadMeminfo(t *testing.T) {
+	fields, err := readMeminfo(writeProcFile(t, "MemTotal:       2048000 kB\nHugePages_Total:       0\n"))
+	if err != nil || fields["MemTotal"] != 2000 {
+		t.Errorf("readMeminfo() = %v, %v", fields, err)
+	}
+
+	if _, err := readMeminfo(writeProcFile(t, "MemTotal: lots kB\n")); err == nil {
+		t.Error("readMeminfo() should reject invalid values")
+	}
+}
+
+func TestDistribution(t *testing.T) {
+	name, err := distribution(writeProcFile(t, "ID=debian\nPRETTY_NAME=\"Debian GNU/Linux 12 (bookworm)\"\n"))
+	if err != nil || name != "Debian GNU/Linux 12 (bookworm)" {
+		t.Errorf("distribution() = %q, %v", name, err)
+	}
+
+	if _, err := distribution(writeProcFile(t, "ID=alpine\n")); err == nil {
+		t.Error("distribution() should fail without PRETTY_NAME")
+	}
+}
+
+func TestLinkSpeed_VirtualOnly(t *testing.T) {
+	dir := t.TempDir()
+	writeFileAt(t, filepath.Join(dir, "veth0", "operstate"), "up\n")
+	writeFileAt(t, filepath.Join(dir, "veth0", "speed"), "-1\n")
+
+	if _, err := linkSpeed(dir); err != errNoLinkSpeed {
+		t.Errorf("Expected errNoLinkSpeed, got %v", err)
+	}
+}
+
+func TestVersionAtLeast(t *testing.T) {
+	testCases := []struct {
+		version  string
+		minimum  string
+		expected bool
+	}{
+		{"6.8.0-45-generic", "6.8", true},
+		{"6.8.0-45-generic", "6.8.1", false},
+		{"5.15.0", "6", false},
+		{"6.18.44-fc-v139", "6.2", true},
+		{"10.0", "9.9", true},
+	}
+
+	for _, tc := range testCases {
+		got, err := versionAtLeast(tc.version, tc.minimum)
+		if err != nil || got != tc.expected {
+			t.Errorf("versionAtLeast(%q, %q) = %v, %v", tc.version, tc.minimum, got, err)
+		}
+	}
+
+	if _, err := versionAtLeast("6.8", "latest"); err == nil {
+		t.Error("versionAtLeast() should reject versions without numbers")
+	}
+}
+
+func writeFileAt(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create %s: %v", path, err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
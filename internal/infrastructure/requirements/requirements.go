@@ -2,12 +2,21 @@ package requirements
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	goruntime "runtime"
 
+	"github.com/rabbytesoftware/quiver/internal/core/config"
 	"github.com/rabbytesoftware/quiver/internal/models/requirement"
 	"github.com/rabbytesoftware/quiver/internal/models/system"
 )
 
 type Requirements struct {
+	// root is prepended to the procfs and sysfs paths,
+	// so tests can probe a fake host.
+	root string
 }
 
 func NewRequirements() SRVInterface {
@@ -18,54 +27,151 @@ func (r *Requirements) Validate(
 	ctx context.Context,
 	requirements *requirement.Requirement,
 ) (bool, error) {
-	return false, nil
+	if requirements == nil || !requirements.IsValid() {
+		return false, nil
+	}
+
+	return r.Check(ctx, requirements, requirement.Tier{}).Passed, nil
+}
+
+// Probe detects what the host offers. Values that cannot be read
+// are left zero and explained in Host.Errors.
+func (r *Requirements) Probe(ctx context.Context) *requirement.Host {
+	host := &requirement.Host{
+		OS:     system.CurrentOS(),
+		Arch:   goruntime.GOARCH,
+		Errors: map[string]string{},
+	}
+
+	r.probeSystem(host)
+
+	if available, err := r.AvailableDisk(ctx, config.GetArrows().InstallDir); err != nil {
+		host.Errors["disk"] = err.Error()
+	} else {
+		host.DiskAvailable = available
+	}
+
+	return host
+}
+
+// Check compares requirements with the host, one check per
+// requirement, so callers can tell why an arrow does not fit.
+// Committed resources are taken off what the host offers.
+func (r *Requirements) Check(
+	ctx context.Context,
+	requirements *requirement.Requirement,
+	committed requirement.Tier,
+) *requirement.Report {
+	return requirement.Evaluate(r.Probe(ctx), requirements, committed)
 }
 
 func (r *Requirements) ValidateOS(
 	ctx context.Context,
 	recommendedOS system.OS,
 ) (bool, error) {
-	return false, nil
+	return recommendedOS == system.CurrentOS(), nil
 }
 
+// ValidateOSVersion compares against the running kernel release.
 func (r *Requirements) ValidateOSVersion(
 	ctx context.Context,
 	recommendedVersion string,
 ) (bool, error) {
-	return false, nil
+	host := r.Probe(ctx)
+	if host.KernelVersion == "" {
+		return false, fmt.Errorf("kernel version is unknown: %s", host.Errors["kernel"])
+	}
+
+	return versionAtLeast(host.KernelVersion, recommendedVersion)
 }
 
 func (r *Requirements) ValidateArch(
 	ctx context.Context,
 	recommendedArch string,
 ) (bool, error) {
-	return false, nil
+	return recommendedArch == goruntime.GOARCH, nil
 }
 
 func (r *Requirements) ValidateCPU(
 	ctx context.Context,
 	recommendedCPU int,
 ) (bool, error) {
-	return false, nil
+	return r.Probe(ctx).CpuCores >= recommendedCPU, nil
 }
 
+// ValidateMemory compares against MemAvailable, so memory already
+// used by running servers is not counted as free.
 func (r *Requirements) ValidateMemory(
 	ctx context.Context,
 	recommendedMemory int,
 ) (bool, error) {
-	return false, nil
+	host := r.Probe(ctx)
+	if err, ok := host.Errors["memory"]; ok {
+		return false, errors.New(err)
+	}
+
+	return host.MemoryAvailable >= recommendedMemory, nil
 }
 
 func (r *Requirements) ValidateDisk(
 	ctx context.Context,
 	recommendedDisk int,
 ) (bool, error) {
-	return false, nil
+	available, err := r.AvailableDisk(ctx, config.GetArrows().InstallDir)
+	if err != nil {
+		return false, err
+	}
+
+	return available >= recommendedDisk, nil
 }
 
+// ValidateNetwork compares against the fastest link speed in Mbit/s.
 func (r *Requirements) ValidateNetwork(
 	ctx context.Context,
 	recommendedNetwork int,
 ) (bool, error) {
-	return false, nil
+	host := r.Probe(ctx)
+	if err, ok := host.Errors["network"]; ok {
+		return false, errors.New(err)
+	}
+
+	return host.NetworkSpeed >= recommendedNetwork, nil
+}
+
+func (r *Requirements) AvailableDisk(
+	ctx context.Context,
+	path string,
+) (int, error) {
+	existing, err := nearestExisting(path)
+	if err != nil {
+		return 0, err
+	}
+
+	bytes, err := freeSpace(existing)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read free space of %s: %w", existing, err)
+	}
+
+	return int(bytes / (1024 * 1024)), nil
+}
+
+// nearestExisting walks up from path until it finds a directory that
+// exists, since arrows are sized before their directory is created.
+func nearestExisting(path string) (string, error) {
+	current, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+
+	for {
+		if _, err := os.Stat(current); err == nil {
+			return current, nil
+		}
+
+		parent := filepath.Dir(current)
+		if parent == current {
+			return "", fmt.Errorf("no existing parent directory for %s", path)
+		}
+		current = parent
+	}
 }
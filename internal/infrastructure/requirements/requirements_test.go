@@ -2,6 +2,9 @@ package requirements
 
 import (
 	"context"
+	"os"
+	"path/filepath"
+	goruntime "runtime"
 	"testing"
 
 	"github.com/rabbytesoftware/quiver/internal/models/requirement"
@@ -25,33 +28,44 @@ func TestRequirements_Validate(t *testing.T) {
 		t.Errorf("Validate() returned error: %v", err)
 	}
 	if valid {
-		t.Error("Validate() should return false for unimplemented method")
+		t.Error("Validate() should return false for incomplete requirements")
+	}
+
+	valid, err = req.Validate(ctx, nil)
+	if err != nil || valid {
+		t.Errorf("Validate() should return false for nil requirements, got %v, %v", valid, err)
 	}
 }
 
-func TestRequirements_ValidateOS(t *testing.T) {
+func TestRequirements_Validate_Unreachable(t *testing.T) {
 	req := NewRequirements()
-	ctx := context.Background()
 
-	valid, err := req.ValidateOS(ctx, system.OSLinuxAMD64)
-	if err != nil {
-		t.Errorf("ValidateOS() returned error: %v", err)
-	}
+	valid, _ := req.Validate(context.Background(), &requirement.Requirement{
+		CpuCores: 1 << 20,
+		Memory:   1,
+		Disk:     1,
+		OS:       system.CurrentOS(),
+	})
 	if valid {
-		t.Error("ValidateOS() should return false for unimplemented method")
+		t.Error("Validate() should return false when the host lacks CPU cores")
 	}
 }
 
-func TestRequirements_ValidateOSVersion(t *testing.T) {
+func TestRequirements_ValidateOS(t *testing.T) {
 	req := NewRequirements()
 	ctx := context.Background()
 
-	valid, err := req.ValidateOSVersion(ctx, "1.0.0")
+	valid, err := req.ValidateOS(ctx, system.CurrentOS())
 	if err != nil {
-		t.Errorf("ValidateOSVersion() returned error: %v", err)
+		t.Errorf("ValidateOS() returned error: %v", err)
 	}
+	if !valid {
+		t.Error("ValidateOS() should accept the host OS")
+	}
+
+	valid, _ = req.ValidateOS(ctx, system.OS("plan9/mips"))
 	if valid {
-		t.Error("ValidateOSVersion() should return false for unimplemented method")
+		t.Error("ValidateOS() should reject a foreign OS")
 	}
 }
 
@@ -59,12 +73,17 @@ func TestRequirements_ValidateArch(t *testing.T) {
 	req := NewRequirements()
 	ctx := context.Background()
 
-	valid, err := req.ValidateArch(ctx, "amd64")
+	valid, err := req.ValidateArch(ctx, goruntime.GOARCH)
 	if err != nil {
 		t.Errorf("ValidateArch() returned error: %v", err)
 	}
+	if !valid {
+		t.Error("ValidateArch() should accept the host architecture")
+	}
+
+	valid, _ = req.ValidateArch(ctx, "mips")
 	if valid {
-		t.Error("ValidateArch() should return false for unimplemented method")
+		t.Error("ValidateArch() should reject a foreign architecture")
 	}
 }
 
@@ -72,25 +91,39 @@ func TestRequirements_ValidateCPU(t *testing.T) {
 	req := NewRequirements()
 	ctx := context.Background()
 
-	valid, err := req.ValidateCPU(ctx, 4)
+	valid, err := req.ValidateCPU(ctx, 1)
 	if err != nil {
 		t.Errorf("ValidateCPU() returned error: %v", err)
 	}
+	if !valid {
+		t.Error("ValidateCPU() should accept a single core")
+	}
+
+	valid, _ = req.ValidateCPU(ctx, goruntime.NumCPU()+1)
 	if valid {
-		t.Error("ValidateCPU() should return false for unimplemented method")
+		t.Error("ValidateCPU() should reject more cores than the host has")
 	}
 }
 
 func TestRequirements_ValidateMemory(t *testing.T) {
+	if _, err := os.Stat(meminfoPath); err != nil {
+		t.Skip("meminfo is not available on this host")
+	}
+
 	req := NewRequirements()
 	ctx := context.Background()
 
-	valid, err := req.ValidateMemory(ctx, 8192)
+	valid, err := req.ValidateMemory(ctx, 1)
 	if err != nil {
 		t.Errorf("ValidateMemory() returned error: %v", err)
 	}
+	if !valid {
+		t.Error("ValidateMemory() should accept 1 MB")
+	}
+
+	valid, _ = req.ValidateMemory(ctx, 1<<40)
 	if valid {
-		t.Error("ValidateMemory() should return false for unimplemented method")
+		t.Error("ValidateMemory() should reject an unrealistic amount")
 	}
 }
 
@@ -98,25 +131,48 @@ func TestRequirements_ValidateDisk(t *testing.T) {
 	req := NewRequirements()
 	ctx := context.Background()
 
-	valid, err := req.ValidateDisk(ctx, 1000000)
+	valid, err := req.ValidateDisk(ctx, 1<<40)
 	if err != nil {
 		t.Errorf("ValidateDisk() returned error: %v", err)
 	}
 	if valid {
-		t.Error("ValidateDisk() should return false for unimplemented method")
+		t.Error("ValidateDisk() should reject an unrealistic amount")
 	}
 }
 
-func TestRequirements_ValidateNetwork(t *testing.T) {
+func TestRequirements_AvailableDisk(t *testing.T) {
 	req := NewRequirements()
 	ctx := context.Background()
 
-	valid, err := req.ValidateNetwork(ctx, 1000)
+	available, err := req.AvailableDisk(ctx, filepath.Join(t.TempDir(), "not", "created", "yet"))
 	if err != nil {
-		t.Errorf("ValidateNetwork() returned error: %v", err)
+		t.Fatalf("AvailableDisk() returned error: %v", err)
 	}
-	if valid {
-		t.Error("ValidateNetwork() should return false for unimplemented method")
+	if available <= 0 {
+		t.Errorf("Expected free space on the temp directory, got %d MB", available)
+	}
+}
+
+func TestAvailableMemory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "meminfo")
+	content := "MemTotal:       16384000 kB\nMemFree:         1024000 kB\nMemAvailable:    8192000 kB\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write meminfo: %v", err)
+	}
+
+	available, err := availableMemory(path)
+	if err != nil {
+		t.Fatalf("availableMemory() returned error: %v", err)
+	}
+	if available != 8000 {
+		t.Errorf("Expected 8000 MB, got %d", available)
+	}
+
+	if err := os.WriteFile(path, []byte("MemTotal: 1 kB\n"), 0644); err != nil {
+		t.Fatalf("failed to write meminfo: %v", err)
+	}
+	if _, err := availableMemory(path); err == nil {
+		t.Error("availableMemory() should fail without MemAvailable")
 	}
 }
 
@@ -143,51 +199,3 @@ func TestRequirements_MultipleInstances(t *testing.T) {
 		t.Error("Both instances should have same Validate behavior")
 	}
 }
-
-func TestRequirements_AllValidationMethods(t *testing.T) {
-	req := NewRequirements()
-	ctx := context.Background()
-
-	// Test all validation methods with various inputs
-	testCases := []struct {
-		name string
-		fn   func() (bool, error)
-	}{
-		{"Validate", func() (bool, error) {
-			return req.Validate(ctx, &requirement.Requirement{})
-		}},
-		{"ValidateOS", func() (bool, error) {
-			return req.ValidateOS(ctx, system.OSLinuxAMD64)
-		}},
-		{"ValidateOSVersion", func() (bool, error) {
-			return req.ValidateOSVersion(ctx, "1.0.0")
-		}},
-		{"ValidateArch", func() (bool, error) {
-			return req.ValidateArch(ctx, "amd64")
-		}},
-		{"ValidateCPU", func() (bool, error) {
-			return req.ValidateCPU(ctx, 4)
-		}},
-		{"ValidateMemory", func() (bool, error) {
-			return req.ValidateMemory(ctx, 8192)
-		}},
-		{"ValidateDisk", func() (bool, error) {
-			return req.ValidateDisk(ctx, 1000000)
-		}},
-		{"ValidateNetwork", func() (bool, error) {
-			return req.ValidateNetwork(ctx, 1000)
-		}},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			valid, err := tc.fn()
-			if err != nil {
-				t.Errorf("%s() returned error: %v", tc.name, err)
-			}
-			if valid {
-				t.Errorf("%s() should return false for unimplemented method", tc.name)
-			}
-		})
-	}
-}
@@ -2,12 +2,33 @@ package atl
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	yaml "gopkg.in/yaml.v3"
 
 	fns "github.com/rabbytesoftware/quiver/internal/infrastructure/fetchnshare"
 	translator "github.com/rabbytesoftware/quiver/internal/infrastructure/translator/models"
 	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+	"github.com/rabbytesoftware/quiver/internal/models/requirement"
+	"github.com/rabbytesoftware/quiver/internal/models/runtime"
+	"github.com/rabbytesoftware/quiver/internal/models/system"
+	"github.com/rabbytesoftware/quiver/internal/models/template"
+	"github.com/rabbytesoftware/quiver/internal/models/variable"
 )
 
+const ManifestV1 = "arrow@v1"
+
+// ? Manifests express memory and disk in gigabytes, while
+// ? the requirement model works with megabytes.
+const megabytesPerGigabyte = 1024
+
 type ArrowTranslationLayer struct {
 	fns fns.FNSInterface
 }
@@ -24,25 +45,278 @@ func (a *ArrowTranslationLayer) IsCompatible(
 	ctx context.Context,
 	manifestPath string,
 ) (bool, error) {
-	return false, nil
+	version, err := a.GetManifestVersion(ctx, manifestPath)
+	if err != nil {
+		return false, err
+	}
+
+	supported, err := a.GetSupportedVersions(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return slices.Contains(supported, version), nil
 }
 
 func (a *ArrowTranslationLayer) Translate(
 	ctx context.Context,
 	manifestPath string,
 ) (*arrow.Arrow, error) {
-	return nil, nil
+	data, err := a.fns.Read(ctx, manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read arrow manifest: %w", err)
+	}
+
+	var header manifestHeader
+	if err := yaml.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("failed to parse arrow manifest %s: %w", manifestPath, err)
+	}
+
+	switch header.Manifest {
+	case ManifestV1:
+		var manifest manifestV1
+		if err := yaml.Unmarshal(data, &manifest); err != nil {
+			return nil, fmt.Errorf("failed to parse arrow manifest %s: %w", manifestPath, err)
+		}
+		translated := translateV1(&manifest)
+		translated.ManifestURL = system.URL(manifestPath)
+		translated.ManifestHash = Checksum(data)
+
+		if err := a.loadTemplates(ctx, translated, manifestPath); err != nil {
+			return nil, err
+		}
+
+		return translated, nil
+	default:
+		return nil, fmt.Errorf("unsupported arrow manifest version %q in %s", header.Manifest, manifestPath)
+	}
 }
 
 func (a *ArrowTranslationLayer) GetManifestVersion(
 	ctx context.Context,
 	manifestPath string,
 ) (string, error) {
-	return "", nil
+	data, err := a.fns.Read(ctx, manifestPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read arrow manifest: %w", err)
+	}
+
+	var header manifestHeader
+	if err := yaml.Unmarshal(data, &header); err != nil {
+		return "", fmt.Errorf("failed to parse arrow manifest %s: %w", manifestPath, err)
+	}
+
+	return header.Manifest, nil
 }
 
 func (a *ArrowTranslationLayer) GetSupportedVersions(
 	ctx context.Context,
 ) ([]string, error) {
-	return nil, nil
+	return []string{ManifestV1}, nil
+}
+
+func translateV1(manifest *manifestV1) *arrow.Arrow {
+	result := &arrow.Arrow{
+		Namespace:     arrow.NewArrowNamespace(manifest.Metadata.Name, manifest.Metadata.Version),
+		ArrowVersion:  []string{manifest.Manifest},
+		Name:          manifest.Metadata.Name,
+		Description:   manifest.Metadata.Description,
+		Version:       manifest.Metadata.Version,
+		License:       manifest.Metadata.License,
+		Maintainers:   manifest.Metadata.Maintainers,
+		URL:           system.URL(manifest.Metadata.URL),
+		Documentation: manifest.Metadata.Documentation,
+		Changelog:     system.URL(manifest.Metadata.Changelog),
+		Requirements:  translateRequirements(manifest.Requirements),
+	}
+
+	for _, credit := range manifest.Metadata.Credits {
+		result.Credits = append(result.Credits, credit.Name)
+	}
+
+	for _, dependency := range manifest.Dependencies {
+		result.Dependencies = append(result.Dependencies, arrow.ArrowNamespace(dependency))
+	}
+
+	for _, rule := range manifest.Netbridge {
+		result.Netbridge = append(result.Netbridge, port.PortRule{
+			Name:             rule.Name,
+			Protocol:         port.Protocol(rule.Protocol),
+			ForwardingStatus: port.ForwardingStatusDisabled,
+		})
+	}
+
+	for _, v := range manifest.Variables {
+		result.Variables = append(result.Variables, variable.Variable{
+			Name:      v.Name,
+			Default:   v.Default,
+			Values:    v.Values,
+			Min:       v.Min,
+			Max:       v.Max,
+			Sensitive: v.Sensitive,
+			Type:      variableType(v),
+		})
+	}
+
+	result.Data = arrow.DataPaths(manifest.Data)
+
+	result.Backup = arrow.BackupSpec{
+		Include: manifest.Backup.Include,
+		Exclude: manifest.Backup.Exclude,
+	}
+
+	for _, t := range manifest.Templates {
+		result.Templates = append(result.Templates, template.Template{
+			Target:  t.Target,
+			Source:  t.Source,
+			Content: t.Content,
+		})
+	}
+
+	result.Methods = translateMethodsV1(manifest.Methods)
+
+	return result
+}
+
+// loadTemplates inlines the content of templates shipped as separate
+// files, so an installed arrow can be rendered without its quiver.
+func (a *ArrowTranslationLayer) loadTemplates(
+	ctx context.Context,
+	translated *arrow.Arrow,
+	manifestPath string,
+) error {
+	for i, t := range translated.Templates {
+		if err := t.Validate(); err != nil {
+			return fmt.Errorf("invalid template in %s: %w", manifestPath, err)
+		}
+
+		if t.Content != "" {
+			continue
+		}
+
+		source := templateSource(manifestPath, t.Source)
+		data, err := a.fns.Read(ctx, source)
+		if err != nil {
+			return fmt.Errorf("failed to read template %s: %w", t.Target, err)
+		}
+
+		translated.Templates[i].Content = string(data)
+	}
+
+	return nil
+}
+
+// templateSource resolves a template source relative to the manifest,
+// which is either a URL or a local path.
+func templateSource(manifestPath, source string) string {
+	if ref, err := url.Parse(source); err == nil && ref.IsAbs() {
+		return source
+	}
+
+	if base, err := url.Parse(manifestPath); err == nil && base.IsAbs() && base.Host != "" {
+		ref, err := url.Parse(source)
+		if err == nil {
+			return base.ResolveReference(ref).String()
+		}
+	}
+
+	if filepath.IsAbs(source) {
+		return source
+	}
+
+	return filepath.Join(filepath.Dir(manifestPath), filepath.FromSlash(source))
+}
+
+// translateRequirements falls back to the flat fields when no
+// minimum tier is declared, so older manifests keep working.
+func translateRequirements(manifest requirementsV1) requirement.Requirement {
+	minimum := tierV1{
+		CpuCores:    manifest.CpuCores,
+		RamGB:       manifest.RamGB,
+		DiskGB:      manifest.DiskGB,
+		NetworkMbps: manifest.NetworkMbps,
+	}
+	if manifest.Minimum != nil {
+		minimum = *manifest.Minimum
+	}
+
+	result := requirement.Requirement{
+		CpuCores: minimum.CpuCores,
+		Memory:   minimum.RamGB * megabytesPerGigabyte,
+		Disk:     minimum.DiskGB * megabytesPerGigabyte,
+		Network:  minimum.NetworkMbps,
+		OS:       selectOS(manifest.System),
+	}
+
+	if manifest.Recommended != nil {
+		result.Recommended = &requirement.Tier{
+			CpuCores: manifest.Recommended.CpuCores,
+			Memory:   manifest.Recommended.RamGB * megabytesPerGigabyte,
+			Disk:     manifest.Recommended.DiskGB * megabytesPerGigabyte,
+			Network:  manifest.Recommended.NetworkMbps,
+		}
+	}
+
+	return result
+}
+
+// selectOS picks the host OS when the manifest supports it,
+// so requirement validation on this host can succeed, and
+// otherwise falls back to the first declared system.
+func selectOS(systems []string) system.OS {
+	if len(systems) == 0 {
+		return ""
+	}
+
+	current := system.CurrentOS()
+	if slices.Contains(systems, current.String()) {
+		return current
+	}
+
+	return system.OS(systems[0])
+}
+
+func variableType(v variableV1) variable.VariableType {
+	if v.Type != "" {
+		return variable.VariableType(v.Type)
+	}
+
+	if v.Min != 0 || v.Max != 0 {
+		return variable.VariableTypeNumber
+	}
+
+	return variable.VariableTypeString
+}
+
+func translateMethodsV1(
+	methods map[string]map[string]map[string][]string,
+) []runtime.Method {
+	var result []runtime.Method
+
+	for osName, archs := range methods {
+		for arch, actions := range archs {
+			for action, steps := range actions {
+				result = append(result, runtime.Method{
+					OS:      system.OS(osName + "/" + arch),
+					Action:  runtime.Action(action),
+					Command: steps,
+				})
+			}
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].OS != result[j].OS {
+			return result[i].OS < result[j].OS
+		}
+		return result[i].Action < result[j].Action
+	})
+
+	return result
+}
+
+// Checksum returns the hex encoded SHA-256 of a manifest.
+func Checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
 }
@@ -2,11 +2,89 @@ package atl
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	fns "github.com/rabbytesoftware/quiver/internal/infrastructure/fetchnshare"
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/requirement"
+	"github.com/rabbytesoftware/quiver/internal/models/runtime"
+	"github.com/rabbytesoftware/quiver/internal/models/system"
 )
 
+const testManifest = `manifest: "arrow@v1"
+
+metadata:
+  version: 25.7.0
+  license: MIT
+  changelog: https://quiver.ar/chat/CHANGELOG.md
+  name: quiver.chat
+  description: Quiver Chat is a chat client for the Quiver platform.
+  credits:
+    - name: char2cs
+      email: info@char2cs.net
+
+requirements:
+  cpu_cores: 1
+  ram_gb: 2
+  disk_gb: 3
+  system:
+    - "linux/amd64"
+    - "windows/amd64"
+
+dependencies:
+  - "steamcmd@1.0.0"
+
+netbridge:
+  - name: "CHAT_PORT"
+    protocol: "tcp/udp"
+
+variables:
+  - name: "QUIVER_CHAT_HOSTNAME"
+    default: "chat.quiver.ar"
+  - name: "MAX_PLAYERS"
+    default: 12
+    min: 2
+    max: 64
+  - name: "RCON_PASSWORD"
+    default: "secret"
+    sensitive: true
+
+data:
+  - "data"
+
+backup:
+  include:
+    - "data"
+  exclude:
+    - "**/*.log"
+
+methods:
+  linux:
+    amd64:
+      install:
+        - "GET: https://example.com/quiver-chat-linux-amd64.tar.gz"
+        - "UNCOMPRESS: quiver-chat-linux-amd64.tar.gz"
+      execute:
+        - "./quiver-chat --port ${CHAT_PORT}"
+  windows:
+    amd64:
+      install:
+        - "GET: https://example.com/quiver-chat-windows-amd64.zip"
+`
+
+func writeManifest(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "arrow.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write manifest: %v", err)
+	}
+
+	return path
+}
+
 func TestNewATL(t *testing.T) {
 	mockFNS := fns.NewFNS()
 	atl := NewATL(mockFNS)
@@ -16,124 +94,265 @@ func TestNewATL(t *testing.T) {
 }
 
 func TestATL_IsCompatible(t *testing.T) {
-	mockFNS := fns.NewFNS()
-	atl := NewATL(mockFNS)
+	atl := NewATL(fns.NewFNS())
 	ctx := context.Background()
 
-	compatible, err := atl.IsCompatible(ctx, "test-manifest")
+	compatible, err := atl.IsCompatible(ctx, writeManifest(t, testManifest))
+	if err != nil {
+		t.Errorf("IsCompatible() returned error: %v", err)
+	}
+	if !compatible {
+		t.Error("IsCompatible() should return true for an arrow@v1 manifest")
+	}
+
+	compatible, err = atl.IsCompatible(ctx, writeManifest(t, `manifest: "arrow@v99"`))
 	if err != nil {
 		t.Errorf("IsCompatible() returned error: %v", err)
 	}
 	if compatible {
-		t.Error("IsCompatible() should return false for unimplemented method")
+		t.Error("IsCompatible() should return false for an unknown manifest version")
+	}
+}
+
+func TestATL_IsCompatible_MissingManifest(t *testing.T) {
+	atl := NewATL(fns.NewFNS())
+
+	compatible, err := atl.IsCompatible(context.Background(), "test-manifest")
+	if err == nil {
+		t.Error("IsCompatible() should return an error for a missing manifest")
+	}
+	if compatible {
+		t.Error("IsCompatible() should return false for a missing manifest")
 	}
 }
 
 func TestATL_Translate(t *testing.T) {
-	mockFNS := fns.NewFNS()
-	atl := NewATL(mockFNS)
+	atl := NewATL(fns.NewFNS())
 	ctx := context.Background()
 
-	result, err := atl.Translate(ctx, "test-input")
+	result, err := atl.Translate(ctx, writeManifest(t, testManifest))
 	if err != nil {
-		t.Errorf("Translate() returned error: %v", err)
+		t.Fatalf("Translate() returned error: %v", err)
+	}
+
+	if result.Namespace != arrow.ArrowNamespace("quiver.chat@25.7.0") {
+		t.Errorf("Expected namespace quiver.chat@25.7.0, got %q", result.Namespace)
+	}
+	if result.ManifestHash != Checksum([]byte(testManifest)) {
+		t.Errorf("Expected the manifest hash to be recorded, got %q", result.ManifestHash)
+	}
+	if result.Changelog != system.URL("https://quiver.ar/chat/CHANGELOG.md") {
+		t.Errorf("Expected the changelog URL, got %q", result.Changelog)
+	}
+	if len(result.Backup.Include) != 1 || result.Backup.Include[0] != "data" ||
+		len(result.Backup.Exclude) != 1 || result.Backup.Exclude[0] != "**/*.log" {
+		t.Errorf("Expected the backup paths, got %+v", result.Backup)
+	}
+	if len(result.Data) != 1 || result.Data[0] != "data" {
+		t.Errorf("Expected the data paths, got %v", result.Data)
+	}
+	if result.Version != "25.7.0" {
+		t.Errorf("Expected version 25.7.0, got %q", result.Version)
 	}
-	if result != nil {
-		t.Error("Translate() should return nil for unimplemented method")
+	if len(result.Credits) != 1 || result.Credits[0] != "char2cs" {
+		t.Errorf("Expected credits [char2cs], got %v", result.Credits)
+	}
+	if result.Requirements.Memory != 2048 || result.Requirements.Disk != 3072 {
+		t.Errorf("Expected memory 2048 and disk 3072, got %d and %d", result.Requirements.Memory, result.Requirements.Disk)
+	}
+	if result.Requirements.Recommended != nil {
+		t.Errorf("Expected a flat requirements block to only declare a minimum, got %+v", result.Requirements.Recommended)
+	}
+	if len(result.Dependencies) != 1 || result.Dependencies[0] != arrow.ArrowNamespace("steamcmd@1.0.0") {
+		t.Errorf("Expected dependencies [steamcmd@1.0.0], got %v", result.Dependencies)
+	}
+	if len(result.Netbridge) != 1 || result.Netbridge[0].Name != "CHAT_PORT" || !result.Netbridge[0].Protocol.IsTCPUDP() {
+		t.Errorf("Unexpected netbridge rules: %v", result.Netbridge)
+	}
+	if len(result.Variables) != 3 {
+		t.Fatalf("Expected 3 variables, got %d", len(result.Variables))
+	}
+	if result.Variables[1].Default != "12" || !result.Variables[1].Type.IsNumber() {
+		t.Errorf("Expected numeric MAX_PLAYERS with default 12, got %+v", result.Variables[1])
+	}
+	if !result.Variables[2].Sensitive {
+		t.Error("Expected RCON_PASSWORD to be sensitive")
+	}
+	if len(result.Methods) != 3 {
+		t.Fatalf("Expected 3 methods, got %d", len(result.Methods))
+	}
+	if result.Methods[0].OS != system.OSLinuxAMD64 || result.Methods[0].Action != runtime.ActionExecute {
+		t.Errorf("Expected methods to be sorted by OS and action, got %+v", result.Methods[0])
+	}
+	if len(result.Methods[1].Command) != 2 {
+		t.Errorf("Expected 2 install steps, got %d", len(result.Methods[1].Command))
+	}
+}
+
+func TestATL_Translate_Unsupported(t *testing.T) {
+	atl := NewATL(fns.NewFNS())
+	ctx := context.Background()
+
+	if _, err := atl.Translate(ctx, writeManifest(t, `manifest: "quiver@v1"`)); err == nil {
+		t.Error("Translate() should reject manifests that are not arrows")
+	}
+
+	if _, err := atl.Translate(ctx, writeManifest(t, "manifest: [")); err == nil {
+		t.Error("Translate() should reject malformed manifests")
+	}
+
+	if _, err := atl.Translate(ctx, "test-input"); err == nil {
+		t.Error("Translate() should return an error for a missing manifest")
 	}
 }
 
 func TestATL_GetManifestVersion(t *testing.T) {
-	mockFNS := fns.NewFNS()
-	atl := NewATL(mockFNS)
+	atl := NewATL(fns.NewFNS())
 	ctx := context.Background()
 
-	version, err := atl.GetManifestVersion(ctx, "test-manifest")
+	version, err := atl.GetManifestVersion(ctx, writeManifest(t, testManifest))
 	if err != nil {
 		t.Errorf("GetManifestVersion() returned error: %v", err)
 	}
-	if version != "" {
-		t.Error("GetManifestVersion() should return empty string for unimplemented method")
+	if version != ManifestV1 {
+		t.Errorf("Expected manifest version %q, got %q", ManifestV1, version)
+	}
+
+	if _, err := atl.GetManifestVersion(ctx, "test-manifest"); err == nil {
+		t.Error("GetManifestVersion() should return an error for a missing manifest")
 	}
 }
 
 func TestATL_GetSupportedVersions(t *testing.T) {
-	mockFNS := fns.NewFNS()
-	atl := NewATL(mockFNS)
-	ctx := context.Background()
+	atl := NewATL(fns.NewFNS())
 
-	versions, err := atl.GetSupportedVersions(ctx)
+	versions, err := atl.GetSupportedVersions(context.Background())
 	if err != nil {
 		t.Errorf("GetSupportedVersions() returned error: %v", err)
 	}
-	if versions != nil {
-		t.Error("GetSupportedVersions() should return nil for unimplemented method")
+	if len(versions) != 1 || versions[0] != ManifestV1 {
+		t.Errorf("Expected supported versions [%s], got %v", ManifestV1, versions)
 	}
 }
 
-func TestATL_InterfaceCompliance(t *testing.T) {
-	// Test that ATL implements the expected interface
-	mockFNS := fns.NewFNS()
-	atl := NewATL(mockFNS)
-	if atl == nil {
-		t.Error("ATL should not be nil")
+func TestSelectOS(t *testing.T) {
+	if selectOS(nil) != "" {
+		t.Error("selectOS() should return an empty OS when none are declared")
 	}
-}
 
-func TestATL_MultipleInstances(t *testing.T) {
-	mockFNS := fns.NewFNS()
-	atl1 := NewATL(mockFNS)
-	atl2 := NewATL(mockFNS)
+	current := system.CurrentOS()
+	if selectOS([]string{"plan9/mips", current.String()}) != current {
+		t.Error("selectOS() should prefer the host OS when it is supported")
+	}
 
-	// Both should be valid
-	if atl1 == nil || atl2 == nil {
-		t.Error("NewATL() returned nil instance")
+	if selectOS([]string{"plan9/mips"}) != system.OS("plan9/mips") {
+		t.Error("selectOS() should fall back to the first declared OS")
 	}
+}
 
-	// Test that both instances work correctly
-	ctx := context.Background()
-	compatible1, _ := atl1.IsCompatible(ctx, "test")
-	compatible2, _ := atl2.IsCompatible(ctx, "test")
+func TestChecksum(t *testing.T) {
+	expected := "c59435a1e9474332701fdc4ce135dbcc2d733502c43078e2d8a3565cc41ef48a"
+	if got := Checksum([]byte("quiver")); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+func TestATL_Translate_RequirementTiers(t *testing.T) {
+	atl := NewATL(fns.NewFNS())
+
+	result, err := atl.Translate(context.Background(), writeManifest(t, `manifest: "arrow@v1"
+metadata:
+  name: cs2
+  version: 1.0.0
+requirements:
+  minimum:
+    cpu_cores: 2
+    ram_gb: 4
+    disk_gb: 30
+    network_mbps: 10
+  recommended:
+    cpu_cores: 4
+    ram_gb: 8
+    disk_gb: 50
+    network_mbps: 20
+  system:
+    - "linux/amd64"
+`))
+	if err != nil {
+		t.Fatalf("Translate() returned error: %v", err)
+	}
 
-	if compatible1 != compatible2 {
-		t.Error("Both instances should have same IsCompatible behavior")
+	requirements := result.Requirements
+	if requirements.CpuCores != 2 || requirements.Memory != 4096 || requirements.Disk != 30720 || requirements.Network != 10 {
+		t.Errorf("Expected the minimum tier, got %+v", requirements)
+	}
+	if requirements.Recommended == nil ||
+		*requirements.Recommended != (requirement.Tier{CpuCores: 4, Memory: 8192, Disk: 51200, Network: 20}) {
+		t.Errorf("Expected the recommended tier, got %+v", requirements.Recommended)
 	}
 }
 
-func TestATL_AllMethods(t *testing.T) {
-	mockFNS := fns.NewFNS()
-	atl := NewATL(mockFNS)
+func TestATL_Translate_Templates(t *testing.T) {
+	atl := NewATL(fns.NewFNS())
 	ctx := context.Background()
 
-	// Test all methods to ensure they don't panic
+	manifest := writeManifest(t, `manifest: "arrow@v1"
+metadata:
+  name: cs2
+  version: 1.0.0
+templates:
+  - target: "cfg/server.cfg"
+    source: "templates/server.cfg"
+  - target: "motd.txt"
+    content: "Welcome to ${SERVER_HOSTNAME}"
+`)
+
+	source := filepath.Join(filepath.Dir(manifest), "templates", "server.cfg")
+	if err := os.MkdirAll(filepath.Dir(source), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(source, []byte("hostname ${SERVER_HOSTNAME}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := atl.Translate(ctx, manifest)
+	if err != nil {
+		t.Fatalf("Translate() returned error: %v", err)
+	}
+
+	if len(result.Templates) != 2 {
+		t.Fatalf("Expected 2 templates, got %d", len(result.Templates))
+	}
+	if result.Templates[0].Content != "hostname ${SERVER_HOSTNAME}\n" {
+		t.Errorf("Expected the template source to be inlined, got %q", result.Templates[0].Content)
+	}
+	if result.Templates[1].Content != "Welcome to ${SERVER_HOSTNAME}" {
+		t.Errorf("Expected the inline template to be kept, got %q", result.Templates[1].Content)
+	}
+
+	if _, err := atl.Translate(ctx, writeManifest(t, `manifest: "arrow@v1"
+templates:
+  - target: "../escape.cfg"
+    content: "x"
+`)); err == nil {
+		t.Error("Translate() should reject templates escaping the install directory")
+	}
+}
+
+func TestTemplateSource(t *testing.T) {
 	testCases := []struct {
-		name string
-		fn   func() error
+		manifest string
+		source   string
+		expected string
 	}{
-		{"IsCompatible", func() error {
-			_, err := atl.IsCompatible(ctx, "test")
-			return err
-		}},
-		{"Translate", func() error {
-			_, err := atl.Translate(ctx, "test")
-			return err
-		}},
-		{"GetManifestVersion", func() error {
-			_, err := atl.GetManifestVersion(ctx, "test")
-			return err
-		}},
-		{"GetSupportedVersions", func() error {
-			_, err := atl.GetSupportedVersions(ctx)
-			return err
-		}},
+		{"https://quiver.ar/cs2/arrow.yaml", "templates/server.cfg", "https://quiver.ar/cs2/templates/server.cfg"},
+		{"https://quiver.ar/cs2/arrow.yaml", "https://cdn.quiver.ar/server.cfg", "https://cdn.quiver.ar/server.cfg"},
+		{filepath.Join("pkgs", "cs2", "arrow.yaml"), "templates/server.cfg", filepath.Join("pkgs", "cs2", "templates", "server.cfg")},
 	}
 
 	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			err := tc.fn()
-			if err != nil {
-				t.Errorf("%s() returned error: %v", tc.name, err)
-			}
-		})
+		if got := templateSource(tc.manifest, tc.source); got != tc.expected {
+			t.Errorf("templateSource(%q, %q) = %q, want %q", tc.manifest, tc.source, got, tc.expected)
+		}
 	}
 }
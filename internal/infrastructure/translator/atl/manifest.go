@@ -0,0 +1,85 @@
+package atl
+
+// ? The structures below mirror the arrow@v1 manifest layout
+// ? as documented in arrow.dev/arrow.yaml
+
+type manifestHeader struct {
+	Manifest string `yaml:"manifest"`
+}
+
+type manifestV1 struct {
+	Manifest     string                                    `yaml:"manifest"`
+	Metadata     metadataV1                                `yaml:"metadata"`
+	Requirements requirementsV1                            `yaml:"requirements"`
+	Dependencies []string                                  `yaml:"dependencies"`
+	Netbridge    []netbridgeV1                             `yaml:"netbridge"`
+	Variables    []variableV1                              `yaml:"variables"`
+	Data         []string                                  `yaml:"data"`
+	Backup       backupV1                                  `yaml:"backup"`
+	Templates    []templateV1                              `yaml:"templates"`
+	Methods      map[string]map[string]map[string][]string `yaml:"methods"`
+}
+
+type metadataV1 struct {
+	Version       string     `yaml:"version"`
+	License       string     `yaml:"license"`
+	QuiverURL     string     `yaml:"quiver_url"`
+	URL           string     `yaml:"url"`
+	Documentation string     `yaml:"documentation"`
+	Changelog     string     `yaml:"changelog"`
+	Name          string     `yaml:"name"`
+	Description   string     `yaml:"description"`
+	Maintainers   []string   `yaml:"maintainers"`
+	Credits       []creditV1 `yaml:"credits"`
+}
+
+type creditV1 struct {
+	Name  string `yaml:"name"`
+	Email string `yaml:"email"`
+	URL   string `yaml:"url"`
+}
+
+// requirementsV1 accepts either a single flat tier, which is the
+// minimum, or explicit minimum and recommended tiers.
+type requirementsV1 struct {
+	CpuCores    int      `yaml:"cpu_cores"`
+	RamGB       int      `yaml:"ram_gb"`
+	DiskGB      int      `yaml:"disk_gb"`
+	NetworkMbps int      `yaml:"network_mbps"`
+	System      []string `yaml:"system"`
+	Minimum     *tierV1  `yaml:"minimum"`
+	Recommended *tierV1  `yaml:"recommended"`
+}
+
+type tierV1 struct {
+	CpuCores    int `yaml:"cpu_cores"`
+	RamGB       int `yaml:"ram_gb"`
+	DiskGB      int `yaml:"disk_gb"`
+	NetworkMbps int `yaml:"network_mbps"`
+}
+
+type netbridgeV1 struct {
+	Name     string `yaml:"name"`
+	Protocol string `yaml:"protocol"`
+}
+
+type variableV1 struct {
+	Name      string   `yaml:"name"`
+	Default   string   `yaml:"default"`
+	Values    []string `yaml:"values"`
+	Min       int      `yaml:"min"`
+	Max       int      `yaml:"max"`
+	Sensitive bool     `yaml:"sensitive"`
+	Type      string   `yaml:"type"`
+}
+
+type backupV1 struct {
+	Include []string `yaml:"include"`
+	Exclude []string `yaml:"exclude"`
+}
+
+type templateV1 struct {
+	Target  string `yaml:"target"`
+	Source  string `yaml:"source"`
+	Content string `yaml:"content"`
+}
@@ -0,0 +1,44 @@
+package qtl
+
+// ? The structures below mirror the quiver@v1 manifest layout
+// ? as documented in arrow.dev/quiver.yaml
+
+type manifestHeader struct {
+	Manifest string `yaml:"manifest"`
+}
+
+type manifestV1 struct {
+	Manifest string        `yaml:"manifest"`
+	Metadata metadataV1    `yaml:"metadata"`
+	Arrows   []listedArrow `yaml:"arrows"`
+}
+
+type metadataV1 struct {
+	Version     string     `yaml:"version"`
+	License     string     `yaml:"license"`
+	URL         string     `yaml:"url"`
+	Name        string     `yaml:"name"`
+	Description string     `yaml:"description"`
+	Media       mediaV1    `yaml:"media"`
+	Credits     []creditV1 `yaml:"credits"`
+}
+
+type mediaV1 struct {
+	Icon   string `yaml:"icon"`
+	Banner string `yaml:"banner"`
+}
+
+type creditV1 struct {
+	Name  string `yaml:"name"`
+	Email string `yaml:"email"`
+	URL   string `yaml:"url"`
+}
+
+// ? A quiver may list several entries with the same name,
+// ? one per published version of that arrow.
+type listedArrow struct {
+	Name        string `yaml:"name"`
+	Version     string `yaml:"version"`
+	Description string `yaml:"description"`
+	ManifestURL string `yaml:"manifest_url"`
+}
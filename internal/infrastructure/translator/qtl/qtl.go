@@ -2,12 +2,23 @@ package qtl
 
 import (
 	"context"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
 
 	fns "github.com/rabbytesoftware/quiver/internal/infrastructure/fetchnshare"
 	translator "github.com/rabbytesoftware/quiver/internal/infrastructure/translator/models"
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
 	"github.com/rabbytesoftware/quiver/internal/models/quiver"
+	"github.com/rabbytesoftware/quiver/internal/models/system"
 )
 
+const ManifestV1 = "quiver@v1"
+
 type QuiverTranslationLayer struct {
 	fns fns.FNSInterface
 }
@@ -24,25 +35,111 @@ func (a *QuiverTranslationLayer) IsCompatible(
 	ctx context.Context,
 	manifestPath string,
 ) (bool, error) {
-	return false, nil
+	version, err := a.GetManifestVersion(ctx, manifestPath)
+	if err != nil {
+		return false, err
+	}
+
+	supported, err := a.GetSupportedVersions(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return slices.Contains(supported, version), nil
 }
 
 func (a *QuiverTranslationLayer) Translate(
 	ctx context.Context,
 	manifestPath string,
 ) (*quiver.Quiver, error) {
-	return nil, nil
+	data, err := a.fns.Read(ctx, manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read quiver manifest: %w", err)
+	}
+
+	var header manifestHeader
+	if err := yaml.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("failed to parse quiver manifest %s: %w", manifestPath, err)
+	}
+
+	switch header.Manifest {
+	case ManifestV1:
+		var manifest manifestV1
+		if err := yaml.Unmarshal(data, &manifest); err != nil {
+			return nil, fmt.Errorf("failed to parse quiver manifest %s: %w", manifestPath, err)
+		}
+		return translateV1(&manifest, manifestPath), nil
+	default:
+		return nil, fmt.Errorf("unsupported quiver manifest version %q in %s", header.Manifest, manifestPath)
+	}
 }
 
 func (a *QuiverTranslationLayer) GetManifestVersion(
 	ctx context.Context,
 	manifestPath string,
 ) (string, error) {
-	return "", nil
+	data, err := a.fns.Read(ctx, manifestPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read quiver manifest: %w", err)
+	}
+
+	var header manifestHeader
+	if err := yaml.Unmarshal(data, &header); err != nil {
+		return "", fmt.Errorf("failed to parse quiver manifest %s: %w", manifestPath, err)
+	}
+
+	return header.Manifest, nil
 }
 
 func (a *QuiverTranslationLayer) GetSupportedVersions(
 	ctx context.Context,
 ) ([]string, error) {
-	return nil, nil
+	return []string{ManifestV1}, nil
+}
+
+func translateV1(manifest *manifestV1, manifestPath string) *quiver.Quiver {
+	result := &quiver.Quiver{
+		ID:          quiver.NewID(manifest.Metadata.Name),
+		Name:        manifest.Metadata.Name,
+		Description: manifest.Metadata.Description,
+		Banner:      system.URL(manifest.Metadata.Media.Banner),
+		URL:         system.URL(manifest.Metadata.URL),
+		Version:     manifest.Metadata.Version,
+		Manifests:   map[arrow.ArrowNamespace]system.URL{},
+	}
+
+	for _, credit := range manifest.Metadata.Credits {
+		result.Maintainers = append(result.Maintainers, credit.Name)
+	}
+
+	for _, listed := range manifest.Arrows {
+		namespace := arrow.NewArrowNamespace(listed.Name, listed.Version)
+
+		result.ListedArrows = append(result.ListedArrows, namespace)
+		result.Manifests[namespace] = system.URL(resolveManifestURL(manifestPath, listed.ManifestURL))
+	}
+
+	return result
+}
+
+// resolveManifestURL makes relative manifest URLs relative to the
+// quiver manifest itself, so local and remote quivers behave alike.
+func resolveManifestURL(manifestPath, manifestURL string) string {
+	if manifestURL == "" || isAbsolute(manifestURL) {
+		return manifestURL
+	}
+
+	if base, err := url.Parse(manifestPath); err == nil && base.IsAbs() {
+		if ref, err := url.Parse(manifestURL); err == nil {
+			return base.ResolveReference(ref).String()
+		}
+	}
+
+	return filepath.Join(filepath.Dir(manifestPath), manifestURL)
+}
+
+func isAbsolute(location string) bool {
+	return strings.HasPrefix(location, "http://") ||
+		strings.HasPrefix(location, "https://") ||
+		filepath.IsAbs(location)
 }
@@ -2,11 +2,52 @@ package qtl
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	fns "github.com/rabbytesoftware/quiver/internal/infrastructure/fetchnshare"
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/quiver"
+	"github.com/rabbytesoftware/quiver/internal/models/system"
 )
 
+const testManifest = `manifest: "quiver@v1"
+
+metadata:
+  version: 25.9.0
+  license: GPL-3.0
+  url: https://quiver.ar/quiver
+  name: core.quiver
+  description: Core arrows for Quiver functionality.
+  media:
+    banner: https://quiver.ar/quiver/banner.png
+  credits:
+    - name: Mateo Urrutia
+
+arrows:
+  - name: chat.quiver
+    description: Chat client for Quiver.
+    manifest_url: https://quiver.ar/quiver/arrows/chat.arrow.yaml
+  - name: steamcmd
+    version: 1.0.0
+    manifest_url: arrows/steamcmd-1.0.0.yaml
+  - name: steamcmd
+    version: 1.1.0
+    manifest_url: arrows/steamcmd-1.1.0.yaml
+`
+
+func writeManifest(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "quiver.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write manifest: %v", err)
+	}
+
+	return path
+}
+
 func TestNewQTL(t *testing.T) {
 	mockFNS := fns.NewFNS()
 	qtl := NewQTL(mockFNS)
@@ -16,123 +57,139 @@ func TestNewQTL(t *testing.T) {
 }
 
 func TestQTL_IsCompatible(t *testing.T) {
-	mockFNS := fns.NewFNS()
-	qtl := NewQTL(mockFNS)
+	qtl := NewQTL(fns.NewFNS())
 	ctx := context.Background()
 
-	compatible, err := qtl.IsCompatible(ctx, "test-manifest")
+	compatible, err := qtl.IsCompatible(ctx, writeManifest(t, testManifest))
+	if err != nil {
+		t.Errorf("IsCompatible() returned error: %v", err)
+	}
+	if !compatible {
+		t.Error("IsCompatible() should return true for a quiver@v1 manifest")
+	}
+
+	compatible, err = qtl.IsCompatible(ctx, writeManifest(t, `manifest: "arrow@v1"`))
 	if err != nil {
 		t.Errorf("IsCompatible() returned error: %v", err)
 	}
 	if compatible {
-		t.Error("IsCompatible() should return false for unimplemented method")
+		t.Error("IsCompatible() should return false for an arrow manifest")
+	}
+
+	if _, err := qtl.IsCompatible(ctx, "test-manifest"); err == nil {
+		t.Error("IsCompatible() should return an error for a missing manifest")
 	}
 }
 
 func TestQTL_Translate(t *testing.T) {
-	mockFNS := fns.NewFNS()
-	qtl := NewQTL(mockFNS)
-	ctx := context.Background()
+	qtl := NewQTL(fns.NewFNS())
+	path := writeManifest(t, testManifest)
 
-	result, err := qtl.Translate(ctx, "test-input")
+	result, err := qtl.Translate(context.Background(), path)
 	if err != nil {
-		t.Errorf("Translate() returned error: %v", err)
+		t.Fatalf("Translate() returned error: %v", err)
 	}
-	if result != nil {
-		t.Error("Translate() should return nil for unimplemented method")
-	}
-}
 
-func TestQTL_GetManifestVersion(t *testing.T) {
-	mockFNS := fns.NewFNS()
-	qtl := NewQTL(mockFNS)
-	ctx := context.Background()
+	if result.ID != quiver.NewID("core.quiver") || result.Name != "core.quiver" {
+		t.Errorf("Expected ID and name of core.quiver, got %s and %q", result.ID, result.Name)
+	}
+	if result.Banner != system.URL("https://quiver.ar/quiver/banner.png") {
+		t.Errorf("Unexpected banner %q", result.Banner)
+	}
+	if len(result.Maintainers) != 1 || result.Maintainers[0] != "Mateo Urrutia" {
+		t.Errorf("Expected maintainers [Mateo Urrutia], got %v", result.Maintainers)
+	}
+	if len(result.ListedArrows) != 3 {
+		t.Fatalf("Expected 3 listed arrows, got %d", len(result.ListedArrows))
+	}
+	if result.ListedArrows[1] != arrow.ArrowNamespace("steamcmd@1.0.0") {
+		t.Errorf("Expected steamcmd@1.0.0, got %q", result.ListedArrows[1])
+	}
 
-	version, err := qtl.GetManifestVersion(ctx, "test-manifest")
-	if err != nil {
-		t.Errorf("GetManifestVersion() returned error: %v", err)
+	if got := result.Manifests[arrow.ArrowNamespace("chat.quiver")]; got != system.URL("https://quiver.ar/quiver/arrows/chat.arrow.yaml") {
+		t.Errorf("Expected absolute manifest URL to be kept, got %q", got)
 	}
-	if version != "" {
-		t.Error("GetManifestVersion() should return empty string for unimplemented method")
+
+	expected := filepath.Join(filepath.Dir(path), "arrows", "steamcmd-1.1.0.yaml")
+	if got := result.Manifests[arrow.ArrowNamespace("steamcmd@1.1.0")]; got != system.URL(expected) {
+		t.Errorf("Expected relative manifest URL %q, got %q", expected, got)
 	}
 }
 
-func TestQTL_GetSupportedVersions(t *testing.T) {
-	mockFNS := fns.NewFNS()
-	qtl := NewQTL(mockFNS)
+func TestQTL_Translate_Unsupported(t *testing.T) {
+	qtl := NewQTL(fns.NewFNS())
 	ctx := context.Background()
 
-	versions, err := qtl.GetSupportedVersions(ctx)
-	if err != nil {
-		t.Errorf("GetSupportedVersions() returned error: %v", err)
+	if _, err := qtl.Translate(ctx, writeManifest(t, `manifest: "arrow@v1"`)); err == nil {
+		t.Error("Translate() should reject manifests that are not quivers")
 	}
-	if versions != nil {
-		t.Error("GetSupportedVersions() should return nil for unimplemented method")
-	}
-}
 
-func TestQTL_InterfaceCompliance(t *testing.T) {
-	// Test that QTL implements the expected interface
-	mockFNS := fns.NewFNS()
-	qtl := NewQTL(mockFNS)
-	if qtl == nil {
-		t.Error("QTL should not be nil")
+	if _, err := qtl.Translate(ctx, "test-input"); err == nil {
+		t.Error("Translate() should return an error for a missing manifest")
 	}
 }
 
-func TestQTL_MultipleInstances(t *testing.T) {
-	mockFNS := fns.NewFNS()
-	qtl1 := NewQTL(mockFNS)
-	qtl2 := NewQTL(mockFNS)
+func TestQTL_GetManifestVersion(t *testing.T) {
+	qtl := NewQTL(fns.NewFNS())
 
-	// Both should be valid
-	if qtl1 == nil || qtl2 == nil {
-		t.Error("NewQTL() returned nil instance")
+	version, err := qtl.GetManifestVersion(context.Background(), writeManifest(t, testManifest))
+	if err != nil {
+		t.Errorf("GetManifestVersion() returned error: %v", err)
 	}
+	if version != ManifestV1 {
+		t.Errorf("Expected manifest version %q, got %q", ManifestV1, version)
+	}
+}
 
-	// Test that both instances work correctly
-	ctx := context.Background()
-	compatible1, _ := qtl1.IsCompatible(ctx, "test")
-	compatible2, _ := qtl2.IsCompatible(ctx, "test")
+func TestQTL_GetSupportedVersions(t *testing.T) {
+	qtl := NewQTL(fns.NewFNS())
 
-	if compatible1 != compatible2 {
-		t.Error("Both instances should have same IsCompatible behavior")
+	versions, err := qtl.GetSupportedVersions(context.Background())
+	if err != nil {
+		t.Errorf("GetSupportedVersions() returned error: %v", err)
+	}
+	if len(versions) != 1 || versions[0] != ManifestV1 {
+		t.Errorf("Expected supported versions [%s], got %v", ManifestV1, versions)
 	}
 }
 
-func TestQTL_AllMethods(t *testing.T) {
-	mockFNS := fns.NewFNS()
-	qtl := NewQTL(mockFNS)
-	ctx := context.Background()
-
-	// Test all methods to ensure they don't panic
+func TestResolveManifestURL(t *testing.T) {
 	testCases := []struct {
-		name string
-		fn   func() error
+		name         string
+		manifestPath string
+		manifestURL  string
+		expected     string
 	}{
-		{"IsCompatible", func() error {
-			_, err := qtl.IsCompatible(ctx, "test")
-			return err
-		}},
-		{"Translate", func() error {
-			_, err := qtl.Translate(ctx, "test")
-			return err
-		}},
-		{"GetManifestVersion", func() error {
-			_, err := qtl.GetManifestVersion(ctx, "test")
-			return err
-		}},
-		{"GetSupportedVersions", func() error {
-			_, err := qtl.GetSupportedVersions(ctx)
-			return err
-		}},
+		{
+			name:         "absolute URL",
+			manifestPath: "https://quiver.ar/quiver.yaml",
+			manifestURL:  "https://cdn.quiver.ar/chat.yaml",
+			expected:     "https://cdn.quiver.ar/chat.yaml",
+		},
+		{
+			name:         "relative to remote quiver",
+			manifestPath: "https://quiver.ar/quiver/quiver.yaml",
+			manifestURL:  "arrows/chat.yaml",
+			expected:     "https://quiver.ar/quiver/arrows/chat.yaml",
+		},
+		{
+			name:         "relative to local quiver",
+			manifestPath: "pkgs/quiver.yaml",
+			manifestURL:  "arrows/chat.yaml",
+			expected:     filepath.Join("pkgs", "arrows", "chat.yaml"),
+		},
+		{
+			name:         "empty URL",
+			manifestPath: "pkgs/quiver.yaml",
+			manifestURL:  "",
+			expected:     "",
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			err := tc.fn()
-			if err != nil {
-				t.Errorf("%s() returned error: %v", tc.name, err)
+			if got := resolveManifestURL(tc.manifestPath, tc.manifestURL); got != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, got)
 			}
 		})
 	}
@@ -1,11 +1,16 @@
 package internal
 
 import (
+	"context"
+	"time"
+
 	"github.com/rabbytesoftware/quiver/internal/api"
 	"github.com/rabbytesoftware/quiver/internal/core"
+	"github.com/rabbytesoftware/quiver/internal/core/config"
 	"github.com/rabbytesoftware/quiver/internal/infrastructure"
 	"github.com/rabbytesoftware/quiver/internal/repositories"
 	"github.com/rabbytesoftware/quiver/internal/usecases"
+	"github.com/rabbytesoftware/quiver/internal/usecases/arrows"
 )
 
 // ? Internal DI Container
@@ -15,6 +20,9 @@ import (
 // ? All other services are internal and are not exposed to the outside world as they are
 // ? essential for the internal workings of the application and not intended to be used directly.
 
+// shutdownTimeout bounds how long Shutdown waits for the gateway.
+const shutdownTimeout = 10 * time.Second
+
 type Internal struct {
 	core           *core.Core
 	api            *api.API
@@ -40,9 +48,27 @@ func NewInternal() *Internal {
 }
 
 func (i *Internal) Run() {
+	go i.usecases.Arrows.WatchUpdates(
+		context.Background(),
+		arrows.UpdateCheckInterval(config.GetArrows().UpdateCheckInterval),
+	)
+	go i.usecases.Arrows.WatchMaintenance(context.Background(), time.Minute)
+	go i.usecases.Arrows.WatchPorts(context.Background(), time.Minute)
+	go i.usecases.Tasks.Watch(context.Background())
+	go i.usecases.System.ProbeNetbridge(context.Background())
+
 	i.api.Run()
 }
 
+// Shutdown removes the ports forwarded on the gateway, so none are
+// left open once Quiver stops.
+func (i *Internal) Shutdown() {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	i.usecases.Arrows.ClosePorts(ctx)
+}
+
 func (i *Internal) GetCore() *core.Core {
 	return i.core
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/rabbytesoftware/quiver/internal/models/requirement"
 	"github.com/rabbytesoftware/quiver/internal/models/runtime"
 	"github.com/rabbytesoftware/quiver/internal/models/system"
+	"github.com/rabbytesoftware/quiver/internal/models/template"
 	"github.com/rabbytesoftware/quiver/internal/models/variable"
 )
 
@@ -21,6 +22,7 @@ type Arrow struct {
 	Credits       []string       `json:"credits" gorm:"serializer:json"`
 	URL           system.URL     `json:"url"`
 	Documentation string         `json:"documentation"`
+	Changelog     system.URL     `json:"changelog"`
 
 	Requirements requirement.Requirement `json:"requirements" gorm:"serializer:json"`
 	Dependencies []ArrowNamespace        `json:"dependencies" gorm:"serializer:json"`
@@ -29,4 +31,26 @@ type Arrow struct {
 	Variables []variable.Variable `json:"variables" gorm:"serializer:json"`
 
 	Methods []runtime.Method `json:"methods" gorm:"serializer:json"`
+
+	// Data lists the install paths kept in the data directory.
+	Data DataPaths `json:"data,omitempty" gorm:"serializer:json"`
+
+	// Templates are config files rendered with the variables,
+	// Rendered what was last written for each of them.
+	Templates []template.Template `json:"templates,omitempty" gorm:"serializer:json"`
+	Rendered  []template.Rendered `json:"rendered,omitempty" gorm:"serializer:json"`
+
+	// Source is the quiver the arrow was found in, ManifestHash the
+	// SHA-256 of the manifest it was translated from.
+	Source       system.URL `json:"source"`
+	ManifestURL  system.URL `json:"manifest_url"`
+	ManifestHash string     `json:"manifest_hash"`
+	Artifacts    []Artifact `json:"artifacts" gorm:"serializer:json"`
+
+	UpdatePolicy UpdatePolicy `json:"update_policy" gorm:"serializer:json"`
+
+	// Backup is declared by the manifest, BackupRetention
+	// is set per installed instance.
+	Backup          BackupSpec      `json:"backup" gorm:"serializer:json"`
+	BackupRetention BackupRetention `json:"backup_retention" gorm:"serializer:json"`
 }
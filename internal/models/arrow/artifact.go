@@ -0,0 +1,8 @@
+package arrow
+
+// Artifact is a file downloaded while installing an arrow,
+// identified by the SHA-256 of its content.
+type Artifact struct {
+	URL    string `json:"url" yaml:"url"`
+	SHA256 string `json:"sha256" yaml:"sha256"`
+}
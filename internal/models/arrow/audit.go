@@ -0,0 +1,41 @@
+package arrow
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rabbytesoftware/quiver/internal/models/variable"
+)
+
+// VariableChange records who changed a variable of an instance.
+// From and To are masked for sensitive variables.
+type VariableChange struct {
+	ID        uuid.UUID `json:"id" gorm:"primaryKey"`
+	ArrowID   uuid.UUID `json:"arrow_id" gorm:"index"`
+	Variable  string    `json:"variable"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Actor     string    `json:"actor"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// NewVariableChange records a change of v from one value to another.
+func NewVariableChange(
+	arrow *Arrow,
+	v variable.Variable,
+	from string,
+	to string,
+	actor string,
+	changedAt time.Time,
+) *VariableChange {
+	return &VariableChange{
+		ID:        uuid.New(),
+		ArrowID:   arrow.ID,
+		Variable:  v.Name,
+		From:      v.Display(from),
+		To:        v.Display(to),
+		Actor:     actor,
+		ChangedAt: changedAt,
+	}
+}
@@ -0,0 +1,25 @@
+package arrow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rabbytesoftware/quiver/internal/models/variable"
+)
+
+func TestNewVariableChange(t *testing.T) {
+	a := &Arrow{ID: uuid.New()}
+	now := time.Now()
+
+	change := NewVariableChange(a, variable.Variable{Name: "MAXPLAYERS"}, "10", "12", "admin", now)
+	if change.ArrowID != a.ID || change.From != "10" || change.To != "12" || change.Actor != "admin" {
+		t.Errorf("Unexpected change: %+v", change)
+	}
+
+	secret := NewVariableChange(a, variable.Variable{Name: "RCON_PASSWORD", Sensitive: true}, "old", "new", "admin", now)
+	if secret.From != variable.Masked || secret.To != variable.Masked {
+		t.Errorf("Expected sensitive values to be masked, got %+v", secret)
+	}
+}
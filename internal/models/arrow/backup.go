@@ -0,0 +1,145 @@
+package arrow
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// BackupSpec selects the files of an install directory that go into
+// a backup. Patterns are slash separated and relative to the install
+// directory; "**" matches any number of directories. An empty
+// Include selects everything.
+type BackupSpec struct {
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// Selects reports whether a relative file path belongs in a backup.
+func (s BackupSpec) Selects(rel string) bool {
+	rel = strings.TrimPrefix(path.Clean(strings.ReplaceAll(rel, "\\", "/")), "./")
+
+	included := len(s.Include) == 0
+	for _, pattern := range s.Include {
+		if MatchGlob(pattern, rel) {
+			included = true
+			break
+		}
+	}
+
+	if !included {
+		return false
+	}
+
+	for _, pattern := range s.Exclude {
+		if MatchGlob(pattern, rel) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s BackupSpec) Validate() error {
+	for _, pattern := range append(append([]string{}, s.Include...), s.Exclude...) {
+		for _, segment := range strings.Split(pattern, "/") {
+			if segment == "**" {
+				continue
+			}
+			if _, err := path.Match(segment, ""); err != nil {
+				return fmt.Errorf("invalid backup pattern %q: %w", pattern, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// MatchGlob matches a slash separated path against a pattern where
+// "**" stands for any number of path segments. A pattern matching
+// a directory also matches everything below it.
+func MatchGlob(pattern, name string) bool {
+	patterns := strings.Split(strings.Trim(pattern, "/"), "/")
+	names := strings.Split(name, "/")
+
+	return matchSegments(patterns, names)
+}
+
+func matchSegments(patterns, names []string) bool {
+	if len(patterns) == 0 {
+		// ? Everything below a matched directory is matched too.
+		return true
+	}
+
+	if patterns[0] == "**" {
+		for i := 0; i <= len(names); i++ {
+			if matchSegments(patterns[1:], names[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(names) == 0 {
+		return false
+	}
+
+	if ok, err := path.Match(patterns[0], names[0]); err != nil || !ok {
+		return false
+	}
+
+	return matchSegments(patterns[1:], names[1:])
+}
+
+// Backup is a compressed archive of an arrow's install directory.
+// ID is the archive file name without its extension.
+type Backup struct {
+	ID        string    `json:"id"`
+	Arrow     string    `json:"arrow"`
+	Version   string    `json:"version"`
+	Path      string    `json:"path"`
+	Size      int64     `json:"size"`
+	Files     int       `json:"files"`
+	Quiesced  bool      `json:"quiesced"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BackupRetention limits how many backups of an arrow are kept.
+// Zero values mean no limit. The newest backup is always kept.
+type BackupRetention struct {
+	Keep       int `json:"keep"`
+	MaxAgeDays int `json:"max_age_days"`
+}
+
+func (r BackupRetention) Validate() error {
+	if r.Keep < 0 || r.MaxAgeDays < 0 {
+		return fmt.Errorf("backup retention cannot be negative")
+	}
+	return nil
+}
+
+// Expired returns the backups the retention policy drops at now.
+func (r BackupRetention) Expired(backups []Backup, now time.Time) []Backup {
+	sorted := append([]Backup{}, backups...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.After(sorted[j].CreatedAt)
+	})
+
+	expired := []Backup{}
+	for i, backup := range sorted {
+		if i == 0 {
+			continue
+		}
+
+		tooMany := r.Keep > 0 && i >= r.Keep
+		tooOld := r.MaxAgeDays > 0 && now.Sub(backup.CreatedAt) > time.Duration(r.MaxAgeDays)*24*time.Hour
+
+		if tooMany || tooOld {
+			expired = append(expired, backup)
+		}
+	}
+
+	return expired
+}
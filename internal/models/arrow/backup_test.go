@@ -0,0 +1,127 @@
+package arrow
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMatchGlob(t *testing.T) {
+	testCases := []struct {
+		pattern  string
+		name     string
+		expected bool
+	}{
+		{"world", "world", true},
+		{"world", "world/region/r.0.0.mca", true},
+		{"world/**", "world/region/r.0.0.mca", true},
+		{"*.cfg", "server.cfg", true},
+		{"*.cfg", "cfg/server.cfg", false},
+		{"**/*.cfg", "cfg/server.cfg", true},
+		{"**/*.cfg", "server.cfg", true},
+		{"**/logs", "game/logs/latest.log", true},
+		{"saves/*.sav", "saves/slot1.sav", true},
+		{"saves/*.sav", "saves/old/slot1.sav", false},
+		{"world", "world_nether", false},
+		{"bin", "binaries", false},
+	}
+
+	for _, tc := range testCases {
+		if got := MatchGlob(tc.pattern, tc.name); got != tc.expected {
+			t.Errorf("MatchGlob(%q, %q) = %v, expected %v", tc.pattern, tc.name, got, tc.expected)
+		}
+	}
+}
+
+func TestBackupSpec_Selects(t *testing.T) {
+	spec := BackupSpec{
+		Include: []string{"world", "*.cfg"},
+		Exclude: []string{"**/*.lock", "world/cache"},
+	}
+
+	testCases := map[string]bool{
+		"world/level.dat":      true,
+		"world/session.lock":   false,
+		"world/cache/tile.bin": false,
+		"server.cfg":           true,
+		"./server.cfg":         true,
+		"bin/server":           false,
+	}
+
+	for name, expected := range testCases {
+		if got := spec.Selects(name); got != expected {
+			t.Errorf("Selects(%q) = %v, expected %v", name, got, expected)
+		}
+	}
+
+	everything := BackupSpec{Exclude: []string{"logs"}}
+	if !everything.Selects("bin/server") || everything.Selects("logs/latest.log") {
+		t.Error("Expected an empty include to select everything but the excludes")
+	}
+}
+
+func TestBackupSpec_Validate(t *testing.T) {
+	if err := (BackupSpec{Include: []string{"world/**", "*.cfg"}}).Validate(); err != nil {
+		t.Errorf("Expected valid patterns, got %v", err)
+	}
+
+	if err := (BackupSpec{Exclude: []string{"[logs"}}).Validate(); err == nil {
+		t.Error("Expected an error for a malformed pattern")
+	}
+}
+
+func TestBackupRetention_Expired(t *testing.T) {
+	now := time.Date(2025, 7, 10, 0, 0, 0, 0, time.UTC)
+	backups := []Backup{
+		{ID: "day9", CreatedAt: now.AddDate(0, 0, -1)},
+		{ID: "day1", CreatedAt: now.AddDate(0, 0, -9)},
+		{ID: "day8", CreatedAt: now.AddDate(0, 0, -2)},
+		{ID: "day5", CreatedAt: now.AddDate(0, 0, -5)},
+	}
+
+	ids := func(backups []Backup) []string {
+		var result []string
+		for _, b := range backups {
+			result = append(result, b.ID)
+		}
+		return result
+	}
+
+	testCases := []struct {
+		name      string
+		retention BackupRetention
+		expected  []string
+	}{
+		{"unlimited", BackupRetention{}, nil},
+		{"keep 2", BackupRetention{Keep: 2}, []string{"day5", "day1"}},
+		{"max age 3 days", BackupRetention{MaxAgeDays: 3}, []string{"day5", "day1"}},
+		{"both", BackupRetention{Keep: 3, MaxAgeDays: 7}, []string{"day1"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ids(tc.retention.Expired(backups, now))
+			if len(got) != len(tc.expected) {
+				t.Fatalf("Expected %v, got %v", tc.expected, got)
+			}
+			for i := range got {
+				if got[i] != tc.expected[i] {
+					t.Errorf("Expected %v, got %v", tc.expected, got)
+				}
+			}
+		})
+	}
+
+	old := []Backup{{ID: "only", CreatedAt: now.AddDate(-1, 0, 0)}}
+	if expired := (BackupRetention{MaxAgeDays: 1}).Expired(old, now); len(expired) != 0 {
+		t.Errorf("Expected the newest backup to always be kept, got %v", ids(expired))
+	}
+}
+
+func TestBackupRetention_Validate(t *testing.T) {
+	if err := (BackupRetention{Keep: 7, MaxAgeDays: 30}).Validate(); err != nil {
+		t.Errorf("Expected a valid retention, got %v", err)
+	}
+	if err := (BackupRetention{Keep: -1}).Validate(); err == nil {
+		t.Error("Expected negative values to be rejected")
+	}
+}
@@ -0,0 +1,47 @@
+package arrow
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// DataPaths are the directories of an install that hold data such as
+// saves and configs, relative to the install directory. They are kept
+// in a separate data directory so updates and reinstalls keep them.
+type DataPaths []string
+
+func (d DataPaths) Validate() error {
+	seen := map[string]bool{}
+
+	for _, p := range d {
+		clean := path.Clean(strings.ReplaceAll(p, "\\", "/"))
+
+		switch {
+		case p == "" || clean == ".":
+			return fmt.Errorf("data path cannot be empty")
+		case path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../"):
+			return fmt.Errorf("data path %q must stay inside the install directory", p)
+		case seen[clean]:
+			return fmt.Errorf("data path %q is declared twice", p)
+		}
+
+		for other := range seen {
+			if strings.HasPrefix(clean, other+"/") || strings.HasPrefix(other, clean+"/") {
+				return fmt.Errorf("data paths %q and %q overlap", other, clean)
+			}
+		}
+		seen[clean] = true
+	}
+
+	return nil
+}
+
+// Clean returns the paths in slash separated, cleaned form.
+func (d DataPaths) Clean() []string {
+	cleaned := make([]string, 0, len(d))
+	for _, p := range d {
+		cleaned = append(cleaned, path.Clean(strings.ReplaceAll(p, "\\", "/")))
+	}
+	return cleaned
+}
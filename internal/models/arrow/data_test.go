@@ -0,0 +1,42 @@
+package arrow
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDataPaths_Validate(t *testing.T) {
+	testCases := []struct {
+		name  string
+		paths DataPaths
+		valid bool
+	}{
+		{"none", nil, true},
+		{"saves and configs", DataPaths{"saves", "cfg/server"}, true},
+		{"empty", DataPaths{""}, false},
+		{"dot", DataPaths{"./"}, false},
+		{"absolute", DataPaths{"/var/lib/saves"}, false},
+		{"escapes", DataPaths{"../saves"}, false},
+		{"escapes after clean", DataPaths{"saves/../../x"}, false},
+		{"duplicate", DataPaths{"saves", "saves/"}, false},
+		{"nested", DataPaths{"cfg", "cfg/server"}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.paths.Validate()
+			if (err == nil) != tc.valid {
+				t.Errorf("Validate() error = %v, valid = %v", err, tc.valid)
+			}
+		})
+	}
+}
+
+func TestDataPaths_Clean(t *testing.T) {
+	got := DataPaths{"saves/", "cfg\\server", "./worlds"}.Clean()
+	expected := []string{"saves", "cfg/server", "worlds"}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Clean() = %v, want %v", got, expected)
+	}
+}
@@ -1,6 +1,10 @@
 package arrow
 
-import "strings"
+import (
+	"strings"
+
+	"github.com/rabbytesoftware/quiver/internal/models/version"
+)
 
 type ArrowNamespace string
 
@@ -15,9 +19,38 @@ func (a ArrowNamespace) IsValid() bool {
 		return false
 	}
 
-	return true
+	// The part after the "@" must be a version or version range
+	_, err := version.ParseConstraint(parts[1])
+	return err == nil
+}
+
+// Constraint parses the version part of the namespace.
+// A namespace without a version accepts any release.
+func (a ArrowNamespace) Constraint() (version.Constraint, error) {
+	return version.ParseConstraint(a.Version())
+}
+
+// Name returns the arrow name, the part before the "@".
+func (a ArrowNamespace) Name() string {
+	name, _, _ := strings.Cut(string(a), "@")
+	return name
+}
+
+// Version returns the version constraint, the part after the "@".
+// An empty string means any version is acceptable.
+func (a ArrowNamespace) Version() string {
+	_, version, _ := strings.Cut(string(a), "@")
+	return version
 }
 
 func (a ArrowNamespace) String() string {
 	return string(a)
 }
+
+func NewArrowNamespace(name, version string) ArrowNamespace {
+	if version == "" {
+		return ArrowNamespace(name)
+	}
+
+	return ArrowNamespace(name + "@" + version)
+}
@@ -1,6 +1,10 @@
 package arrow
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/rabbytesoftware/quiver/internal/models/version"
+)
 
 func TestArrowNamespace_IsValid(t *testing.T) {
 	testCases := []struct {
@@ -10,12 +14,12 @@ func TestArrowNamespace_IsValid(t *testing.T) {
 	}{
 		{
 			name:      "valid namespace",
-			namespace: ArrowNamespace("user@domain"),
+			namespace: ArrowNamespace("steamcmd@1.0.0"),
 			expected:  true,
 		},
 		{
 			name:      "valid namespace with numbers",
-			namespace: ArrowNamespace("user123@domain456"),
+			namespace: ArrowNamespace("arrow123@456"),
 			expected:  true,
 		},
 		{
@@ -50,14 +54,39 @@ func TestArrowNamespace_IsValid(t *testing.T) {
 		},
 		{
 			name:      "valid with special characters",
-			namespace: ArrowNamespace("user-name@domain.com"),
+			namespace: ArrowNamespace("quiver-chat.server@27.7.1-d278aae"),
 			expected:  true,
 		},
 		{
 			name:      "valid with underscores",
-			namespace: ArrowNamespace("user_name@domain_name"),
+			namespace: ArrowNamespace("arrow_name@v2.0.0"),
+			expected:  true,
+		},
+		{
+			name:      "valid caret range",
+			namespace: ArrowNamespace("steamcmd@^1.2.0"),
+			expected:  true,
+		},
+		{
+			name:      "valid compound range",
+			namespace: ArrowNamespace("steamcmd@>=1.0.0 <2.0.0"),
 			expected:  true,
 		},
+		{
+			name:      "valid latest",
+			namespace: ArrowNamespace("steamcmd@latest"),
+			expected:  true,
+		},
+		{
+			name:      "version part is not a version",
+			namespace: ArrowNamespace("user@domain"),
+			expected:  false,
+		},
+		{
+			name:      "malformed range",
+			namespace: ArrowNamespace("steamcmd@^one"),
+			expected:  false,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -125,28 +154,107 @@ func TestArrowNamespace_TypeConversion(t *testing.T) {
 
 func TestArrowNamespace_EdgeCases(t *testing.T) {
 	// Test with whitespace
-	namespace := ArrowNamespace(" user @ domain ")
+	namespace := ArrowNamespace(" user @ 1.0.0 ")
 	if !namespace.IsValid() {
 		t.Error("Expected namespace with spaces to be valid (spaces are allowed)")
 	}
 
 	// Test with unicode characters
-	namespace = ArrowNamespace("用户@域名")
+	namespace = ArrowNamespace("用户@1.0.0")
 	if !namespace.IsValid() {
 		t.Error("Expected unicode namespace to be valid")
 	}
 
+	// Test with a version part that is not a version
+	namespace = ArrowNamespace("用户@域名")
+	if namespace.IsValid() {
+		t.Error("Expected unicode version to be invalid")
+	}
+
 	// Test very long namespace
 	longUser := make([]byte, 1000)
 	for i := range longUser {
 		longUser[i] = 'a'
 	}
-	longDomain := make([]byte, 1000)
-	for i := range longDomain {
-		longDomain[i] = 'b'
-	}
-	longNamespace := ArrowNamespace(string(longUser) + "@" + string(longDomain))
+	longNamespace := ArrowNamespace(string(longUser) + "@1.0.0")
 	if !longNamespace.IsValid() {
 		t.Error("Expected very long namespace to be valid")
 	}
 }
+
+func TestArrowNamespace_NameAndVersion(t *testing.T) {
+	testCases := []struct {
+		name            string
+		namespace       ArrowNamespace
+		expectedName    string
+		expectedVersion string
+	}{
+		{
+			name:            "name and version",
+			namespace:       ArrowNamespace("steamcmd@1.2.0"),
+			expectedName:    "steamcmd",
+			expectedVersion: "1.2.0",
+		},
+		{
+			name:            "name only",
+			namespace:       ArrowNamespace("steamcmd"),
+			expectedName:    "steamcmd",
+			expectedVersion: "",
+		},
+		{
+			name:            "calendar version with build",
+			namespace:       ArrowNamespace("quiver.chat@27.7.1-d278aae"),
+			expectedName:    "quiver.chat",
+			expectedVersion: "27.7.1-d278aae",
+		},
+		{
+			name:            "empty namespace",
+			namespace:       ArrowNamespace(""),
+			expectedName:    "",
+			expectedVersion: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.namespace.Name(); got != tc.expectedName {
+				t.Errorf("Expected Name() to return %q, got %q", tc.expectedName, got)
+			}
+			if got := tc.namespace.Version(); got != tc.expectedVersion {
+				t.Errorf("Expected Version() to return %q, got %q", tc.expectedVersion, got)
+			}
+		})
+	}
+}
+
+func TestNewArrowNamespace(t *testing.T) {
+	if got := NewArrowNamespace("steamcmd", "1.0.0"); got != ArrowNamespace("steamcmd@1.0.0") {
+		t.Errorf("Expected steamcmd@1.0.0, got %q", got)
+	}
+
+	if got := NewArrowNamespace("steamcmd", ""); got != ArrowNamespace("steamcmd") {
+		t.Errorf("Expected steamcmd, got %q", got)
+	}
+}
+
+func TestArrowNamespace_Constraint(t *testing.T) {
+	constraint, err := ArrowNamespace("steamcmd@^1.2.0").Constraint()
+	if err != nil {
+		t.Fatalf("Constraint() returned error: %v", err)
+	}
+	if !constraint.Check(version.MustParse("1.9.0")) {
+		t.Error("Expected ^1.2.0 to accept 1.9.0")
+	}
+
+	constraint, err = ArrowNamespace("steamcmd").Constraint()
+	if err != nil {
+		t.Fatalf("Constraint() returned error: %v", err)
+	}
+	if !constraint.Check(version.MustParse("0.0.1")) {
+		t.Error("Expected a namespace without a version to accept any release")
+	}
+
+	if _, err := ArrowNamespace("steamcmd@^one").Constraint(); err == nil {
+		t.Error("Expected Constraint() to fail for a malformed range")
+	}
+}
@@ -0,0 +1,69 @@
+package arrow
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rabbytesoftware/quiver/internal/models/schedule"
+)
+
+type UpdateMode string
+
+const (
+	UpdateManual    UpdateMode = "manual"
+	UpdateNotify    UpdateMode = "notify"
+	UpdateAutomatic UpdateMode = "automatic"
+)
+
+func (m UpdateMode) IsValid() bool {
+	return m == UpdateManual || m == UpdateNotify || m == UpdateAutomatic
+}
+
+// UpdatePolicy decides what happens when a newer release of an
+// installed arrow shows up. Automatic updates only run during the
+// minutes Window matches, e.g. "* 4-5 * * *" for 4 to 6am.
+type UpdatePolicy struct {
+	Mode   UpdateMode    `json:"mode"`
+	Window schedule.Cron `json:"window,omitempty"`
+}
+
+// Effective returns the policy with an empty mode read as manual.
+func (p UpdatePolicy) Effective() UpdatePolicy {
+	if p.Mode == "" {
+		p.Mode = UpdateManual
+	}
+	return p
+}
+
+func (p UpdatePolicy) Validate() error {
+	p = p.Effective()
+
+	if !p.Mode.IsValid() {
+		return fmt.Errorf("invalid update mode %q", p.Mode)
+	}
+
+	if p.Window == "" {
+		if p.Mode == UpdateAutomatic {
+			return fmt.Errorf("automatic updates require a maintenance window")
+		}
+		return nil
+	}
+
+	_, err := p.Window.Parse()
+	return err
+}
+
+// InWindow reports whether t falls inside the maintenance window.
+// A policy without a window is never in it.
+func (p UpdatePolicy) InWindow(t time.Time) bool {
+	if p.Window == "" {
+		return false
+	}
+
+	expr, err := p.Window.Parse()
+	if err != nil {
+		return false
+	}
+
+	return expr.Matches(t)
+}
@@ -0,0 +1,71 @@
+package arrow
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUpdateMode_IsValid(t *testing.T) {
+	for _, mode := range []UpdateMode{UpdateManual, UpdateNotify, UpdateAutomatic} {
+		if !mode.IsValid() {
+			t.Errorf("Expected %q to be valid", mode)
+		}
+	}
+
+	for _, mode := range []UpdateMode{"", "auto", "AUTOMATIC"} {
+		if mode.IsValid() {
+			t.Errorf("Expected %q to be invalid", mode)
+		}
+	}
+}
+
+func TestUpdatePolicy_Validate(t *testing.T) {
+	testCases := []struct {
+		name   string
+		policy UpdatePolicy
+		valid  bool
+	}{
+		{"empty is manual", UpdatePolicy{}, true},
+		{"manual", UpdatePolicy{Mode: UpdateManual}, true},
+		{"notify", UpdatePolicy{Mode: UpdateNotify}, true},
+		{"automatic with window", UpdatePolicy{Mode: UpdateAutomatic, Window: "* 4-5 * * *"}, true},
+		{"automatic without window", UpdatePolicy{Mode: UpdateAutomatic}, false},
+		{"invalid window", UpdatePolicy{Mode: UpdateNotify, Window: "* 25 * * *"}, false},
+		{"unknown mode", UpdatePolicy{Mode: "sometimes"}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.policy.Validate()
+			if (err == nil) != tc.valid {
+				t.Errorf("Validate() error = %v, expected valid = %v", err, tc.valid)
+			}
+		})
+	}
+}
+
+func TestUpdatePolicy_Effective(t *testing.T) {
+	if mode := (UpdatePolicy{}).Effective().Mode; mode != UpdateManual {
+		t.Errorf("Expected manual, got %q", mode)
+	}
+
+	if mode := (UpdatePolicy{Mode: UpdateNotify}).Effective().Mode; mode != UpdateNotify {
+		t.Errorf("Expected notify, got %q", mode)
+	}
+}
+
+func TestUpdatePolicy_InWindow(t *testing.T) {
+	policy := UpdatePolicy{Mode: UpdateAutomatic, Window: "* 4-5 * * *"}
+
+	testCases := map[int]bool{3: false, 4: true, 5: true, 6: false}
+	for hour, expected := range testCases {
+		now := time.Date(2025, 7, 1, hour, 30, 0, 0, time.UTC)
+		if got := policy.InWindow(now); got != expected {
+			t.Errorf("InWindow(%02d:30) = %v, expected %v", hour, got, expected)
+		}
+	}
+
+	if (UpdatePolicy{Mode: UpdateAutomatic}).InWindow(time.Now()) {
+		t.Error("Expected a policy without window to never be in it")
+	}
+}
@@ -0,0 +1,50 @@
+package arrow
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+)
+
+// PortAssignment records the ports allocated to a named netbridge
+// entry of an instance, so it keeps them across restarts. Port is
+// the first port and EndPort the last.
+type PortAssignment struct {
+	ID       uuid.UUID     `json:"id" gorm:"primaryKey"`
+	ArrowID  uuid.UUID     `json:"arrow_id" gorm:"index"`
+	Name     string        `json:"name"`
+	Port     int           `json:"port"`
+	EndPort  int           `json:"end_port"`
+	Protocol port.Protocol `json:"protocol"`
+}
+
+// NewPortAssignment records the ports allocated to rule.
+func NewPortAssignment(arrow *Arrow, rule port.PortRule) *PortAssignment {
+	assignment := &PortAssignment{
+		ID:       uuid.New(),
+		ArrowID:  arrow.ID,
+		Name:     rule.Name,
+		Protocol: rule.Protocol,
+	}
+	assignment.Set(rule.Range())
+
+	return assignment
+}
+
+// Range returns the assigned ports.
+func (p *PortAssignment) Range() port.Range {
+	return port.Range{Start: p.Port, End: p.EndPort}
+}
+
+// Set replaces the assigned ports.
+func (p *PortAssignment) Set(r port.Range) {
+	p.Port = r.Start
+	p.EndPort = r.End
+}
+
+// Apply gives rule the assigned ports.
+func (p *PortAssignment) Apply(rule *port.PortRule) {
+	r := p.Range()
+	rule.StartPort = r.Start
+	rule.EndPort = r.End
+}
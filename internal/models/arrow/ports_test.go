@@ -0,0 +1,49 @@
+package arrow
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+)
+
+func TestPortAssignment(t *testing.T) {
+	cs2 := &Arrow{ID: uuid.New()}
+	assigned := port.PortRule{Name: "GAME_PORT", StartPort: 40128, EndPort: 40128, Protocol: port.ProtocolUDP}
+
+	assignment := NewPortAssignment(cs2, assigned)
+	if assignment.ID == uuid.Nil || assignment.ArrowID != cs2.ID {
+		t.Errorf("Expected a new assignment of the arrow, got %+v", assignment)
+	}
+	if assignment.Name != "GAME_PORT" || assignment.Port != 40128 || assignment.Protocol != port.ProtocolUDP {
+		t.Errorf("Expected the rule to be recorded, got %+v", assignment)
+	}
+
+	rule := port.PortRule{Name: "GAME_PORT", Protocol: port.ProtocolUDP}
+	assignment.Apply(&rule)
+	if rule.StartPort != 40128 || rule.EndPort != 40128 {
+		t.Errorf("Expected Apply() to set the port, got %+v", rule)
+	}
+}
+
+func TestPortAssignment_Range(t *testing.T) {
+	cs2 := &Arrow{ID: uuid.New()}
+	assigned := port.PortRule{Name: "RTP_PORTS", StartPort: 40130, EndPort: 40135, Protocol: port.ProtocolUDP}
+
+	assignment := NewPortAssignment(cs2, assigned)
+	if assignment.Range() != (port.Range{Start: 40130, End: 40135}) {
+		t.Errorf("Expected the whole range to be recorded, got %v", assignment.Range())
+	}
+
+	rule := port.PortRule{Name: "RTP_PORTS"}
+	assignment.Apply(&rule)
+	if rule.StartPort != 40130 || rule.EndPort != 40135 {
+		t.Errorf("Expected Apply() to keep the range, got %+v", rule)
+	}
+
+	single := NewPortAssignment(cs2, port.PortRule{Name: "GAME_PORT", StartPort: 27015})
+	if single.EndPort != 27015 {
+		t.Errorf("Expected EndPort to be recorded for a single port, got %+v", single)
+	}
+}
@@ -0,0 +1,51 @@
+package arrow
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RevisionAction string
+
+const (
+	RevisionInstall  RevisionAction = "install"
+	RevisionUpdate   RevisionAction = "update"
+	RevisionRollback RevisionAction = "rollback"
+)
+
+// Revision records a version an arrow instance ran at. Arrow keeps
+// the manifest and variable values of that version; Snapshot is the
+// copy of its install tree, kept only for the version before the
+// current one.
+type Revision struct {
+	ID        uuid.UUID      `json:"id" gorm:"primaryKey"`
+	ArrowID   uuid.UUID      `json:"arrow_id" gorm:"index"`
+	Version   string         `json:"version"`
+	Action    RevisionAction `json:"action"`
+	Arrow     Arrow          `json:"arrow" gorm:"serializer:json"`
+	Snapshot  string         `json:"snapshot,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// NewRevision records the current state of an arrow.
+func NewRevision(
+	arrow *Arrow,
+	action RevisionAction,
+	createdAt time.Time,
+) *Revision {
+	return &Revision{
+		ID:        uuid.New(),
+		ArrowID:   arrow.ID,
+		Version:   arrow.Version,
+		Action:    action,
+		Arrow:     *arrow,
+		CreatedAt: createdAt,
+	}
+}
+
+// HasSnapshot reports whether the install tree of this
+// revision can still be restored.
+func (r *Revision) HasSnapshot() bool {
+	return r.Snapshot != ""
+}
@@ -0,0 +1,45 @@
+package arrow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNewRevision(t *testing.T) {
+	now := time.Now()
+	a := &Arrow{ID: uuid.New(), Name: "cs2", Version: "1.0.0"}
+
+	revision := NewRevision(a, RevisionUpdate, now)
+
+	if revision.ID == uuid.Nil {
+		t.Error("Expected a revision ID")
+	}
+	if revision.ArrowID != a.ID {
+		t.Errorf("Expected arrow ID %s, got %s", a.ID, revision.ArrowID)
+	}
+	if revision.Version != "1.0.0" || revision.Action != RevisionUpdate {
+		t.Errorf("Unexpected revision %+v", revision)
+	}
+	if !revision.CreatedAt.Equal(now) {
+		t.Errorf("Expected created at %v, got %v", now, revision.CreatedAt)
+	}
+
+	a.Version = "2.0.0"
+	if revision.Arrow.Version != "1.0.0" {
+		t.Error("Expected the revision to keep a copy of the arrow")
+	}
+}
+
+func TestRevision_HasSnapshot(t *testing.T) {
+	revision := &Revision{}
+	if revision.HasSnapshot() {
+		t.Error("Expected no snapshot")
+	}
+
+	revision.Snapshot = "/tmp/snapshot"
+	if !revision.HasSnapshot() {
+		t.Error("Expected a snapshot")
+	}
+}
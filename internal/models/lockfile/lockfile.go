@@ -0,0 +1,146 @@
+package lockfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+	"github.com/rabbytesoftware/quiver/internal/models/system"
+)
+
+const LockfileV1 = "quiver-lock@v1"
+
+type Format string
+
+const (
+	FormatYAML Format = "yaml"
+	FormatJSON Format = "json"
+)
+
+// Lockfile pins every installed arrow so the same setup can be
+// reproduced on another host. Arrows are listed in install order.
+type Lockfile struct {
+	Lockfile    string    `json:"lockfile" yaml:"lockfile"`
+	GeneratedAt time.Time `json:"generated_at" yaml:"generated_at"`
+	Arrows      []Entry   `json:"arrows" yaml:"arrows"`
+}
+
+type Entry struct {
+	Namespace    arrow.ArrowNamespace `json:"namespace" yaml:"namespace"`
+	Name         string               `json:"name" yaml:"name"`
+	Version      string               `json:"version" yaml:"version"`
+	Source       system.URL           `json:"source" yaml:"source"`
+	ManifestURL  system.URL           `json:"manifest_url" yaml:"manifest_url"`
+	ManifestHash string               `json:"manifest_hash" yaml:"manifest_hash"`
+	Artifacts    []arrow.Artifact     `json:"artifacts" yaml:"artifacts"`
+	// Variables holds non-sensitive values only; sensitive ones
+	// fall back to the manifest default on import.
+	Variables map[string]string `json:"variables" yaml:"variables"`
+	Ports     []Port            `json:"ports" yaml:"ports"`
+}
+
+type Port struct {
+	Name      string        `json:"name" yaml:"name"`
+	StartPort int           `json:"start_port" yaml:"start_port"`
+	EndPort   int           `json:"end_port" yaml:"end_port"`
+	Protocol  port.Protocol `json:"protocol" yaml:"protocol"`
+}
+
+func New(arrows []arrow.Arrow, generatedAt time.Time) *Lockfile {
+	lock := &Lockfile{
+		Lockfile:    LockfileV1,
+		GeneratedAt: generatedAt.UTC(),
+		Arrows:      make([]Entry, 0, len(arrows)),
+	}
+
+	for _, a := range arrows {
+		lock.Arrows = append(lock.Arrows, NewEntry(&a))
+	}
+
+	return lock
+}
+
+func NewEntry(a *arrow.Arrow) Entry {
+	entry := Entry{
+		Namespace:    arrow.NewArrowNamespace(a.Name, a.Version),
+		Name:         a.Name,
+		Version:      a.Version,
+		Source:       a.Source,
+		ManifestURL:  a.ManifestURL,
+		ManifestHash: a.ManifestHash,
+		Artifacts:    append([]arrow.Artifact{}, a.Artifacts...),
+		Variables:    map[string]string{},
+		Ports:        []Port{},
+	}
+
+	for _, variable := range a.Variables {
+		if !variable.Sensitive {
+			entry.Variables[variable.Name] = variable.Current()
+		}
+	}
+
+	for _, rule := range a.Netbridge {
+		entry.Ports = append(entry.Ports, Port{
+			Name:      rule.Name,
+			StartPort: rule.StartPort,
+			EndPort:   rule.EndPort,
+			Protocol:  rule.Protocol,
+		})
+	}
+
+	return entry
+}
+
+// Apply copies the locked variable values and port assignments
+// onto a freshly translated arrow.
+func (e *Entry) Apply(a *arrow.Arrow) {
+	for i := range a.Variables {
+		if value, ok := e.Variables[a.Variables[i].Name]; ok && !a.Variables[i].Sensitive {
+			a.Variables[i].Value = &value
+		}
+	}
+
+	for i := range a.Netbridge {
+		for _, locked := range e.Ports {
+			if locked.Name == a.Netbridge[i].Name {
+				a.Netbridge[i].StartPort = locked.StartPort
+				a.Netbridge[i].EndPort = locked.EndPort
+			}
+		}
+	}
+}
+
+// Parse reads a lockfile in YAML or JSON, JSON being valid YAML.
+func Parse(data []byte) (*Lockfile, error) {
+	var lock Lockfile
+	if err := yaml.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse lockfile: %w", err)
+	}
+
+	if lock.Lockfile != LockfileV1 {
+		return nil, fmt.Errorf("unsupported lockfile version %q", lock.Lockfile)
+	}
+
+	for i, entry := range lock.Arrows {
+		if entry.Name == "" || entry.Version == "" || entry.ManifestURL == "" {
+			return nil, fmt.Errorf("lockfile entry %d is missing its name, version or manifest_url", i)
+		}
+	}
+
+	return &lock, nil
+}
+
+func (l *Lockfile) Marshal(format Format) ([]byte, error) {
+	switch format {
+	case FormatYAML:
+		return yaml.Marshal(l)
+	case FormatJSON:
+		return json.MarshalIndent(l, "", "  ")
+	default:
+		return nil, fmt.Errorf("unsupported lockfile format %q", format)
+	}
+}
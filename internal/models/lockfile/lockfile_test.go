@@ -0,0 +1,122 @@
+package lockfile
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+	"github.com/rabbytesoftware/quiver/internal/models/variable"
+)
+
+func installedArrow() arrow.Arrow {
+	return arrow.Arrow{
+		Name:         "cs2",
+		Version:      "1.0.0",
+		Namespace:    "cs2@1.0.0",
+		Source:       "https://quiver.ar/quiver",
+		ManifestURL:  "https://quiver.ar/quiver/arrows/cs2.yaml",
+		ManifestHash: "abc123",
+		Artifacts:    []arrow.Artifact{{URL: "https://example.com/cs2.tar.gz", SHA256: "def456"}},
+		Variables: []variable.Variable{
+			{Name: "MAX_PLAYERS", Default: "16"},
+			{Name: "RCON_PASSWORD", Default: "hunter2", Sensitive: true},
+		},
+		Netbridge: []port.PortRule{
+			{Name: "GAME_PORT", StartPort: 40130, EndPort: 40130, Protocol: port.ProtocolTCPUDP},
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	generated := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	lock := New([]arrow.Arrow{installedArrow()}, generated)
+
+	if lock.Lockfile != LockfileV1 || !lock.GeneratedAt.Equal(generated) {
+		t.Errorf("Unexpected header %q at %v", lock.Lockfile, lock.GeneratedAt)
+	}
+	if len(lock.Arrows) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(lock.Arrows))
+	}
+
+	entry := lock.Arrows[0]
+	if entry.Namespace != "cs2@1.0.0" || entry.ManifestHash != "abc123" || len(entry.Artifacts) != 1 {
+		t.Errorf("Unexpected entry %+v", entry)
+	}
+	if entry.Variables["MAX_PLAYERS"] != "16" {
+		t.Errorf("Expected MAX_PLAYERS to be locked, got %v", entry.Variables)
+	}
+	if _, ok := entry.Variables["RCON_PASSWORD"]; ok {
+		t.Error("Sensitive variables must not be written to the lockfile")
+	}
+	if len(entry.Ports) != 1 || entry.Ports[0].StartPort != 40130 {
+		t.Errorf("Expected the port assignment to be locked, got %v", entry.Ports)
+	}
+}
+
+func TestEntry_Apply(t *testing.T) {
+	entry := NewEntry(&arrow.Arrow{
+		Variables: []variable.Variable{{Name: "MAX_PLAYERS", Default: "32"}},
+		Netbridge: []port.PortRule{{Name: "GAME_PORT", StartPort: 40200, EndPort: 40201}},
+	})
+	entry.Variables["RCON_PASSWORD"] = "leaked"
+
+	fresh := installedArrow()
+	entry.Apply(&fresh)
+
+	if fresh.Variables[0].Current() != "32" {
+		t.Errorf("Expected MAX_PLAYERS 32, got %q", fresh.Variables[0].Current())
+	}
+	if fresh.Variables[1].Current() != "hunter2" {
+		t.Error("Apply() must not overwrite sensitive variables")
+	}
+	if fresh.Netbridge[0].StartPort != 40200 || fresh.Netbridge[0].EndPort != 40201 {
+		t.Errorf("Expected ports 40200-40201, got %+v", fresh.Netbridge[0])
+	}
+}
+
+func TestMarshalAndParse(t *testing.T) {
+	lock := New([]arrow.Arrow{installedArrow()}, time.Now())
+
+	for _, format := range []Format{FormatYAML, FormatJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			data, err := lock.Marshal(format)
+			if err != nil {
+				t.Fatalf("Marshal() returned error: %v", err)
+			}
+
+			parsed, err := Parse(data)
+			if err != nil {
+				t.Fatalf("Parse() returned error: %v", err)
+			}
+			if len(parsed.Arrows) != 1 || parsed.Arrows[0].Artifacts[0].SHA256 != "def456" {
+				t.Errorf("Round trip lost data: %+v", parsed)
+			}
+		})
+	}
+
+	if _, err := lock.Marshal("toml"); err == nil {
+		t.Error("Marshal() should reject unknown formats")
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	testCases := map[string]string{
+		"malformed":      "lockfile: [",
+		"wrong version":  "lockfile: quiver-lock@v9\narrows: []\n",
+		"missing fields": "lockfile: quiver-lock@v1\narrows:\n  - name: cs2\n",
+	}
+
+	for name, data := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse([]byte(data)); err == nil {
+				t.Error("Parse() should reject the lockfile")
+			}
+		})
+	}
+
+	if _, err := Parse([]byte(strings.TrimSpace("lockfile: quiver-lock@v1\narrows: []"))); err != nil {
+		t.Errorf("Parse() should accept an empty lockfile, got %v", err)
+	}
+}
@@ -23,3 +23,24 @@ func (f ForwardingStatus) IsDisabled() bool {
 func (f ForwardingStatus) IsError() bool {
 	return f == ForwardingStatusError
 }
+
+// CombineStatuses sums up the statuses of several mappings: an error
+// on any of them is an error, and they are enabled only when all of
+// them are.
+func CombineStatuses(statuses ...ForwardingStatus) ForwardingStatus {
+	if len(statuses) == 0 {
+		return ForwardingStatusDisabled
+	}
+
+	combined := ForwardingStatusEnabled
+	for _, status := range statuses {
+		switch {
+		case status.IsError():
+			return ForwardingStatusError
+		case !status.IsEnabled():
+			combined = ForwardingStatusDisabled
+		}
+	}
+
+	return combined
+}
@@ -220,3 +220,24 @@ func TestForwardingStatus_AllMethods(t *testing.T) {
 		}
 	}
 }
+
+func TestCombineStatuses(t *testing.T) {
+	testCases := []struct {
+		name     string
+		statuses []ForwardingStatus
+		expected ForwardingStatus
+	}{
+		{"none", nil, ForwardingStatusDisabled},
+		{"all enabled", []ForwardingStatus{ForwardingStatusEnabled, ForwardingStatusEnabled}, ForwardingStatusEnabled},
+		{"partly enabled", []ForwardingStatus{ForwardingStatusEnabled, ForwardingStatusDisabled}, ForwardingStatusDisabled},
+		{"any error", []ForwardingStatus{ForwardingStatusDisabled, ForwardingStatusError, ForwardingStatusEnabled}, ForwardingStatusError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if status := CombineStatuses(tc.statuses...); status != tc.expected {
+				t.Errorf("CombineStatuses() = %s, expected %s", status, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package port
+
+// NetbridgeStatus reports which port forwarding mechanism is in use.
+// Strategies are listed in the order they are tried.
+type NetbridgeStatus struct {
+	Enabled    bool             `json:"enabled"`
+	Available  bool             `json:"available"`
+	Active     string           `json:"active,omitempty"`
+	Strategies []StrategyStatus `json:"strategies"`
+	// Firewall is the host firewall forwarded ports are opened in,
+	// if one is configured.
+	Firewall *StrategyStatus `json:"firewall,omitempty"`
+}
+
+// StrategyStatus is the outcome of probing one mechanism, such as
+// UPnP or NAT-PMP, on the local gateway.
+type StrategyStatus struct {
+	Name      string `json:"name"`
+	Available bool   `json:"available"`
+	Error     string `json:"error,omitempty"`
+	// Warnings lists what may still block ports the strategy
+	// reports as open.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Strategy returns the status of the named strategy.
+func (s NetbridgeStatus) Strategy(name string) (StrategyStatus, bool) {
+	for _, strategy := range s.Strategies {
+		if strategy.Name == name {
+			return strategy, true
+		}
+	}
+
+	return StrategyStatus{}, false
+}
@@ -0,0 +1,24 @@
+package port
+
+import "testing"
+
+func TestNetbridgeStatus_Strategy(t *testing.T) {
+	status := NetbridgeStatus{
+		Enabled:   true,
+		Available: true,
+		Active:    "natpmp",
+		Strategies: []StrategyStatus{
+			{Name: "upnp", Error: "no gateway found"},
+			{Name: "natpmp", Available: true},
+		},
+	}
+
+	upnp, ok := status.Strategy("upnp")
+	if !ok || upnp.Available || upnp.Error == "" {
+		t.Errorf("Expected upnp to be unavailable with its error, got %+v", upnp)
+	}
+
+	if _, ok := status.Strategy("pcp"); ok {
+		t.Error("Expected no status for a strategy that was not probed")
+	}
+}
@@ -2,10 +2,28 @@ package port
 
 type PortRule struct {
 	ID               string           `json:"id"`
+	Name             string           `json:"name"`
 	StartPort        int              `json:"start_port"`
 	EndPort          int              `json:"end_port"`
 	Protocol         Protocol         `json:"protocol"`
 	ForwardingStatus ForwardingStatus `json:"forwarding_status"`
+
+	// Statuses breaks ForwardingStatus down per protocol, so a
+	// tcp/udp rule shows which of the two could not be forwarded.
+	Statuses []ProtocolStatus `json:"statuses,omitempty"`
+
+	// Reachability is the outcome of the last reachability check of
+	// the forwarded ports, and Diagnosis explains it when they could
+	// not be reached.
+	Reachability Reachability `json:"reachability,omitempty"`
+	Diagnosis    string       `json:"diagnosis,omitempty"`
+}
+
+// ProtocolStatus is the forwarding status of every port of a rule
+// for one protocol.
+type ProtocolStatus struct {
+	Protocol         Protocol         `json:"protocol"`
+	ForwardingStatus ForwardingStatus `json:"forwarding_status"`
 }
 
 func (p *PortRule) IsStartPortValid() bool {
@@ -15,3 +33,20 @@ func (p *PortRule) IsStartPortValid() bool {
 func (p *PortRule) IsEndPortValid() bool {
 	return p.EndPort > 0 && p.EndPort <= 65535
 }
+
+// Range returns the ports of the rule. An EndPort before StartPort
+// is read as a single port.
+func (p *PortRule) Range() Range {
+	return Range{Start: p.StartPort, End: max(p.EndPort, p.StartPort)}
+}
+
+// Ports lists every port from StartPort to EndPort, or none while
+// the rule has no port assigned.
+func (p *PortRule) Ports() []int {
+	var ports []int
+	for portNum := p.StartPort; portNum > 0 && portNum <= p.EndPort; portNum++ {
+		ports = append(ports, portNum)
+	}
+
+	return ports
+}
@@ -259,3 +259,51 @@ func TestPortRule_EdgeCasePorts(t *testing.T) {
 		})
 	}
 }
+
+func TestPortRule_Ports(t *testing.T) {
+	testCases := []struct {
+		name     string
+		rule     PortRule
+		expected []int
+	}{
+		{"single", PortRule{StartPort: 27015, EndPort: 27015}, []int{27015}},
+		{"range", PortRule{StartPort: 27015, EndPort: 27017}, []int{27015, 27016, 27017}},
+		{"unassigned", PortRule{Name: "GAME_PORT"}, nil},
+		{"reversed", PortRule{StartPort: 27017, EndPort: 27015}, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ports := tc.rule.Ports()
+			if len(ports) != len(tc.expected) {
+				t.Fatalf("Ports() = %v, expected %v", ports, tc.expected)
+			}
+			for i := range ports {
+				if ports[i] != tc.expected[i] {
+					t.Errorf("Ports() = %v, expected %v", ports, tc.expected)
+				}
+			}
+		})
+	}
+}
+
+func TestPortRule_Range(t *testing.T) {
+	testCases := []struct {
+		name     string
+		rule     PortRule
+		expected Range
+	}{
+		{"single", PortRule{StartPort: 27015, EndPort: 27015}, Range{27015, 27015}},
+		{"range", PortRule{StartPort: 27015, EndPort: 27017}, Range{27015, 27017}},
+		{"no end", PortRule{StartPort: 27015}, Range{27015, 27015}},
+		{"unassigned", PortRule{Name: "GAME_PORT"}, Range{0, 0}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.rule.Range(); got != tc.expected {
+				t.Errorf("Range() = %v, expected %v", got, tc.expected)
+			}
+		})
+	}
+}
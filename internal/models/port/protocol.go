@@ -27,3 +27,14 @@ func (p Protocol) IsUDP() bool {
 func (p Protocol) IsTCPUDP() bool {
 	return p == ProtocolTCPUDP
 }
+
+// Protocols splits tcp/udp into its two protocols. An unset or
+// unknown protocol is treated as tcp/udp.
+func (p Protocol) Protocols() []Protocol {
+	switch p {
+	case ProtocolTCP, ProtocolUDP:
+		return []Protocol{p}
+	}
+
+	return []Protocol{ProtocolTCP, ProtocolUDP}
+}
@@ -278,3 +278,29 @@ func TestProtocol_AllMethods(t *testing.T) {
 		}
 	}
 }
+
+func TestProtocol_Protocols(t *testing.T) {
+	testCases := []struct {
+		protocol Protocol
+		expected []Protocol
+	}{
+		{ProtocolTCP, []Protocol{ProtocolTCP}},
+		{ProtocolUDP, []Protocol{ProtocolUDP}},
+		{ProtocolTCPUDP, []Protocol{ProtocolTCP, ProtocolUDP}},
+		{Protocol(""), []Protocol{ProtocolTCP, ProtocolUDP}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.protocol), func(t *testing.T) {
+			protocols := tc.protocol.Protocols()
+			if len(protocols) != len(tc.expected) {
+				t.Fatalf("Protocols() = %v, expected %v", protocols, tc.expected)
+			}
+			for i := range protocols {
+				if protocols[i] != tc.expected[i] {
+					t.Errorf("Protocols() = %v, expected %v", protocols, tc.expected)
+				}
+			}
+		})
+	}
+}
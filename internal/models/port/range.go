@@ -0,0 +1,87 @@
+package port
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidRange = errors.New("invalid port range")
+
+// Range is an inclusive span of port numbers.
+type Range struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+func (r Range) Contains(port int) bool {
+	return port >= r.Start && port <= r.End
+}
+
+// Overlaps reports whether r and other share a port.
+func (r Range) Overlaps(other Range) bool {
+	return r.Start <= other.End && other.Start <= r.End
+}
+
+// Len is the number of ports in r.
+func (r Range) Len() int {
+	return r.End - r.Start + 1
+}
+
+func (r Range) String() string {
+	if r.Start == r.End {
+		return strconv.Itoa(r.Start)
+	}
+
+	return fmt.Sprintf("%d-%d", r.Start, r.End)
+}
+
+// ParseRanges reads a comma separated list of ports and port ranges
+// such as "27015,40128-40256". An empty list allows no ports.
+func ParseRanges(spec string) ([]Range, error) {
+	ranges := []Range{}
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		start, end, isRange := strings.Cut(part, "-")
+		if !isRange {
+			end = start
+		}
+
+		r, err := parseRange(strings.TrimSpace(start), strings.TrimSpace(end))
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: %w", ErrInvalidRange, part, err)
+		}
+
+		ranges = append(ranges, r)
+	}
+
+	return ranges, nil
+}
+
+func parseRange(start, end string) (Range, error) {
+	first, err := strconv.Atoi(start)
+	if err != nil {
+		return Range{}, err
+	}
+
+	last, err := strconv.Atoi(end)
+	if err != nil {
+		return Range{}, err
+	}
+
+	r := Range{Start: first, End: last}
+	if r.Start < 1 || r.End > 65535 {
+		return Range{}, fmt.Errorf("ports must be between 1 and 65535")
+	}
+	if r.Start > r.End {
+		return Range{}, fmt.Errorf("start is after end")
+	}
+
+	return r, nil
+}
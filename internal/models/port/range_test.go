@@ -0,0 +1,64 @@
+package port
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseRanges(t *testing.T) {
+	testCases := []struct {
+		name     string
+		spec     string
+		expected []Range
+	}{
+		{"range", "40128-40256", []Range{{40128, 40256}}},
+		{"single port", "27015", []Range{{27015, 27015}}},
+		{"list", " 27015 , 40128 - 40130 ", []Range{{27015, 27015}, {40128, 40130}}},
+		{"trailing comma", "27015,", []Range{{27015, 27015}}},
+		{"empty", "", []Range{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ranges, err := ParseRanges(tc.spec)
+			if err != nil {
+				t.Fatalf("ParseRanges(%q) returned error: %v", tc.spec, err)
+			}
+			if !reflect.DeepEqual(ranges, tc.expected) {
+				t.Errorf("ParseRanges(%q) = %v, expected %v", tc.spec, ranges, tc.expected)
+			}
+		})
+	}
+}
+
+func TestParseRanges_Invalid(t *testing.T) {
+	for _, spec := range []string{"abc", "40256-40128", "0-10", "65530-65536", "1-2-3", "-5"} {
+		t.Run(spec, func(t *testing.T) {
+			if _, err := ParseRanges(spec); !errors.Is(err, ErrInvalidRange) {
+				t.Errorf("Expected ErrInvalidRange for %q, got %v", spec, err)
+			}
+		})
+	}
+}
+
+func TestRange(t *testing.T) {
+	r := Range{Start: 40128, End: 40130}
+
+	if !r.Contains(40128) || !r.Contains(40130) || r.Contains(40131) {
+		t.Errorf("Contains() should include both ends of %v only", r)
+	}
+	if r.String() != "40128-40130" || (Range{27015, 27015}).String() != "27015" {
+		t.Errorf("Unexpected String() of %v", r)
+	}
+	if r.Len() != 3 || (Range{27015, 27015}).Len() != 1 {
+		t.Errorf("Unexpected Len() of %v", r)
+	}
+
+	if !r.Overlaps(Range{40130, 40140}) || !r.Overlaps(Range{40129, 40129}) || !r.Overlaps(Range{40100, 40200}) {
+		t.Errorf("Expected %v to overlap ranges sharing a port", r)
+	}
+	if r.Overlaps(Range{40131, 40140}) || r.Overlaps(Range{40100, 40127}) {
+		t.Errorf("Expected %v not to overlap adjacent ranges", r)
+	}
+}
@@ -0,0 +1,27 @@
+package port
+
+// Reachability tells whether a forwarded port answers from the
+// internet.
+type Reachability string
+
+const (
+	ReachabilityReachable   Reachability = "reachable"
+	ReachabilityUnreachable Reachability = "unreachable"
+	ReachabilityUnknown     Reachability = "unknown"
+)
+
+func (r Reachability) String() string {
+	return string(r)
+}
+
+func (r Reachability) IsReachable() bool {
+	return r == ReachabilityReachable
+}
+
+func (r Reachability) IsUnreachable() bool {
+	return r == ReachabilityUnreachable
+}
+
+func (r Reachability) IsUnknown() bool {
+	return r == ReachabilityUnknown
+}
@@ -0,0 +1,35 @@
+package port
+
+import "testing"
+
+func TestReachability(t *testing.T) {
+	testCases := []struct {
+		name         string
+		reachability Reachability
+		reachable    bool
+		unreachable  bool
+		unknown      bool
+	}{
+		{"reachable", ReachabilityReachable, true, false, false},
+		{"unreachable", ReachabilityUnreachable, false, true, false},
+		{"unknown", ReachabilityUnknown, false, false, true},
+		{"unchecked", Reachability(""), false, false, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.reachability.String() != string(tc.reachability) {
+				t.Errorf("String() = %q", tc.reachability.String())
+			}
+			if tc.reachability.IsReachable() != tc.reachable {
+				t.Errorf("IsReachable() = %v, expected %v", tc.reachability.IsReachable(), tc.reachable)
+			}
+			if tc.reachability.IsUnreachable() != tc.unreachable {
+				t.Errorf("IsUnreachable() = %v, expected %v", tc.reachability.IsUnreachable(), tc.unreachable)
+			}
+			if tc.reachability.IsUnknown() != tc.unknown {
+				t.Errorf("IsUnknown() = %v, expected %v", tc.reachability.IsUnknown(), tc.unknown)
+			}
+		})
+	}
+}
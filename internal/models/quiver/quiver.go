@@ -1,19 +1,30 @@
 package quiver
 
 import (
+	"github.com/google/uuid"
+
 	"github.com/rabbytesoftware/quiver/internal/models/arrow"
 	"github.com/rabbytesoftware/quiver/internal/models/system"
 )
 
 type Quiver struct {
-	ID              string                 `json:"id"`
+	ID              uuid.UUID              `json:"id" gorm:"primaryKey"`
 	Name            string                 `json:"name"`
 	Description     string                 `json:"description"`
 	Banner          system.URL             `json:"banner"`
 	URL             system.URL             `json:"url"`
 	Security        system.Security        `json:"security"`
-	Maintainers     []string               `json:"maintainers"`
+	Maintainers     []string               `json:"maintainers" gorm:"serializer:json"`
 	Version         string                 `json:"version"`
-	InstalledArrows []arrow.Arrow          `json:"installed_arrows"`
-	ListedArrows    []arrow.ArrowNamespace `json:"listed_arrows"`
+	InstalledArrows []arrow.Arrow          `json:"installed_arrows" gorm:"serializer:json"`
+	ListedArrows    []arrow.ArrowNamespace `json:"listed_arrows" gorm:"serializer:json"`
+
+	// Manifests maps every listed arrow to the URL of its manifest
+	Manifests map[arrow.ArrowNamespace]system.URL `json:"manifests" gorm:"serializer:json"`
+}
+
+// NewID derives the ID of a quiver from its name, so the same quiver
+// keeps its ID across fetches.
+func NewID(name string) uuid.UUID {
+	return uuid.NewSHA1(uuid.NameSpaceDNS, []byte(name))
 }
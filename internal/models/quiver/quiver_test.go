@@ -10,8 +10,9 @@ import (
 
 func TestQuiver_Structure(t *testing.T) {
 	// Test that Quiver struct can be created and has expected fields
+	id := uuid.New()
 	quiver := Quiver{
-		ID:          "test-quiver-123",
+		ID:          id,
 		Name:        "Test Quiver",
 		Description: "A test quiver for unit testing",
 		Banner:      system.URL("https://example.com/banner.png"),
@@ -28,13 +29,13 @@ func TestQuiver_Structure(t *testing.T) {
 			},
 		},
 		ListedArrows: []arrow.ArrowNamespace{
-			arrow.ArrowNamespace("test@namespace"),
+			arrow.ArrowNamespace("test@1.0.0"),
 		},
 	}
 
 	// Test field access
-	if quiver.ID != "test-quiver-123" {
-		t.Errorf("Expected ID 'test-quiver-123', got %q", quiver.ID)
+	if quiver.ID != id {
+		t.Errorf("Expected ID %s, got %s", id, quiver.ID)
 	}
 
 	if quiver.Name != "Test Quiver" {
@@ -66,8 +67,8 @@ func TestQuiver_EmptyQuiver(t *testing.T) {
 	// Test empty quiver
 	quiver := Quiver{}
 
-	if quiver.ID != "" {
-		t.Errorf("Expected empty ID, got %q", quiver.ID)
+	if quiver.ID != uuid.Nil {
+		t.Errorf("Expected nil ID, got %s", quiver.ID)
 	}
 
 	if quiver.Name != "" {
@@ -119,7 +120,7 @@ func TestQuiver_ArrowTypes(t *testing.T) {
 		Version:     "1.0.0",
 	}
 
-	namespace1 := arrow.ArrowNamespace("test@namespace")
+	namespace1 := arrow.ArrowNamespace("test@1.0.0")
 
 	quiver := Quiver{
 		InstalledArrows: []arrow.Arrow{arrow1},
@@ -142,3 +143,17 @@ func TestQuiver_ArrowTypes(t *testing.T) {
 		t.Error("Expected arrow namespace to be valid")
 	}
 }
+
+func TestNewID(t *testing.T) {
+	if NewID("core.quiver") != NewID("core.quiver") {
+		t.Error("Expected the same name to yield the same ID")
+	}
+
+	if NewID("core.quiver") == NewID("other.quiver") {
+		t.Error("Expected different names to yield different IDs")
+	}
+
+	if NewID("core.quiver") == uuid.Nil {
+		t.Error("Expected a non-nil ID")
+	}
+}
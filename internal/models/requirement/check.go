@@ -0,0 +1,65 @@
+package requirement
+
+import "fmt"
+
+// Check is the outcome of comparing one requirement with the host.
+// Detected is what is left for the arrow once the resources Committed
+// to other instances are taken off. It is empty when the host value
+// could not be read, in which case Error says why. Warning is set when
+// the arrow gets less than Recommended.
+type Check struct {
+	Name        string `json:"name"`
+	Required    string `json:"required"`
+	Recommended string `json:"recommended,omitempty"`
+	Detected    string `json:"detected"`
+	Committed   string `json:"committed,omitempty"`
+	Passed      bool   `json:"passed"`
+	Warning     string `json:"warning,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// Unknown reports whether the host value could not be read, so the
+// check can neither pass nor fail.
+func (c Check) Unknown() bool {
+	return c.Error != ""
+}
+
+// Report explains whether the host meets a set of requirements.
+// It only fails on checks known to be below the minimum; unknown
+// checks and checks below the recommended tier become warnings.
+type Report struct {
+	Passed   bool     `json:"passed"`
+	Checks   []Check  `json:"checks"`
+	Warnings []string `json:"warnings"`
+}
+
+// NewReport passes unless a check is known to fail.
+func NewReport(checks []Check) *Report {
+	report := &Report{Passed: true, Checks: checks, Warnings: []string{}}
+
+	for _, check := range checks {
+		switch {
+		case check.Unknown():
+			report.Warnings = append(report.Warnings, fmt.Sprintf("could not check %s: %s", check.Name, check.Error))
+		case !check.Passed:
+			report.Passed = false
+		case check.Warning != "":
+			report.Warnings = append(report.Warnings, check.Warning)
+		}
+	}
+
+	return report
+}
+
+// Failed returns the checks known to be below the minimum.
+func (r *Report) Failed() []Check {
+	failed := []Check{}
+
+	for _, check := range r.Checks {
+		if !check.Passed && !check.Unknown() {
+			failed = append(failed, check)
+		}
+	}
+
+	return failed
+}
@@ -0,0 +1,37 @@
+package requirement
+
+import "testing"
+
+func TestNewReport(t *testing.T) {
+	report := NewReport([]Check{
+		{Name: "os", Required: "linux/amd64", Detected: "linux/amd64", Passed: true},
+		{Name: "memory_mb", Required: "4096", Detected: "2048", Passed: false},
+		{Name: "disk_mb", Required: "1024", Error: "statfs failed"},
+		{Name: "cpu_cores", Required: "2", Detected: "2", Passed: true, Warning: "cpu_cores: 2 available, 4 recommended"},
+	})
+
+	if report.Passed {
+		t.Error("Expected a report with a failed check to fail")
+	}
+
+	failed := report.Failed()
+	if len(failed) != 1 || failed[0].Name != "memory_mb" {
+		t.Errorf("Expected memory to be the only failed check, got %+v", failed)
+	}
+
+	if len(report.Warnings) != 2 {
+		t.Errorf("Expected warnings for the unknown and the below recommended check, got %v", report.Warnings)
+	}
+
+	if !NewReport(nil).Passed {
+		t.Error("Expected a report without checks to pass")
+	}
+}
+
+func TestNewReport_UnknownDoesNotFail(t *testing.T) {
+	report := NewReport([]Check{{Name: "memory_mb", Required: "4096", Error: "not supported"}})
+
+	if !report.Passed || len(report.Failed()) != 0 {
+		t.Errorf("Expected an unknown value to only warn, got %+v", report)
+	}
+}
@@ -0,0 +1,70 @@
+package requirement
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Evaluate compares requirements with what the host has left once
+// the resources committed to other instances are taken off. CPU cores,
+// memory and network are compared with the host totals, since stopped
+// instances still claim them; memory is further capped by what is
+// available right now. Network is only checked when required.
+func Evaluate(host *Host, requirements *Requirement, committed Tier) *Report {
+	recommended := Tier{}
+	if requirements.Recommended != nil {
+		recommended = *requirements.Recommended
+	}
+
+	checks := []Check{
+		{
+			Name:     "os",
+			Required: requirements.OS.String(),
+			Detected: host.OS.String(),
+			Passed:   host.OS == requirements.OS,
+		},
+		// The core count falls back to what the Go runtime
+		// sees, so a cpuinfo error does not make it unknown.
+		compare("cpu_cores", requirements.CpuCores, recommended.CpuCores,
+			host.CpuCores-committed.CpuCores, committed.CpuCores, ""),
+		compare("memory_mb", requirements.Memory, recommended.Memory,
+			min(host.MemoryAvailable, host.MemoryTotal-committed.Memory), committed.Memory, host.Errors["memory"]),
+		compare("disk_mb", requirements.Disk, recommended.Disk,
+			host.DiskAvailable-committed.Disk, committed.Disk, host.Errors["disk"]),
+	}
+
+	if requirements.Network > 0 || recommended.Network > 0 {
+		checks = append(checks, compare("network_mbps", requirements.Network, recommended.Network,
+			host.NetworkSpeed-committed.Network, committed.Network, host.Errors["network"]))
+	}
+
+	return NewReport(checks)
+}
+
+func compare(name string, required, recommended, free, committed int, err string) Check {
+	check := Check{
+		Name:     name,
+		Required: strconv.Itoa(required),
+	}
+
+	if recommended > 0 {
+		check.Recommended = strconv.Itoa(recommended)
+	}
+	if committed > 0 {
+		check.Committed = strconv.Itoa(committed)
+	}
+
+	if err != "" {
+		check.Error = err
+		return check
+	}
+
+	check.Detected = strconv.Itoa(free)
+	check.Passed = free >= required
+
+	if check.Passed && free < recommended {
+		check.Warning = fmt.Sprintf("%s: %d available, %d recommended", name, free, recommended)
+	}
+
+	return check
+}
@@ -0,0 +1,136 @@
+package requirement
+
+import (
+	"testing"
+
+	system "github.com/rabbytesoftware/quiver/internal/models/system"
+)
+
+func testHost() *Host {
+	return &Host{
+		OS:              system.OSLinuxAMD64,
+		CpuCores:        8,
+		MemoryTotal:     16000,
+		MemoryAvailable: 12000,
+		DiskAvailable:   100000,
+		NetworkSpeed:    1000,
+		Errors:          map[string]string{},
+	}
+}
+
+func cs2Requirements() *Requirement {
+	return &Requirement{
+		CpuCores: 2,
+		Memory:   4096,
+		Disk:     30720,
+		Network:  10,
+		OS:       system.OSLinuxAMD64,
+		Recommended: &Tier{
+			CpuCores: 4,
+			Memory:   8192,
+			Disk:     51200,
+			Network:  20,
+		},
+	}
+}
+
+func findCheck(t *testing.T, report *Report, name string) Check {
+	t.Helper()
+
+	for _, check := range report.Checks {
+		if check.Name == name {
+			return check
+		}
+	}
+
+	t.Fatalf("Expected a %s check, got %+v", name, report.Checks)
+	return Check{}
+}
+
+func TestEvaluate(t *testing.T) {
+	report := Evaluate(testHost(), cs2Requirements(), Tier{})
+
+	if !report.Passed || len(report.Warnings) != 0 {
+		t.Errorf("Expected an idle host to meet the recommended tier, got %+v", report)
+	}
+
+	memory := findCheck(t, report, "memory_mb")
+	if memory.Required != "4096" || memory.Recommended != "8192" || memory.Detected != "12000" {
+		t.Errorf("Expected required, recommended and detected memory, got %+v", memory)
+	}
+}
+
+func TestEvaluate_Committed(t *testing.T) {
+	testCases := []struct {
+		name      string
+		committed Tier
+		passed    bool
+		failed    string
+		warnings  int
+	}{
+		{"below recommended", Tier{CpuCores: 5}, true, "", 1},
+		{"below minimum", Tier{CpuCores: 7}, false, "cpu_cores", 0},
+		{"memory claimed by stopped instances", Tier{Memory: 10000}, true, "", 1},
+		{"memory exhausted", Tier{Memory: 13000}, false, "memory_mb", 0},
+		{"network", Tier{Network: 995}, false, "network_mbps", 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			report := Evaluate(testHost(), cs2Requirements(), tc.committed)
+
+			if report.Passed != tc.passed {
+				t.Fatalf("Expected passed = %v, got %+v", tc.passed, report)
+			}
+			if tc.failed != "" {
+				failed := report.Failed()
+				if len(failed) != 1 || failed[0].Name != tc.failed {
+					t.Errorf("Expected %s to fail, got %+v", tc.failed, failed)
+				}
+			}
+			if len(report.Warnings) != tc.warnings {
+				t.Errorf("Expected %d warnings, got %v", tc.warnings, report.Warnings)
+			}
+		})
+	}
+
+	cpu := findCheck(t, Evaluate(testHost(), cs2Requirements(), Tier{CpuCores: 5}), "cpu_cores")
+	if cpu.Committed != "5" || cpu.Detected != "3" {
+		t.Errorf("Expected 3 of 8 cores left with 5 committed, got %+v", cpu)
+	}
+}
+
+func TestEvaluate_SingleTier(t *testing.T) {
+	requirements := &Requirement{CpuCores: 1, Memory: 1, Disk: 1, OS: system.OSLinuxAMD64}
+
+	report := Evaluate(testHost(), requirements, Tier{})
+	if !report.Passed || len(report.Checks) != 4 {
+		t.Errorf("Expected network to be skipped when not required, got %+v", report.Checks)
+	}
+	if findCheck(t, report, "memory_mb").Recommended != "" {
+		t.Error("Expected no recommended value without a recommended tier")
+	}
+}
+
+func TestEvaluate_Unknown(t *testing.T) {
+	host := testHost()
+	host.MemoryAvailable, host.MemoryTotal = 0, 0
+	host.Errors["memory"] = "not supported"
+
+	report := Evaluate(host, cs2Requirements(), Tier{})
+	if !report.Passed {
+		t.Errorf("Expected undetectable memory not to block, got %+v", report)
+	}
+	if memory := findCheck(t, report, "memory_mb"); !memory.Unknown() || memory.Passed {
+		t.Errorf("Expected memory to be unknown, got %+v", memory)
+	}
+}
+
+func TestEvaluate_WrongOS(t *testing.T) {
+	requirements := cs2Requirements()
+	requirements.OS = system.OSWindowsAMD64
+
+	if Evaluate(testHost(), requirements, Tier{}).Passed {
+		t.Error("Expected a different OS to fail")
+	}
+}
@@ -0,0 +1,20 @@
+package requirement
+
+import system "github.com/rabbytesoftware/quiver/internal/models/system"
+
+// Host is what was detected about the machine Quiver runs on.
+// Memory and disk are in MB and NetworkSpeed in Mbit/s; values that
+// could not be detected are zero and explained in Errors.
+type Host struct {
+	OS              system.OS         `json:"os"`
+	Arch            string            `json:"arch"`
+	KernelVersion   string            `json:"kernel_version"`
+	Distribution    string            `json:"distribution"`
+	CpuCores        int               `json:"cpu_cores"`
+	CpuModel        string            `json:"cpu_model"`
+	MemoryTotal     int               `json:"memory_total"`
+	MemoryAvailable int               `json:"memory_available"`
+	DiskAvailable   int               `json:"disk_available"`
+	NetworkSpeed    int               `json:"network_speed"`
+	Errors          map[string]string `json:"errors,omitempty"`
+}
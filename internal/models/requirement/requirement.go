@@ -4,13 +4,47 @@ import (
 	system "github.com/rabbytesoftware/quiver/internal/models/system"
 )
 
+// Requirement is what an arrow needs from the host. Its own fields
+// are the minimum an arrow can be installed with; Recommended is what
+// it needs to run well, nil when the manifest declares a single tier.
+// Memory and disk are in MB, network in Mbit/s.
 type Requirement struct {
-	CpuCores int       `json:"cpu_cores"`
-	Memory   int       `json:"memory"`
-	Disk     int       `json:"disk"`
-	OS       system.OS `json:"os"`
+	CpuCores    int       `json:"cpu_cores"`
+	Memory      int       `json:"memory"`
+	Disk        int       `json:"disk"`
+	Network     int       `json:"network,omitempty"`
+	OS          system.OS `json:"os"`
+	Recommended *Tier     `json:"recommended,omitempty"`
+}
+
+// Tier is an amount of host resources.
+type Tier struct {
+	CpuCores int `json:"cpu_cores"`
+	Memory   int `json:"memory"`
+	Disk     int `json:"disk"`
+	Network  int `json:"network,omitempty"`
 }
 
 func (r *Requirement) IsValid() bool {
 	return r.CpuCores > 0 && r.Memory > 0 && r.Disk > 0 && r.OS.IsValid()
 }
+
+// Minimum returns the resources an arrow cannot be installed without.
+func (r *Requirement) Minimum() Tier {
+	return Tier{
+		CpuCores: r.CpuCores,
+		Memory:   r.Memory,
+		Disk:     r.Disk,
+		Network:  r.Network,
+	}
+}
+
+// Add sums two tiers.
+func (t Tier) Add(other Tier) Tier {
+	return Tier{
+		CpuCores: t.CpuCores + other.CpuCores,
+		Memory:   t.Memory + other.Memory,
+		Disk:     t.Disk + other.Disk,
+		Network:  t.Network + other.Network,
+	}
+}
@@ -297,3 +297,28 @@ func TestRequirement_PartialInvalidity(t *testing.T) {
 		})
 	}
 }
+
+func TestRequirement_Minimum(t *testing.T) {
+	r := Requirement{
+		CpuCores:    2,
+		Memory:      4096,
+		Disk:        30720,
+		Network:     10,
+		OS:          system.OSLinuxAMD64,
+		Recommended: &Tier{CpuCores: 4},
+	}
+
+	expected := Tier{CpuCores: 2, Memory: 4096, Disk: 30720, Network: 10}
+	if r.Minimum() != expected {
+		t.Errorf("Minimum() = %+v, expected %+v", r.Minimum(), expected)
+	}
+}
+
+func TestTier_Add(t *testing.T) {
+	sum := Tier{CpuCores: 2, Memory: 4096, Disk: 10, Network: 10}.Add(Tier{CpuCores: 1, Memory: 1024, Disk: 5})
+
+	expected := Tier{CpuCores: 3, Memory: 5120, Disk: 15, Network: 10}
+	if sum != expected {
+		t.Errorf("Add() = %+v, expected %+v", sum, expected)
+	}
+}
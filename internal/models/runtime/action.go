@@ -0,0 +1,19 @@
+package runtime
+
+type Action string
+
+const (
+	ActionInstall   Action = "install"
+	ActionExecute   Action = "execute"
+	ActionUninstall Action = "uninstall"
+	ActionUpdate    Action = "update"
+	ActionValidate  Action = "validate"
+)
+
+func (a Action) String() string {
+	return string(a)
+}
+
+func (a Action) IsValid() bool {
+	return a == ActionInstall || a == ActionExecute || a == ActionUninstall || a == ActionUpdate || a == ActionValidate
+}
@@ -0,0 +1,38 @@
+package runtime
+
+import "testing"
+
+func TestAction_String(t *testing.T) {
+	if ActionInstall.String() != "install" {
+		t.Errorf("Expected 'install', got %q", ActionInstall.String())
+	}
+
+	if Action("custom").String() != "custom" {
+		t.Errorf("Expected 'custom', got %q", Action("custom").String())
+	}
+}
+
+func TestAction_IsValid(t *testing.T) {
+	testCases := []struct {
+		name     string
+		action   Action
+		expected bool
+	}{
+		{name: "install", action: ActionInstall, expected: true},
+		{name: "execute", action: ActionExecute, expected: true},
+		{name: "uninstall", action: ActionUninstall, expected: true},
+		{name: "update", action: ActionUpdate, expected: true},
+		{name: "validate", action: ActionValidate, expected: true},
+		{name: "empty", action: Action(""), expected: false},
+		{name: "uppercase", action: Action("INSTALL"), expected: false},
+		{name: "unknown", action: Action("backup"), expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := tc.action.IsValid(); result != tc.expected {
+				t.Errorf("Expected IsValid() to return %v for %q, got %v", tc.expected, tc.action, result)
+			}
+		})
+	}
+}
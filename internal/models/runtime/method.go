@@ -4,5 +4,6 @@ import "github.com/rabbytesoftware/quiver/internal/models/system"
 
 type Method struct {
 	OS      system.OS `json:"os"`
+	Action  Action    `json:"action"`
 	Command []string  `json:"command"`
 }
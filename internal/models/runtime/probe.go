@@ -0,0 +1,35 @@
+package runtime
+
+import (
+	"bufio"
+	"strconv"
+	"strings"
+)
+
+// ParsePlayers reads the number of connected players from the
+// output of a validate method. Arrows report it on a line of its
+// own as "players: N" or "players=N".
+func ParsePlayers(output string) (int, bool) {
+	scanner := bufio.NewScanner(strings.NewReader(output))
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			key, value, ok = strings.Cut(line, "=")
+		}
+		if !ok || !strings.EqualFold(strings.TrimSpace(key), "players") {
+			continue
+		}
+
+		players, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || players < 0 {
+			continue
+		}
+
+		return players, true
+	}
+
+	return 0, false
+}
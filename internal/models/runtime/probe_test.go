@@ -0,0 +1,26 @@
+package runtime
+
+import "testing"
+
+func TestParsePlayers(t *testing.T) {
+	testCases := []struct {
+		output   string
+		players  int
+		reported bool
+	}{
+		{"players: 3", 3, true},
+		{"Server is up\nplayers=0\n", 0, true},
+		{"  Players : 12  ", 12, true},
+		{"players: many\nplayers: 2", 2, true},
+		{"players: -1", 0, false},
+		{"Quiver Chat is installed and running.", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tc := range testCases {
+		players, reported := ParsePlayers(tc.output)
+		if players != tc.players || reported != tc.reported {
+			t.Errorf("ParsePlayers(%q) = (%d, %v), expected (%d, %v)", tc.output, players, reported, tc.players, tc.reported)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package runtime
+
+import "strings"
+
+type StepKind string
+
+const (
+	StepGet        StepKind = "GET"
+	StepUncompress StepKind = "UNCOMPRESS"
+	StepMove       StepKind = "MOVE"
+	StepRemove     StepKind = "REMOVE"
+	StepShell      StepKind = "SHELL"
+)
+
+// Step is a single line of a method. Built-in steps are written as
+// "KIND: arguments", anything else is run as a shell command.
+type Step struct {
+	Kind StepKind `json:"kind"`
+	Args string   `json:"args"`
+}
+
+func ParseStep(raw string) Step {
+	trimmed := strings.TrimSpace(raw)
+
+	if kind, args, ok := strings.Cut(trimmed, ":"); ok {
+		switch StepKind(kind) {
+		case StepGet, StepUncompress, StepMove, StepRemove:
+			return Step{Kind: StepKind(kind), Args: strings.TrimSpace(args)}
+		}
+	}
+
+	return Step{Kind: StepShell, Args: trimmed}
+}
+
+// URL returns the address a GET step downloads from.
+func (s Step) URL() (string, bool) {
+	if s.Kind != StepGet || s.Args == "" {
+		return "", false
+	}
+
+	return s.Args, true
+}
@@ -0,0 +1,38 @@
+package runtime
+
+import "testing"
+
+func TestParseStep(t *testing.T) {
+	testCases := []struct {
+		name     string
+		raw      string
+		expected Step
+	}{
+		{name: "get", raw: "GET: https://example.com/a.tar.gz", expected: Step{Kind: StepGet, Args: "https://example.com/a.tar.gz"}},
+		{name: "uncompress", raw: "UNCOMPRESS: a.tar.gz", expected: Step{Kind: StepUncompress, Args: "a.tar.gz"}},
+		{name: "move", raw: "MOVE: a TO: b", expected: Step{Kind: StepMove, Args: "a TO: b"}},
+		{name: "remove", raw: " REMOVE: ${INSTALL_DIR} ", expected: Step{Kind: StepRemove, Args: "${INSTALL_DIR}"}},
+		{name: "shell", raw: "./server --port 27015", expected: Step{Kind: StepShell, Args: "./server --port 27015"}},
+		{name: "shell with colon", raw: "echo time: now", expected: Step{Kind: StepShell, Args: "echo time: now"}},
+		{name: "lowercase is shell", raw: "get: file", expected: Step{Kind: StepShell, Args: "get: file"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := ParseStep(tc.raw); result != tc.expected {
+				t.Errorf("Expected %+v, got %+v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestStep_URL(t *testing.T) {
+	url, ok := ParseStep("GET: https://example.com/a.zip").URL()
+	if !ok || url != "https://example.com/a.zip" {
+		t.Errorf("Expected the GET url, got %q, %v", url, ok)
+	}
+
+	if _, ok := ParseStep("REMOVE: a.zip").URL(); ok {
+		t.Error("URL() should only be available on GET steps")
+	}
+}
@@ -0,0 +1,212 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron is a standard five field cron expression:
+// minute, hour, day of month, month and day of week.
+type Cron string
+
+type field struct {
+	name     string
+	min, max int
+}
+
+var fields = []field{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 6},
+}
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Expression is a parsed Cron. Each field holds the set of values
+// it matches, as a bit mask.
+type Expression struct {
+	minute, hour, dom, month, dow uint64
+
+	// ? As in Vixie cron, when both day fields are restricted
+	// ? a time matches if either of them does.
+	domAny, dowAny bool
+}
+
+func (c Cron) String() string {
+	return string(c)
+}
+
+func (c Cron) IsValid() bool {
+	_, err := c.Parse()
+	return err == nil
+}
+
+// Parse supports *, lists, ranges, steps and the @daily style
+// descriptors. Day of week accepts 7 as Sunday.
+func (c Cron) Parse() (*Expression, error) {
+	expr := strings.TrimSpace(string(c))
+	if descriptor, ok := descriptors[strings.ToLower(expr)]; ok {
+		expr = descriptor
+	}
+
+	parts := strings.Fields(expr)
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf("invalid cron expression %q: expected %d fields, got %d", c, len(fields), len(parts))
+	}
+
+	masks := make([]uint64, len(fields))
+	for i, part := range parts {
+		mask, err := parseField(part, fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", c, err)
+		}
+		masks[i] = mask
+	}
+
+	return &Expression{
+		minute: masks[0],
+		hour:   masks[1],
+		dom:    masks[2],
+		month:  masks[3],
+		dow:    masks[4],
+		domAny: parts[2] == "*",
+		dowAny: parts[4] == "*",
+	}, nil
+}
+
+// Matches reports whether the minute t falls in is selected.
+func (e *Expression) Matches(t time.Time) bool {
+	if !has(e.minute, t.Minute()) || !has(e.hour, t.Hour()) || !has(e.month, int(t.Month())) {
+		return false
+	}
+
+	dom := has(e.dom, t.Day())
+	dow := has(e.dow, int(t.Weekday()))
+
+	switch {
+	case e.domAny && e.dowAny:
+		return true
+	case e.domAny:
+		return dow
+	case e.dowAny:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// Next returns the first selected minute after t, or the zero
+// time when none exists within the next five years.
+func (e *Expression) Next(t time.Time) time.Time {
+	next := t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for next.Before(limit) {
+		switch {
+		case !has(e.month, int(next.Month())):
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, next.Location())
+		case !e.dayMatches(next):
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, next.Location())
+		case !has(e.hour, next.Hour()):
+			next = next.Truncate(time.Hour).Add(time.Hour)
+		case !has(e.minute, next.Minute()):
+			next = next.Add(time.Minute)
+		default:
+			return next
+		}
+	}
+
+	return time.Time{}
+}
+
+func (e *Expression) dayMatches(t time.Time) bool {
+	return e.Matches(time.Date(t.Year(), t.Month(), t.Day(), firstSet(e.hour), firstSet(e.minute), 0, 0, t.Location()))
+}
+
+func parseField(part string, f field) (uint64, error) {
+	var mask uint64
+
+	for _, item := range strings.Split(part, ",") {
+		rangePart, step := item, 1
+
+		if base, stepPart, ok := strings.Cut(item, "/"); ok {
+			value, err := strconv.Atoi(stepPart)
+			if err != nil || value <= 0 {
+				return 0, fmt.Errorf("invalid step %q in %s", stepPart, f.name)
+			}
+			rangePart, step = base, value
+		}
+
+		low, high, err := parseRange(rangePart, f)
+		if err != nil {
+			return 0, err
+		}
+
+		for value := low; value <= high; value += step {
+			mask |= 1 << normalize(value, f)
+		}
+	}
+
+	return mask, nil
+}
+
+func parseRange(part string, f field) (int, int, error) {
+	max := f.max
+	if f.name == "day of week" {
+		max = 7
+	}
+
+	if part == "*" {
+		return f.min, f.max, nil
+	}
+
+	lowPart, highPart, isRange := strings.Cut(part, "-")
+
+	low, err := strconv.Atoi(lowPart)
+	if err != nil || low < f.min || low > max {
+		return 0, 0, fmt.Errorf("invalid %s %q", f.name, lowPart)
+	}
+
+	if !isRange {
+		return low, low, nil
+	}
+
+	high, err := strconv.Atoi(highPart)
+	if err != nil || high < low || high > max {
+		return 0, 0, fmt.Errorf("invalid %s range %q", f.name, part)
+	}
+
+	return low, high, nil
+}
+
+func normalize(value int, f field) int {
+	if f.name == "day of week" && value == 7 {
+		return 0
+	}
+	return value
+}
+
+func has(mask uint64, value int) bool {
+	return mask&(1<<value) != 0
+}
+
+func firstSet(mask uint64) int {
+	for i := 0; i < 64; i++ {
+		if has(mask, i) {
+			return i
+		}
+	}
+	return 0
+}
@@ -0,0 +1,127 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func at(value string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04", value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestCron_Parse(t *testing.T) {
+	valid := []Cron{
+		"* * * * *",
+		"0 4 * * *",
+		"*/15 4-5 * * 1-5",
+		"0,30 0 1,15 * *",
+		"0 0 * * 7",
+		"@daily",
+		"@HOURLY",
+	}
+
+	for _, c := range valid {
+		if !c.IsValid() {
+			_, err := c.Parse()
+			t.Errorf("Expected %q to be valid, got %v", c, err)
+		}
+	}
+
+	invalid := []Cron{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"@sometimes",
+	}
+
+	for _, c := range invalid {
+		if c.IsValid() {
+			t.Errorf("Expected %q to be invalid", c)
+		}
+	}
+}
+
+func TestExpression_Matches(t *testing.T) {
+	testCases := []struct {
+		cron     Cron
+		time     string
+		expected bool
+	}{
+		{"* 4-5 * * *", "2025-07-01 04:00", true},
+		{"* 4-5 * * *", "2025-07-01 05:59", true},
+		{"* 4-5 * * *", "2025-07-01 06:00", false},
+		{"* 4-5 * * *", "2025-07-01 03:59", false},
+		{"*/15 * * * *", "2025-07-01 10:45", true},
+		{"*/15 * * * *", "2025-07-01 10:46", false},
+		{"0 0 * * 0", "2025-07-06 00:00", true},
+		{"0 0 * * 7", "2025-07-06 00:00", true},
+		{"0 0 * * 0", "2025-07-07 00:00", false},
+		// Both day fields restricted: either one matching is enough.
+		{"0 0 1 * 1", "2025-07-01 00:00", true},
+		{"0 0 1 * 1", "2025-07-07 00:00", true},
+		{"0 0 1 * 1", "2025-07-08 00:00", false},
+		{"@daily", "2025-07-08 00:00", true},
+		{"@daily", "2025-07-08 00:01", false},
+	}
+
+	for _, tc := range testCases {
+		expr, err := tc.cron.Parse()
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", tc.cron, err)
+		}
+
+		if got := expr.Matches(at(tc.time)); got != tc.expected {
+			t.Errorf("%q.Matches(%s) = %v, expected %v", tc.cron, tc.time, got, tc.expected)
+		}
+	}
+}
+
+func TestExpression_Next(t *testing.T) {
+	testCases := []struct {
+		cron     Cron
+		from     string
+		expected string
+	}{
+		{"0 4 * * *", "2025-07-01 03:00", "2025-07-01 04:00"},
+		{"0 4 * * *", "2025-07-01 04:00", "2025-07-02 04:00"},
+		{"*/15 * * * *", "2025-07-01 10:46", "2025-07-01 11:00"},
+		{"30 2 * * 1", "2025-07-01 00:00", "2025-07-07 02:30"},
+		{"0 0 1 1 *", "2025-07-01 00:00", "2026-01-01 00:00"},
+		{"0 0 29 2 *", "2025-03-01 00:00", "2028-02-29 00:00"},
+		{"0 0 31 12 *", "2025-12-31 23:59", "2026-12-31 00:00"},
+	}
+
+	for _, tc := range testCases {
+		expr, err := tc.cron.Parse()
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", tc.cron, err)
+		}
+
+		if got := expr.Next(at(tc.from)); !got.Equal(at(tc.expected)) {
+			t.Errorf("%q.Next(%s) = %s, expected %s", tc.cron, tc.from, got.Format("2006-01-02 15:04"), tc.expected)
+		}
+	}
+}
+
+func TestExpression_Next_Never(t *testing.T) {
+	expr, err := Cron("0 0 31 2 *").Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if next := expr.Next(at("2025-01-01 00:00")); !next.IsZero() {
+		t.Errorf("Expected no next time for February 31st, got %s", next)
+	}
+}
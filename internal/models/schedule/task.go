@@ -0,0 +1,145 @@
+package schedule
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TaskAction string
+
+const (
+	TaskStart   TaskAction = "start"
+	TaskStop    TaskAction = "stop"
+	TaskRestart TaskAction = "restart"
+	TaskMethod  TaskAction = "method"
+	TaskBackup  TaskAction = "backup"
+)
+
+func (a TaskAction) IsValid() bool {
+	switch a {
+	case TaskStart, TaskStop, TaskRestart, TaskMethod, TaskBackup:
+		return true
+	}
+	return false
+}
+
+// Task runs an action on an installed arrow every time its cron
+// expression matches. Method names the manifest method a
+// TaskMethod runs, e.g. "rotate_map". Quiesce stops the instance
+// while a TaskBackup runs.
+type Task struct {
+	ID        uuid.UUID  `json:"id" gorm:"primaryKey"`
+	Arrow     string     `json:"arrow" gorm:"index"`
+	Name      string     `json:"name"`
+	Cron      Cron       `json:"cron"`
+	Action    TaskAction `json:"action"`
+	Method    string     `json:"method,omitempty"`
+	Quiesce   bool       `json:"quiesce,omitempty"`
+	Enabled   bool       `json:"enabled"`
+	LastRun   *time.Time `json:"last_run,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (t *Task) Validate() error {
+	if t.Arrow == "" {
+		return fmt.Errorf("task has no arrow")
+	}
+
+	if !t.Action.IsValid() {
+		return fmt.Errorf("invalid task action %q", t.Action)
+	}
+
+	if t.Action == TaskMethod && t.Method == "" {
+		return fmt.Errorf("method tasks require a method name")
+	}
+
+	_, err := t.Cron.Parse()
+	return err
+}
+
+// Due reports whether the task should run at now. A task runs at
+// most once per minute, so a restart within the same minute does
+// not run it twice.
+func (t *Task) Due(now time.Time) bool {
+	if !t.Enabled {
+		return false
+	}
+
+	expr, err := t.Cron.Parse()
+	if err != nil || !expr.Matches(now) {
+		return false
+	}
+
+	return t.LastRun == nil || !t.LastRun.Truncate(time.Minute).Equal(now.Truncate(time.Minute))
+}
+
+// Next returns when the task runs next, or the zero time when it
+// is disabled or never matches.
+func (t *Task) Next(now time.Time) time.Time {
+	if !t.Enabled {
+		return time.Time{}
+	}
+
+	expr, err := t.Cron.Parse()
+	if err != nil {
+		return time.Time{}
+	}
+
+	return expr.Next(now)
+}
+
+type RunStatus string
+
+const (
+	RunSucceeded RunStatus = "succeeded"
+	RunFailed    RunStatus = "failed"
+	RunSkipped   RunStatus = "skipped"
+)
+
+// Run is the outcome of a single execution of a task.
+type Run struct {
+	ID         uuid.UUID `json:"id" gorm:"primaryKey"`
+	TaskID     uuid.UUID `json:"task_id" gorm:"index"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Status     RunStatus `json:"status"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// NewRun records the outcome of a task that started at startedAt.
+func NewRun(
+	task *Task,
+	startedAt time.Time,
+	finishedAt time.Time,
+	err error,
+) *Run {
+	run := &Run{
+		ID:         uuid.New(),
+		TaskID:     task.ID,
+		StartedAt:  startedAt,
+		FinishedAt: finishedAt,
+		Status:     RunSucceeded,
+	}
+
+	if err != nil {
+		run.Status = RunFailed
+		run.Error = err.Error()
+	}
+
+	return run
+}
+
+// NewSkippedRun records that a task was due at but did not run,
+// and why.
+func NewSkippedRun(task *Task, at time.Time, reason string) *Run {
+	return &Run{
+		ID:         uuid.New(),
+		TaskID:     task.ID,
+		StartedAt:  at,
+		FinishedAt: at,
+		Status:     RunSkipped,
+		Error:      reason,
+	}
+}
@@ -0,0 +1,112 @@
+package schedule
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestTaskAction_IsValid(t *testing.T) {
+	for _, action := range []TaskAction{TaskStart, TaskStop, TaskRestart, TaskMethod, TaskBackup} {
+		if !action.IsValid() {
+			t.Errorf("Expected %q to be valid", action)
+		}
+	}
+
+	for _, action := range []TaskAction{"", "install", "START"} {
+		if action.IsValid() {
+			t.Errorf("Expected %q to be invalid", action)
+		}
+	}
+}
+
+func TestTask_Validate(t *testing.T) {
+	testCases := []struct {
+		name  string
+		task  Task
+		valid bool
+	}{
+		{"restart", Task{Arrow: "cs2", Cron: "0 4 * * *", Action: TaskRestart}, true},
+		{"method", Task{Arrow: "cs2", Cron: "*/30 * * * *", Action: TaskMethod, Method: "rotate_map"}, true},
+		{"backup", Task{Arrow: "cs2", Cron: "@daily", Action: TaskBackup, Quiesce: true}, true},
+		{"no arrow", Task{Cron: "0 4 * * *", Action: TaskRestart}, false},
+		{"bad action", Task{Arrow: "cs2", Cron: "0 4 * * *", Action: "reboot"}, false},
+		{"method without name", Task{Arrow: "cs2", Cron: "0 4 * * *", Action: TaskMethod}, false},
+		{"bad cron", Task{Arrow: "cs2", Cron: "0 4 * *", Action: TaskStop}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.task.Validate()
+			if (err == nil) != tc.valid {
+				t.Errorf("Validate() error = %v, expected valid = %v", err, tc.valid)
+			}
+		})
+	}
+}
+
+func TestTask_Due(t *testing.T) {
+	now := at("2025-07-01 04:00")
+	task := &Task{Cron: "0 4 * * *", Action: TaskRestart, Enabled: true}
+
+	if !task.Due(now) {
+		t.Error("Expected the task to be due")
+	}
+	if task.Due(at("2025-07-01 04:01")) {
+		t.Error("Expected the task not to be due outside its schedule")
+	}
+
+	ranAt := now.Add(20 * time.Second)
+	task.LastRun = &ranAt
+	if task.Due(now.Add(40 * time.Second)) {
+		t.Error("Expected the task not to run twice in the same minute")
+	}
+	if !task.Due(at("2025-07-02 04:00")) {
+		t.Error("Expected the task to be due the next day")
+	}
+
+	task.Enabled = false
+	if task.Due(at("2025-07-02 04:00")) {
+		t.Error("Expected a disabled task never to be due")
+	}
+}
+
+func TestTask_Next(t *testing.T) {
+	task := &Task{Cron: "0 4 * * *", Enabled: true}
+
+	if next := task.Next(at("2025-07-01 05:00")); !next.Equal(at("2025-07-02 04:00")) {
+		t.Errorf("Expected next run at 2025-07-02 04:00, got %s", next)
+	}
+
+	task.Enabled = false
+	if next := task.Next(at("2025-07-01 05:00")); !next.IsZero() {
+		t.Errorf("Expected no next run for a disabled task, got %s", next)
+	}
+}
+
+func TestNewRun(t *testing.T) {
+	task := &Task{ID: uuid.New()}
+	start := time.Now()
+
+	run := NewRun(task, start, start.Add(time.Second), nil)
+	if run.TaskID != task.ID || run.Status != RunSucceeded || run.Error != "" {
+		t.Errorf("Unexpected successful run %+v", run)
+	}
+
+	run = NewRun(task, start, start.Add(time.Second), errors.New("boom"))
+	if run.Status != RunFailed || run.Error != "boom" {
+		t.Errorf("Unexpected failed run %+v", run)
+	}
+}
+
+func TestNewSkippedRun(t *testing.T) {
+	task := &Task{ID: uuid.New()}
+	at := time.Date(2025, 7, 1, 4, 1, 0, 0, time.UTC)
+
+	run := NewSkippedRun(task, at, "still running")
+	if run.TaskID != task.ID || run.Status != RunSkipped || run.Error != "still running" || !run.StartedAt.Equal(at) {
+		t.Errorf("Unexpected skipped run %+v", run)
+	}
+}
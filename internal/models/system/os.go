@@ -1,5 +1,7 @@
 package system
 
+import "runtime"
+
 type OS string
 
 const (
@@ -13,6 +15,11 @@ const (
 	OSDarwinARM64 OS = "darwin/arm64"
 )
 
+// CurrentOS returns the OS of the host Quiver is running on.
+func CurrentOS() OS {
+	return OS(runtime.GOOS + "/" + runtime.GOARCH)
+}
+
 func (o OS) String() string {
 	return string(o)
 }
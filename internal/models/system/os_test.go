@@ -1,6 +1,9 @@
 package system
 
-import "testing"
+import (
+	"runtime"
+	"testing"
+)
 
 func TestOS_String(t *testing.T) {
 	testCases := []struct {
@@ -428,3 +431,11 @@ func TestOS_AllMethods(t *testing.T) {
 		}
 	}
 }
+
+func TestCurrentOS(t *testing.T) {
+	current := CurrentOS()
+
+	if current.String() != runtime.GOOS+"/"+runtime.GOARCH {
+		t.Errorf("Expected CurrentOS() to be %s/%s, got %q", runtime.GOOS, runtime.GOARCH, current)
+	}
+}
@@ -0,0 +1,60 @@
+package template
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiff_Equal(t *testing.T) {
+	if diff := Diff("a\nb\n", "a\nb\n", "a", "b"); diff != "" {
+		t.Errorf("Expected no diff for equal texts, got %q", diff)
+	}
+}
+
+func TestDiff_SingleChange(t *testing.T) {
+	from := "hostname old\nport 27015\nmaxplayers 10\n"
+	to := "hostname new\nport 27015\nmaxplayers 10\n"
+
+	expected := strings.Join([]string{
+		"--- server.cfg",
+		"+++ server.cfg (rendered)",
+		"@@ -1,3 +1,3 @@",
+		"-hostname old",
+		"+hostname new",
+		" port 27015",
+		" maxplayers 10",
+		"",
+	}, "\n")
+
+	if diff := Diff(from, to, "server.cfg", "server.cfg (rendered)"); diff != expected {
+		t.Errorf("Diff() =\n%s\nwant\n%s", diff, expected)
+	}
+}
+
+func TestDiff_SeparateHunks(t *testing.T) {
+	lines := make([]string, 20)
+	for i := range lines {
+		lines[i] = string(rune('a' + i))
+	}
+	from := strings.Join(lines, "\n") + "\n"
+
+	changed := append([]string{}, lines...)
+	changed[0] = "A"
+	changed[19] = "T"
+	to := strings.Join(changed, "\n") + "\n"
+
+	diff := Diff(from, to, "a", "b")
+	if strings.Count(diff, "@@ -") != 2 {
+		t.Errorf("Expected two hunks for changes far apart, got\n%s", diff)
+	}
+	if !strings.Contains(diff, "@@ -17,4 +17,4 @@") {
+		t.Errorf("Expected the second hunk to start at line 17, got\n%s", diff)
+	}
+}
+
+func TestDiff_NewFile(t *testing.T) {
+	diff := Diff("", "a\nb\n", "/dev/null", "server.cfg")
+	if !strings.Contains(diff, "@@ -0,0 +1,2 @@\n+a\n+b\n") {
+		t.Errorf("Expected every line to be added, got\n%s", diff)
+	}
+}
@@ -0,0 +1,70 @@
+package template
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Template is a config file an arrow ships, rendered with the
+// instance's variables into Target, relative to the install
+// directory. The content is either inline or fetched from Source,
+// which may be relative to the manifest URL.
+type Template struct {
+	Target  string `json:"target"`
+	Source  string `json:"source,omitempty"`
+	Content string `json:"content,omitempty"`
+}
+
+func (t Template) Validate() error {
+	target := path.Clean(strings.ReplaceAll(t.Target, "\\", "/"))
+
+	switch {
+	case t.Target == "" || target == ".":
+		return fmt.Errorf("template has no target")
+	case path.IsAbs(target) || target == ".." || strings.HasPrefix(target, "../"):
+		return fmt.Errorf("template target %q must stay inside the install directory", t.Target)
+	case t.Source == "" && t.Content == "":
+		return fmt.Errorf("template %q has neither source nor content", t.Target)
+	case t.Source != "" && t.Content != "":
+		return fmt.Errorf("template %q has both source and content", t.Target)
+	}
+
+	return nil
+}
+
+// Rendered records what Quiver last wrote to a target, so edits
+// made to the file afterwards can be told apart.
+type Rendered struct {
+	Target string `json:"target"`
+	SHA256 string `json:"sha256"`
+}
+
+type ChangeStatus string
+
+const (
+	ChangeCreated   ChangeStatus = "created"
+	ChangeUpdated   ChangeStatus = "updated"
+	ChangeUnchanged ChangeStatus = "unchanged"
+
+	// ChangeConflict is a target modified since it was last rendered.
+	// It is only overwritten when forced.
+	ChangeConflict ChangeStatus = "conflict"
+)
+
+// Change is what rendering a template does, or did, to its target.
+// Diff goes from the current file to the rendered one.
+type Change struct {
+	Target  string       `json:"target"`
+	Status  ChangeStatus `json:"status"`
+	Diff    string       `json:"diff,omitempty"`
+	Written bool         `json:"written"`
+}
+
+// Checksum is the SHA-256 recorded for rendered content.
+func Checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,37 @@
+package template
+
+import "testing"
+
+func TestTemplate_Validate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		template Template
+		valid    bool
+	}{
+		{"inline", Template{Target: "cfg/server.cfg", Content: "hostname ${SERVER_HOSTNAME}"}, true},
+		{"source", Template{Target: "server.properties", Source: "templates/server.properties"}, true},
+		{"no target", Template{Content: "x"}, false},
+		{"absolute target", Template{Target: "/etc/passwd", Content: "x"}, false},
+		{"escaping target", Template{Target: "../server.cfg", Content: "x"}, false},
+		{"no content", Template{Target: "server.cfg"}, false},
+		{"both", Template{Target: "server.cfg", Source: "a", Content: "b"}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.template.Validate()
+			if (err == nil) != tc.valid {
+				t.Errorf("Validate() error = %v, valid = %v", err, tc.valid)
+			}
+		})
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	if Checksum([]byte("a")) == Checksum([]byte("b")) {
+		t.Error("Expected different content to have different checksums")
+	}
+	if len(Checksum(nil)) != 64 {
+		t.Errorf("Expected a hex encoded SHA-256, got %q", Checksum(nil))
+	}
+}
@@ -0,0 +1,87 @@
+package variable
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/rabbytesoftware/quiver/internal/core/secrets"
+)
+
+// Masked replaces sensitive values wherever they are shown.
+const Masked = secrets.Mask
+
+// SealedPrefix marks a value that was encrypted for storage.
+const SealedPrefix = "sealed:"
+
+// IsSealed reports whether value was encrypted for storage.
+func IsSealed(value string) bool {
+	return strings.HasPrefix(value, SealedPrefix)
+}
+
+// MarshalJSON masks the value and default of a sensitive variable
+// unless they are sealed, so a plain text secret ends up neither in
+// API responses nor in the database.
+func (v Variable) MarshalJSON() ([]byte, error) {
+	type plain Variable
+
+	if v.Sensitive && v.Value != nil && !IsSealed(*v.Value) {
+		masked := Masked
+		v.Value = &masked
+	}
+
+	if v.Sensitive && v.Default != "" && !IsSealed(v.Default) {
+		v.Default = Masked
+	}
+
+	return json.Marshal(plain(v))
+}
+
+// Current returns the instance's value, or the default when
+// none was set.
+func (v Variable) Current() string {
+	if v.Value != nil {
+		return *v.Value
+	}
+	return v.Default
+}
+
+// Display returns value, masked when the variable is sensitive.
+func (v Variable) Display(value string) string {
+	if v.Sensitive {
+		return Masked
+	}
+	return value
+}
+
+// Validate checks a value against the definition: its type, the
+// allowed values and, for numbers, the Min/Max range when set.
+func (v Variable) Validate(value string) error {
+	if v.Sensitive && IsSealed(value) {
+		return fmt.Errorf("%s must not start with %q", v.Name, SealedPrefix)
+	}
+
+	if len(v.Values) > 0 && !slices.Contains(v.Values, value) {
+		return fmt.Errorf("%s must be one of %v", v.Name, v.Values)
+	}
+
+	switch v.Type {
+	case VariableTypeNumber:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%s must be a number", v.Name)
+		}
+
+		if (v.Min != 0 || v.Max != 0) && (number < float64(v.Min) || number > float64(v.Max)) {
+			return fmt.Errorf("%s must be between %d and %d", v.Name, v.Min, v.Max)
+		}
+	case VariableTypeBoolean:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("%s must be true or false", v.Name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package variable
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestVariable_Current(t *testing.T) {
+	v := Variable{Name: "MAXPLAYERS", Default: "10"}
+	if v.Current() != "10" {
+		t.Errorf("Expected the default without a value, got %q", v.Current())
+	}
+
+	empty := ""
+	v.Value = &empty
+	if v.Current() != "" {
+		t.Errorf("Expected an empty value to win over the default, got %q", v.Current())
+	}
+}
+
+func TestVariable_Display(t *testing.T) {
+	if got := (Variable{Sensitive: true}).Display("hunter2"); got != Masked {
+		t.Errorf("Expected sensitive values to be masked, got %q", got)
+	}
+	if got := (Variable{}).Display("quiver"); got != "quiver" {
+		t.Errorf("Expected plain values to be shown, got %q", got)
+	}
+}
+
+func TestVariable_Validate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		variable Variable
+		value    string
+		valid    bool
+	}{
+		{"string", Variable{Type: VariableTypeString}, "anything", true},
+		{"number", Variable{Type: VariableTypeNumber}, "12", true},
+		{"not a number", Variable{Type: VariableTypeNumber}, "twelve", false},
+		{"in range", Variable{Type: VariableTypeNumber, Min: 1, Max: 64}, "64", true},
+		{"below range", Variable{Type: VariableTypeNumber, Min: 1, Max: 64}, "0", false},
+		{"above range", Variable{Type: VariableTypeNumber, Min: 1, Max: 64}, "65", false},
+		{"boolean", Variable{Type: VariableTypeBoolean}, "true", true},
+		{"not a boolean", Variable{Type: VariableTypeBoolean}, "yes please", false},
+		{"allowed value", Variable{Type: VariableTypeString, Values: []string{"de_dust2", "de_inferno"}}, "de_inferno", true},
+		{"unknown value", Variable{Type: VariableTypeString, Values: []string{"de_dust2"}}, "de_nuke", false},
+		{"sealed looking secret", Variable{Type: VariableTypeString, Sensitive: true}, SealedPrefix + "abc", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.variable.Validate(tc.value)
+			if (err == nil) != tc.valid {
+				t.Errorf("Validate(%q) error = %v, valid = %v", tc.value, err, tc.valid)
+			}
+		})
+	}
+}
+
+func TestVariable_MarshalJSON(t *testing.T) {
+	password := "hunter2"
+	sealed := SealedPrefix + "c2VhbGVk"
+	port := "27016"
+
+	testCases := []struct {
+		name     string
+		variable Variable
+		expected string
+	}{
+		{"sensitive", Variable{Name: "PASSWORD", Sensitive: true, Value: &password}, Masked},
+		{"sealed", Variable{Name: "PASSWORD", Sensitive: true, Value: &sealed}, sealed},
+		{"plain", Variable{Name: "PORT", Value: &port}, port},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := json.Marshal(tc.variable)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+
+			if !strings.Contains(string(data), `"value":"`+tc.expected+`"`) {
+				t.Errorf("Expected the value %q, got %s", tc.expected, data)
+			}
+			if strings.Contains(string(data), password) {
+				t.Errorf("Expected the password to never be marshalled, got %s", data)
+			}
+		})
+	}
+
+	v := Variable{Sensitive: true, Value: &password}
+	if _, err := json.Marshal(v); err != nil || *v.Value != "hunter2" {
+		t.Error("Expected marshalling to leave the variable untouched")
+	}
+}
+
+func TestVariable_MarshalJSON_Default(t *testing.T) {
+	sealed := SealedPrefix + "c2VhbGVk"
+
+	testCases := []struct {
+		name     string
+		variable Variable
+		expected string
+	}{
+		{"sensitive", Variable{Name: "PASSWORD", Sensitive: true, Default: "hunter2"}, Masked},
+		{"sealed", Variable{Name: "PASSWORD", Sensitive: true, Default: sealed}, sealed},
+		{"empty", Variable{Name: "PASSWORD", Sensitive: true}, ""},
+		{"plain", Variable{Name: "PORT", Default: "27015"}, "27015"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := json.Marshal(tc.variable)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+
+			if !strings.Contains(string(data), `"default":"`+tc.expected+`"`) {
+				t.Errorf("Expected the default %q, got %s", tc.expected, data)
+			}
+		})
+	}
+}
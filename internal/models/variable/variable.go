@@ -8,4 +8,8 @@ type Variable struct {
 	Max       int          `json:"max"`
 	Sensitive bool         `json:"sensitive"`
 	Type      VariableType `json:"type"`
+
+	// Value is what the instance was configured with. Nil falls
+	// back to Default.
+	Value *string `json:"value,omitempty"`
 }
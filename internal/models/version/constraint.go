@@ -0,0 +1,239 @@
+package version
+
+import (
+	"fmt"
+	"strings"
+)
+
+type operator string
+
+const (
+	opEqual          operator = "="
+	opGreater        operator = ">"
+	opGreaterOrEqual operator = ">="
+	opLess           operator = "<"
+	opLessOrEqual    operator = "<="
+)
+
+type comparator struct {
+	op      operator
+	version Version
+}
+
+// Constraint is a version range such as "^1.2.0", "~1.2",
+// ">=1.0.0 <2.0.0" or "1.0.0 || ^2.0.0". An empty constraint,
+// "*" and "latest" match any release.
+type Constraint struct {
+	raw string
+	// sets are OR-ed together, comparators within a set are AND-ed
+	sets [][]comparator
+}
+
+func ParseConstraint(value string) (Constraint, error) {
+	c := Constraint{raw: strings.TrimSpace(value)}
+
+	alternatives := strings.Split(c.raw, "||")
+	for _, alternative := range alternatives {
+		alternative = strings.TrimSpace(alternative)
+		if alternative == "" && len(alternatives) > 1 {
+			return Constraint{}, fmt.Errorf("invalid constraint %q: empty alternative", value)
+		}
+
+		set, err := parseSet(alternative)
+		if err != nil {
+			return Constraint{}, fmt.Errorf("invalid constraint %q: %w", value, err)
+		}
+		c.sets = append(c.sets, set)
+	}
+
+	return c, nil
+}
+
+// MustParseConstraint is like ParseConstraint but panics on invalid input.
+func MustParseConstraint(value string) Constraint {
+	c, err := ParseConstraint(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return c
+}
+
+func (c Constraint) String() string {
+	return c.raw
+}
+
+// Check reports whether v falls within the constraint. Following the
+// usual semver convention, a pre-release only matches when a
+// comparator in the same set names a pre-release of the same
+// major.minor.patch, so ranges never pull in unstable versions.
+func (c Constraint) Check(v Version) bool {
+	for _, set := range c.sets {
+		if checkSet(set, v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Satisfies parses both arguments and checks the version against the
+// constraint, treating anything unparseable as not matching.
+func Satisfies(v, constraint string) bool {
+	parsed, err := Parse(v)
+	if err != nil {
+		return false
+	}
+
+	c, err := ParseConstraint(constraint)
+	if err != nil {
+		return false
+	}
+
+	return c.Check(parsed)
+}
+
+func checkSet(set []comparator, v Version) bool {
+	for _, comp := range set {
+		if !comp.matches(v) {
+			return false
+		}
+	}
+
+	if !v.IsPrerelease() {
+		return true
+	}
+
+	for _, comp := range set {
+		if comp.version.IsPrerelease() && comp.version.sameCore(v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c comparator) matches(v Version) bool {
+	cmp := v.Compare(c.version)
+
+	switch c.op {
+	case opEqual:
+		return cmp == 0
+	case opGreater:
+		return cmp > 0
+	case opGreaterOrEqual:
+		return cmp >= 0
+	case opLess:
+		return cmp < 0
+	case opLessOrEqual:
+		return cmp <= 0
+	}
+
+	return false
+}
+
+func parseSet(value string) ([]comparator, error) {
+	switch value {
+	case "", "*", "latest", "x":
+		return []comparator{{op: opGreaterOrEqual, version: Version{}}}, nil
+	}
+
+	var set []comparator
+	fields := strings.Fields(value)
+	for i := 0; i < len(fields); i++ {
+		term := fields[i]
+
+		// ? ">= 1.0.0" is written with a space as often as without.
+		if isOperator(term) && i+1 < len(fields) {
+			i++
+			term += fields[i]
+		}
+
+		comparators, err := parseTerm(term)
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, comparators...)
+	}
+
+	return set, nil
+}
+
+// isOperator reports whether term is a comparison operator alone.
+func isOperator(term string) bool {
+	switch operator(term) {
+	case opEqual, opGreater, opGreaterOrEqual, opLess, opLessOrEqual:
+		return true
+	}
+
+	return false
+}
+
+func parseTerm(term string) ([]comparator, error) {
+	switch {
+	case strings.HasPrefix(term, "^"):
+		return caret(strings.TrimPrefix(term, "^"))
+	case strings.HasPrefix(term, "~"):
+		return tilde(strings.TrimPrefix(term, "~"))
+	}
+
+	for _, op := range []operator{opGreaterOrEqual, opLessOrEqual, opGreater, opLess, opEqual} {
+		if strings.HasPrefix(term, string(op)) {
+			v, err := Parse(strings.TrimPrefix(term, string(op)))
+			if err != nil {
+				return nil, err
+			}
+			return []comparator{{op: op, version: v}}, nil
+		}
+	}
+
+	v, err := Parse(term)
+	if err != nil {
+		return nil, err
+	}
+
+	return []comparator{{op: opEqual, version: v}}, nil
+}
+
+// caret allows changes that do not modify the left-most non-zero
+// component: ^1.2.3 is >=1.2.3 <2.0.0 and ^0.2.3 is >=0.2.3 <0.3.0.
+func caret(value string) ([]comparator, error) {
+	v, err := Parse(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var upper Version
+	switch {
+	case v.Major > 0:
+		upper = Version{Major: v.Major + 1}
+	case v.Minor > 0:
+		upper = Version{Minor: v.Minor + 1}
+	default:
+		upper = Version{Patch: v.Patch + 1}
+	}
+
+	return []comparator{
+		{op: opGreaterOrEqual, version: v},
+		{op: opLess, version: upper},
+	}, nil
+}
+
+// tilde allows patch-level changes: ~1.2.3 is >=1.2.3 <1.3.0, while a
+// bare major such as ~1 is >=1.0.0 <2.0.0.
+func tilde(value string) ([]comparator, error) {
+	v, err := Parse(value)
+	if err != nil {
+		return nil, err
+	}
+
+	upper := Version{Major: v.Major, Minor: v.Minor + 1}
+	if !strings.Contains(strings.TrimPrefix(value, "v"), ".") {
+		upper = Version{Major: v.Major + 1}
+	}
+
+	return []comparator{
+		{op: opGreaterOrEqual, version: v},
+		{op: opLess, version: upper},
+	}, nil
+}
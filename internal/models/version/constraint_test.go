@@ -0,0 +1,104 @@
+package version
+
+import "testing"
+
+func TestConstraint_Check(t *testing.T) {
+	testCases := []struct {
+		constraint string
+		version    string
+		expected   bool
+	}{
+		{constraint: "", version: "1.0.0", expected: true},
+		{constraint: "*", version: "25.7.0", expected: true},
+		{constraint: "latest", version: "0.0.1", expected: true},
+		{constraint: "latest", version: "1.0.0-rc.1", expected: false},
+		{constraint: "1.2.3", version: "1.2.3", expected: true},
+		{constraint: "1.2.3", version: "1.2.4", expected: false},
+		{constraint: "=1.2.3", version: "1.2.3", expected: true},
+		{constraint: "27.7.1-d278aae", version: "27.7.1-d278aae", expected: true},
+
+		{constraint: "^1.2.3", version: "1.2.3", expected: true},
+		{constraint: "^1.2.3", version: "1.9.0", expected: true},
+		{constraint: "^1.2.3", version: "2.0.0", expected: false},
+		{constraint: "^1.2.3", version: "1.2.2", expected: false},
+		{constraint: "^0.2.3", version: "0.2.9", expected: true},
+		{constraint: "^0.2.3", version: "0.3.0", expected: false},
+		{constraint: "^0.0.3", version: "0.0.4", expected: false},
+		{constraint: "^25.7", version: "25.12.1", expected: true},
+
+		{constraint: "~1.2.3", version: "1.2.9", expected: true},
+		{constraint: "~1.2.3", version: "1.3.0", expected: false},
+		{constraint: "~1", version: "1.9.0", expected: true},
+		{constraint: "~1", version: "2.0.0", expected: false},
+
+		{constraint: ">=1.0.0", version: "1.0.0", expected: true},
+		{constraint: ">1.0.0", version: "1.0.0", expected: false},
+		{constraint: "<2.0.0", version: "1.9.9", expected: true},
+		{constraint: "<=2.0.0", version: "2.0.0", expected: true},
+		{constraint: ">=1.0.0 <2.0.0", version: "1.5.0", expected: true},
+		{constraint: ">=1.0.0 <2.0.0", version: "2.0.0", expected: false},
+		{constraint: ">= 1.0.0 < 2.0.0", version: "1.5.0", expected: true},
+		{constraint: ">= 1.0.0 < 2.0.0", version: "2.0.0", expected: false},
+		{constraint: "= 1.2.3", version: "1.2.3", expected: true},
+
+		{constraint: "1.0.0 || ^2.0.0", version: "1.0.0", expected: true},
+		{constraint: "1.0.0 || ^2.0.0", version: "2.3.0", expected: true},
+		{constraint: "1.0.0 || ^2.0.0", version: "1.5.0", expected: false},
+
+		{constraint: "^1.0.0", version: "1.1.0-rc.1", expected: false},
+		{constraint: ">=1.1.0-rc.1", version: "1.1.0-rc.2", expected: true},
+		{constraint: ">=1.1.0-rc.1", version: "1.2.0-rc.1", expected: false},
+		{constraint: ">=1.1.0-rc.1", version: "1.2.0", expected: true},
+		{constraint: "^27.7.0", version: "27.7.1-d278aae", expected: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.constraint+" "+tc.version, func(t *testing.T) {
+			c, err := ParseConstraint(tc.constraint)
+			if err != nil {
+				t.Fatalf("ParseConstraint(%q) returned error: %v", tc.constraint, err)
+			}
+
+			if got := c.Check(MustParse(tc.version)); got != tc.expected {
+				t.Errorf("Expected %q to match %q: %v, got %v", tc.version, tc.constraint, tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseConstraint_Invalid(t *testing.T) {
+	inputs := []string{"^", "~x", ">=abc", "1.0.0 || ", "domain", "1.2.3.4", ">=", "1.0.0 <", ">= >= 1.0.0"}
+
+	for _, input := range inputs {
+		if _, err := ParseConstraint(input); err == nil {
+			t.Errorf("ParseConstraint(%q) should return an error", input)
+		}
+	}
+}
+
+func TestConstraint_String(t *testing.T) {
+	if MustParseConstraint(" ^1.0.0 ").String() != "^1.0.0" {
+		t.Error("String() should return the trimmed constraint")
+	}
+}
+
+func TestMustParseConstraint_Panics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("MustParseConstraint() should panic on invalid input")
+		}
+	}()
+	MustParseConstraint(">=abc")
+}
+
+func TestSatisfies(t *testing.T) {
+	if !Satisfies("1.2.0", "^1.0.0") {
+		t.Error("Expected 1.2.0 to satisfy ^1.0.0")
+	}
+	if Satisfies("invalid", "*") {
+		t.Error("Expected unparseable versions not to satisfy any constraint")
+	}
+	if Satisfies("1.0.0", ">=abc") {
+		t.Error("Expected unparseable constraints not to be satisfied")
+	}
+}
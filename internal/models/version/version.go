@@ -0,0 +1,217 @@
+package version
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version. Calendar-style versions such as
+// 25.7.0 share the same layout and are handled the same way.
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease []string
+	Build      string
+}
+
+// Parse reads versions like 1.2.3, v1.2, 1.2.3-rc.1+build and the
+// calendar-style 27.7.1-d278aae used by release tags. A single
+// suffix that looks like a commit hash is kept as build metadata,
+// so tagged releases are not mistaken for pre-releases.
+func Parse(value string) (Version, error) {
+	raw := strings.TrimPrefix(strings.TrimSpace(value), "v")
+	if raw == "" {
+		return Version{}, fmt.Errorf("invalid version %q: empty", value)
+	}
+
+	var v Version
+
+	if core, build, ok := strings.Cut(raw, "+"); ok {
+		if build == "" {
+			return Version{}, fmt.Errorf("invalid version %q: empty build metadata", value)
+		}
+		raw, v.Build = core, build
+	}
+
+	if core, prerelease, ok := strings.Cut(raw, "-"); ok {
+		if prerelease == "" {
+			return Version{}, fmt.Errorf("invalid version %q: empty pre-release", value)
+		}
+		raw = core
+
+		if isCommitHash(prerelease) && v.Build == "" {
+			v.Build = prerelease
+		} else {
+			v.Prerelease = strings.Split(prerelease, ".")
+			for _, identifier := range v.Prerelease {
+				if identifier == "" {
+					return Version{}, fmt.Errorf("invalid version %q: empty pre-release identifier", value)
+				}
+			}
+		}
+	}
+
+	parts := strings.Split(raw, ".")
+	if len(parts) > 3 {
+		return Version{}, fmt.Errorf("invalid version %q: too many components", value)
+	}
+
+	numbers := [3]int{}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("invalid version %q: %q is not a number", value, part)
+		}
+		numbers[i] = n
+	}
+
+	v.Major, v.Minor, v.Patch = numbers[0], numbers[1], numbers[2]
+
+	return v, nil
+}
+
+// MustParse is like Parse but panics on invalid input.
+func MustParse(value string) Version {
+	v, err := Parse(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if len(v.Prerelease) > 0 {
+		s += "-" + strings.Join(v.Prerelease, ".")
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+
+	return s
+}
+
+func (v Version) IsPrerelease() bool {
+	return len(v.Prerelease) > 0
+}
+
+// Compare returns -1, 0 or 1 following semver precedence.
+// Build metadata does not take part in the comparison.
+func (v Version) Compare(other Version) int {
+	if c := compareInt(v.Major, other.Major); c != 0 {
+		return c
+	}
+	if c := compareInt(v.Minor, other.Minor); c != 0 {
+		return c
+	}
+	if c := compareInt(v.Patch, other.Patch); c != 0 {
+		return c
+	}
+
+	return comparePrerelease(v.Prerelease, other.Prerelease)
+}
+
+func (v Version) LessThan(other Version) bool {
+	return v.Compare(other) < 0
+}
+
+func (v Version) GreaterThan(other Version) bool {
+	return v.Compare(other) > 0
+}
+
+func (v Version) Equal(other Version) bool {
+	return v.Compare(other) == 0
+}
+
+// Compare parses and compares two version strings. Versions that
+// cannot be parsed sort before valid ones and lexically among
+// themselves, so listings stay deterministic.
+func Compare(a, b string) int {
+	va, errA := Parse(a)
+	vb, errB := Parse(b)
+
+	switch {
+	case errA == nil && errB == nil:
+		return va.Compare(vb)
+	case errA != nil && errB != nil:
+		return strings.Compare(a, b)
+	case errA != nil:
+		return -1
+	default:
+		return 1
+	}
+}
+
+func (v Version) sameCore(other Version) bool {
+	return v.Major == other.Major && v.Minor == other.Minor && v.Patch == other.Patch
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// comparePrerelease implements semver rule 11: a release ranks above
+// its pre-releases, numeric identifiers compare numerically and rank
+// below alphanumeric ones, and a longer list wins a shared prefix.
+func comparePrerelease(a, b []string) int {
+	switch {
+	case len(a) == 0 && len(b) == 0:
+		return 0
+	case len(a) == 0:
+		return 1
+	case len(b) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(a) && i < len(b); i++ {
+		an, aErr := strconv.Atoi(a[i])
+		bn, bErr := strconv.Atoi(b[i])
+
+		var c int
+		switch {
+		case aErr == nil && bErr == nil:
+			c = compareInt(an, bn)
+		case aErr == nil:
+			c = -1
+		case bErr == nil:
+			c = 1
+		default:
+			c = strings.Compare(a[i], b[i])
+		}
+
+		if c != 0 {
+			return c
+		}
+	}
+
+	return compareInt(len(a), len(b))
+}
+
+func isCommitHash(value string) bool {
+	if len(value) < 7 || len(value) > 40 {
+		return false
+	}
+
+	hasLetter := false
+	for _, r := range value {
+		switch {
+		case r >= '0' && r <= '9':
+		case r >= 'a' && r <= 'f':
+			hasLetter = true
+		default:
+			return false
+		}
+	}
+
+	return hasLetter
+}
@@ -0,0 +1,127 @@
+package version
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name       string
+		input      string
+		expected   string
+		prerelease bool
+	}{
+		{name: "semver", input: "1.2.3", expected: "1.2.3"},
+		{name: "v prefix", input: "v1.2.3", expected: "1.2.3"},
+		{name: "missing patch", input: "1.2", expected: "1.2.0"},
+		{name: "major only", input: "2", expected: "2.0.0"},
+		{name: "calendar", input: "25.7.0", expected: "25.7.0"},
+		{name: "calendar with commit", input: "27.7.1-d278aae", expected: "27.7.1+d278aae"},
+		{name: "pre-release", input: "1.0.0-rc.1", expected: "1.0.0-rc.1", prerelease: true},
+		{name: "numeric pre-release", input: "1.0.0-1", expected: "1.0.0-1", prerelease: true},
+		{name: "pre-release and build", input: "1.0.0-beta+exp.sha", expected: "1.0.0-beta+exp.sha", prerelease: true},
+		{name: "build only", input: "1.0.0+20250101", expected: "1.0.0+20250101"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v, err := Parse(tc.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", tc.input, err)
+			}
+			if v.String() != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, v.String())
+			}
+			if v.IsPrerelease() != tc.prerelease {
+				t.Errorf("Expected IsPrerelease() to return %v", tc.prerelease)
+			}
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	inputs := []string{"", "v", "latest", "1.2.3.4", "1.x.0", "-1.0.0", "1.0.0-", "1.0.0+", "1.0.0-rc..1", "domain"}
+
+	for _, input := range inputs {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q) should return an error", input)
+		}
+	}
+}
+
+func TestMustParse(t *testing.T) {
+	if MustParse("1.0.0").Major != 1 {
+		t.Error("MustParse() returned the wrong version")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("MustParse() should panic on invalid input")
+		}
+	}()
+	MustParse("invalid")
+}
+
+func TestVersion_Compare(t *testing.T) {
+	// Ordered from lowest to highest precedence
+	ordered := []string{
+		"0.9.0",
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.2.0",
+		"1.10.0",
+		"25.7.0",
+		"27.7.1-d278aae",
+	}
+
+	for i := range ordered {
+		for j := range ordered {
+			a, b := MustParse(ordered[i]), MustParse(ordered[j])
+			expected := compareInt(i, j)
+
+			if got := a.Compare(b); got != expected {
+				t.Errorf("Compare(%s, %s) = %d, expected %d", ordered[i], ordered[j], got, expected)
+			}
+		}
+	}
+}
+
+func TestVersion_BuildMetadataIgnored(t *testing.T) {
+	if !MustParse("1.0.0+a").Equal(MustParse("1.0.0+b")) {
+		t.Error("Build metadata should not affect precedence")
+	}
+}
+
+func TestVersion_Helpers(t *testing.T) {
+	low, high := MustParse("1.0.0"), MustParse("2.0.0")
+
+	if !low.LessThan(high) || low.GreaterThan(high) {
+		t.Error("Expected 1.0.0 to be less than 2.0.0")
+	}
+	if !high.GreaterThan(low) {
+		t.Error("Expected 2.0.0 to be greater than 1.0.0")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	testCases := []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "1.10.0", b: "1.9.0", expected: 1},
+		{a: "1.0.0", b: "v1.0.0", expected: 0},
+		{a: "invalid", b: "1.0.0", expected: -1},
+		{a: "1.0.0", b: "invalid", expected: 1},
+		{a: "abc", b: "abd", expected: -1},
+	}
+
+	for _, tc := range testCases {
+		if got := Compare(tc.a, tc.b); got != tc.expected {
+			t.Errorf("Compare(%q, %q) = %d, expected %d", tc.a, tc.b, got, tc.expected)
+		}
+	}
+}
@@ -1,12 +1,34 @@
 package arrows
 
 import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/rabbytesoftware/quiver/internal/core/database"
+	interfaces "github.com/rabbytesoftware/quiver/internal/core/database/interface"
+	"github.com/rabbytesoftware/quiver/internal/core/secrets"
 	"github.com/rabbytesoftware/quiver/internal/infrastructure"
 	domain "github.com/rabbytesoftware/quiver/internal/models/arrow"
 )
 
+const arrowsDatabase = "arrows"
+
 type ArrowsRepository struct {
 	infrastructure *infrastructure.Infrastructure
+
+	mu        sync.Mutex
+	arrows    interfaces.RepositoryInterface[domain.Arrow]
+	revisions interfaces.RepositoryInterface[domain.Revision]
+	audit     interfaces.RepositoryInterface[domain.VariableChange]
+	ports     interfaces.RepositoryInterface[domain.PortAssignment]
+	cipher    *secrets.Cipher
+	processes map[uuid.UUID][]string
+
+	// assigning serializes port allocation across installs.
+	assigning sync.Mutex
 }
 
 func NewArrowsRepository(
@@ -14,25 +36,152 @@ func NewArrowsRepository(
 ) ArrowsInterface {
 	return &ArrowsRepository{
 		infrastructure: infrastructure,
+		processes:      map[uuid.UUID][]string{},
+	}
+}
+
+// Get returns every installed arrow.
+func (a *ArrowsRepository) Get(ctx context.Context) ([]domain.Arrow, error) {
+	arrows, err := a.arrowStore(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := arrows.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	all := make([]domain.Arrow, 0, len(stored))
+	for _, arrow := range stored {
+		if err := a.unsealArrow(arrow); err != nil {
+			return nil, err
+		}
+		all = append(all, *arrow)
+	}
+
+	return all, nil
+}
+
+func (a *ArrowsRepository) GetById(ctx context.Context, id uuid.UUID) (*domain.Arrow, error) {
+	arrows, err := a.arrowStore(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	arrow, err := arrows.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.unsealArrow(arrow); err != nil {
+		return nil, err
+	}
+
+	return arrow, nil
+}
+
+// Create stores a newly installed arrow, giving it an ID if it has
+// none yet.
+func (a *ArrowsRepository) Create(ctx context.Context, arrow *domain.Arrow) (*domain.Arrow, error) {
+	arrows, err := a.arrowStore(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if arrow.ID == uuid.Nil {
+		arrow.ID = uuid.New()
+	}
+
+	stored, err := a.sealArrow(arrow)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := arrows.Create(ctx, stored); err != nil {
+		return nil, err
 	}
+
+	return arrow, nil
 }
 
-func (a *ArrowsRepository) Get() []domain.Arrow {
-	return []domain.Arrow{}
+func (a *ArrowsRepository) Update(ctx context.Context, arrow *domain.Arrow) (*domain.Arrow, error) {
+	arrows, err := a.arrowStore(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := a.sealArrow(arrow)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := arrows.Update(ctx, stored); err != nil {
+		return nil, err
+	}
+
+	return arrow, nil
 }
 
-func (a *ArrowsRepository) GetById(id string) *domain.Arrow {
-	return nil
+func (a *ArrowsRepository) DeleteById(ctx context.Context, id uuid.UUID) error {
+	arrows, err := a.arrowStore(ctx)
+	if err != nil {
+		return err
+	}
+
+	return arrows.Delete(ctx, id)
 }
 
-func (a *ArrowsRepository) Create(arrow *domain.Arrow) *domain.Arrow {
-	return nil
+// sealArrow returns the copy of arrow that is stored, with its
+// sensitive values encrypted, as SaveRevision does for revisions.
+func (a *ArrowsRepository) sealArrow(arrow *domain.Arrow) (*domain.Arrow, error) {
+	stored := *arrow
+	if !hasSecrets(&stored) {
+		return &stored, nil
+	}
+
+	cipher, err := a.secretCipher()
+	if err != nil {
+		return nil, err
+	}
+
+	if stored, err = seal(cipher, stored); err != nil {
+		return nil, err
+	}
+
+	return &stored, nil
 }
 
-func (a *ArrowsRepository) Update(arrow *domain.Arrow) *domain.Arrow {
-	return nil
+// unsealArrow decrypts the sensitive values of a stored arrow in place.
+func (a *ArrowsRepository) unsealArrow(arrow *domain.Arrow) error {
+	if !hasSecrets(arrow) {
+		return nil
+	}
+
+	cipher, err := a.secretCipher()
+	if err != nil {
+		return err
+	}
+
+	return unseal(cipher, arrow)
 }
 
-func (a *ArrowsRepository) DeleteById(id string) error {
-	return nil
+// arrowStore opens the installed arrows on first use, like revisionStore.
+func (a *ArrowsRepository) arrowStore(
+	ctx context.Context,
+) (interfaces.RepositoryInterface[domain.Arrow], error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.arrows != nil {
+		return a.arrows, nil
+	}
+
+	arrows, err := database.NewDatabase[domain.Arrow](ctx, arrowsDatabase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open installed arrows: %w", err)
+	}
+	a.arrows = arrows
+
+	return arrows, nil
 }
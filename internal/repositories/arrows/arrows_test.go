@@ -1,189 +1,152 @@
 package arrows
 
 import (
+	"context"
 	"testing"
 
+	"github.com/google/uuid"
+
 	"github.com/rabbytesoftware/quiver/internal/infrastructure"
 	domain "github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+	"github.com/rabbytesoftware/quiver/internal/models/variable"
 )
 
-func TestNewArrowsRepository(t *testing.T) {
-	infra := infrastructure.NewInfrastructure()
-	repo := NewArrowsRepository(infra)
-
-	if repo == nil {
-		t.Fatal("NewArrowsRepository() returned nil")
-	}
-
-	// Test interface compliance (repo is already ArrowsInterface type)
-	_ = repo
+func newArrowsRepository(t *testing.T) ArrowsInterface {
+	t.Helper()
+	t.Setenv("QUIVER_DATABASE_PATH", t.TempDir())
 
-	// Test concrete type
-	if _, ok := repo.(*ArrowsRepository); !ok {
-		t.Error("NewArrowsRepository() did not return *ArrowsRepository")
-	}
+	return NewArrowsRepository(infrastructure.NewInfrastructure())
 }
 
-func TestArrowsRepository_Get(t *testing.T) {
+func TestNewArrowsRepository(t *testing.T) {
 	infra := infrastructure.NewInfrastructure()
 	repo := NewArrowsRepository(infra)
 
-	arrows := repo.Get()
-
-	// Test that it returns a slice (even if empty)
-	if arrows == nil {
-		t.Error("Get() returned nil instead of empty slice")
+	concrete, ok := repo.(*ArrowsRepository)
+	if !ok {
+		t.Fatal("NewArrowsRepository() did not return *ArrowsRepository")
 	}
 
-	// Test that it returns a slice
-	_ = arrows // arrows is already []domain.Arrow type
-
-	// Current implementation returns empty slice
-	if len(arrows) != 0 {
-		t.Errorf("Expected empty slice, got %d arrows", len(arrows))
+	if concrete.infrastructure != infra {
+		t.Error("ArrowsRepository.infrastructure is not the same instance passed to constructor")
 	}
 }
 
-func TestArrowsRepository_GetById(t *testing.T) {
-	infra := infrastructure.NewInfrastructure()
-	repo := NewArrowsRepository(infra)
-
-	// Test with various IDs
-	testCases := []string{"", "test-id", "123", "non-existent"}
+func TestArrowsRepository_GetEmpty(t *testing.T) {
+	repo := newArrowsRepository(t)
 
-	for _, id := range testCases {
-		arrow := repo.GetById(id)
+	arrows, err := repo.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Get() returned error: %v", err)
+	}
 
-		// Current implementation returns nil
-		if arrow != nil {
-			t.Errorf("GetById(%q) expected nil, got %v", id, arrow)
-		}
+	if arrows == nil || len(arrows) != 0 {
+		t.Errorf("Expected an empty slice, got %v", arrows)
 	}
 }
 
-func TestArrowsRepository_Create(t *testing.T) {
-	infra := infrastructure.NewInfrastructure()
-	repo := NewArrowsRepository(infra)
-
-	// Test with nil arrow
-	result := repo.Create(nil)
-	if result != nil {
-		t.Error("Create(nil) expected nil, got non-nil result")
+func TestArrowsRepository_CRUD(t *testing.T) {
+	ctx := context.Background()
+	repo := newArrowsRepository(t)
+
+	value := "20"
+	created, err := repo.Create(ctx, &domain.Arrow{
+		Name:      "cs2",
+		Version:   "1.0.0",
+		Namespace: "core.quiver/cs2@1.0.0",
+		Netbridge: []port.PortRule{
+			{Name: "GAME_PORT", StartPort: 27015, EndPort: 27015, Protocol: port.ProtocolTCPUDP},
+		},
+		Variables: []variable.Variable{{Name: "MAX_PLAYERS", Value: &value}},
+	})
+	if err != nil {
+		t.Fatalf("Create() returned error: %v", err)
 	}
 
-	// Test with valid arrow
-	arrow := &domain.Arrow{}
-	result = repo.Create(arrow)
-
-	// Current implementation returns nil
-	if result != nil {
-		t.Error("Create() expected nil, got non-nil result")
+	if created.ID == uuid.Nil {
+		t.Fatal("Expected Create() to assign an ID")
 	}
-}
-
-func TestArrowsRepository_Update(t *testing.T) {
-	infra := infrastructure.NewInfrastructure()
-	repo := NewArrowsRepository(infra)
 
-	// Test with nil arrow
-	result := repo.Update(nil)
-	if result != nil {
-		t.Error("Update(nil) expected nil, got non-nil result")
+	found, err := repo.GetById(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetById() returned error: %v", err)
 	}
 
-	// Test with valid arrow
-	arrow := &domain.Arrow{}
-	result = repo.Update(arrow)
-
-	// Current implementation returns nil
-	if result != nil {
-		t.Error("Update() expected nil, got non-nil result")
+	if found.Name != "cs2" || found.Namespace != "core.quiver/cs2@1.0.0" {
+		t.Errorf("Expected cs2 to be stored, got %+v", found)
 	}
-}
-
-func TestArrowsRepository_DeleteById(t *testing.T) {
-	infra := infrastructure.NewInfrastructure()
-	repo := NewArrowsRepository(infra)
-
-	// Test with various IDs
-	testCases := []string{"", "test-id", "123", "non-existent"}
 
-	for _, id := range testCases {
-		err := repo.DeleteById(id)
-
-		// Current implementation returns nil (no error)
-		if err != nil {
-			t.Errorf("DeleteById(%q) expected nil error, got %v", id, err)
-		}
+	if len(found.Netbridge) != 1 || found.Netbridge[0].StartPort != 27015 {
+		t.Errorf("Expected the netbridge rules to be stored, got %+v", found.Netbridge)
 	}
-}
-
-func TestArrowsRepositoryStructure(t *testing.T) {
-	infra := infrastructure.NewInfrastructure()
-	repo := NewArrowsRepository(infra).(*ArrowsRepository)
 
-	// Test that infrastructure is properly set
-	if repo.infrastructure == nil {
-		t.Error("ArrowsRepository.infrastructure is nil")
+	if len(found.Variables) != 1 || *found.Variables[0].Value != "20" {
+		t.Errorf("Expected the variables to be stored, got %+v", found.Variables)
 	}
 
-	if repo.infrastructure != infra {
-		t.Error("ArrowsRepository.infrastructure is not the same instance passed to constructor")
+	found.Version = "1.1.0"
+	if _, err := repo.Update(ctx, found); err != nil {
+		t.Fatalf("Update() returned error: %v", err)
 	}
-}
 
-func TestArrowsRepositoryWithNilInfrastructure(t *testing.T) {
-	// Test behavior when nil infrastructure is passed
-	repo := NewArrowsRepository(nil)
+	arrows, err := repo.Get(ctx)
+	if err != nil {
+		t.Fatalf("Get() returned error: %v", err)
+	}
 
-	if repo == nil {
-		t.Fatal("NewArrowsRepository(nil) returned nil")
+	if len(arrows) != 1 || arrows[0].Version != "1.1.0" {
+		t.Errorf("Expected the updated arrow, got %+v", arrows)
 	}
 
-	// Test that methods still work with nil infrastructure
-	arrows := repo.Get()
-	if arrows == nil {
-		t.Error("Get() with nil infrastructure returned nil")
+	if err := repo.DeleteById(ctx, created.ID); err != nil {
+		t.Fatalf("DeleteById() returned error: %v", err)
 	}
 
-	arrow := repo.GetById("test")
-	if arrow != nil {
-		t.Error("GetById() with nil infrastructure should return nil")
+	if _, err := repo.GetById(ctx, created.ID); err == nil {
+		t.Error("Expected GetById() to fail after DeleteById()")
 	}
+}
 
-	err := repo.DeleteById("test")
+func TestArrowsRepository_CreateKeepsID(t *testing.T) {
+	ctx := context.Background()
+	repo := newArrowsRepository(t)
+
+	id := uuid.New()
+	created, err := repo.Create(ctx, &domain.Arrow{ID: id, Name: "cs2"})
 	if err != nil {
-		t.Error("DeleteById() with nil infrastructure should return nil error")
+		t.Fatalf("Create() returned error: %v", err)
+	}
+
+	if created.ID != id {
+		t.Errorf("Expected ID %s to be kept, got %s", id, created.ID)
 	}
 }
 
-func TestArrowsRepositoryInterfaceMethods(t *testing.T) {
-	infra := infrastructure.NewInfrastructure()
-	var repo ArrowsInterface = NewArrowsRepository(infra)
+func TestArrowsRepository_Persists(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("QUIVER_DATABASE_PATH", t.TempDir())
 
-	// Test that all interface methods are callable
-	arrows := repo.Get()
-	if arrows == nil {
-		t.Error("Interface Get() method returned nil")
+	created, err := NewArrowsRepository(nil).Create(ctx, &domain.Arrow{Name: "cs2"})
+	if err != nil {
+		t.Fatalf("Create() returned error: %v", err)
 	}
 
-	arrow := repo.GetById("test")
-	if arrow != nil {
-		t.Error("Interface GetById() method should return nil")
+	// ? A new repository stands in for a restart of Quiver.
+	found, err := NewArrowsRepository(nil).GetById(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetById() returned error: %v", err)
 	}
 
-	result := repo.Create(&domain.Arrow{})
-	if result != nil {
-		t.Error("Interface Create() method should return nil")
+	if found.Name != "cs2" {
+		t.Errorf("Expected cs2 to outlive the repository, got %q", found.Name)
 	}
+}
 
-	result = repo.Update(&domain.Arrow{})
-	if result != nil {
-		t.Error("Interface Update() method should return nil")
-	}
+func TestArrowsRepository_GetByIdMissing(t *testing.T) {
+	repo := newArrowsRepository(t)
 
-	err := repo.DeleteById("test")
-	if err != nil {
-		t.Error("Interface DeleteById() method should return nil error")
+	if _, err := repo.GetById(context.Background(), uuid.New()); err == nil {
+		t.Error("Expected an error for a missing arrow")
 	}
 }
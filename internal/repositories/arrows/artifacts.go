@@ -0,0 +1,66 @@
+package arrows
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+
+	domain "github.com/rabbytesoftware/quiver/internal/models/arrow"
+)
+
+// fetch runs a GET step, downloading rawURL into dir under the last
+// element of its path. The content is hashed while it is written, so
+// the checksum is that of the file that was installed.
+func (a *ArrowsRepository) fetch(
+	ctx context.Context,
+	rawURL string,
+	dir string,
+) (domain.Artifact, error) {
+	if a.infrastructure == nil || a.infrastructure.FNS == nil {
+		return domain.Artifact{}, fmt.Errorf("fetch and share is not available")
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return domain.Artifact{}, fmt.Errorf("invalid download URL %s: %w", rawURL, err)
+	}
+
+	name := path.Base(parsed.Path)
+	if name == "/" || name == "." {
+		return domain.Artifact{}, fmt.Errorf("download URL %s does not name a file", rawURL)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return domain.Artifact{}, err
+	}
+
+	reader, err := a.infrastructure.FNS.DownloadStream(ctx, rawURL, nil)
+	if err != nil {
+		return domain.Artifact{}, err
+	}
+	defer reader.Close()
+
+	dst := filepath.Join(dir, name)
+	file, err := os.Create(dst)
+	if err != nil {
+		return domain.Artifact{}, err
+	}
+
+	hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(file, hash), reader)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(dst)
+		return domain.Artifact{}, fmt.Errorf("failed to download %s: %w", rawURL, err)
+	}
+
+	return domain.Artifact{URL: rawURL, SHA256: hex.EncodeToString(hash.Sum(nil))}, nil
+}
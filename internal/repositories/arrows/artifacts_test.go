@@ -0,0 +1,50 @@
+package arrows
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rabbytesoftware/quiver/internal/infrastructure"
+)
+
+func TestArrowsRepository_Fetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing.tar.gz" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("quiver"))
+	}))
+	defer server.Close()
+
+	dir := filepath.Join(t.TempDir(), "cs2")
+	repo := NewArrowsRepository(infrastructure.NewInfrastructure()).(*ArrowsRepository)
+
+	artifact, err := repo.fetch(context.Background(), server.URL+"/cs2.tar.gz?mirror=eu", dir)
+	if err != nil {
+		t.Fatalf("fetch() returned error: %v", err)
+	}
+
+	if artifact.SHA256 != "c59435a1e9474332701fdc4ce135dbcc2d733502c43078e2d8a3565cc41ef48a" {
+		t.Errorf("Expected the checksum of the downloaded bytes, got %s", artifact.SHA256)
+	}
+
+	if got := readFile(t, filepath.Join(dir, "cs2.tar.gz")); got != "quiver" {
+		t.Errorf("Expected the download to be written, got %q", got)
+	}
+
+	if _, err := repo.fetch(context.Background(), server.URL+"/missing.tar.gz", dir); err == nil {
+		t.Error("fetch() should fail when a download is missing")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "missing.tar.gz")); !os.IsNotExist(err) {
+		t.Error("Expected no file to be left behind for a failed download")
+	}
+
+	if _, err := repo.fetch(context.Background(), server.URL+"/", dir); err == nil {
+		t.Error("fetch() should fail when the URL names no file")
+	}
+}
@@ -0,0 +1,76 @@
+package arrows
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"github.com/rabbytesoftware/quiver/internal/core/database"
+	interfaces "github.com/rabbytesoftware/quiver/internal/core/database/interface"
+	domain "github.com/rabbytesoftware/quiver/internal/models/arrow"
+)
+
+const auditDatabase = "variable_changes"
+
+func (a *ArrowsRepository) RecordVariableChange(
+	ctx context.Context,
+	change *domain.VariableChange,
+) error {
+	audit, err := a.auditStore(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = audit.Create(ctx, change)
+	return err
+}
+
+func (a *ArrowsRepository) VariableChanges(
+	ctx context.Context,
+	arrowID uuid.UUID,
+) ([]domain.VariableChange, error) {
+	audit, err := a.auditStore(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	all, err := audit.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []domain.VariableChange{}
+	for _, change := range all {
+		if change.ArrowID == arrowID {
+			changes = append(changes, *change)
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].ChangedAt.Before(changes[j].ChangedAt)
+	})
+
+	return changes, nil
+}
+
+// auditStore opens the audit trail on first use, like revisionStore.
+func (a *ArrowsRepository) auditStore(
+	ctx context.Context,
+) (interfaces.RepositoryInterface[domain.VariableChange], error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.audit != nil {
+		return a.audit, nil
+	}
+
+	audit, err := database.NewDatabase[domain.VariableChange](ctx, auditDatabase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open variable audit trail: %w", err)
+	}
+	a.audit = audit
+
+	return audit, nil
+}
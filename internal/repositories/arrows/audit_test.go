@@ -0,0 +1,39 @@
+package arrows
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	domain "github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/variable"
+)
+
+func TestVariableChanges(t *testing.T) {
+	t.Setenv("QUIVER_DATABASE_PATH", t.TempDir())
+
+	ctx := context.Background()
+	repo := &ArrowsRepository{}
+	cs2 := &domain.Arrow{ID: uuid.New()}
+	start := time.Now()
+
+	later := domain.NewVariableChange(cs2, variable.Variable{Name: "MAXPLAYERS"}, "12", "16", "admin", start.Add(time.Minute))
+	earlier := domain.NewVariableChange(cs2, variable.Variable{Name: "MAXPLAYERS"}, "10", "12", "admin", start)
+	other := domain.NewVariableChange(&domain.Arrow{ID: uuid.New()}, variable.Variable{Name: "MAXPLAYERS"}, "1", "2", "admin", start)
+
+	for _, change := range []*domain.VariableChange{later, earlier, other} {
+		if err := repo.RecordVariableChange(ctx, change); err != nil {
+			t.Fatalf("RecordVariableChange() error = %v", err)
+		}
+	}
+
+	changes, err := repo.VariableChanges(ctx, cs2.ID)
+	if err != nil {
+		t.Fatalf("VariableChanges() error = %v", err)
+	}
+	if len(changes) != 2 || changes[0].To != "12" || changes[1].To != "16" {
+		t.Errorf("Expected the two changes oldest first, got %+v", changes)
+	}
+}
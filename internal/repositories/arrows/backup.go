@@ -0,0 +1,372 @@
+package arrows
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rabbytesoftware/quiver/internal/core/config"
+	domain "github.com/rabbytesoftware/quiver/internal/models/arrow"
+)
+
+const (
+	backupIDLayout  = "20060102T150405.000Z"
+	backupExtension = ".tar.gz"
+	backupMetadata  = ".json"
+)
+
+var backupID = regexp.MustCompile(`^\d{8}T\d{6}\.\d{3}Z$`)
+
+// BackupDir returns the directory the backups of an arrow are kept in.
+func BackupDir(arrow *domain.Arrow) string {
+	return filepath.Join(config.GetArrows().BackupDir, arrow.Name)
+}
+
+func (a *ArrowsRepository) CreateBackup(
+	ctx context.Context,
+	arrow *domain.Arrow,
+	quiesced bool,
+) (*domain.Backup, error) {
+	return createBackup(BackupDir(arrow), archiveSources(arrow), arrow, quiesced, time.Now())
+}
+
+func (a *ArrowsRepository) Backups(
+	ctx context.Context,
+	arrow *domain.Arrow,
+) ([]domain.Backup, error) {
+	return listBackups(BackupDir(arrow))
+}
+
+func (a *ArrowsRepository) RestoreBackup(
+	ctx context.Context,
+	arrow *domain.Arrow,
+	id string,
+) error {
+	archive, err := backupPath(BackupDir(arrow), id)
+	if err != nil {
+		return err
+	}
+
+	if err := extractArchive(archive, InstallDir(arrow)); err != nil {
+		return fmt.Errorf("failed to restore backup %s of %s: %w", id, arrow.Name, err)
+	}
+
+	return nil
+}
+
+func (a *ArrowsRepository) DeleteBackup(
+	ctx context.Context,
+	arrow *domain.Arrow,
+	id string,
+) error {
+	return deleteBackup(BackupDir(arrow), id)
+}
+
+// archiveSource is a directory stored in an archive under prefix.
+type archiveSource struct {
+	dir    string
+	prefix string
+}
+
+// archiveSources returns the install directory and, since the walk
+// does not follow the symlinks pointing there, every data path.
+func archiveSources(arrow *domain.Arrow) []archiveSource {
+	sources := []archiveSource{{dir: InstallDir(arrow)}}
+
+	for _, p := range arrow.Data.Clean() {
+		sources = append(sources, archiveSource{
+			dir:    filepath.Join(DataDir(arrow), filepath.FromSlash(p)),
+			prefix: p,
+		})
+	}
+
+	return sources
+}
+
+func createBackup(
+	dir string,
+	sources []archiveSource,
+	arrow *domain.Arrow,
+	quiesced bool,
+	now time.Time,
+) (*domain.Backup, error) {
+	if err := arrow.Backup.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	id := now.UTC().Format(backupIDLayout)
+	archive := filepath.Join(dir, id+backupExtension)
+
+	files, err := writeArchive(sources, archive, arrow.Backup)
+	if err != nil {
+		os.Remove(archive)
+		return nil, fmt.Errorf("failed to back up %s: %w", arrow.Name, err)
+	}
+
+	info, err := os.Stat(archive)
+	if err != nil {
+		return nil, err
+	}
+
+	backup := &domain.Backup{
+		ID:        id,
+		Arrow:     arrow.Name,
+		Version:   arrow.Version,
+		Path:      archive,
+		Size:      info.Size(),
+		Files:     files,
+		Quiesced:  quiesced,
+		CreatedAt: now.UTC(),
+	}
+
+	data, err := json.MarshalIndent(backup, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, id+backupMetadata), data, 0644); err != nil {
+		os.Remove(archive)
+		return nil, err
+	}
+
+	return backup, nil
+}
+
+// listBackups reads the metadata written next to every
+// archive, newest first.
+func listBackups(dir string) ([]domain.Backup, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return []domain.Backup{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	backups := []domain.Backup{}
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), backupMetadata)
+		if !ok || !backupID.MatchString(id) {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		var backup domain.Backup
+		if err := json.Unmarshal(data, &backup); err != nil {
+			return nil, fmt.Errorf("invalid backup metadata %s: %w", entry.Name(), err)
+		}
+
+		backups = append(backups, backup)
+	}
+
+	sort.SliceStable(backups, func(i, j int) bool {
+		return backups[i].CreatedAt.After(backups[j].CreatedAt)
+	})
+
+	return backups, nil
+}
+
+func deleteBackup(dir, id string) error {
+	archive, err := backupPath(dir, id)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(archive); err != nil {
+		return err
+	}
+
+	return os.Remove(filepath.Join(dir, id+backupMetadata))
+}
+
+// backupPath resolves a backup ID, which is never allowed to
+// point outside of the backup directory.
+func backupPath(dir, id string) (string, error) {
+	if !backupID.MatchString(id) {
+		return "", fmt.Errorf("%w: %s", os.ErrNotExist, id)
+	}
+
+	archive := filepath.Join(dir, id+backupExtension)
+	if _, err := os.Stat(archive); err != nil {
+		return "", err
+	}
+
+	return archive, nil
+}
+
+// writeArchive stores the regular files of the sources selected by
+// spec in a gzip compressed tarball and returns how many were stored.
+func writeArchive(
+	sources []archiveSource,
+	dst string,
+	spec domain.BackupSpec,
+) (int, error) {
+	out, err := os.Create(dst)
+	if err != nil {
+		return 0, err
+	}
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	files := 0
+
+	for _, source := range sources {
+		stored, err := writeSource(tw, source, spec)
+		if err != nil {
+			return 0, err
+		}
+		files += stored
+	}
+
+	if err := tw.Close(); err != nil {
+		return 0, err
+	}
+	if err := gz.Close(); err != nil {
+		return 0, err
+	}
+
+	return files, out.Close()
+}
+
+func writeSource(
+	tw *tar.Writer,
+	source archiveSource,
+	spec domain.BackupSpec,
+) (int, error) {
+	files := 0
+
+	err := filepath.Walk(source.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(source.dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(filepath.Join(source.prefix, rel))
+
+		if !spec.Selects(rel) {
+			return nil
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = rel
+
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		if _, err := io.Copy(tw, file); err != nil {
+			return err
+		}
+
+		files++
+		return nil
+	})
+	if os.IsNotExist(err) {
+		err = nil
+	}
+
+	return files, err
+}
+
+// extractArchive writes every file of an archive into dst,
+// replacing files that already exist. Other files are kept.
+func extractArchive(archive, dst string) error {
+	in, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	gz, err := gzip.NewReader(in)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	root := filepath.Clean(dst) + string(os.PathSeparator)
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		target := filepath.Join(dst, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(target, root) {
+			return fmt.Errorf("archive entry %q escapes %s", header.Name, dst)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+
+		if err := replaceFile(target, tr, header.FileInfo().Mode().Perm()); err != nil {
+			return err
+		}
+	}
+}
+
+// replaceFile replaces path atomically with the content of r.
+func replaceFile(path string, r io.Reader, mode os.FileMode) error {
+	tmp := path + ".restore"
+
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		os.Remove(tmp)
+		return err
+	}
+
+	if err := out.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,143 @@
+package arrows
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	domain "github.com/rabbytesoftware/quiver/internal/models/arrow"
+)
+
+func TestCreateAndRestoreBackup(t *testing.T) {
+	dir := t.TempDir()
+	install := filepath.Join(dir, "install")
+	backups := filepath.Join(dir, "backups")
+
+	writeFile(t, filepath.Join(install, "data", "world.dat"), "world v1")
+	writeFile(t, filepath.Join(install, "data", "logs", "latest.log"), "log")
+	writeFile(t, filepath.Join(install, "bin", "server"), "binary")
+
+	arrow := &domain.Arrow{
+		Name:    "cs2",
+		Version: "1.0.0",
+		Backup: domain.BackupSpec{
+			Include: []string{"data"},
+			Exclude: []string{"**/*.log"},
+		},
+	}
+
+	now := time.Date(2025, 7, 1, 4, 0, 0, 0, time.UTC)
+	backup, err := createBackup(backups, []archiveSource{{dir: install}}, arrow, true, now)
+	if err != nil {
+		t.Fatalf("createBackup() error = %v", err)
+	}
+
+	if backup.Files != 1 {
+		t.Errorf("Expected 1 file in backup, got %d", backup.Files)
+	}
+	if !backup.Quiesced || backup.Version != "1.0.0" || backup.Size == 0 {
+		t.Errorf("Unexpected backup metadata: %+v", backup)
+	}
+
+	writeFile(t, filepath.Join(install, "data", "world.dat"), "world v2")
+	writeFile(t, filepath.Join(install, "data", "new.dat"), "kept")
+
+	archive, err := backupPath(backups, backup.ID)
+	if err != nil {
+		t.Fatalf("backupPath() error = %v", err)
+	}
+	if err := extractArchive(archive, install); err != nil {
+		t.Fatalf("extractArchive() error = %v", err)
+	}
+
+	if got := readFile(t, filepath.Join(install, "data", "world.dat")); got != "world v1" {
+		t.Errorf("Expected world.dat to be restored, got %q", got)
+	}
+	if got := readFile(t, filepath.Join(install, "data", "new.dat")); got != "kept" {
+		t.Errorf("Expected files missing from the backup to be kept, got %q", got)
+	}
+}
+
+func TestListAndDeleteBackups(t *testing.T) {
+	dir := t.TempDir()
+	install := filepath.Join(dir, "install")
+	backups := filepath.Join(dir, "backups")
+	writeFile(t, filepath.Join(install, "server.cfg"), "cfg")
+
+	arrow := &domain.Arrow{Name: "cs2", Version: "1.0.0"}
+
+	list, err := listBackups(backups)
+	if err != nil || len(list) != 0 {
+		t.Fatalf("Expected no backups before the first one, got %v, %v", list, err)
+	}
+
+	start := time.Date(2025, 7, 1, 4, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		if _, err := createBackup(backups, []archiveSource{{dir: install}}, arrow, false, start.Add(time.Duration(i)*time.Hour)); err != nil {
+			t.Fatalf("createBackup() error = %v", err)
+		}
+	}
+
+	list, err = listBackups(backups)
+	if err != nil {
+		t.Fatalf("listBackups() error = %v", err)
+	}
+	if len(list) != 3 {
+		t.Fatalf("Expected 3 backups, got %d", len(list))
+	}
+	if !list[0].CreatedAt.After(list[2].CreatedAt) {
+		t.Errorf("Expected backups newest first, got %v then %v", list[0].CreatedAt, list[2].CreatedAt)
+	}
+
+	if err := deleteBackup(backups, list[0].ID); err != nil {
+		t.Fatalf("deleteBackup() error = %v", err)
+	}
+
+	list, _ = listBackups(backups)
+	if len(list) != 2 {
+		t.Errorf("Expected 2 backups after delete, got %d", len(list))
+	}
+}
+
+func TestBackupPath_RejectsInvalidIDs(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, id := range []string{"", "../secret", "20250701T040000.000Z/../../x", "missing"} {
+		if _, err := backupPath(dir, id); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("backupPath(%q) error = %v, want not exist", id, err)
+		}
+	}
+
+	if _, err := backupPath(dir, "20250701T040000.000Z"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected missing backup to not exist, got %v", err)
+	}
+}
+
+func TestExtractArchive_RejectsTraversal(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "evil.tar.gz")
+
+	out, err := os.Create(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	content := []byte("owned")
+	tw.WriteHeader(&tar.Header{Name: "../escape.txt", Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+	tw.Write(content)
+	tw.Close()
+	gz.Close()
+	out.Close()
+
+	if err := extractArchive(archive, filepath.Join(dir, "install")); err == nil {
+		t.Error("Expected an entry escaping the install directory to be rejected")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "escape.txt")); !os.IsNotExist(err) {
+		t.Error("Expected no file to be written outside of the install directory")
+	}
+}
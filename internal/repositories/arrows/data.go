@@ -0,0 +1,126 @@
+package arrows
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rabbytesoftware/quiver/internal/core/config"
+	domain "github.com/rabbytesoftware/quiver/internal/models/arrow"
+)
+
+// DataDir returns the directory the persistent data of an arrow is
+// kept in. It outlives the install directory.
+func DataDir(arrow *domain.Arrow) string {
+	return filepath.Join(config.GetArrows().DataDir, arrow.Name)
+}
+
+func (a *ArrowsRepository) PrepareData(
+	ctx context.Context,
+	arrow *domain.Arrow,
+) error {
+	if err := arrow.Data.Validate(); err != nil {
+		return fmt.Errorf("invalid data paths of %s: %w", arrow.Namespace, err)
+	}
+
+	for _, p := range arrow.Data.Clean() {
+		if err := os.MkdirAll(filepath.Join(DataDir(arrow), filepath.FromSlash(p)), 0755); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *ArrowsRepository) LinkData(
+	ctx context.Context,
+	arrow *domain.Arrow,
+) error {
+	if err := a.PrepareData(ctx, arrow); err != nil {
+		return err
+	}
+
+	for _, p := range arrow.Data.Clean() {
+		rel := filepath.FromSlash(p)
+		if err := linkData(filepath.Join(InstallDir(arrow), rel), filepath.Join(DataDir(arrow), rel)); err != nil {
+			return fmt.Errorf("failed to link %s of %s: %w", p, arrow.Namespace, err)
+		}
+	}
+
+	return nil
+}
+
+func (a *ArrowsRepository) PurgeData(
+	ctx context.Context,
+	arrow *domain.Arrow,
+) error {
+	return os.RemoveAll(DataDir(arrow))
+}
+
+// linkData makes path a symlink to data. Files an install shipped at
+// path seed the data directory without overwriting what is already
+// there, so defaults only apply on the first install.
+func linkData(path, data string) error {
+	target, err := filepath.Abs(data)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Lstat(path)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return err
+	case info.Mode()&os.ModeSymlink != 0:
+		if link, err := os.Readlink(path); err == nil && link == target {
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	case info.IsDir():
+		if err := seed(path, target); err != nil {
+			return err
+		}
+		if err := os.RemoveAll(path); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%s is a file, data paths must be directories", path)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	return os.Symlink(target, path)
+}
+
+// seed moves the entries of src that dst does not have yet into dst.
+func seed(src, dst string) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		from := filepath.Join(src, entry.Name())
+		to := filepath.Join(dst, entry.Name())
+
+		if _, err := os.Lstat(to); err == nil {
+			if entry.IsDir() {
+				if err := seed(from, to); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if err := os.Rename(from, to); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package arrows
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	domain "github.com/rabbytesoftware/quiver/internal/models/arrow"
+)
+
+func TestLinkData_SeedsAndKeepsData(t *testing.T) {
+	dir := t.TempDir()
+	install := filepath.Join(dir, "install", "saves")
+	data := filepath.Join(dir, "data", "saves")
+
+	if err := os.MkdirAll(data, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(data, "world.dat"), "player world")
+	writeFile(t, filepath.Join(install, "world.dat"), "default world")
+	writeFile(t, filepath.Join(install, "defaults.cfg"), "defaults")
+
+	if err := linkData(install, data); err != nil {
+		t.Fatalf("linkData() error = %v", err)
+	}
+
+	info, err := os.Lstat(install)
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Fatalf("Expected %s to be a symlink, got %v, %v", install, info, err)
+	}
+
+	if got := readFile(t, filepath.Join(install, "world.dat")); got != "player world" {
+		t.Errorf("Expected existing data to win over shipped files, got %q", got)
+	}
+	if got := readFile(t, filepath.Join(data, "defaults.cfg")); got != "defaults" {
+		t.Errorf("Expected shipped files to seed the data directory, got %q", got)
+	}
+
+	if err := linkData(install, data); err != nil {
+		t.Errorf("Expected linking twice to be a no-op, got %v", err)
+	}
+}
+
+func TestLinkData_SurvivesInstallRemoval(t *testing.T) {
+	dir := t.TempDir()
+	install := filepath.Join(dir, "install")
+	data := filepath.Join(dir, "data", "saves")
+
+	if err := os.MkdirAll(data, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(data, "world.dat"), "player world")
+
+	if err := linkData(filepath.Join(install, "saves"), data); err != nil {
+		t.Fatalf("linkData() error = %v", err)
+	}
+
+	// ? What an uninstall method's REMOVE: ${INSTALL_DIR} does.
+	if err := os.RemoveAll(install); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := readFile(t, filepath.Join(data, "world.dat")); got != "player world" {
+		t.Errorf("Expected data to survive removing the install directory, got %q", got)
+	}
+}
+
+func TestLinkData_RejectsFiles(t *testing.T) {
+	dir := t.TempDir()
+	install := filepath.Join(dir, "install", "saves")
+	writeFile(t, install, "not a directory")
+
+	if err := linkData(install, filepath.Join(dir, "data", "saves")); err == nil {
+		t.Error("Expected a file at a data path to be rejected")
+	}
+}
+
+func TestBackup_IncludesLinkedData(t *testing.T) {
+	dir := t.TempDir()
+	install := filepath.Join(dir, "install")
+	data := filepath.Join(dir, "data", "saves")
+
+	writeFile(t, filepath.Join(install, "server.cfg"), "cfg")
+	writeFile(t, filepath.Join(data, "world.dat"), "player world")
+	if err := linkData(filepath.Join(install, "saves"), data); err != nil {
+		t.Fatal(err)
+	}
+
+	arrow := &domain.Arrow{Name: "cs2", Version: "1.0.0", Data: domain.DataPaths{"saves"}}
+	sources := []archiveSource{{dir: install}, {dir: data, prefix: "saves"}}
+
+	backup, err := createBackup(filepath.Join(dir, "backups"), sources, arrow, false, time.Now())
+	if err != nil {
+		t.Fatalf("createBackup() error = %v", err)
+	}
+	if backup.Files != 2 {
+		t.Errorf("Expected the install and data files to be archived, got %d files", backup.Files)
+	}
+
+	writeFile(t, filepath.Join(data, "world.dat"), "griefed world")
+	if err := extractArchive(backup.Path, install); err != nil {
+		t.Fatalf("extractArchive() error = %v", err)
+	}
+
+	if got := readFile(t, filepath.Join(data, "world.dat")); got != "player world" {
+		t.Errorf("Expected restoring to write through to the data directory, got %q", got)
+	}
+}
@@ -0,0 +1,258 @@
+package arrows
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"github.com/rabbytesoftware/quiver/internal/core/config"
+	"github.com/rabbytesoftware/quiver/internal/core/database"
+	interfaces "github.com/rabbytesoftware/quiver/internal/core/database/interface"
+	domain "github.com/rabbytesoftware/quiver/internal/models/arrow"
+)
+
+const revisionsDatabase = "revisions"
+
+// SnapshotDir returns the directory the install tree of an
+// arrow version is copied to before it gets replaced.
+func SnapshotDir(arrow *domain.Arrow) string {
+	return filepath.Join(
+		config.GetArrows().InstallDir,
+		".snapshots",
+		arrow.Name,
+		arrow.Version,
+	)
+}
+
+func (a *ArrowsRepository) History(
+	ctx context.Context,
+	arrowID uuid.UUID,
+) ([]domain.Revision, error) {
+	revisions, err := a.revisionStore(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	all, err := revisions.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	history := []domain.Revision{}
+	for _, revision := range all {
+		if revision.ArrowID != arrowID {
+			continue
+		}
+
+		if hasSecrets(&revision.Arrow) {
+			cipher, err := a.secretCipher()
+			if err != nil {
+				return nil, err
+			}
+			if err := unseal(cipher, &revision.Arrow); err != nil {
+				return nil, err
+			}
+		}
+
+		history = append(history, *revision)
+	}
+
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].CreatedAt.Before(history[j].CreatedAt)
+	})
+
+	return history, nil
+}
+
+func (a *ArrowsRepository) SaveRevision(
+	ctx context.Context,
+	revision *domain.Revision,
+) error {
+	revisions, err := a.revisionStore(ctx)
+	if err != nil {
+		return err
+	}
+
+	stored := *revision
+	if hasSecrets(&stored.Arrow) {
+		cipher, err := a.secretCipher()
+		if err != nil {
+			return err
+		}
+		if stored.Arrow, err = seal(cipher, stored.Arrow); err != nil {
+			return err
+		}
+	}
+
+	_, err = revisions.Update(ctx, &stored)
+	return err
+}
+
+func (a *ArrowsRepository) DeleteHistory(
+	ctx context.Context,
+	arrowID uuid.UUID,
+) error {
+	history, err := a.History(ctx, arrowID)
+	if err != nil {
+		return err
+	}
+
+	revisions, err := a.revisionStore(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, revision := range history {
+		if err := a.RemoveSnapshot(revision.Snapshot); err != nil {
+			return err
+		}
+		if err := revisions.Delete(ctx, revision.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *ArrowsRepository) Snapshot(
+	ctx context.Context,
+	arrow *domain.Arrow,
+) (string, error) {
+	dir := SnapshotDir(arrow)
+	if err := snapshot(InstallDir(arrow), dir); err != nil {
+		return "", fmt.Errorf("failed to snapshot %s: %w", arrow.Namespace, err)
+	}
+
+	return dir, nil
+}
+
+func (a *ArrowsRepository) Restore(
+	ctx context.Context,
+	arrow *domain.Arrow,
+	snapshot string,
+) error {
+	if err := restore(snapshot, InstallDir(arrow)); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", arrow.Namespace, err)
+	}
+
+	return nil
+}
+
+func (a *ArrowsRepository) RemoveSnapshot(snapshot string) error {
+	if snapshot == "" {
+		return nil
+	}
+
+	return os.RemoveAll(snapshot)
+}
+
+// revisionStore opens the revisions database on first use, so
+// nothing is written to disk until an arrow is installed.
+func (a *ArrowsRepository) revisionStore(
+	ctx context.Context,
+) (interfaces.RepositoryInterface[domain.Revision], error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.revisions != nil {
+		return a.revisions, nil
+	}
+
+	revisions, err := database.NewDatabase[domain.Revision](ctx, revisionsDatabase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open revision history: %w", err)
+	}
+	a.revisions = revisions
+
+	return revisions, nil
+}
+
+// snapshot replaces dst with a copy of src. A missing src
+// yields an empty snapshot.
+func snapshot(src, dst string) error {
+	if err := os.RemoveAll(dst); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(src); os.IsNotExist(err) {
+		return os.MkdirAll(dst, 0755)
+	}
+
+	return copyTree(src, dst)
+}
+
+// restore replaces dst with a copy of src. The copy is made next to
+// dst first so a failure leaves the current install tree in place.
+func restore(src, dst string) error {
+	if _, err := os.Stat(src); err != nil {
+		return err
+	}
+
+	staging := dst + ".restore"
+	if err := os.RemoveAll(staging); err != nil {
+		return err
+	}
+
+	if err := copyTree(src, staging); err != nil {
+		os.RemoveAll(staging)
+		return err
+	}
+
+	if err := os.RemoveAll(dst); err != nil {
+		return err
+	}
+
+	return os.Rename(staging, dst)
+}
+
+func copyTree(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		switch {
+		case info.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		default:
+			return copyFile(path, target, info.Mode().Perm())
+		}
+	})
+}
+
+func copyFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
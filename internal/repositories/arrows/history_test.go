@@ -0,0 +1,149 @@
+package arrows
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	domain "github.com/rabbytesoftware/quiver/internal/models/arrow"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestSnapshotAndRestore(t *testing.T) {
+	dir := t.TempDir()
+	install := filepath.Join(dir, "cs2")
+	snap := filepath.Join(dir, ".snapshots", "cs2", "1.0.0")
+
+	writeFile(t, filepath.Join(install, "server.cfg"), "v1")
+	writeFile(t, filepath.Join(install, "bin", "server"), "binary v1")
+
+	if err := snapshot(install, snap); err != nil {
+		t.Fatalf("snapshot() error = %v", err)
+	}
+
+	writeFile(t, filepath.Join(install, "server.cfg"), "v2")
+	writeFile(t, filepath.Join(install, "new.txt"), "added in v2")
+
+	if err := restore(snap, install); err != nil {
+		t.Fatalf("restore() error = %v", err)
+	}
+
+	if got := readFile(t, filepath.Join(install, "server.cfg")); got != "v1" {
+		t.Errorf("Expected server.cfg to be restored, got %q", got)
+	}
+	if got := readFile(t, filepath.Join(install, "bin", "server")); got != "binary v1" {
+		t.Errorf("Expected nested files to be restored, got %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(install, "new.txt")); !os.IsNotExist(err) {
+		t.Error("Expected files added after the snapshot to be removed")
+	}
+	if _, err := os.Stat(install + ".restore"); !os.IsNotExist(err) {
+		t.Error("Expected the staging directory to be gone")
+	}
+}
+
+func TestSnapshot_MissingInstallDir(t *testing.T) {
+	dir := t.TempDir()
+	snap := filepath.Join(dir, "snapshot")
+
+	if err := snapshot(filepath.Join(dir, "missing"), snap); err != nil {
+		t.Fatalf("snapshot() error = %v", err)
+	}
+
+	entries, err := os.ReadDir(snap)
+	if err != nil || len(entries) != 0 {
+		t.Errorf("Expected an empty snapshot, got %v (%v)", entries, err)
+	}
+}
+
+func TestRestore_MissingSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	install := filepath.Join(dir, "cs2")
+	writeFile(t, filepath.Join(install, "server.cfg"), "v2")
+
+	if err := restore(filepath.Join(dir, "missing"), install); err == nil {
+		t.Error("Expected an error for a missing snapshot")
+	}
+
+	if got := readFile(t, filepath.Join(install, "server.cfg")); got != "v2" {
+		t.Errorf("Expected the install tree to be untouched, got %q", got)
+	}
+}
+
+func TestArrowsRepository_History(t *testing.T) {
+	t.Setenv("QUIVER_DATABASE_PATH", t.TempDir())
+
+	ctx := context.Background()
+	repo := &ArrowsRepository{}
+	arrowID := uuid.New()
+	start := time.Now()
+
+	older := domain.NewRevision(&domain.Arrow{ID: arrowID, Version: "1.0.0"}, domain.RevisionInstall, start)
+	newer := domain.NewRevision(&domain.Arrow{ID: arrowID, Version: "2.0.0"}, domain.RevisionUpdate, start.Add(time.Minute))
+	other := domain.NewRevision(&domain.Arrow{ID: uuid.New(), Version: "1.0.0"}, domain.RevisionInstall, start)
+
+	for _, revision := range []*domain.Revision{newer, older, other} {
+		if err := repo.SaveRevision(ctx, revision); err != nil {
+			t.Fatalf("SaveRevision() error = %v", err)
+		}
+	}
+
+	history, err := repo.History(ctx, arrowID)
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+	if len(history) != 2 || history[0].Version != "1.0.0" || history[1].Version != "2.0.0" {
+		t.Fatalf("Expected the two revisions oldest first, got %+v", history)
+	}
+	if history[1].Arrow.Version != "2.0.0" {
+		t.Errorf("Expected the arrow to be stored with the revision, got %+v", history[1].Arrow)
+	}
+
+	snap := filepath.Join(t.TempDir(), "snapshot")
+	writeFile(t, filepath.Join(snap, "server.cfg"), "v1")
+	older.Snapshot = snap
+	if err := repo.SaveRevision(ctx, older); err != nil {
+		t.Fatalf("SaveRevision() error = %v", err)
+	}
+
+	if err := repo.DeleteHistory(ctx, arrowID); err != nil {
+		t.Fatalf("DeleteHistory() error = %v", err)
+	}
+
+	history, _ = repo.History(ctx, arrowID)
+	if len(history) != 0 {
+		t.Errorf("Expected no history, got %+v", history)
+	}
+	if _, err := os.Stat(snap); !os.IsNotExist(err) {
+		t.Error("Expected snapshots to be removed with the history")
+	}
+
+	remaining, _ := repo.History(ctx, other.ArrowID)
+	if len(remaining) != 1 {
+		t.Errorf("Expected other arrows to keep their history, got %+v", remaining)
+	}
+}
@@ -1,10 +1,149 @@
 package arrows
 
 import (
+	"context"
+
+	"github.com/google/uuid"
+
 	domain "github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+	"github.com/rabbytesoftware/quiver/internal/models/requirement"
+	"github.com/rabbytesoftware/quiver/internal/models/runtime"
+	"github.com/rabbytesoftware/quiver/internal/models/template"
 	"github.com/rabbytesoftware/quiver/internal/repositories/common"
 )
 
 type ArrowsInterface interface {
 	common.CRUD[domain.Arrow]
+
+	// Run executes the steps an arrow declares for the given
+	// action on the host OS.
+	Run(ctx context.Context, arrow *domain.Arrow, action runtime.Action) error
+
+	// Steps returns the interpolated steps for an action on the
+	// host OS with sensitive values masked, without running them.
+	Steps(arrow *domain.Arrow, action runtime.Action) ([]string, error)
+
+	// ArtifactSize returns the size in bytes of a download,
+	// or -1 when the server does not report it.
+	ArtifactSize(ctx context.Context, url string) (int64, error)
+
+	// CheckRequirements compares an arrow's requirements with the host.
+	CheckRequirements(ctx context.Context, requirements *requirement.Requirement, committed requirement.Tier) *requirement.Report
+
+	// AvailableDisk returns the free space in MB for the install directory.
+	AvailableDisk(ctx context.Context) (int, error)
+
+	// AssignPorts gives every netbridge entry of an arrow its
+	// recorded port, or a free one from netbridge.allowed_ports
+	// that is then recorded.
+	AssignPorts(ctx context.Context, arrow *domain.Arrow) error
+
+	// PreviewPorts returns the ports AssignPorts would give an arrow
+	// without recording them. Ranges in reserved are treated as
+	// taken, for arrows installed earlier in the same plan.
+	PreviewPorts(ctx context.Context, arrow *domain.Arrow, reserved []port.Range) ([]port.PortRule, error)
+
+	// ReleasePorts forgets the ports assigned to an arrow.
+	ReleasePorts(ctx context.Context, arrowID uuid.UUID) error
+
+	// RestorePorts records the ports of an arrow's netbridge rules as
+	// its assignment, replacing the one it holds, so a failed update
+	// gives the instance its previous ports back.
+	RestorePorts(ctx context.Context, arrow *domain.Arrow) error
+
+	// OpenPorts forwards the assigned ports of an arrow on the
+	// gateway and records the outcome in each rule's status.
+	OpenPorts(ctx context.Context, arrow *domain.Arrow) error
+
+	// ClosePorts removes the forwarded ports of an arrow.
+	ClosePorts(ctx context.Context, arrow *domain.Arrow) error
+
+	// RefreshPorts copies the current forwarding status of each
+	// port onto the rules of an arrow and reports any change.
+	RefreshPorts(ctx context.Context, arrow *domain.Arrow) bool
+
+	// CheckPorts tests whether the forwarded ports of an arrow can
+	// be reached from the internet, records the diagnosis on each
+	// rule and reports any change.
+	CheckPorts(ctx context.Context, arrow *domain.Arrow) bool
+
+	// ReconcilePorts renews forwarded ports and re-creates the
+	// ones the gateway lost.
+	ReconcilePorts(ctx context.Context) error
+
+	// CloseAllPorts removes every forwarded port, for shutdown.
+	CloseAllPorts(ctx context.Context) error
+
+	// History returns the recorded revisions of an installed
+	// arrow, oldest first.
+	History(ctx context.Context, arrowID uuid.UUID) ([]domain.Revision, error)
+
+	// SaveRevision creates or updates a revision.
+	SaveRevision(ctx context.Context, revision *domain.Revision) error
+
+	// DeleteHistory forgets every revision of an arrow and
+	// removes their snapshots.
+	DeleteHistory(ctx context.Context, arrowID uuid.UUID) error
+
+	// Snapshot copies an arrow's install tree aside and
+	// returns where it was copied to.
+	Snapshot(ctx context.Context, arrow *domain.Arrow) (string, error)
+
+	// Restore replaces an arrow's install tree with a snapshot.
+	Restore(ctx context.Context, arrow *domain.Arrow, snapshot string) error
+
+	// RemoveSnapshot deletes a snapshot that is no longer needed.
+	RemoveSnapshot(snapshot string) error
+
+	// Start runs an arrow's execute method as long-running processes.
+	Start(ctx context.Context, arrow *domain.Arrow) error
+
+	// Stop stops the processes started for an arrow.
+	Stop(ctx context.Context, arrow *domain.Arrow) error
+
+	// Running reports whether an arrow was started and not stopped.
+	Running(arrow *domain.Arrow) bool
+
+	// Probe runs an arrow's validate method and returns its output.
+	Probe(ctx context.Context, arrow *domain.Arrow) (string, error)
+
+	// PrepareData creates the data directory of an arrow so its
+	// methods can use ${DATA_DIR}.
+	PrepareData(ctx context.Context, arrow *domain.Arrow) error
+
+	// LinkData points the data paths of an install to the data
+	// directory, moving files the install shipped there.
+	LinkData(ctx context.Context, arrow *domain.Arrow) error
+
+	// PurgeData deletes the data directory of an arrow.
+	PurgeData(ctx context.Context, arrow *domain.Arrow) error
+
+	// PlanTemplates reports what rendering the templates of an
+	// arrow would change, without writing anything.
+	PlanTemplates(ctx context.Context, arrow *domain.Arrow) ([]template.Change, error)
+
+	// RenderTemplates writes the templates of an arrow and records
+	// what was written in arrow.Rendered. Hand edited targets are
+	// kept unless force is set.
+	RenderTemplates(ctx context.Context, arrow *domain.Arrow, force bool) ([]template.Change, error)
+
+	// RecordVariableChange adds a variable change to the audit trail.
+	RecordVariableChange(ctx context.Context, change *domain.VariableChange) error
+
+	// VariableChanges returns the audit trail of an arrow, oldest first.
+	VariableChanges(ctx context.Context, arrowID uuid.UUID) ([]domain.VariableChange, error)
+
+	// CreateBackup archives the files of an arrow's install
+	// directory selected by its manifest.
+	CreateBackup(ctx context.Context, arrow *domain.Arrow, quiesced bool) (*domain.Backup, error)
+
+	// Backups lists the backups of an arrow, newest first.
+	Backups(ctx context.Context, arrow *domain.Arrow) ([]domain.Backup, error)
+
+	// RestoreBackup extracts a backup over an arrow's install directory.
+	RestoreBackup(ctx context.Context, arrow *domain.Arrow, id string) error
+
+	// DeleteBackup removes a backup.
+	DeleteBackup(ctx context.Context, arrow *domain.Arrow, id string) error
 }
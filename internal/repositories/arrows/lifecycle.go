@@ -0,0 +1,125 @@
+package arrows
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rabbytesoftware/quiver/internal/core/secrets"
+	domain "github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/runtime"
+	"github.com/rabbytesoftware/quiver/internal/models/system"
+)
+
+func (a *ArrowsRepository) Start(
+	ctx context.Context,
+	arrow *domain.Arrow,
+) error {
+	if a.infrastructure == nil || a.infrastructure.Runtime == nil {
+		return fmt.Errorf("runtime is not available")
+	}
+
+	if a.Running(arrow) {
+		return nil
+	}
+
+	method, ok := findMethod(arrow, system.CurrentOS(), runtime.ActionExecute)
+	if !ok {
+		return fmt.Errorf(
+			"arrow %s has no %s method for %s",
+			arrow.Namespace,
+			runtime.ActionExecute,
+			system.CurrentOS(),
+		)
+	}
+
+	env := environment(arrow)
+	processes := []string{}
+
+	for _, step := range method.Command {
+		id, err := a.infrastructure.Runtime.StartProcess(ctx, []string{interpolate(step, env)})
+		if err != nil {
+			a.stop(ctx, processes)
+			return fmt.Errorf("failed to start %s: %w", arrow.Namespace, secrets.RedactError(err))
+		}
+
+		processes = append(processes, id)
+	}
+
+	a.mu.Lock()
+	a.processes[arrow.ID] = processes
+	a.mu.Unlock()
+
+	return nil
+}
+
+func (a *ArrowsRepository) Stop(
+	ctx context.Context,
+	arrow *domain.Arrow,
+) error {
+	a.mu.Lock()
+	processes, ok := a.processes[arrow.ID]
+	delete(a.processes, arrow.ID)
+	a.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	if err := a.stop(ctx, processes); err != nil {
+		return fmt.Errorf("failed to stop %s: %w", arrow.Namespace, err)
+	}
+
+	return nil
+}
+
+func (a *ArrowsRepository) Running(arrow *domain.Arrow) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	_, ok := a.processes[arrow.ID]
+	return ok
+}
+
+func (a *ArrowsRepository) Probe(
+	ctx context.Context,
+	arrow *domain.Arrow,
+) (string, error) {
+	if a.infrastructure == nil || a.infrastructure.Runtime == nil {
+		return "", fmt.Errorf("runtime is not available")
+	}
+
+	method, ok := findMethod(arrow, system.CurrentOS(), runtime.ActionValidate)
+	if !ok {
+		return "", nil
+	}
+
+	env := environment(arrow)
+	var output strings.Builder
+
+	for _, step := range method.Command {
+		out, err := a.infrastructure.Runtime.ExecuteWithEnvironment(ctx, []string{step}, env)
+		if err != nil {
+			return output.String(), fmt.Errorf("failed to validate %s: %w", arrow.Namespace, secrets.RedactError(err))
+		}
+
+		output.WriteString(secrets.Redact(out))
+		if out != "" && !strings.HasSuffix(out, "\n") {
+			output.WriteString("\n")
+		}
+	}
+
+	return output.String(), nil
+}
+
+func (a *ArrowsRepository) stop(ctx context.Context, processes []string) error {
+	var firstErr error
+
+	for _, id := range processes {
+		if err := a.infrastructure.Runtime.StopProcess(ctx, id); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
@@ -0,0 +1,144 @@
+package arrows
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/rabbytesoftware/quiver/internal/infrastructure"
+	ree "github.com/rabbytesoftware/quiver/internal/infrastructure/runtime"
+	domain "github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/runtime"
+	"github.com/rabbytesoftware/quiver/internal/models/system"
+)
+
+type fakeRuntime struct {
+	ree.REEInterface
+
+	started []string
+	stopped []string
+	output  string
+	fail    error
+}
+
+func (f *fakeRuntime) StartProcess(ctx context.Context, command []string) (string, error) {
+	if f.fail != nil {
+		return "", f.fail
+	}
+
+	f.started = append(f.started, command[0])
+	return fmt.Sprintf("pid-%d", len(f.started)), nil
+}
+
+func (f *fakeRuntime) StopProcess(ctx context.Context, processID string) error {
+	f.stopped = append(f.stopped, processID)
+	return nil
+}
+
+func (f *fakeRuntime) ExecuteWithEnvironment(ctx context.Context, command []string, env map[string]string) (string, error) {
+	return f.output, f.fail
+}
+
+func lifecycleArrow() *domain.Arrow {
+	a := testArrow()
+	a.ID = uuid.New()
+	a.Methods = append(a.Methods,
+		runtime.Method{
+			OS:      system.CurrentOS(),
+			Action:  runtime.ActionExecute,
+			Command: []string{"./server --hostname ${SERVER_HOSTNAME}"},
+		},
+		runtime.Method{
+			OS:      system.CurrentOS(),
+			Action:  runtime.ActionValidate,
+			Command: []string{"./status"},
+		},
+	)
+	return a
+}
+
+func newLifecycleRepository(fake *fakeRuntime) ArrowsInterface {
+	infra := infrastructure.NewInfrastructure()
+	infra.Runtime = fake
+	return NewArrowsRepository(infra)
+}
+
+func TestArrowsRepository_StartStop(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeRuntime{}
+	repo := newLifecycleRepository(fake)
+	a := lifecycleArrow()
+
+	if err := repo.Start(ctx, a); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if !repo.Running(a) {
+		t.Error("Expected the arrow to be running")
+	}
+	if len(fake.started) != 1 || fake.started[0] != "./server --hostname quiver" {
+		t.Errorf("Expected the interpolated execute step to start, got %v", fake.started)
+	}
+
+	if err := repo.Start(ctx, a); err != nil || len(fake.started) != 1 {
+		t.Errorf("Expected starting a running arrow to do nothing, got %v (%v)", fake.started, err)
+	}
+
+	if err := repo.Stop(ctx, a); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if repo.Running(a) {
+		t.Error("Expected the arrow to be stopped")
+	}
+	if len(fake.stopped) != 1 || fake.stopped[0] != "pid-1" {
+		t.Errorf("Expected pid-1 to be stopped, got %v", fake.stopped)
+	}
+
+	if err := repo.Stop(ctx, a); err != nil || len(fake.stopped) != 1 {
+		t.Errorf("Expected stopping a stopped arrow to do nothing, got %v (%v)", fake.stopped, err)
+	}
+}
+
+func TestArrowsRepository_Start_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	if err := NewArrowsRepository(nil).Start(ctx, lifecycleArrow()); err == nil {
+		t.Error("Expected an error without runtime")
+	}
+
+	repo := newLifecycleRepository(&fakeRuntime{})
+	if err := repo.Start(ctx, testArrow()); err == nil {
+		t.Error("Expected an error for an arrow without execute method")
+	}
+
+	failing := newLifecycleRepository(&fakeRuntime{fail: errors.New("boom")})
+	a := lifecycleArrow()
+	if err := failing.Start(ctx, a); err == nil || failing.Running(a) {
+		t.Errorf("Expected a failed start to leave the arrow stopped, got %v", err)
+	}
+}
+
+func TestArrowsRepository_Probe(t *testing.T) {
+	ctx := context.Background()
+	repo := newLifecycleRepository(&fakeRuntime{output: "players: 2"})
+
+	output, err := repo.Probe(ctx, lifecycleArrow())
+	if err != nil {
+		t.Fatalf("Probe() error = %v", err)
+	}
+	if output != "players: 2\n" {
+		t.Errorf("Expected the validate output, got %q", output)
+	}
+
+	output, err = repo.Probe(ctx, testArrow())
+	if err != nil || output != "" {
+		t.Errorf("Expected no output without validate method, got %q (%v)", output, err)
+	}
+
+	failing := newLifecycleRepository(&fakeRuntime{fail: errors.New("boom")})
+	if _, err := failing.Probe(ctx, lifecycleArrow()); err == nil {
+		t.Error("Expected the validate error to be returned")
+	}
+}
@@ -0,0 +1,358 @@
+package arrows
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/rabbytesoftware/quiver/internal/core/database"
+	interfaces "github.com/rabbytesoftware/quiver/internal/core/database/interface"
+	domain "github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+)
+
+const portsDatabase = "port_assignments"
+
+func (a *ArrowsRepository) AssignPorts(
+	ctx context.Context,
+	arrow *domain.Arrow,
+) error {
+	if len(arrow.Netbridge) == 0 {
+		return nil
+	}
+
+	if a.infrastructure == nil || a.infrastructure.Netbridge == nil {
+		return fmt.Errorf("netbridge module is not available")
+	}
+
+	// ? Two installs must not be handed the same port.
+	a.assigning.Lock()
+	defer a.assigning.Unlock()
+
+	assignments, err := a.portStore(ctx)
+	if err != nil {
+		return err
+	}
+
+	allocated, own, err := a.allocatePorts(ctx, assignments, arrow, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range allocated {
+		if assignment, ok := own[rule.Name]; ok {
+			delete(own, rule.Name)
+			if assignment.Range() == rule.Range() {
+				continue
+			}
+
+			assignment.Set(rule.Range())
+			if _, err := assignments.Update(ctx, assignment); err != nil {
+				return fmt.Errorf("failed to save the ports of %s: %w", rule.Name, err)
+			}
+			continue
+		}
+
+		if _, err := assignments.Create(ctx, domain.NewPortAssignment(arrow, rule)); err != nil {
+			return fmt.Errorf("failed to save the ports of %s: %w", rule.Name, err)
+		}
+	}
+
+	// ? Entries the manifest no longer declares give their port back.
+	for _, stale := range own {
+		if err := assignments.Delete(ctx, stale.ID); err != nil {
+			return err
+		}
+	}
+
+	arrow.Netbridge = allocated
+	return nil
+}
+
+func (a *ArrowsRepository) PreviewPorts(
+	ctx context.Context,
+	arrow *domain.Arrow,
+	reserved []port.Range,
+) ([]port.PortRule, error) {
+	if len(arrow.Netbridge) == 0 {
+		return []port.PortRule{}, nil
+	}
+
+	if a.infrastructure == nil || a.infrastructure.Netbridge == nil {
+		return nil, fmt.Errorf("netbridge module is not available")
+	}
+
+	a.assigning.Lock()
+	defer a.assigning.Unlock()
+
+	assignments, err := a.portStore(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	allocated, _, err := a.allocatePorts(ctx, assignments, arrow, reserved)
+	return allocated, err
+}
+
+// allocatePorts runs the allocation AssignPorts records, without
+// recording it. It returns the assignments the arrow already holds,
+// keyed by name, so the caller can update or drop them.
+func (a *ArrowsRepository) allocatePorts(
+	ctx context.Context,
+	assignments interfaces.RepositoryInterface[domain.PortAssignment],
+	arrow *domain.Arrow,
+	reserved []port.Range,
+) ([]port.PortRule, map[string]*domain.PortAssignment, error) {
+	all, err := assignments.Get(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	own := map[string]*domain.PortAssignment{}
+	taken := append([]port.Range{}, reserved...)
+	held := []port.Range{}
+	for _, assignment := range all {
+		if assignment.ArrowID == arrow.ID {
+			own[assignment.Name] = assignment
+			held = append(held, assignment.Range())
+			continue
+		}
+		taken = append(taken, assignment.Range())
+	}
+
+	// ? Ports carried over from a lockfile or an earlier install are
+	// ? checked again by AllocatePorts, which moves them when they
+	// ? are no longer allowed, free or bindable.
+	rules := append([]port.PortRule{}, arrow.Netbridge...)
+	for i := range rules {
+		if assignment, ok := own[rules[i].Name]; ok {
+			assignment.Apply(&rules[i])
+		}
+	}
+
+	allocated, err := a.infrastructure.Netbridge.AllocatePorts(ctx, rules, taken, held)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return allocated, own, nil
+}
+
+func (a *ArrowsRepository) ReleasePorts(
+	ctx context.Context,
+	arrowID uuid.UUID,
+) error {
+	a.assigning.Lock()
+	defer a.assigning.Unlock()
+
+	assignments, err := a.portStore(ctx)
+	if err != nil {
+		return err
+	}
+
+	return releasePorts(ctx, assignments, arrowID)
+}
+
+func (a *ArrowsRepository) RestorePorts(
+	ctx context.Context,
+	arrow *domain.Arrow,
+) error {
+	a.assigning.Lock()
+	defer a.assigning.Unlock()
+
+	assignments, err := a.portStore(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := releasePorts(ctx, assignments, arrow.ID); err != nil {
+		return err
+	}
+
+	for _, rule := range arrow.Netbridge {
+		if rule.StartPort <= 0 {
+			continue
+		}
+
+		if _, err := assignments.Create(ctx, domain.NewPortAssignment(arrow, rule)); err != nil {
+			return fmt.Errorf("failed to save the ports of %s: %w", rule.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (a *ArrowsRepository) OpenPorts(
+	ctx context.Context,
+	arrow *domain.Arrow,
+) error {
+	if len(arrow.Netbridge) == 0 {
+		return nil
+	}
+
+	if a.infrastructure == nil || a.infrastructure.Netbridge == nil {
+		return fmt.Errorf("netbridge module is not available")
+	}
+
+	bridge := a.infrastructure.Netbridge
+	if !bridge.IsEnabled() {
+		return nil
+	}
+
+	var errs []error
+	for i := range arrow.Netbridge {
+		rule := &arrow.Netbridge[i]
+		if len(rule.Ports()) == 0 {
+			continue
+		}
+
+		forwarded, err := bridge.ForwardPort(ctx, *rule)
+		*rule = forwarded
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", rule.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (a *ArrowsRepository) ClosePorts(
+	ctx context.Context,
+	arrow *domain.Arrow,
+) error {
+	if len(arrow.Netbridge) == 0 || a.infrastructure == nil || a.infrastructure.Netbridge == nil {
+		return nil
+	}
+
+	bridge := a.infrastructure.Netbridge
+
+	var errs []error
+	for i := range arrow.Netbridge {
+		rule := &arrow.Netbridge[i]
+
+		reversed, err := bridge.ReversePort(ctx, *rule)
+		*rule = reversed
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", rule.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (a *ArrowsRepository) RefreshPorts(
+	ctx context.Context,
+	arrow *domain.Arrow,
+) bool {
+	if a.infrastructure == nil || a.infrastructure.Netbridge == nil {
+		return false
+	}
+
+	statuses, err := a.infrastructure.Netbridge.GetPortForwardingStatuses(ctx, arrow.Netbridge)
+	if err != nil || len(statuses) != len(arrow.Netbridge) {
+		return false
+	}
+
+	changed := false
+	for i, status := range statuses {
+		rule := &arrow.Netbridge[i]
+
+		if status.ForwardingStatus != rule.ForwardingStatus || !slices.Equal(status.Statuses, rule.Statuses) {
+			rule.ForwardingStatus = status.ForwardingStatus
+			rule.Statuses = status.Statuses
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+func (a *ArrowsRepository) CheckPorts(
+	ctx context.Context,
+	arrow *domain.Arrow,
+) bool {
+	if a.infrastructure == nil || a.infrastructure.Netbridge == nil {
+		return false
+	}
+
+	changed := false
+	for i := range arrow.Netbridge {
+		rule := &arrow.Netbridge[i]
+
+		// ? Ports that are not forwarded have nothing to test.
+		checked := port.PortRule{}
+		if rule.ForwardingStatus.IsEnabled() {
+			checked, _ = a.infrastructure.Netbridge.CheckReachability(ctx, *rule)
+		}
+
+		if checked.Reachability != rule.Reachability || checked.Diagnosis != rule.Diagnosis {
+			rule.Reachability, rule.Diagnosis = checked.Reachability, checked.Diagnosis
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+func (a *ArrowsRepository) ReconcilePorts(ctx context.Context) error {
+	if a.infrastructure == nil || a.infrastructure.Netbridge == nil {
+		return nil
+	}
+
+	return a.infrastructure.Netbridge.Reconcile(ctx)
+}
+
+func (a *ArrowsRepository) CloseAllPorts(ctx context.Context) error {
+	if a.infrastructure == nil || a.infrastructure.Netbridge == nil {
+		return nil
+	}
+
+	return a.infrastructure.Netbridge.Close(ctx)
+}
+
+// releasePorts deletes every assignment of an arrow.
+func releasePorts(
+	ctx context.Context,
+	assignments interfaces.RepositoryInterface[domain.PortAssignment],
+	arrowID uuid.UUID,
+) error {
+	all, err := assignments.Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, assignment := range all {
+		if assignment.ArrowID != arrowID {
+			continue
+		}
+
+		if err := assignments.Delete(ctx, assignment.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// portStore opens the port assignments on first use, like revisionStore.
+func (a *ArrowsRepository) portStore(
+	ctx context.Context,
+) (interfaces.RepositoryInterface[domain.PortAssignment], error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.ports != nil {
+		return a.ports, nil
+	}
+
+	ports, err := database.NewDatabase[domain.PortAssignment](ctx, portsDatabase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open port assignments: %w", err)
+	}
+	a.ports = ports
+
+	return ports, nil
+}
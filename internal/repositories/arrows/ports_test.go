@@ -0,0 +1,336 @@
+package arrows
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/rabbytesoftware/quiver/internal/infrastructure"
+	"github.com/rabbytesoftware/quiver/internal/infrastructure/netbridge"
+	domain "github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+)
+
+// freeBase returns a port whose next few ports are likely free, by
+// binding an ephemeral one and giving it back.
+func freeBase(t *testing.T) int {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func portsRepository(t *testing.T, allowed string) *ArrowsRepository {
+	t.Helper()
+	t.Setenv("QUIVER_DATABASE_PATH", t.TempDir())
+
+	bridge := netbridge.NewNetbridgeWith(false)
+	bridge.AllowPorts(allowed)
+
+	return &ArrowsRepository{
+		infrastructure: &infrastructure.Infrastructure{Netbridge: bridge},
+	}
+}
+
+func portArrow() *domain.Arrow {
+	return &domain.Arrow{
+		ID: uuid.New(),
+		Netbridge: []port.PortRule{
+			{Name: "GAME_PORT", Protocol: port.ProtocolUDP},
+			{Name: "CHAT_PORT", Protocol: port.ProtocolTCP},
+		},
+	}
+}
+
+func TestAssignPorts(t *testing.T) {
+	base := freeBase(t)
+	repo := portsRepository(t, fmt.Sprintf("%d-%d", base, base+3))
+	ctx := context.Background()
+
+	cs2 := portArrow()
+	if err := repo.AssignPorts(ctx, cs2); err != nil {
+		t.Fatalf("AssignPorts() returned error: %v", err)
+	}
+	if cs2.Netbridge[0].StartPort != base || cs2.Netbridge[1].StartPort != base+1 {
+		t.Fatalf("Expected the first allowed ports, got %+v", cs2.Netbridge)
+	}
+
+	// ? A restarted daemon reads the manifest again, without ports.
+	reloaded := portArrow()
+	reloaded.ID = cs2.ID
+	repo.ports = nil
+	if err := repo.AssignPorts(ctx, reloaded); err != nil {
+		t.Fatalf("AssignPorts() returned error: %v", err)
+	}
+	if reloaded.Netbridge[0].StartPort != base || reloaded.Netbridge[1].StartPort != base+1 {
+		t.Errorf("Expected the recorded ports to be kept, got %+v", reloaded.Netbridge)
+	}
+
+	other := portArrow()
+	other.Netbridge[0].StartPort = base
+	if err := repo.AssignPorts(ctx, other); err != nil {
+		t.Fatalf("AssignPorts() returned error: %v", err)
+	}
+	if other.Netbridge[0].StartPort != base+2 || other.Netbridge[1].StartPort != base+3 {
+		t.Errorf("Expected ports held by another instance to be skipped, got %+v", other.Netbridge)
+	}
+
+	if err := repo.AssignPorts(ctx, portArrow()); err == nil {
+		t.Error("Expected an error once the allowed ports run out")
+	}
+
+	if err := repo.ReleasePorts(ctx, cs2.ID); err != nil {
+		t.Fatalf("ReleasePorts() returned error: %v", err)
+	}
+	released := portArrow()
+	if err := repo.AssignPorts(ctx, released); err != nil {
+		t.Fatalf("AssignPorts() returned error: %v", err)
+	}
+	if released.Netbridge[0].StartPort != base || released.Netbridge[1].StartPort != base+1 {
+		t.Errorf("Expected released ports to be handed out again, got %+v", released.Netbridge)
+	}
+}
+
+func TestAssignPorts_RemovedEntry(t *testing.T) {
+	base := freeBase(t)
+	repo := portsRepository(t, fmt.Sprintf("%d-%d", base, base+1))
+	ctx := context.Background()
+
+	cs2 := portArrow()
+	if err := repo.AssignPorts(ctx, cs2); err != nil {
+		t.Fatalf("AssignPorts() returned error: %v", err)
+	}
+
+	updated := &domain.Arrow{ID: cs2.ID, Netbridge: []port.PortRule{{Name: "GAME_PORT"}}}
+	if err := repo.AssignPorts(ctx, updated); err != nil {
+		t.Fatalf("AssignPorts() returned error: %v", err)
+	}
+
+	other := &domain.Arrow{ID: uuid.New(), Netbridge: []port.PortRule{{Name: "GAME_PORT"}}}
+	if err := repo.AssignPorts(ctx, other); err != nil {
+		t.Fatalf("Expected the port of the dropped entry to be free again, got %v", err)
+	}
+	if other.Netbridge[0].StartPort != base+1 {
+		t.Errorf("Expected the dropped CHAT_PORT port, got %+v", other.Netbridge)
+	}
+}
+
+func TestAssignPorts_Ranges(t *testing.T) {
+	base := freeBase(t)
+	repo := portsRepository(t, fmt.Sprintf("%d-%d", base, base+5))
+	ctx := context.Background()
+
+	rtp := func(start, end int) *domain.Arrow {
+		return &domain.Arrow{ID: uuid.New(), Netbridge: []port.PortRule{
+			{Name: "RTP_PORTS", StartPort: start, EndPort: end, Protocol: port.ProtocolUDP},
+		}}
+	}
+
+	cs2 := rtp(base, base+2)
+	if err := repo.AssignPorts(ctx, cs2); err != nil {
+		t.Fatalf("AssignPorts() returned error: %v", err)
+	}
+	if got := cs2.Netbridge[0].Range(); got != (port.Range{Start: base, End: base + 2}) {
+		t.Fatalf("Expected the range from the lockfile to be kept, got %v", got)
+	}
+
+	// ? A restarted daemon reads the manifest again, without ports.
+	reloaded := &domain.Arrow{ID: cs2.ID, Netbridge: []port.PortRule{{Name: "RTP_PORTS", Protocol: port.ProtocolUDP}}}
+	repo.ports = nil
+	if err := repo.AssignPorts(ctx, reloaded); err != nil {
+		t.Fatalf("AssignPorts() returned error: %v", err)
+	}
+	if got := reloaded.Netbridge[0].Range(); got != (port.Range{Start: base, End: base + 2}) {
+		t.Errorf("Expected the whole recorded range to be kept, got %v", got)
+	}
+
+	overlapping := rtp(base+2, base+4)
+	if err := repo.AssignPorts(ctx, overlapping); err != nil {
+		t.Fatalf("AssignPorts() returned error: %v", err)
+	}
+	if got := overlapping.Netbridge[0].Range(); got != (port.Range{Start: base + 3, End: base + 5}) {
+		t.Errorf("Expected a range overlapping another instance to move, got %v", got)
+	}
+
+	if err := repo.AssignPorts(ctx, rtp(base, base)); err == nil {
+		t.Error("Expected an error once the allowed ports run out")
+	}
+}
+
+func TestAssignPorts_NotAllowed(t *testing.T) {
+	base := freeBase(t)
+	repo := portsRepository(t, fmt.Sprintf("%d-%d", base, base+1))
+	ctx := context.Background()
+
+	cs2 := &domain.Arrow{ID: uuid.New(), Netbridge: []port.PortRule{
+		{Name: "GAME_PORT", StartPort: base + 10, EndPort: base + 10, Protocol: port.ProtocolTCP},
+	}}
+	if err := repo.AssignPorts(ctx, cs2); err != nil {
+		t.Fatalf("AssignPorts() returned error: %v", err)
+	}
+	if cs2.Netbridge[0].StartPort != base {
+		t.Errorf("Expected a port outside netbridge.allowed_ports to be replaced, got %+v", cs2.Netbridge[0])
+	}
+}
+
+func TestRestorePorts(t *testing.T) {
+	base := freeBase(t)
+	repo := portsRepository(t, fmt.Sprintf("%d-%d", base, base+3))
+	ctx := context.Background()
+
+	cs2 := portArrow()
+	if err := repo.AssignPorts(ctx, cs2); err != nil {
+		t.Fatalf("AssignPorts() returned error: %v", err)
+	}
+	previous := &domain.Arrow{ID: cs2.ID, Netbridge: append([]port.PortRule{}, cs2.Netbridge...)}
+
+	// ? The update drops CHAT_PORT, which gives its port back.
+	updated := &domain.Arrow{ID: cs2.ID, Netbridge: []port.PortRule{
+		{Name: "GAME_PORT", Protocol: port.ProtocolUDP},
+	}}
+	if err := repo.AssignPorts(ctx, updated); err != nil {
+		t.Fatalf("AssignPorts() returned error: %v", err)
+	}
+
+	if err := repo.RestorePorts(ctx, previous); err != nil {
+		t.Fatalf("RestorePorts() returned error: %v", err)
+	}
+
+	reloaded := portArrow()
+	reloaded.ID = cs2.ID
+	if err := repo.AssignPorts(ctx, reloaded); err != nil {
+		t.Fatalf("AssignPorts() returned error: %v", err)
+	}
+	if reloaded.Netbridge[0].StartPort != base || reloaded.Netbridge[1].StartPort != base+1 {
+		t.Errorf("Expected the previous ports back, got %+v", reloaded.Netbridge)
+	}
+}
+
+func TestPreviewPorts(t *testing.T) {
+	base := freeBase(t)
+	repo := portsRepository(t, fmt.Sprintf("%d-%d", base, base+4))
+	ctx := context.Background()
+
+	installed := portArrow()
+	if err := repo.AssignPorts(ctx, installed); err != nil {
+		t.Fatalf("AssignPorts() returned error: %v", err)
+	}
+
+	cs2 := portArrow()
+	ports, err := repo.PreviewPorts(ctx, cs2, []port.Range{{Start: base + 2, End: base + 2}})
+	if err != nil {
+		t.Fatalf("PreviewPorts() returned error: %v", err)
+	}
+	if ports[0].StartPort != base+3 {
+		t.Errorf("Expected assigned and reserved ports to be skipped, got %+v", ports)
+	}
+	if cs2.Netbridge[0].StartPort != 0 {
+		t.Errorf("Expected the arrow to be left alone, got %+v", cs2.Netbridge)
+	}
+
+	// ? Nothing was recorded, so the same ports are still free.
+	if err := repo.AssignPorts(ctx, cs2); err != nil {
+		t.Fatalf("AssignPorts() returned error: %v", err)
+	}
+	if cs2.Netbridge[0].StartPort != base+2 {
+		t.Errorf("Expected the previewed ports to stay free, got %+v", cs2.Netbridge)
+	}
+
+	if _, err := (&ArrowsRepository{}).PreviewPorts(ctx, cs2, nil); err == nil {
+		t.Error("Expected an error without the netbridge module")
+	}
+}
+
+func TestAssignPorts_NoNetbridge(t *testing.T) {
+	repo := &ArrowsRepository{}
+
+	if err := repo.AssignPorts(context.Background(), &domain.Arrow{}); err != nil {
+		t.Errorf("Arrows without netbridge entries need no ports, got %v", err)
+	}
+	if err := repo.AssignPorts(context.Background(), portArrow()); err == nil {
+		t.Error("Expected an error without the netbridge module")
+	}
+}
+
+func TestOpenPorts(t *testing.T) {
+	ctx := context.Background()
+	rules := func() *domain.Arrow {
+		return &domain.Arrow{Netbridge: []port.PortRule{
+			{Name: "GAME_PORT", StartPort: 40128, EndPort: 40128, ForwardingStatus: port.ForwardingStatusDisabled},
+		}}
+	}
+
+	disabled := &ArrowsRepository{
+		infrastructure: &infrastructure.Infrastructure{Netbridge: netbridge.NewNetbridgeWith(false)},
+	}
+	cs2 := rules()
+	if err := disabled.OpenPorts(ctx, cs2); err != nil {
+		t.Errorf("Expected nothing to forward while netbridge is disabled, got %v", err)
+	}
+	if !cs2.Netbridge[0].ForwardingStatus.IsDisabled() {
+		t.Errorf("Expected the port to stay disabled, got %s", cs2.Netbridge[0].ForwardingStatus)
+	}
+
+	// ? Enabled without any strategy, so no gateway is ever found.
+	unavailable := &ArrowsRepository{
+		infrastructure: &infrastructure.Infrastructure{Netbridge: netbridge.NewNetbridgeWith(true)},
+	}
+	cs2 = rules()
+	if err := unavailable.OpenPorts(ctx, cs2); err == nil {
+		t.Error("Expected OpenPorts() to report the port it could not forward")
+	}
+	if !cs2.Netbridge[0].ForwardingStatus.IsError() {
+		t.Errorf("Expected an error status, got %s", cs2.Netbridge[0].ForwardingStatus)
+	}
+	if unavailable.RefreshPorts(ctx, cs2) {
+		t.Error("RefreshPorts() should report no change right after OpenPorts()")
+	}
+
+	if err := unavailable.CloseAllPorts(ctx); err == nil {
+		t.Error("Expected CloseAllPorts() to report the mapping it could not remove")
+	}
+	cs2.Netbridge[0].ForwardingStatus = port.ForwardingStatusEnabled
+	if !unavailable.RefreshPorts(ctx, cs2) || !cs2.Netbridge[0].ForwardingStatus.IsDisabled() {
+		t.Errorf("Expected RefreshPorts() to pick up the closed port, got %s", cs2.Netbridge[0].ForwardingStatus)
+	}
+
+	if err := unavailable.ClosePorts(ctx, cs2); err != nil {
+		t.Errorf("Expected ports that are not forwarded to be skipped, got %v", err)
+	}
+}
+
+func TestCheckPorts(t *testing.T) {
+	ctx := context.Background()
+	repo := &ArrowsRepository{
+		infrastructure: &infrastructure.Infrastructure{Netbridge: netbridge.NewNetbridgeWith(false)},
+	}
+
+	cs2 := &domain.Arrow{Netbridge: []port.PortRule{
+		{Name: "GAME_PORT", StartPort: 40128, EndPort: 40128, ForwardingStatus: port.ForwardingStatusEnabled},
+		{
+			Name: "QUERY_PORT", StartPort: 40129, EndPort: 40129, ForwardingStatus: port.ForwardingStatusDisabled,
+			Reachability: port.ReachabilityUnreachable, Diagnosis: "port 40129/udp is not reachable from the internet",
+		},
+	}}
+
+	if !repo.CheckPorts(ctx, cs2) {
+		t.Fatal("Expected CheckPorts() to report a change")
+	}
+	if !cs2.Netbridge[0].Reachability.IsUnknown() {
+		t.Errorf("Expected a forwarded port that cannot be tested to be unknown, got %s", cs2.Netbridge[0].Reachability)
+	}
+	if cs2.Netbridge[1].Reachability != "" || cs2.Netbridge[1].Diagnosis != "" {
+		t.Errorf("Expected the diagnosis of a port no longer forwarded to be cleared, got %+v", cs2.Netbridge[1])
+	}
+	if repo.CheckPorts(ctx, cs2) {
+		t.Error("CheckPorts() should report no change on a second check")
+	}
+}
@@ -0,0 +1,97 @@
+package arrows
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/rabbytesoftware/quiver/internal/core/config"
+	domain "github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/requirement"
+	"github.com/rabbytesoftware/quiver/internal/models/runtime"
+	"github.com/rabbytesoftware/quiver/internal/models/system"
+	"github.com/rabbytesoftware/quiver/internal/models/variable"
+)
+
+// MaskedValue replaces sensitive variables wherever they are shown.
+const MaskedValue = variable.Masked
+
+var placeholder = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+func (a *ArrowsRepository) Steps(
+	arrow *domain.Arrow,
+	action runtime.Action,
+) ([]string, error) {
+	method, ok := findMethod(arrow, system.CurrentOS(), action)
+	if !ok {
+		return nil, fmt.Errorf(
+			"arrow %s has no %s method for %s",
+			arrow.Namespace,
+			action,
+			system.CurrentOS(),
+		)
+	}
+
+	env := environment(arrow)
+	for _, variable := range arrow.Variables {
+		if variable.Sensitive {
+			env[variable.Name] = MaskedValue
+		}
+	}
+
+	steps := make([]string, 0, len(method.Command))
+	for _, step := range method.Command {
+		steps = append(steps, interpolate(step, env))
+	}
+
+	return steps, nil
+}
+
+func (a *ArrowsRepository) ArtifactSize(
+	ctx context.Context,
+	url string,
+) (int64, error) {
+	if a.infrastructure == nil || a.infrastructure.FNS == nil {
+		return -1, fmt.Errorf("fetch and share is not available")
+	}
+
+	info, err := a.infrastructure.FNS.GetInfo(ctx, url)
+	if err != nil {
+		return -1, err
+	}
+
+	return info.Size, nil
+}
+
+func (a *ArrowsRepository) CheckRequirements(
+	ctx context.Context,
+	requirements *requirement.Requirement,
+	committed requirement.Tier,
+) *requirement.Report {
+	if a.infrastructure == nil || a.infrastructure.Requirements == nil {
+		return nil
+	}
+
+	return a.infrastructure.Requirements.Check(ctx, requirements, committed)
+}
+
+func (a *ArrowsRepository) AvailableDisk(ctx context.Context) (int, error) {
+	if a.infrastructure == nil || a.infrastructure.Requirements == nil {
+		return 0, fmt.Errorf("requirements module is not available")
+	}
+
+	return a.infrastructure.Requirements.AvailableDisk(ctx, config.GetArrows().InstallDir)
+}
+
+// interpolate replaces ${NAME} placeholders, leaving unknown ones
+// untouched so shell variables keep working.
+func interpolate(step string, env map[string]string) string {
+	return placeholder.ReplaceAllStringFunc(step, func(match string) string {
+		name := placeholder.FindStringSubmatch(match)[1]
+		if value, ok := env[name]; ok {
+			return value
+		}
+
+		return match
+	})
+}
@@ -0,0 +1,136 @@
+package arrows
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/rabbytesoftware/quiver/internal/core/config"
+	"github.com/rabbytesoftware/quiver/internal/infrastructure"
+	domain "github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/requirement"
+	"github.com/rabbytesoftware/quiver/internal/models/runtime"
+	"github.com/rabbytesoftware/quiver/internal/models/system"
+	"github.com/rabbytesoftware/quiver/internal/models/variable"
+)
+
+func previewArrow() *domain.Arrow {
+	return &domain.Arrow{
+		Name:      "cs2",
+		Namespace: domain.ArrowNamespace("cs2@1.0.0"),
+		Variables: []variable.Variable{
+			{Name: "MAX_PLAYERS", Default: "12"},
+			{Name: "RCON_PASSWORD", Default: "hunter2", Sensitive: true},
+		},
+		Methods: []runtime.Method{
+			{
+				OS:     system.CurrentOS(),
+				Action: runtime.ActionInstall,
+				Command: []string{
+					"GET: https://example.com/cs2.tar.gz",
+					"./cs2 -dir ${INSTALL_DIR} -maxplayers ${MAX_PLAYERS} +rcon_password ${RCON_PASSWORD} -home $HOME ${UNKNOWN}",
+				},
+			},
+		},
+	}
+}
+
+func TestArrowsRepository_Steps(t *testing.T) {
+	repo := NewArrowsRepository(infrastructure.NewInfrastructure())
+
+	steps, err := repo.Steps(previewArrow(), runtime.ActionInstall)
+	if err != nil {
+		t.Fatalf("Steps() returned error: %v", err)
+	}
+	if len(steps) != 2 {
+		t.Fatalf("Expected 2 steps, got %d", len(steps))
+	}
+
+	installDir := filepath.Join(config.GetArrows().InstallDir, "cs2")
+	expected := "./cs2 -dir " + installDir + " -maxplayers 12 +rcon_password " + MaskedValue + " -home $HOME ${UNKNOWN}"
+	if steps[1] != expected {
+		t.Errorf("Expected %q, got %q", expected, steps[1])
+	}
+
+	if _, err := repo.Steps(previewArrow(), runtime.ActionUninstall); err == nil {
+		t.Error("Steps() should fail when the arrow has no method for the action")
+	}
+}
+
+func TestArrowsRepository_ArtifactSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "1024")
+	}))
+	defer server.Close()
+
+	repo := NewArrowsRepository(infrastructure.NewInfrastructure())
+
+	size, err := repo.ArtifactSize(context.Background(), server.URL+"/cs2.tar.gz")
+	if err != nil {
+		t.Fatalf("ArtifactSize() returned error: %v", err)
+	}
+	if size != 1024 {
+		t.Errorf("Expected 1024 bytes, got %d", size)
+	}
+
+	if _, err := NewArrowsRepository(nil).ArtifactSize(context.Background(), server.URL); err == nil {
+		t.Error("ArtifactSize() should fail without infrastructure")
+	}
+}
+
+func TestArrowsRepository_CheckRequirements(t *testing.T) {
+	repo := NewArrowsRepository(infrastructure.NewInfrastructure())
+
+	report := repo.CheckRequirements(context.Background(), &requirement.Requirement{
+		CpuCores: 1,
+		Memory:   1,
+		Disk:     1,
+		OS:       system.OS("plan9/mips"),
+	}, requirement.Tier{})
+	if report.Passed {
+		t.Error("Expected a foreign OS to fail the report")
+	}
+
+	checks := report.Checks
+	if len(checks) != 4 {
+		t.Fatalf("Expected 4 checks, got %d", len(checks))
+	}
+
+	if checks[0].Name != "os" || checks[0].Passed {
+		t.Errorf("Expected the os check to fail, got %+v", checks[0])
+	}
+	if checks[1].Name != "cpu_cores" || !checks[1].Passed {
+		t.Errorf("Expected the cpu check to pass, got %+v", checks[1])
+	}
+
+	if NewArrowsRepository(nil).CheckRequirements(context.Background(), &requirement.Requirement{}, requirement.Tier{}) != nil {
+		t.Error("CheckRequirements() should return nothing without infrastructure")
+	}
+}
+
+func TestArrowsRepository_AvailableDisk(t *testing.T) {
+	repo := NewArrowsRepository(infrastructure.NewInfrastructure())
+
+	available, err := repo.AvailableDisk(context.Background())
+	if err != nil {
+		t.Fatalf("AvailableDisk() returned error: %v", err)
+	}
+	if available <= 0 {
+		t.Errorf("Expected free space, got %d MB", available)
+	}
+
+	if _, err := NewArrowsRepository(nil).AvailableDisk(context.Background()); err == nil {
+		t.Error("AvailableDisk() should fail without infrastructure")
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	env := map[string]string{"A": "1", "B_2": "two"}
+
+	result := interpolate("${A}-${B_2}-${C}-$A", env)
+	if result != "1-two-${C}-$A" {
+		t.Errorf("Unexpected interpolation result %q", result)
+	}
+}
@@ -0,0 +1,107 @@
+package arrows
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	"github.com/rabbytesoftware/quiver/internal/core/config"
+	"github.com/rabbytesoftware/quiver/internal/core/secrets"
+	domain "github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/runtime"
+	"github.com/rabbytesoftware/quiver/internal/models/system"
+)
+
+func (a *ArrowsRepository) Run(
+	ctx context.Context,
+	arrow *domain.Arrow,
+	action runtime.Action,
+) error {
+	if a.infrastructure == nil || a.infrastructure.Runtime == nil {
+		return fmt.Errorf("runtime is not available")
+	}
+
+	method, ok := findMethod(arrow, system.CurrentOS(), action)
+	if !ok {
+		return fmt.Errorf(
+			"arrow %s has no %s method for %s",
+			arrow.Namespace,
+			action,
+			system.CurrentOS(),
+		)
+	}
+
+	env := environment(arrow)
+	artifacts := []domain.Artifact{}
+
+	for _, step := range method.Command {
+		// ? GET steps are downloaded here rather than by the runtime,
+		// ? so the checksum is taken from the bytes that were installed.
+		if url, ok := runtime.ParseStep(interpolate(step, env)).URL(); ok {
+			artifact, err := a.fetch(ctx, url, InstallDir(arrow))
+			if err != nil {
+				return fmt.Errorf("failed to %s %s: %w", action, arrow.Namespace, err)
+			}
+
+			artifacts = append(artifacts, artifact)
+			continue
+		}
+
+		if _, err := a.infrastructure.Runtime.ExecuteWithEnvironment(
+			ctx,
+			[]string{step},
+			env,
+		); err != nil {
+			return fmt.Errorf("failed to %s %s: %w", action, arrow.Namespace, secrets.RedactError(err))
+		}
+	}
+
+	if action == runtime.ActionInstall || action == runtime.ActionUpdate {
+		arrow.Artifacts = artifacts
+	}
+
+	return nil
+}
+
+func findMethod(
+	arrow *domain.Arrow,
+	os system.OS,
+	action runtime.Action,
+) (runtime.Method, bool) {
+	for _, method := range arrow.Methods {
+		if method.OS == os && method.Action == action {
+			return method, true
+		}
+	}
+
+	return runtime.Method{}, false
+}
+
+// InstallDir returns the directory an arrow is installed into.
+func InstallDir(arrow *domain.Arrow) string {
+	return filepath.Join(config.GetArrows().InstallDir, arrow.Name)
+}
+
+func environment(arrow *domain.Arrow) map[string]string {
+	registerSecrets(arrow)
+
+	env := map[string]string{
+		"INSTALL_DIR": InstallDir(arrow),
+		"DATA_DIR":    DataDir(arrow),
+	}
+
+	for _, variable := range arrow.Variables {
+		env[variable.Name] = variable.Current()
+	}
+
+	// ? Assigned ports win over a variable of the same name, as
+	// ? only they are opened on the gateway.
+	for _, rule := range arrow.Netbridge {
+		if rule.StartPort > 0 {
+			env[rule.Name] = strconv.Itoa(rule.StartPort)
+		}
+	}
+
+	return env
+}
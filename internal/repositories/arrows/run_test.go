@@ -0,0 +1,128 @@
+package arrows
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rabbytesoftware/quiver/internal/core/config"
+	"github.com/rabbytesoftware/quiver/internal/infrastructure"
+	domain "github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+	"github.com/rabbytesoftware/quiver/internal/models/runtime"
+	"github.com/rabbytesoftware/quiver/internal/models/system"
+	"github.com/rabbytesoftware/quiver/internal/models/variable"
+)
+
+func testArrow() *domain.Arrow {
+	return &domain.Arrow{
+		Name:      "steamcmd",
+		Namespace: domain.ArrowNamespace("steamcmd@1.0.0"),
+		Variables: []variable.Variable{
+			{Name: "SERVER_HOSTNAME", Default: "quiver"},
+		},
+		Methods: []runtime.Method{
+			{
+				OS:      system.CurrentOS(),
+				Action:  runtime.ActionInstall,
+				Command: []string{"GET: https://example.com/steamcmd.tar.gz"},
+			},
+		},
+	}
+}
+
+func TestArrowsRepository_Run(t *testing.T) {
+	var downloads atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downloads.Add(1)
+		w.Write([]byte("quiver"))
+	}))
+	defer server.Close()
+
+	arrow := testArrow()
+	arrow.Name = "quiver-run-test"
+	arrow.Methods[0].Command = []string{"GET: " + server.URL + "/steamcmd.tar.gz", "UNCOMPRESS: steamcmd.tar.gz"}
+	t.Cleanup(func() {
+		os.RemoveAll(InstallDir(arrow))
+		os.Remove(filepath.Dir(InstallDir(arrow)))
+	})
+
+	repo := NewArrowsRepository(infrastructure.NewInfrastructure())
+
+	if err := repo.Run(context.Background(), arrow, runtime.ActionInstall); err != nil {
+		t.Fatalf("Run() returned error: %v", err)
+	}
+
+	expected := []domain.Artifact{{
+		URL:    server.URL + "/steamcmd.tar.gz",
+		SHA256: "c59435a1e9474332701fdc4ce135dbcc2d733502c43078e2d8a3565cc41ef48a",
+	}}
+	if !reflect.DeepEqual(arrow.Artifacts, expected) {
+		t.Errorf("Expected artifacts %+v, got %+v", expected, arrow.Artifacts)
+	}
+
+	if got := downloads.Load(); got != 1 {
+		t.Errorf("Expected a single download, got %d", got)
+	}
+
+	if got := readFile(t, filepath.Join(InstallDir(arrow), "steamcmd.tar.gz")); got != "quiver" {
+		t.Errorf("Expected the download in the install directory, got %q", got)
+	}
+}
+
+func TestArrowsRepository_Run_MissingMethod(t *testing.T) {
+	repo := NewArrowsRepository(infrastructure.NewInfrastructure())
+
+	if err := repo.Run(context.Background(), testArrow(), runtime.ActionUninstall); err == nil {
+		t.Error("Run() should fail when the arrow has no method for the action")
+	}
+}
+
+func TestArrowsRepository_Run_NilInfrastructure(t *testing.T) {
+	repo := NewArrowsRepository(nil)
+
+	if err := repo.Run(context.Background(), testArrow(), runtime.ActionInstall); err == nil {
+		t.Error("Run() should fail without infrastructure")
+	}
+}
+
+func TestEnvironment(t *testing.T) {
+	env := environment(testArrow())
+
+	if env["SERVER_HOSTNAME"] != "quiver" {
+		t.Errorf("Expected SERVER_HOSTNAME to default to quiver, got %q", env["SERVER_HOSTNAME"])
+	}
+
+	expected := filepath.Join(config.GetArrows().InstallDir, "steamcmd")
+	if env["INSTALL_DIR"] != expected {
+		t.Errorf("Expected INSTALL_DIR %q, got %q", expected, env["INSTALL_DIR"])
+	}
+
+	expected = filepath.Join(config.GetArrows().DataDir, "steamcmd")
+	if env["DATA_DIR"] != expected {
+		t.Errorf("Expected DATA_DIR %q, got %q", expected, env["DATA_DIR"])
+	}
+}
+
+func TestEnvironment_Ports(t *testing.T) {
+	arrow := testArrow()
+	arrow.Variables = append(arrow.Variables, variable.Variable{Name: "GAME_PORT", Default: "27015"})
+	arrow.Netbridge = []port.PortRule{
+		{Name: "GAME_PORT", StartPort: 40128, EndPort: 40128},
+		{Name: "CHAT_PORT"},
+	}
+
+	env := environment(arrow)
+
+	if env["GAME_PORT"] != "40128" {
+		t.Errorf("Expected GAME_PORT to be the assigned port over the variable, got %q", env["GAME_PORT"])
+	}
+	if _, ok := env["CHAT_PORT"]; ok {
+		t.Error("Expected unassigned ports to be left out")
+	}
+}
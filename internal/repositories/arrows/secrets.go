@@ -0,0 +1,124 @@
+package arrows
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rabbytesoftware/quiver/internal/core/secrets"
+	domain "github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/variable"
+)
+
+// secretCipher loads the key sensitive values are sealed with on
+// first use, so no key file is created until there is a secret.
+func (a *ArrowsRepository) secretCipher() (*secrets.Cipher, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.cipher != nil {
+		return a.cipher, nil
+	}
+
+	cipher, err := secrets.LoadCipher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load secret key: %w", err)
+	}
+	a.cipher = cipher
+
+	return cipher, nil
+}
+
+// hasSecrets reports whether any sensitive variable of arrow has a
+// value or a default, sealed or not.
+func hasSecrets(arrow *domain.Arrow) bool {
+	for _, v := range arrow.Variables {
+		if v.Sensitive && (v.Value != nil || v.Default != "") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// seal returns a copy of arrow with the values and defaults of its
+// sensitive variables encrypted for storage.
+func seal(cipher *secrets.Cipher, arrow domain.Arrow) (domain.Arrow, error) {
+	arrow.Variables = append([]variable.Variable(nil), arrow.Variables...)
+
+	for i, v := range arrow.Variables {
+		if !v.Sensitive {
+			continue
+		}
+
+		if v.Value != nil && !variable.IsSealed(*v.Value) {
+			sealed, err := sealValue(cipher, *v.Value)
+			if err != nil {
+				return arrow, fmt.Errorf("failed to seal %s: %w", v.Name, err)
+			}
+			arrow.Variables[i].Value = &sealed
+		}
+
+		if v.Default != "" && !variable.IsSealed(v.Default) {
+			sealed, err := sealValue(cipher, v.Default)
+			if err != nil {
+				return arrow, fmt.Errorf("failed to seal the default of %s: %w", v.Name, err)
+			}
+			arrow.Variables[i].Default = sealed
+		}
+	}
+
+	return arrow, nil
+}
+
+// unseal decrypts the values sealed by seal in place. Every value is
+// registered as a secret, so it is masked wherever it gets logged.
+func unseal(cipher *secrets.Cipher, arrow *domain.Arrow) error {
+	for i, v := range arrow.Variables {
+		if v.Value != nil && variable.IsSealed(*v.Value) {
+			value, err := unsealValue(cipher, *v.Value)
+			if err != nil {
+				return fmt.Errorf("failed to unseal %s of %s: %w", v.Name, arrow.Name, err)
+			}
+			arrow.Variables[i].Value = &value
+		}
+
+		if variable.IsSealed(v.Default) {
+			value, err := unsealValue(cipher, v.Default)
+			if err != nil {
+				return fmt.Errorf("failed to unseal the default of %s of %s: %w", v.Name, arrow.Name, err)
+			}
+			arrow.Variables[i].Default = value
+		}
+	}
+
+	return nil
+}
+
+func sealValue(cipher *secrets.Cipher, value string) (string, error) {
+	encrypted, err := cipher.Encrypt(value)
+	if err != nil {
+		return "", err
+	}
+
+	return variable.SealedPrefix + encrypted, nil
+}
+
+func unsealValue(cipher *secrets.Cipher, sealed string) (string, error) {
+	value, err := cipher.Decrypt(strings.TrimPrefix(sealed, variable.SealedPrefix))
+	if err != nil {
+		return "", err
+	}
+
+	secrets.Register(value)
+	return value, nil
+}
+
+// registerSecrets makes sure the sensitive values of arrow are masked
+// in logs and process output before they are handed to a process.
+func registerSecrets(arrow *domain.Arrow) {
+	for _, v := range arrow.Variables {
+		if v.Sensitive {
+			secrets.Register(v.Current())
+		}
+	}
+}
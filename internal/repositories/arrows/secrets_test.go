@@ -0,0 +1,173 @@
+package arrows
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rabbytesoftware/quiver/internal/core/secrets"
+	domain "github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/variable"
+)
+
+func secretArrow(password string) *domain.Arrow {
+	port := "27016"
+
+	return &domain.Arrow{
+		ID:   uuid.New(),
+		Name: "cs2",
+		Variables: []variable.Variable{
+			{Name: "PORT", Value: &port},
+			{Name: "RCON_PASSWORD", Sensitive: true, Value: &password},
+		},
+	}
+}
+
+func TestSealAndUnseal(t *testing.T) {
+	cipher, err := secrets.NewCipher(bytes.Repeat([]byte{3}, secrets.KeySize))
+	if err != nil {
+		t.Fatalf("NewCipher() error = %v", err)
+	}
+
+	arrow := secretArrow("seal-rcon")
+
+	sealed, err := seal(cipher, *arrow)
+	if err != nil {
+		t.Fatalf("seal() error = %v", err)
+	}
+
+	if !variable.IsSealed(*sealed.Variables[1].Value) || strings.Contains(*sealed.Variables[1].Value, "seal-rcon") {
+		t.Errorf("Expected the password to be sealed, got %q", *sealed.Variables[1].Value)
+	}
+	if *sealed.Variables[0].Value != "27016" {
+		t.Errorf("Expected other values to be kept, got %q", *sealed.Variables[0].Value)
+	}
+	if *arrow.Variables[1].Value != "seal-rcon" {
+		t.Error("Expected seal to leave the arrow untouched")
+	}
+
+	if err := unseal(cipher, &sealed); err != nil {
+		t.Fatalf("unseal() error = %v", err)
+	}
+	if *sealed.Variables[1].Value != "seal-rcon" {
+		t.Errorf("Expected the password back, got %q", *sealed.Variables[1].Value)
+	}
+	if secrets.Redact("+rcon_password seal-rcon") != "+rcon_password "+secrets.Mask {
+		t.Error("Expected unsealed values to be registered as secrets")
+	}
+}
+
+func TestSealAndUnseal_Default(t *testing.T) {
+	cipher, err := secrets.NewCipher(bytes.Repeat([]byte{3}, secrets.KeySize))
+	if err != nil {
+		t.Fatalf("NewCipher() error = %v", err)
+	}
+
+	arrow := &domain.Arrow{Name: "cs2", Variables: []variable.Variable{
+		{Name: "RCON_PASSWORD", Sensitive: true, Default: "default-rcon"},
+	}}
+	if !hasSecrets(arrow) {
+		t.Error("Expected a sensitive default to count as a secret")
+	}
+
+	sealed, err := seal(cipher, *arrow)
+	if err != nil {
+		t.Fatalf("seal() error = %v", err)
+	}
+	if !variable.IsSealed(sealed.Variables[0].Default) || sealed.Variables[0].Value != nil {
+		t.Errorf("Expected only the default to be sealed, got %+v", sealed.Variables[0])
+	}
+
+	if err := unseal(cipher, &sealed); err != nil {
+		t.Fatalf("unseal() error = %v", err)
+	}
+	if sealed.Variables[0].Current() != "default-rcon" {
+		t.Errorf("Expected the default back, got %q", sealed.Variables[0].Current())
+	}
+}
+
+func TestArrowsRepository_History_SealsSecrets(t *testing.T) {
+	dbPath := t.TempDir()
+	t.Setenv("QUIVER_DATABASE_PATH", dbPath)
+	t.Setenv(secrets.KeyEnv, base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{5}, secrets.KeySize)))
+
+	ctx := context.Background()
+	repo := &ArrowsRepository{}
+	arrow := secretArrow("history-rcon")
+
+	if err := repo.SaveRevision(ctx, domain.NewRevision(arrow, domain.RevisionInstall, time.Now())); err != nil {
+		t.Fatalf("SaveRevision() error = %v", err)
+	}
+
+	stored, err := os.ReadFile(filepath.Join(dbPath, revisionsDatabase+".db"))
+	if err != nil {
+		t.Fatalf("Failed to read the database: %v", err)
+	}
+	if bytes.Contains(stored, []byte("history-rcon")) {
+		t.Error("Expected the password to be encrypted at rest")
+	}
+
+	history, err := repo.History(ctx, arrow.ID)
+	if err != nil || len(history) != 1 {
+		t.Fatalf("History() = %+v, %v", history, err)
+	}
+	if history[0].Arrow.Variables[1].Current() != "history-rcon" {
+		t.Errorf("Expected the password to be decrypted, got %q", history[0].Arrow.Variables[1].Current())
+	}
+}
+
+func TestArrowsRepository_CRUD_SealsSecrets(t *testing.T) {
+	dbPath := t.TempDir()
+	t.Setenv("QUIVER_DATABASE_PATH", dbPath)
+	t.Setenv(secrets.KeyEnv, base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{6}, secrets.KeySize)))
+
+	ctx := context.Background()
+	repo := &ArrowsRepository{}
+	arrow := secretArrow("installed-rcon")
+	arrow.Variables = append(arrow.Variables, variable.Variable{Name: "STEAM_TOKEN", Sensitive: true, Default: "default-token"})
+
+	if _, err := repo.Create(ctx, arrow); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if arrow.Variables[1].Current() != "installed-rcon" {
+		t.Errorf("Expected Create() to leave the caller's value alone, got %q", arrow.Variables[1].Current())
+	}
+
+	stored, err := os.ReadFile(filepath.Join(dbPath, arrowsDatabase+".db"))
+	if err != nil {
+		t.Fatalf("Failed to read the database: %v", err)
+	}
+	if bytes.Contains(stored, []byte("installed-rcon")) || bytes.Contains(stored, []byte("default-token")) {
+		t.Error("Expected the password and the default token to be encrypted at rest")
+	}
+
+	found, err := repo.GetById(ctx, arrow.ID)
+	if err != nil {
+		t.Fatalf("GetById() error = %v", err)
+	}
+	if found.Variables[1].Current() != "installed-rcon" {
+		t.Errorf("Expected the password to survive a round trip, got %q", found.Variables[1].Current())
+	}
+
+	if _, err := repo.Update(ctx, found); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	all, err := repo.Get(ctx)
+	if err != nil || len(all) != 1 {
+		t.Fatalf("Get() = %+v, %v", all, err)
+	}
+	if all[0].Variables[1].Current() != "installed-rcon" {
+		t.Errorf("Expected the password to survive an update, got %q", all[0].Variables[1].Current())
+	}
+	if all[0].Variables[2].Current() != "default-token" {
+		t.Errorf("Expected the default token to survive an update, got %q", all[0].Variables[2].Current())
+	}
+}
@@ -0,0 +1,109 @@
+package arrows
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	domain "github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/template"
+)
+
+func (a *ArrowsRepository) PlanTemplates(
+	ctx context.Context,
+	arrow *domain.Arrow,
+) ([]template.Change, error) {
+	changes, _, err := renderTemplates(InstallDir(arrow), environment(arrow), arrow, false, false)
+	return changes, err
+}
+
+func (a *ArrowsRepository) RenderTemplates(
+	ctx context.Context,
+	arrow *domain.Arrow,
+	force bool,
+) ([]template.Change, error) {
+	changes, rendered, err := renderTemplates(InstallDir(arrow), environment(arrow), arrow, true, force)
+	if err != nil {
+		return changes, fmt.Errorf("failed to render templates of %s: %w", arrow.Namespace, err)
+	}
+	arrow.Rendered = rendered
+
+	return changes, nil
+}
+
+// renderTemplates interpolates every template of an arrow and compares
+// the result with its target. A target that differs from what was last
+// rendered was edited by hand: it is reported as a conflict and only
+// overwritten when forced. Targets never rendered before are replaced.
+func renderTemplates(
+	installDir string,
+	env map[string]string,
+	arrow *domain.Arrow,
+	write bool,
+	force bool,
+) ([]template.Change, []template.Rendered, error) {
+	previous := map[string]string{}
+	for _, r := range arrow.Rendered {
+		previous[r.Target] = r.SHA256
+	}
+
+	changes := []template.Change{}
+	rendered := []template.Rendered{}
+
+	for _, t := range arrow.Templates {
+		if err := t.Validate(); err != nil {
+			return changes, nil, err
+		}
+
+		target := filepath.Join(installDir, filepath.FromSlash(t.Target))
+		content := interpolate(t.Content, env)
+		record := template.Rendered{Target: t.Target, SHA256: template.Checksum([]byte(content))}
+
+		current, err := os.ReadFile(target)
+		exists := err == nil
+		if err != nil && !os.IsNotExist(err) {
+			return changes, nil, err
+		}
+
+		change := template.Change{Target: t.Target}
+		switch {
+		case !exists:
+			change.Status = template.ChangeCreated
+			change.Diff = template.Diff("", content, "/dev/null", t.Target)
+		case string(current) == content:
+			change.Status = template.ChangeUnchanged
+		case previous[t.Target] != "" && previous[t.Target] != template.Checksum(current):
+			change.Status = template.ChangeConflict
+			change.Diff = template.Diff(string(current), content, t.Target, t.Target+" (rendered)")
+		default:
+			change.Status = template.ChangeUpdated
+			change.Diff = template.Diff(string(current), content, t.Target, t.Target+" (rendered)")
+		}
+
+		overwrite := change.Status == template.ChangeCreated ||
+			change.Status == template.ChangeUpdated ||
+			(change.Status == template.ChangeConflict && force)
+
+		if write && overwrite {
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return changes, nil, err
+			}
+			if err := os.WriteFile(target, []byte(content), 0644); err != nil {
+				return changes, nil, err
+			}
+			change.Written = true
+		}
+
+		// ? A kept conflict keeps its old record, so it is still
+		// ? reported until it is resolved or forced.
+		if change.Status == template.ChangeConflict && !change.Written {
+			record.SHA256 = previous[t.Target]
+		}
+
+		changes = append(changes, change)
+		rendered = append(rendered, record)
+	}
+
+	return changes, rendered, nil
+}
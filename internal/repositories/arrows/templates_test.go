@@ -0,0 +1,88 @@
+package arrows
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	domain "github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/template"
+)
+
+func templateArrow() *domain.Arrow {
+	return &domain.Arrow{
+		Name: "cs2",
+		Templates: []template.Template{
+			{Target: "cfg/server.cfg", Content: "hostname ${SERVER_HOSTNAME}\nport 27015\n"},
+		},
+	}
+}
+
+func TestRenderTemplates_Lifecycle(t *testing.T) {
+	install := t.TempDir()
+	target := filepath.Join(install, "cfg", "server.cfg")
+	arrow := templateArrow()
+	env := map[string]string{"SERVER_HOSTNAME": "quiver"}
+
+	changes, rendered, err := renderTemplates(install, env, arrow, true, false)
+	if err != nil {
+		t.Fatalf("renderTemplates() error = %v", err)
+	}
+	if changes[0].Status != template.ChangeCreated || !changes[0].Written {
+		t.Errorf("Expected the target to be created, got %+v", changes[0])
+	}
+	if got := readFile(t, target); got != "hostname quiver\nport 27015\n" {
+		t.Errorf("Expected the variables to be rendered, got %q", got)
+	}
+	arrow.Rendered = rendered
+
+	changes, _, _ = renderTemplates(install, env, arrow, true, false)
+	if changes[0].Status != template.ChangeUnchanged || changes[0].Written {
+		t.Errorf("Expected rendering again to change nothing, got %+v", changes[0])
+	}
+
+	env["SERVER_HOSTNAME"] = "renamed"
+	changes, rendered, _ = renderTemplates(install, env, arrow, true, false)
+	if changes[0].Status != template.ChangeUpdated || !changes[0].Written {
+		t.Errorf("Expected a variable change to re-render, got %+v", changes[0])
+	}
+	arrow.Rendered = rendered
+
+	writeFile(t, target, "hostname renamed\nport 27016\n")
+	env["SERVER_HOSTNAME"] = "again"
+
+	changes, rendered, _ = renderTemplates(install, env, arrow, true, false)
+	if changes[0].Status != template.ChangeConflict || changes[0].Written {
+		t.Errorf("Expected a hand edited target to conflict, got %+v", changes[0])
+	}
+	if changes[0].Diff == "" {
+		t.Error("Expected a conflict to come with a diff")
+	}
+	if got := readFile(t, target); got != "hostname renamed\nport 27016\n" {
+		t.Errorf("Expected the hand edit to be kept, got %q", got)
+	}
+	arrow.Rendered = rendered
+
+	changes, _, _ = renderTemplates(install, env, arrow, true, true)
+	if changes[0].Status != template.ChangeConflict || !changes[0].Written {
+		t.Errorf("Expected a forced render to overwrite the conflict, got %+v", changes[0])
+	}
+	if got := readFile(t, target); got != "hostname again\nport 27015\n" {
+		t.Errorf("Expected the forced render to be written, got %q", got)
+	}
+}
+
+func TestRenderTemplates_PlanDoesNotWrite(t *testing.T) {
+	install := t.TempDir()
+
+	changes, _, err := renderTemplates(install, map[string]string{}, templateArrow(), false, false)
+	if err != nil {
+		t.Fatalf("renderTemplates() error = %v", err)
+	}
+	if changes[0].Status != template.ChangeCreated || changes[0].Written {
+		t.Errorf("Expected a planned creation, got %+v", changes[0])
+	}
+	if _, err := os.Stat(filepath.Join(install, "cfg", "server.cfg")); !os.IsNotExist(err) {
+		t.Error("Expected planning not to write the target")
+	}
+}
@@ -1,9 +1,18 @@
 package common
 
-type CRUD[t any] interface {
-	Get() []t
-	GetById(id string) *t
-	Create(t *t) *t
-	Update(t *t) *t
-	DeleteById(id string) error
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// CRUD is the storage side of a repository, keyed by UUID like the
+// database RepositoryInterface it is backed by. GetById fails when
+// there is no entity with the id.
+type CRUD[T any] interface {
+	Get(ctx context.Context) ([]T, error)
+	GetById(ctx context.Context, id uuid.UUID) (*T, error)
+	Create(ctx context.Context, entity *T) (*T, error)
+	Update(ctx context.Context, entity *T) (*T, error)
+	DeleteById(ctx context.Context, id uuid.UUID) error
 }
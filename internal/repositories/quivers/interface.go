@@ -1,10 +1,21 @@
 package quivers
 
 import (
+	"context"
+
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
 	domain "github.com/rabbytesoftware/quiver/internal/models/quiver"
 	"github.com/rabbytesoftware/quiver/internal/repositories/common"
 )
 
 type QuiversInterface interface {
 	common.CRUD[domain.Quiver]
+
+	// FindArrow returns every published version of the named arrow
+	// across all configured quivers.
+	FindArrow(ctx context.Context, name string) ([]*arrow.Arrow, error)
+
+	// FetchManifest translates a single arrow manifest, recording
+	// the hash of its current content.
+	FetchManifest(ctx context.Context, manifestURL string) (*arrow.Arrow, error)
 }
@@ -0,0 +1,121 @@
+package quivers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rabbytesoftware/quiver/internal/core/config"
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+	domain "github.com/rabbytesoftware/quiver/internal/models/quiver"
+	"github.com/rabbytesoftware/quiver/internal/models/system"
+)
+
+const quiverManifestName = "quiver.yaml"
+
+func (q *QuiversRepository) FindArrow(
+	ctx context.Context,
+	name string,
+) ([]*arrow.Arrow, error) {
+	if q.infrastructure == nil || q.infrastructure.Translator == nil {
+		return nil, fmt.Errorf("translator is not available")
+	}
+
+	var (
+		found []*arrow.Arrow
+		errs  []error
+	)
+
+	for _, repository := range q.repositories() {
+		quiver, err := q.infrastructure.Translator.
+			GetQuiverTranslator().
+			Translate(ctx, quiverManifestPath(repository))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		arrows, err := q.fetchListed(ctx, repository, quiver, name)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		found = append(found, arrows...)
+	}
+
+	// ? Unreachable quivers are tolerated as long as another
+	// ? quiver was able to provide the arrow.
+	if len(found) == 0 && len(errs) > 0 {
+		return nil, fmt.Errorf("failed to find arrow %s: %w", name, errors.Join(errs...))
+	}
+
+	return found, nil
+}
+
+func (q *QuiversRepository) FetchManifest(
+	ctx context.Context,
+	manifestURL string,
+) (*arrow.Arrow, error) {
+	if q.infrastructure == nil || q.infrastructure.Translator == nil {
+		return nil, fmt.Errorf("translator is not available")
+	}
+
+	return q.infrastructure.Translator.
+		GetArrowTranslator().
+		Translate(ctx, manifestURL)
+}
+
+func (q *QuiversRepository) fetchListed(
+	ctx context.Context,
+	repository string,
+	quiver *domain.Quiver,
+	name string,
+) ([]*arrow.Arrow, error) {
+	var (
+		found []*arrow.Arrow
+		errs  []error
+	)
+
+	for _, namespace := range quiver.ListedArrows {
+		if namespace.Name() != name {
+			continue
+		}
+
+		manifest, ok := quiver.Manifests[namespace]
+		if !ok || manifest == "" {
+			errs = append(errs, fmt.Errorf("quiver %s lists %s without a manifest", quiver.Name, namespace))
+			continue
+		}
+
+		translated, err := q.infrastructure.Translator.
+			GetArrowTranslator().
+			Translate(ctx, manifest.String())
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		translated.Source = system.URL(repository)
+		found = append(found, translated)
+	}
+
+	return found, errors.Join(errs...)
+}
+
+func (q *QuiversRepository) repositories() []string {
+	if q.sources != nil {
+		return q.sources
+	}
+
+	return config.GetArrows().Repositories
+}
+
+// quiverManifestPath accepts either a direct path to a quiver manifest
+// or the root of a quiver, in which case quiver.yaml is appended.
+func quiverManifestPath(repository string) string {
+	if strings.HasSuffix(repository, ".yaml") || strings.HasSuffix(repository, ".yml") {
+		return repository
+	}
+
+	return strings.TrimSuffix(repository, "/") + "/" + quiverManifestName
+}
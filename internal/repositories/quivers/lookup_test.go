@@ -0,0 +1,159 @@
+package quivers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rabbytesoftware/quiver/internal/infrastructure"
+	"github.com/rabbytesoftware/quiver/internal/models/system"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func arrowManifest(name, version string) string {
+	return `manifest: "arrow@v1"
+metadata:
+  name: ` + name + `
+  version: ` + version + `
+`
+}
+
+func setupQuiver(t *testing.T) string {
+	t.Helper()
+
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "quiver.yaml"), `manifest: "quiver@v1"
+metadata:
+  name: test.quiver
+arrows:
+  - name: steamcmd
+    version: 1.0.0
+    manifest_url: arrows/steamcmd-1.0.0.yaml
+  - name: steamcmd
+    version: 1.1.0
+    manifest_url: arrows/steamcmd-1.1.0.yaml
+  - name: cs2
+    manifest_url: arrows/cs2.yaml
+`)
+	writeFile(t, filepath.Join(root, "arrows", "steamcmd-1.0.0.yaml"), arrowManifest("steamcmd", "1.0.0"))
+	writeFile(t, filepath.Join(root, "arrows", "steamcmd-1.1.0.yaml"), arrowManifest("steamcmd", "1.1.0"))
+	writeFile(t, filepath.Join(root, "arrows", "cs2.yaml"), arrowManifest("cs2", "0.0.1"))
+
+	return root
+}
+
+func TestQuiversRepository_FindArrow(t *testing.T) {
+	repo := NewQuiversRepository(infrastructure.NewInfrastructure()).(*QuiversRepository)
+	repo.sources = []string{setupQuiver(t)}
+
+	arrows, err := repo.FindArrow(context.Background(), "steamcmd")
+	if err != nil {
+		t.Fatalf("FindArrow() returned error: %v", err)
+	}
+	if len(arrows) != 2 {
+		t.Fatalf("Expected 2 versions of steamcmd, got %d", len(arrows))
+	}
+	if arrows[0].Version != "1.0.0" || arrows[1].Version != "1.1.0" {
+		t.Errorf("Unexpected versions %q and %q", arrows[0].Version, arrows[1].Version)
+	}
+	if arrows[0].Source != system.URL(repo.sources[0]) {
+		t.Errorf("Expected source %q, got %q", repo.sources[0], arrows[0].Source)
+	}
+	if arrows[0].ManifestHash == "" || arrows[0].ManifestHash == arrows[1].ManifestHash {
+		t.Errorf("Expected distinct manifest hashes, got %q and %q", arrows[0].ManifestHash, arrows[1].ManifestHash)
+	}
+
+	arrows, err = repo.FindArrow(context.Background(), "cs2")
+	if err != nil {
+		t.Fatalf("FindArrow() returned error: %v", err)
+	}
+	if len(arrows) != 1 || arrows[0].Version != "0.0.1" {
+		t.Errorf("Expected cs2 0.0.1, got %v", arrows)
+	}
+}
+
+func TestQuiversRepository_FindArrow_Unknown(t *testing.T) {
+	repo := NewQuiversRepository(infrastructure.NewInfrastructure()).(*QuiversRepository)
+	repo.sources = []string{setupQuiver(t)}
+
+	arrows, err := repo.FindArrow(context.Background(), "unknown")
+	if err != nil {
+		t.Fatalf("FindArrow() returned error: %v", err)
+	}
+	if len(arrows) != 0 {
+		t.Errorf("Expected no arrows, got %d", len(arrows))
+	}
+}
+
+func TestQuiversRepository_FindArrow_UnreachableQuiver(t *testing.T) {
+	repo := NewQuiversRepository(infrastructure.NewInfrastructure()).(*QuiversRepository)
+	repo.sources = []string{filepath.Join(t.TempDir(), "missing"), setupQuiver(t)}
+
+	arrows, err := repo.FindArrow(context.Background(), "cs2")
+	if err != nil {
+		t.Fatalf("FindArrow() should tolerate unreachable quivers, got %v", err)
+	}
+	if len(arrows) != 1 {
+		t.Errorf("Expected 1 arrow, got %d", len(arrows))
+	}
+
+	repo.sources = []string{filepath.Join(t.TempDir(), "missing")}
+	if _, err := repo.FindArrow(context.Background(), "cs2"); err == nil {
+		t.Error("FindArrow() should fail when no quiver is reachable")
+	}
+}
+
+func TestQuiversRepository_FindArrow_NilInfrastructure(t *testing.T) {
+	repo := NewQuiversRepository(nil)
+
+	if _, err := repo.FindArrow(context.Background(), "cs2"); err == nil {
+		t.Error("FindArrow() should fail without infrastructure")
+	}
+}
+
+func TestQuiverManifestPath(t *testing.T) {
+	testCases := []struct {
+		repository string
+		expected   string
+	}{
+		{repository: "./pkgs", expected: "./pkgs/quiver.yaml"},
+		{repository: "./pkgs/", expected: "./pkgs/quiver.yaml"},
+		{repository: "https://quiver.ar/main", expected: "https://quiver.ar/main/quiver.yaml"},
+		{repository: "https://quiver.ar/custom.yaml", expected: "https://quiver.ar/custom.yaml"},
+	}
+
+	for _, tc := range testCases {
+		if got := quiverManifestPath(tc.repository); got != tc.expected {
+			t.Errorf("quiverManifestPath(%q) = %q, expected %q", tc.repository, got, tc.expected)
+		}
+	}
+}
+
+func TestQuiversRepository_FetchManifest(t *testing.T) {
+	repo := NewQuiversRepository(infrastructure.NewInfrastructure())
+	root := setupQuiver(t)
+	manifest := filepath.Join(root, "arrows", "cs2.yaml")
+
+	arrow, err := repo.FetchManifest(context.Background(), manifest)
+	if err != nil {
+		t.Fatalf("FetchManifest() returned error: %v", err)
+	}
+	if arrow.Name != "cs2" || arrow.ManifestURL != system.URL(manifest) || arrow.ManifestHash == "" {
+		t.Errorf("Unexpected manifest result %+v", arrow)
+	}
+
+	if _, err := NewQuiversRepository(nil).FetchManifest(context.Background(), manifest); err == nil {
+		t.Error("FetchManifest() should fail without infrastructure")
+	}
+}
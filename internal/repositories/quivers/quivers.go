@@ -1,12 +1,28 @@
 package quivers
 
 import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/rabbytesoftware/quiver/internal/core/database"
+	interfaces "github.com/rabbytesoftware/quiver/internal/core/database/interface"
 	"github.com/rabbytesoftware/quiver/internal/infrastructure"
 	domain "github.com/rabbytesoftware/quiver/internal/models/quiver"
 )
 
+const quiversDatabase = "quivers"
+
 type QuiversRepository struct {
 	infrastructure *infrastructure.Infrastructure
+
+	mu      sync.Mutex
+	quivers interfaces.RepositoryInterface[domain.Quiver]
+
+	// sources overrides the configured quiver repositories when set
+	sources []string
 }
 
 func NewQuiversRepository(
@@ -17,22 +33,84 @@ func NewQuiversRepository(
 	}
 }
 
-func (q *QuiversRepository) Get() []domain.Quiver {
-	return []domain.Quiver{}
+// Get returns every stored quiver.
+func (q *QuiversRepository) Get(ctx context.Context) ([]domain.Quiver, error) {
+	quivers, err := q.quiverStore(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := quivers.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	all := make([]domain.Quiver, 0, len(stored))
+	for _, quiver := range stored {
+		all = append(all, *quiver)
+	}
+
+	return all, nil
+}
+
+func (q *QuiversRepository) GetById(ctx context.Context, id uuid.UUID) (*domain.Quiver, error) {
+	quivers, err := q.quiverStore(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return quivers.GetByID(ctx, id)
 }
 
-func (q *QuiversRepository) GetById(id string) *domain.Quiver {
-	return nil
+// Create stores a quiver, deriving its ID from its name if it has
+// none yet.
+func (q *QuiversRepository) Create(ctx context.Context, quiver *domain.Quiver) (*domain.Quiver, error) {
+	quivers, err := q.quiverStore(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if quiver.ID == uuid.Nil {
+		quiver.ID = domain.NewID(quiver.Name)
+	}
+
+	return quivers.Create(ctx, quiver)
 }
 
-func (q *QuiversRepository) Create(quiver *domain.Quiver) *domain.Quiver {
-	return nil
+func (q *QuiversRepository) Update(ctx context.Context, quiver *domain.Quiver) (*domain.Quiver, error) {
+	quivers, err := q.quiverStore(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return quivers.Update(ctx, quiver)
 }
 
-func (q *QuiversRepository) Update(quiver *domain.Quiver) *domain.Quiver {
-	return nil
+func (q *QuiversRepository) DeleteById(ctx context.Context, id uuid.UUID) error {
+	quivers, err := q.quiverStore(ctx)
+	if err != nil {
+		return err
+	}
+
+	return quivers.Delete(ctx, id)
 }
 
-func (q *QuiversRepository) DeleteById(id string) error {
-	return nil
+// quiverStore opens the stored quivers on first use.
+func (q *QuiversRepository) quiverStore(
+	ctx context.Context,
+) (interfaces.RepositoryInterface[domain.Quiver], error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.quivers != nil {
+		return q.quivers, nil
+	}
+
+	quivers, err := database.NewDatabase[domain.Quiver](ctx, quiversDatabase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open quivers: %w", err)
+	}
+	q.quivers = quivers
+
+	return quivers, nil
 }
@@ -1,185 +1,116 @@
 package quivers
 
 import (
+	"context"
 	"testing"
 
+	"github.com/google/uuid"
+
 	"github.com/rabbytesoftware/quiver/internal/infrastructure"
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
 	domain "github.com/rabbytesoftware/quiver/internal/models/quiver"
+	"github.com/rabbytesoftware/quiver/internal/models/system"
 )
 
-func TestNewQuiversRepository(t *testing.T) {
-	infra := infrastructure.NewInfrastructure()
-	repo := NewQuiversRepository(infra)
-
-	if repo == nil {
-		t.Fatal("NewQuiversRepository() returned nil")
-	}
-
-	// Test interface compliance (repo is already QuiversInterface type)
-	_ = repo
-
-	// Test concrete type
-	if _, ok := repo.(*QuiversRepository); !ok {
-		t.Error("NewQuiversRepository() did not return *QuiversRepository")
-	}
-}
-
-func TestNewQuiversRepository_WithNilInfrastructure(t *testing.T) {
-	repo := NewQuiversRepository(nil)
-
-	if repo == nil {
-		t.Fatal("NewQuiversRepository() returned nil")
-	}
+func newQuiversRepository(t *testing.T) QuiversInterface {
+	t.Helper()
+	t.Setenv("QUIVER_DATABASE_PATH", t.TempDir())
 
-	// Should still work with nil infrastructure
-	if concreteRepo, ok := repo.(*QuiversRepository); ok {
-		if concreteRepo.infrastructure != nil {
-			t.Error("Expected infrastructure to be nil")
-		}
-	}
+	return NewQuiversRepository(infrastructure.NewInfrastructure())
 }
 
-func TestQuiversRepository_Get(t *testing.T) {
+func TestNewQuiversRepository(t *testing.T) {
 	infra := infrastructure.NewInfrastructure()
 	repo := NewQuiversRepository(infra)
 
-	result := repo.Get()
-
-	// Should return empty slice (current implementation)
-	if result == nil {
-		t.Error("Get() should not return nil")
+	concrete, ok := repo.(*QuiversRepository)
+	if !ok {
+		t.Fatal("NewQuiversRepository() did not return *QuiversRepository")
 	}
 
-	if len(result) != 0 {
-		t.Errorf("Expected empty slice, got %d items", len(result))
+	if concrete.infrastructure != infra {
+		t.Error("QuiversRepository.infrastructure is not the same instance passed to constructor")
 	}
 }
 
-func TestQuiversRepository_GetById(t *testing.T) {
-	infra := infrastructure.NewInfrastructure()
-	repo := NewQuiversRepository(infra)
+func TestQuiversRepository_GetEmpty(t *testing.T) {
+	repo := newQuiversRepository(t)
 
-	result := repo.GetById("test-id")
-
-	// Should return nil (current implementation)
-	if result != nil {
-		t.Error("GetById() should return nil in current implementation")
+	quivers, err := repo.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Get() returned error: %v", err)
 	}
-}
-
-func TestQuiversRepository_GetById_EmptyId(t *testing.T) {
-	infra := infrastructure.NewInfrastructure()
-	repo := NewQuiversRepository(infra)
 
-	result := repo.GetById("")
-
-	// Should return nil (current implementation)
-	if result != nil {
-		t.Error("GetById() with empty id should return nil")
+	if quivers == nil || len(quivers) != 0 {
+		t.Errorf("Expected an empty slice, got %v", quivers)
 	}
 }
 
-func TestQuiversRepository_Create(t *testing.T) {
-	infra := infrastructure.NewInfrastructure()
-	repo := NewQuiversRepository(infra)
-
-	quiver := &domain.Quiver{}
-	result := repo.Create(quiver)
-
-	// Should return nil (current implementation)
-	if result != nil {
-		t.Error("Create() should return nil in current implementation")
+func TestQuiversRepository_CRUD(t *testing.T) {
+	ctx := context.Background()
+	repo := newQuiversRepository(t)
+
+	created, err := repo.Create(ctx, &domain.Quiver{
+		Name:         "core.quiver",
+		Version:      "25.9.0",
+		Maintainers:  []string{"team@quiver.ar"},
+		ListedArrows: []arrow.ArrowNamespace{"cs2"},
+		Manifests: map[arrow.ArrowNamespace]system.URL{
+			"cs2": "https://quiver.ar/quiver/arrows/cs2.arrow.yaml",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Create() returned error: %v", err)
 	}
-}
-
-func TestQuiversRepository_Create_WithNilQuiver(t *testing.T) {
-	infra := infrastructure.NewInfrastructure()
-	repo := NewQuiversRepository(infra)
 
-	result := repo.Create(nil)
-
-	// Should return nil (current implementation)
-	if result != nil {
-		t.Error("Create() with nil quiver should return nil")
+	if created.ID != domain.NewID("core.quiver") {
+		t.Fatalf("Expected Create() to derive the ID from the name, got %s", created.ID)
 	}
-}
-
-func TestQuiversRepository_Update(t *testing.T) {
-	infra := infrastructure.NewInfrastructure()
-	repo := NewQuiversRepository(infra)
-
-	quiver := &domain.Quiver{}
-	result := repo.Update(quiver)
 
-	// Should return nil (current implementation)
-	if result != nil {
-		t.Error("Update() should return nil in current implementation")
+	found, err := repo.GetById(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetById() returned error: %v", err)
 	}
-}
-
-func TestQuiversRepository_Update_WithNilQuiver(t *testing.T) {
-	infra := infrastructure.NewInfrastructure()
-	repo := NewQuiversRepository(infra)
-
-	result := repo.Update(nil)
 
-	// Should return nil (current implementation)
-	if result != nil {
-		t.Error("Update() with nil quiver should return nil")
+	if len(found.Maintainers) != 1 || found.Maintainers[0] != "team@quiver.ar" {
+		t.Errorf("Expected the maintainers to be stored, got %v", found.Maintainers)
 	}
-}
-
-func TestQuiversRepository_DeleteById(t *testing.T) {
-	infra := infrastructure.NewInfrastructure()
-	repo := NewQuiversRepository(infra)
 
-	err := repo.DeleteById("test-id")
-
-	// Should return nil (current implementation)
-	if err != nil {
-		t.Errorf("DeleteById() should return nil, got %v", err)
+	if len(found.ListedArrows) != 1 || found.ListedArrows[0] != "cs2" {
+		t.Errorf("Expected the listed arrows to be stored, got %v", found.ListedArrows)
 	}
-}
 
-func TestQuiversRepository_DeleteById_EmptyId(t *testing.T) {
-	infra := infrastructure.NewInfrastructure()
-	repo := NewQuiversRepository(infra)
+	if found.Manifests["cs2"] != "https://quiver.ar/quiver/arrows/cs2.arrow.yaml" {
+		t.Errorf("Expected the manifests to be stored, got %v", found.Manifests)
+	}
 
-	err := repo.DeleteById("")
+	found.Version = "25.10.0"
+	if _, err := repo.Update(ctx, found); err != nil {
+		t.Fatalf("Update() returned error: %v", err)
+	}
 
-	// Should return nil (current implementation)
+	quivers, err := repo.Get(ctx)
 	if err != nil {
-		t.Errorf("DeleteById() with empty id should return nil, got %v", err)
+		t.Fatalf("Get() returned error: %v", err)
 	}
-}
-
-func TestQuiversRepository_AllMethods(t *testing.T) {
-	infra := infrastructure.NewInfrastructure()
-	repo := NewQuiversRepository(infra)
 
-	// Test all methods in sequence to ensure they don't interfere
-	quivers := repo.Get()
-	if len(quivers) != 0 {
-		t.Error("Expected empty quivers list")
+	if len(quivers) != 1 || quivers[0].Version != "25.10.0" {
+		t.Errorf("Expected the updated quiver, got %+v", quivers)
 	}
 
-	quiver := repo.GetById("test")
-	if quiver != nil {
-		t.Error("Expected nil quiver")
+	if err := repo.DeleteById(ctx, created.ID); err != nil {
+		t.Fatalf("DeleteById() returned error: %v", err)
 	}
 
-	created := repo.Create(&domain.Quiver{})
-	if created != nil {
-		t.Error("Expected nil created quiver")
+	if _, err := repo.GetById(ctx, created.ID); err == nil {
+		t.Error("Expected GetById() to fail after DeleteById()")
 	}
+}
 
-	updated := repo.Update(&domain.Quiver{})
-	if updated != nil {
-		t.Error("Expected nil updated quiver")
-	}
+func TestQuiversRepository_GetByIdMissing(t *testing.T) {
+	repo := newQuiversRepository(t)
 
-	err := repo.DeleteById("test")
-	if err != nil {
-		t.Error("Expected nil error from delete")
+	if _, err := repo.GetById(context.Background(), uuid.New()); err == nil {
+		t.Error("Expected an error for a missing quiver")
 	}
 }
@@ -5,12 +5,14 @@ import (
 	"github.com/rabbytesoftware/quiver/internal/repositories/arrows"
 	"github.com/rabbytesoftware/quiver/internal/repositories/quivers"
 	"github.com/rabbytesoftware/quiver/internal/repositories/system"
+	"github.com/rabbytesoftware/quiver/internal/repositories/tasks"
 )
 
 type Repositories struct {
 	arrows  arrows.ArrowsInterface
 	system  system.SystemInterface
 	quivers quivers.QuiversInterface
+	tasks   tasks.TasksInterface
 }
 
 func NewRepositories(
@@ -20,6 +22,7 @@ func NewRepositories(
 		arrows:  arrows.NewArrowsRepository(infrastructure),
 		system:  system.NewSystemRepository(infrastructure),
 		quivers: quivers.NewQuiversRepository(infrastructure),
+		tasks:   tasks.NewTasksRepository(infrastructure),
 	}
 }
 
@@ -34,3 +37,7 @@ func (r *Repositories) GetSystem() system.SystemInterface {
 func (r *Repositories) GetQuivers() quivers.QuiversInterface {
 	return r.quivers
 }
+
+func (r *Repositories) GetTasks() tasks.TasksInterface {
+	return r.tasks
+}
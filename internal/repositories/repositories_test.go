@@ -85,6 +85,18 @@ func TestRepositories_GetQuivers(t *testing.T) {
 	_ = quiversRepo
 }
 
+func TestRepositories_GetTasks(t *testing.T) {
+	repos := NewRepositories(infrastructure.NewInfrastructure())
+
+	if repos.GetTasks() == nil {
+		t.Error("GetTasks() returned nil")
+	}
+
+	if repos.GetTasks() != repos.GetTasks() {
+		t.Error("GetTasks() should return the same instance")
+	}
+}
+
 func TestRepositoriesStructure(t *testing.T) {
 	infra := infrastructure.NewInfrastructure()
 	repos := NewRepositories(infra)
@@ -1,9 +1,26 @@
 package system
 
-import "github.com/rabbytesoftware/quiver/internal/core/metadata"
+import (
+	"context"
+
+	"github.com/rabbytesoftware/quiver/internal/core/metadata"
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+	"github.com/rabbytesoftware/quiver/internal/models/requirement"
+)
 
 type SystemInterface interface {
 	GetMetadata() *metadata.Metadata
+
+	// Host probes what the machine offers, or returns nil when
+	// the requirements module is not available.
+	Host(ctx context.Context) *requirement.Host
+
+	// Netbridge reports the port forwarding strategies, or returns
+	// nil when the netbridge module is not available.
+	Netbridge(ctx context.Context) *port.NetbridgeStatus
+	// ProbeNetbridge picks the port forwarding strategy to use.
+	ProbeNetbridge(ctx context.Context) error
+
 	UpdateQuiver() error
 	UninstallQuiver() error
 
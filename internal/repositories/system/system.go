@@ -1,8 +1,13 @@
 package system
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/rabbytesoftware/quiver/internal/core/metadata"
 	"github.com/rabbytesoftware/quiver/internal/infrastructure"
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+	"github.com/rabbytesoftware/quiver/internal/models/requirement"
 )
 
 type SystemRepository struct {
@@ -21,6 +26,31 @@ func (s *SystemRepository) GetMetadata() *metadata.Metadata {
 	return nil
 }
 
+func (s *SystemRepository) Host(ctx context.Context) *requirement.Host {
+	if s.infrastructure == nil || s.infrastructure.Requirements == nil {
+		return nil
+	}
+
+	return s.infrastructure.Requirements.Probe(ctx)
+}
+
+func (s *SystemRepository) Netbridge(ctx context.Context) *port.NetbridgeStatus {
+	if s.infrastructure == nil || s.infrastructure.Netbridge == nil {
+		return nil
+	}
+
+	status := s.infrastructure.Netbridge.Status(ctx)
+	return &status
+}
+
+func (s *SystemRepository) ProbeNetbridge(ctx context.Context) error {
+	if s.infrastructure == nil || s.infrastructure.Netbridge == nil {
+		return fmt.Errorf("netbridge module is not available")
+	}
+
+	return s.infrastructure.Netbridge.Probe(ctx)
+}
+
 func (s *SystemRepository) UpdateQuiver() error {
 	return nil
 }
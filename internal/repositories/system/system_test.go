@@ -1,9 +1,13 @@
 package system
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	"github.com/rabbytesoftware/quiver/internal/infrastructure"
+	"github.com/rabbytesoftware/quiver/internal/infrastructure/netbridge"
+	"github.com/rabbytesoftware/quiver/internal/models/system"
 )
 
 func TestNewSystemRepository(t *testing.T) {
@@ -199,3 +203,40 @@ func TestSystemRepositoryInterfaceMethods(t *testing.T) {
 		t.Error("Interface StopQuiver() method should return nil error")
 	}
 }
+
+func TestSystemRepository_Host(t *testing.T) {
+	repo := NewSystemRepository(infrastructure.NewInfrastructure())
+
+	host := repo.Host(context.Background())
+	if host == nil {
+		t.Fatal("Host() returned nil")
+	}
+	if host.OS != system.CurrentOS() || host.CpuCores <= 0 {
+		t.Errorf("Expected the host platform and cores, got %+v", host)
+	}
+
+	if NewSystemRepository(nil).Host(context.Background()) != nil {
+		t.Error("Host() should return nil without infrastructure")
+	}
+}
+
+func TestSystemRepository_Netbridge(t *testing.T) {
+	repo := NewSystemRepository(&infrastructure.Infrastructure{
+		Netbridge: netbridge.NewNetbridgeWith(false),
+	})
+
+	status := repo.Netbridge(context.Background())
+	if status == nil || status.Enabled || status.Available {
+		t.Errorf("Expected a disabled netbridge, got %+v", status)
+	}
+	if err := repo.ProbeNetbridge(context.Background()); !errors.Is(err, netbridge.ErrDisabled) {
+		t.Errorf("Expected probing a disabled netbridge to fail, got %v", err)
+	}
+
+	if NewSystemRepository(nil).Netbridge(context.Background()) != nil {
+		t.Error("Netbridge() should return nil without infrastructure")
+	}
+	if NewSystemRepository(nil).ProbeNetbridge(context.Background()) == nil {
+		t.Error("ProbeNetbridge() should fail without infrastructure")
+	}
+}
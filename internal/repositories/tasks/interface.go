@@ -0,0 +1,29 @@
+package tasks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	domain "github.com/rabbytesoftware/quiver/internal/models/schedule"
+)
+
+type TasksInterface interface {
+	// Get returns every scheduled task.
+	Get(ctx context.Context) ([]*domain.Task, error)
+
+	// GetByID returns a single task.
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Task, error)
+
+	// Save creates or updates a task.
+	Save(ctx context.Context, task *domain.Task) error
+
+	// Delete removes a task and its runs.
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// RecordRun stores the outcome of a task execution.
+	RecordRun(ctx context.Context, run *domain.Run) error
+
+	// Runs returns the latest runs of a task, newest first.
+	Runs(ctx context.Context, taskID uuid.UUID, limit int) ([]*domain.Run, error)
+}
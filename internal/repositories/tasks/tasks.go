@@ -0,0 +1,178 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/rabbytesoftware/quiver/internal/core/database"
+	interfaces "github.com/rabbytesoftware/quiver/internal/core/database/interface"
+	"github.com/rabbytesoftware/quiver/internal/infrastructure"
+	domain "github.com/rabbytesoftware/quiver/internal/models/schedule"
+)
+
+const (
+	tasksDatabase = "tasks"
+	runsDatabase  = "task_runs"
+)
+
+type TasksRepository struct {
+	infrastructure *infrastructure.Infrastructure
+
+	mu    sync.Mutex
+	tasks interfaces.RepositoryInterface[domain.Task]
+	runs  interfaces.RepositoryInterface[domain.Run]
+}
+
+func NewTasksRepository(
+	infrastructure *infrastructure.Infrastructure,
+) TasksInterface {
+	return &TasksRepository{
+		infrastructure: infrastructure,
+	}
+}
+
+func (t *TasksRepository) Get(ctx context.Context) ([]*domain.Task, error) {
+	tasks, _, err := t.stores(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	all, err := tasks.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].CreatedAt.Before(all[j].CreatedAt)
+	})
+
+	return all, nil
+}
+
+func (t *TasksRepository) GetByID(
+	ctx context.Context,
+	id uuid.UUID,
+) (*domain.Task, error) {
+	tasks, _, err := t.stores(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks.GetByID(ctx, id)
+}
+
+func (t *TasksRepository) Save(
+	ctx context.Context,
+	task *domain.Task,
+) error {
+	tasks, _, err := t.stores(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = tasks.Update(ctx, task)
+	return err
+}
+
+func (t *TasksRepository) Delete(
+	ctx context.Context,
+	id uuid.UUID,
+) error {
+	tasks, runs, err := t.stores(ctx)
+	if err != nil {
+		return err
+	}
+
+	all, err := runs.Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, run := range all {
+		if run.TaskID != id {
+			continue
+		}
+		if err := runs.Delete(ctx, run.ID); err != nil {
+			return err
+		}
+	}
+
+	return tasks.Delete(ctx, id)
+}
+
+func (t *TasksRepository) RecordRun(
+	ctx context.Context,
+	run *domain.Run,
+) error {
+	_, runs, err := t.stores(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = runs.Create(ctx, run)
+	return err
+}
+
+func (t *TasksRepository) Runs(
+	ctx context.Context,
+	taskID uuid.UUID,
+	limit int,
+) ([]*domain.Run, error) {
+	_, runs, err := t.stores(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	all, err := runs.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*domain.Run{}
+	for _, run := range all {
+		if run.TaskID == taskID {
+			result = append(result, run)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].StartedAt.After(result[j].StartedAt)
+	})
+
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+
+	return result, nil
+}
+
+// stores opens the task databases on first use, so nothing is
+// written to disk until a task is scheduled or listed.
+func (t *TasksRepository) stores(
+	ctx context.Context,
+) (interfaces.RepositoryInterface[domain.Task], interfaces.RepositoryInterface[domain.Run], error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.tasks != nil && t.runs != nil {
+		return t.tasks, t.runs, nil
+	}
+
+	tasks, err := database.NewDatabase[domain.Task](ctx, tasksDatabase)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open tasks: %w", err)
+	}
+
+	runs, err := database.NewDatabase[domain.Run](ctx, runsDatabase)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open task runs: %w", err)
+	}
+
+	t.tasks, t.runs = tasks, runs
+
+	return tasks, runs, nil
+}
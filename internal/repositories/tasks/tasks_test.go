@@ -0,0 +1,110 @@
+package tasks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rabbytesoftware/quiver/internal/infrastructure"
+	domain "github.com/rabbytesoftware/quiver/internal/models/schedule"
+)
+
+func newTestRepository(t *testing.T) TasksInterface {
+	t.Helper()
+	t.Setenv("QUIVER_DATABASE_PATH", t.TempDir())
+
+	return NewTasksRepository(infrastructure.NewInfrastructure())
+}
+
+func TestNewTasksRepository(t *testing.T) {
+	repo := NewTasksRepository(nil)
+
+	if _, ok := repo.(*TasksRepository); !ok {
+		t.Error("NewTasksRepository() did not return *TasksRepository")
+	}
+}
+
+func TestTasksRepository_Tasks(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	now := time.Now()
+
+	restart := &domain.Task{ID: uuid.New(), Arrow: "cs2", Cron: "0 4 * * *", Action: domain.TaskRestart, Enabled: true, CreatedAt: now}
+	rotate := &domain.Task{ID: uuid.New(), Arrow: "cs2", Cron: "*/30 * * * *", Action: domain.TaskMethod, Method: "rotate_map", CreatedAt: now.Add(time.Second)}
+
+	for _, task := range []*domain.Task{rotate, restart} {
+		if err := repo.Save(ctx, task); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	tasks, err := repo.Get(ctx)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if len(tasks) != 2 || tasks[0].ID != restart.ID || tasks[1].Method != "rotate_map" {
+		t.Fatalf("Expected both tasks oldest first, got %+v", tasks)
+	}
+
+	ranAt := now.Truncate(time.Second)
+	restart.LastRun = &ranAt
+	if err := repo.Save(ctx, restart); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	stored, err := repo.GetByID(ctx, restart.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if stored.LastRun == nil || !stored.LastRun.Equal(ranAt) {
+		t.Errorf("Expected last run to be persisted, got %v", stored.LastRun)
+	}
+
+	if err := repo.Delete(ctx, rotate.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := repo.GetByID(ctx, rotate.ID); err == nil {
+		t.Error("Expected the deleted task to be gone")
+	}
+}
+
+func TestTasksRepository_Runs(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	task := &domain.Task{ID: uuid.New(), Arrow: "cs2", Cron: "0 4 * * *", Action: domain.TaskRestart}
+	other := &domain.Task{ID: uuid.New()}
+	start := time.Now()
+
+	for i := 0; i < 3; i++ {
+		at := start.Add(time.Duration(i) * time.Minute)
+		if err := repo.RecordRun(ctx, domain.NewRun(task, at, at, nil)); err != nil {
+			t.Fatalf("RecordRun() error = %v", err)
+		}
+	}
+	repo.RecordRun(ctx, domain.NewRun(other, start, start, nil))
+
+	runs, err := repo.Runs(ctx, task.ID, 2)
+	if err != nil {
+		t.Fatalf("Runs() error = %v", err)
+	}
+	if len(runs) != 2 || !runs[0].StartedAt.After(runs[1].StartedAt) {
+		t.Fatalf("Expected the 2 newest runs first, got %+v", runs)
+	}
+
+	repo.Save(ctx, task)
+	if err := repo.Delete(ctx, task.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	runs, _ = repo.Runs(ctx, task.ID, 0)
+	if len(runs) != 0 {
+		t.Errorf("Expected runs to be deleted with the task, got %d", len(runs))
+	}
+
+	runs, _ = repo.Runs(ctx, other.ID, 0)
+	if len(runs) != 1 {
+		t.Errorf("Expected other runs to be kept, got %d", len(runs))
+	}
+}
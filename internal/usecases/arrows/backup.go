@@ -0,0 +1,178 @@
+package arrows
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+)
+
+var (
+	ErrBackupNotFound   = errors.New("backup not found")
+	ErrInvalidRetention = errors.New("invalid backup retention")
+)
+
+type archiver interface {
+	CreateBackup(ctx context.Context, arrow *arrow.Arrow, quiesced bool) (*arrow.Backup, error)
+	Backups(ctx context.Context, arrow *arrow.Arrow) ([]arrow.Backup, error)
+	RestoreBackup(ctx context.Context, arrow *arrow.Arrow, id string) error
+	DeleteBackup(ctx context.Context, arrow *arrow.Arrow, id string) error
+}
+
+// Backup archives the files an installed arrow's manifest selects
+// and prunes the backups its retention policy no longer keeps.
+// With quiesce, a running instance is stopped for the duration of
+// the backup so its files are consistent.
+func (u *ArrowsUsecase) Backup(
+	ctx context.Context,
+	namespace arrow.ArrowNamespace,
+	quiesce bool,
+) (*arrow.Backup, error) {
+	current, err := u.installed(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	repository := u.repositories.GetArrows()
+	return backup(ctx, repository, repository, current, quiesce, time.Now())
+}
+
+// Backups lists the backups of an installed arrow, newest first.
+func (u *ArrowsUsecase) Backups(
+	ctx context.Context,
+	namespace arrow.ArrowNamespace,
+) ([]arrow.Backup, error) {
+	current, err := u.installed(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.repositories.GetArrows().Backups(ctx, current)
+}
+
+// RestoreBackup extracts a backup over an installed arrow. A running
+// instance is stopped first and started again afterwards.
+func (u *ArrowsUsecase) RestoreBackup(
+	ctx context.Context,
+	namespace arrow.ArrowNamespace,
+	id string,
+) error {
+	current, err := u.installed(ctx, namespace)
+	if err != nil {
+		return err
+	}
+
+	repository := u.repositories.GetArrows()
+	return restoreBackup(ctx, repository, repository, current, id)
+}
+
+// DeleteBackup removes a backup of an installed arrow.
+func (u *ArrowsUsecase) DeleteBackup(
+	ctx context.Context,
+	namespace arrow.ArrowNamespace,
+	id string,
+) error {
+	current, err := u.installed(ctx, namespace)
+	if err != nil {
+		return err
+	}
+
+	return backupError(u.repositories.GetArrows().DeleteBackup(ctx, current, id), id)
+}
+
+// SetBackupRetention changes how many backups of an installed arrow are kept.
+// Backups beyond the new limits are pruned on the next backup.
+func (u *ArrowsUsecase) SetBackupRetention(
+	ctx context.Context,
+	namespace arrow.ArrowNamespace,
+	retention arrow.BackupRetention,
+) (*arrow.Arrow, error) {
+	if err := retention.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRetention, err)
+	}
+
+	current, err := u.installed(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	current.BackupRetention = retention
+
+	return u.repositories.GetArrows().Update(ctx, current)
+}
+
+func backup(
+	ctx context.Context,
+	a archiver,
+	inst instance,
+	current *arrow.Arrow,
+	quiesce bool,
+	now time.Time,
+) (*arrow.Backup, error) {
+	stopped := quiesce && inst.Running(current)
+	if stopped {
+		if err := inst.Stop(ctx, current); err != nil {
+			return nil, err
+		}
+	}
+
+	created, err := a.CreateBackup(ctx, current, stopped)
+
+	if stopped {
+		if startErr := inst.Start(ctx, current); startErr != nil {
+			return created, errors.Join(err, startErr)
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	backups, err := a.Backups(ctx, current)
+	if err != nil {
+		return created, err
+	}
+
+	for _, expired := range current.BackupRetention.Expired(backups, now) {
+		if err := a.DeleteBackup(ctx, current, expired.ID); err != nil {
+			return created, err
+		}
+	}
+
+	return created, nil
+}
+
+func restoreBackup(
+	ctx context.Context,
+	a archiver,
+	inst instance,
+	current *arrow.Arrow,
+	id string,
+) error {
+	running := inst.Running(current)
+	if running {
+		if err := inst.Stop(ctx, current); err != nil {
+			return err
+		}
+	}
+
+	err := backupError(a.RestoreBackup(ctx, current, id), id)
+
+	if running {
+		if startErr := inst.Start(ctx, current); startErr != nil {
+			return errors.Join(err, startErr)
+		}
+	}
+
+	return err
+}
+
+func backupError(err error, id string) error {
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrBackupNotFound, id)
+	}
+	return err
+}
@@ -0,0 +1,175 @@
+package arrows
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/rabbytesoftware/quiver/internal/infrastructure"
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/repositories"
+)
+
+type fakeArchiver struct {
+	inst          *fakeInstance
+	backups       []arrow.Backup
+	createdWhile  []bool
+	restored      []string
+	deleted       []string
+	createErr     error
+	nextCreatedAt time.Time
+}
+
+func (f *fakeArchiver) CreateBackup(ctx context.Context, a *arrow.Arrow, quiesced bool) (*arrow.Backup, error) {
+	f.createdWhile = append(f.createdWhile, f.inst.running)
+	if f.createErr != nil {
+		return nil, f.createErr
+	}
+
+	created := arrow.Backup{
+		ID:        f.nextCreatedAt.Format(time.RFC3339),
+		Arrow:     a.Name,
+		Quiesced:  quiesced,
+		CreatedAt: f.nextCreatedAt,
+	}
+	f.backups = append(f.backups, created)
+
+	return &created, nil
+}
+
+func (f *fakeArchiver) Backups(ctx context.Context, a *arrow.Arrow) ([]arrow.Backup, error) {
+	return f.backups, nil
+}
+
+func (f *fakeArchiver) RestoreBackup(ctx context.Context, a *arrow.Arrow, id string) error {
+	for _, b := range f.backups {
+		if b.ID == id {
+			f.restored = append(f.restored, id)
+			return nil
+		}
+	}
+	return os.ErrNotExist
+}
+
+func (f *fakeArchiver) DeleteBackup(ctx context.Context, a *arrow.Arrow, id string) error {
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+func TestBackup_QuiescesRunningInstance(t *testing.T) {
+	inst := &fakeInstance{running: true}
+	archiver := &fakeArchiver{inst: inst, nextCreatedAt: time.Now()}
+	current := newArrow("cs2", "1.0.0")
+
+	created, err := backup(context.Background(), archiver, inst, current, true, time.Now())
+	if err != nil {
+		t.Fatalf("backup() error = %v", err)
+	}
+
+	if !created.Quiesced {
+		t.Error("Expected the backup to be marked as quiesced")
+	}
+	if len(archiver.createdWhile) != 1 || archiver.createdWhile[0] {
+		t.Errorf("Expected the instance to be stopped during the backup, got %v", archiver.createdWhile)
+	}
+	if !inst.running {
+		t.Error("Expected the instance to be started again")
+	}
+}
+
+func TestBackup_WithoutQuiesce(t *testing.T) {
+	inst := &fakeInstance{running: true}
+	archiver := &fakeArchiver{inst: inst, nextCreatedAt: time.Now()}
+
+	created, err := backup(context.Background(), archiver, inst, newArrow("cs2", "1.0.0"), false, time.Now())
+	if err != nil {
+		t.Fatalf("backup() error = %v", err)
+	}
+
+	if created.Quiesced || len(inst.calls) != 0 {
+		t.Errorf("Expected a live backup without stopping, got quiesced=%v calls=%v", created.Quiesced, inst.calls)
+	}
+}
+
+func TestBackup_RestartsAfterFailure(t *testing.T) {
+	inst := &fakeInstance{running: true}
+	archiver := &fakeArchiver{inst: inst, createErr: errors.New("disk full")}
+
+	if _, err := backup(context.Background(), archiver, inst, newArrow("cs2", "1.0.0"), true, time.Now()); err == nil {
+		t.Fatal("Expected the backup error to be returned")
+	}
+
+	if !inst.running {
+		t.Error("Expected the instance to be started again after a failed backup")
+	}
+}
+
+func TestBackup_AppliesRetention(t *testing.T) {
+	now := time.Date(2025, 7, 10, 4, 0, 0, 0, time.UTC)
+	inst := &fakeInstance{}
+	archiver := &fakeArchiver{
+		inst:          inst,
+		nextCreatedAt: now,
+		backups: []arrow.Backup{
+			{ID: "old", CreatedAt: now.AddDate(0, 0, -2)},
+			{ID: "older", CreatedAt: now.AddDate(0, 0, -3)},
+		},
+	}
+
+	current := newArrow("cs2", "1.0.0")
+	current.BackupRetention = arrow.BackupRetention{Keep: 2}
+
+	if _, err := backup(context.Background(), archiver, inst, current, false, now); err != nil {
+		t.Fatalf("backup() error = %v", err)
+	}
+
+	if len(archiver.deleted) != 1 || archiver.deleted[0] != "older" {
+		t.Errorf("Expected only the oldest backup to be pruned, got %v", archiver.deleted)
+	}
+}
+
+func TestRestoreBackup(t *testing.T) {
+	inst := &fakeInstance{running: true}
+	archiver := &fakeArchiver{inst: inst, backups: []arrow.Backup{{ID: "b1"}}}
+	current := newArrow("cs2", "1.0.0")
+
+	if err := restoreBackup(context.Background(), archiver, inst, current, "b1"); err != nil {
+		t.Fatalf("restoreBackup() error = %v", err)
+	}
+
+	if len(archiver.restored) != 1 || !inst.running {
+		t.Errorf("Expected the backup to be restored and the instance restarted, got %v %v", archiver.restored, inst.calls)
+	}
+	if len(inst.calls) != 2 || inst.calls[0] != "stop 1.0.0" {
+		t.Errorf("Expected the instance to be stopped before restoring, got %v", inst.calls)
+	}
+
+	err := restoreBackup(context.Background(), archiver, inst, current, "missing")
+	if !errors.Is(err, ErrBackupNotFound) {
+		t.Errorf("Expected ErrBackupNotFound, got %v", err)
+	}
+}
+
+func TestArrowsUsecase_Backup_Validation(t *testing.T) {
+	t.Setenv("QUIVER_DATABASE_PATH", t.TempDir())
+	usecase := NewArrowsUsecase(repositories.NewRepositories(infrastructure.NewInfrastructure()))
+	ctx := context.Background()
+
+	if _, err := usecase.SetBackupRetention(ctx, "cs2", arrow.BackupRetention{Keep: -1}); !errors.Is(err, ErrInvalidRetention) {
+		t.Errorf("Expected ErrInvalidRetention, got %v", err)
+	}
+	if _, err := usecase.SetBackupRetention(ctx, "cs2", arrow.BackupRetention{Keep: 3}); !errors.Is(err, ErrNotInstalled) {
+		t.Errorf("Expected ErrNotInstalled, got %v", err)
+	}
+	if _, err := usecase.Backup(ctx, "cs2", true); !errors.Is(err, ErrNotInstalled) {
+		t.Errorf("Expected ErrNotInstalled, got %v", err)
+	}
+	if _, err := usecase.Backups(ctx, "cs2"); !errors.Is(err, ErrNotInstalled) {
+		t.Errorf("Expected ErrNotInstalled, got %v", err)
+	}
+	if err := usecase.RestoreBackup(ctx, "cs2", "b1"); !errors.Is(err, ErrNotInstalled) {
+		t.Errorf("Expected ErrNotInstalled, got %v", err)
+	}
+}
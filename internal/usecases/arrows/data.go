@@ -0,0 +1,54 @@
+package arrows
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/runtime"
+)
+
+var ErrDataInUse = errors.New("arrow is installed")
+
+// PurgeData deletes the data an uninstalled arrow left behind.
+// Installed arrows are purged with Uninstall instead.
+func (u *ArrowsUsecase) PurgeData(
+	ctx context.Context,
+	namespace arrow.ArrowNamespace,
+) error {
+	if _, err := u.installed(ctx, namespace); err == nil {
+		return fmt.Errorf("%w: uninstall %s with purge to delete its data", ErrDataInUse, namespace.Name())
+	} else if !errors.Is(err, ErrNotInstalled) {
+		return err
+	}
+
+	return u.repositories.GetArrows().PurgeData(ctx, &arrow.Arrow{Name: namespace.Name()})
+}
+
+// runWithData runs a method that installs or replaces program files.
+// The data directory exists before the method runs, so it can use
+// ${DATA_DIR}, and is linked into the install tree afterwards,
+// followed by the config templates.
+func (u *ArrowsUsecase) runWithData(
+	ctx context.Context,
+	a *arrow.Arrow,
+	action runtime.Action,
+) error {
+	arrows := u.repositories.GetArrows()
+
+	if err := arrows.PrepareData(ctx, a); err != nil {
+		return err
+	}
+
+	if err := arrows.Run(ctx, a, action); err != nil {
+		return err
+	}
+
+	if err := arrows.LinkData(ctx, a); err != nil {
+		return err
+	}
+
+	_, err := arrows.RenderTemplates(ctx, a, false)
+	return err
+}
@@ -0,0 +1,24 @@
+package arrows
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rabbytesoftware/quiver/internal/infrastructure"
+	"github.com/rabbytesoftware/quiver/internal/repositories"
+)
+
+func TestArrowsUsecase_Data_NotInstalled(t *testing.T) {
+	t.Setenv("QUIVER_DATABASE_PATH", t.TempDir())
+	usecase := NewArrowsUsecase(repositories.NewRepositories(infrastructure.NewInfrastructure()))
+	ctx := context.Background()
+
+	if err := usecase.Uninstall(ctx, "cs2", true); !errors.Is(err, ErrNotInstalled) {
+		t.Errorf("Expected ErrNotInstalled, got %v", err)
+	}
+
+	if err := usecase.PurgeData(ctx, "cs2"); err != nil {
+		t.Errorf("Expected leftover data of an uninstalled arrow to be purged, got %v", err)
+	}
+}
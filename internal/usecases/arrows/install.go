@@ -0,0 +1,238 @@
+package arrows
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/runtime"
+)
+
+// Resolve computes the install plan for an arrow and its
+// dependencies without changing anything on the host.
+func (u *ArrowsUsecase) Resolve(
+	ctx context.Context,
+	namespace arrow.ArrowNamespace,
+) (*Plan, error) {
+	installed, err := u.repositories.GetArrows().Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver := NewResolver(u.repositories.GetQuivers())
+
+	return resolver.Resolve(
+		ctx,
+		[]arrow.ArrowNamespace{namespace},
+		installed,
+	)
+}
+
+// Install resolves an arrow and installs it after its dependencies,
+// skipping the ones that are already installed. Nothing is run when
+// any of them does not meet its minimum requirements.
+func (u *ArrowsUsecase) Install(
+	ctx context.Context,
+	namespace arrow.ArrowNamespace,
+) (*Plan, error) {
+	plan, err := u.Resolve(ctx, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", namespace, err)
+	}
+
+	var pending []*arrow.Arrow
+	for _, step := range plan.Pending() {
+		pending = append(pending, step.Arrow)
+	}
+
+	if plan.Warnings, err = u.admit(ctx, pending); err != nil {
+		return plan, err
+	}
+
+	for _, step := range plan.Pending() {
+		if err := u.install(ctx, step.Arrow, runtime.ActionInstall, nil); err != nil {
+			return plan, err
+		}
+	}
+
+	return plan, nil
+}
+
+// Update replaces an installed arrow with the newest release matching
+// namespace, installing any new dependencies first. The previous
+// install tree is kept so the update can be rolled back.
+func (u *ArrowsUsecase) Update(
+	ctx context.Context,
+	namespace arrow.ArrowNamespace,
+) (*Plan, error) {
+	plan, current, err := u.planUpdate(ctx, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", namespace, err)
+	}
+
+	steps := planSteps(plan, current)
+	if len(steps) == 0 {
+		return plan, nil
+	}
+
+	var pending []*arrow.Arrow
+	for _, step := range steps {
+		pending = append(pending, step.Arrow)
+	}
+
+	if plan.Warnings, err = u.admit(ctx, pending); err != nil {
+		return plan, err
+	}
+
+	history := u.repositories.GetArrows()
+	snapshot, err := history.Snapshot(ctx, current)
+	if err != nil {
+		return nil, err
+	}
+
+	kept, assigned, updated := false, false, false
+	defer func() {
+		if !kept {
+			history.RemoveSnapshot(snapshot)
+		}
+
+		// ? The instance keeps its previous ports unless the update
+		// ? is stored.
+		if assigned && !updated {
+			history.RestorePorts(ctx, current)
+		}
+	}()
+
+	for _, step := range steps {
+		if step.Arrow.Name != current.Name {
+			if err := u.install(ctx, step.Arrow, step.Action, nil); err != nil {
+				return plan, err
+			}
+			continue
+		}
+
+		carryVariables(current, step.Arrow)
+		step.Arrow.ID = current.ID
+
+		assigned = true
+		if err := u.assignPorts(ctx, step.Arrow); err != nil {
+			return plan, err
+		}
+
+		if err := u.runWithData(ctx, step.Arrow, step.Action); err != nil {
+			return plan, err
+		}
+
+		if err := keepSnapshot(ctx, history, current, snapshot, time.Now()); err != nil {
+			return plan, err
+		}
+		kept = true
+
+		step.Arrow.UpdatePolicy = current.UpdatePolicy
+		if _, err := u.repositories.GetArrows().Update(ctx, step.Arrow); err != nil {
+			return plan, err
+		}
+		updated = true
+
+		if err := u.recordRevision(ctx, step.Arrow, arrow.RevisionUpdate); err != nil {
+			return plan, err
+		}
+	}
+
+	return plan, nil
+}
+
+// Uninstall runs an arrow's uninstall method and forgets it,
+// giving its ports back.
+// Arrows that depend on it are left in place. Its data directory
+// is kept for a later reinstall unless purge is set.
+func (u *ArrowsUsecase) Uninstall(
+	ctx context.Context,
+	namespace arrow.ArrowNamespace,
+	purge bool,
+) error {
+	current, err := u.installed(ctx, namespace)
+	if err != nil {
+		return err
+	}
+
+	u.closePorts(ctx, current)
+
+	if err := u.repositories.GetArrows().Run(ctx, current, runtime.ActionUninstall); err != nil {
+		return err
+	}
+
+	if err := u.repositories.GetArrows().DeleteHistory(ctx, current.ID); err != nil {
+		return err
+	}
+
+	if err := u.repositories.GetArrows().ReleasePorts(ctx, current.ID); err != nil {
+		return err
+	}
+
+	if purge {
+		if err := u.repositories.GetArrows().PurgeData(ctx, current); err != nil {
+			return err
+		}
+	}
+
+	return u.repositories.GetArrows().DeleteById(ctx, current.ID)
+}
+
+// install assigns ports to a new arrow, runs action and stores it.
+// verify, when set, runs before it is stored. The ports are given
+// back unless the arrow is stored, so a failed attempt holds none.
+func (u *ArrowsUsecase) install(
+	ctx context.Context,
+	a *arrow.Arrow,
+	action runtime.Action,
+	verify func() error,
+) error {
+	stored := false
+	defer func() {
+		if !stored && a.ID != uuid.Nil {
+			u.repositories.GetArrows().ReleasePorts(ctx, a.ID)
+		}
+	}()
+
+	if err := u.assignPorts(ctx, a); err != nil {
+		return err
+	}
+
+	if err := u.runWithData(ctx, a, action); err != nil {
+		return err
+	}
+
+	if verify != nil {
+		if err := verify(); err != nil {
+			return err
+		}
+	}
+
+	if _, err := u.repositories.GetArrows().Create(ctx, a); err != nil {
+		return err
+	}
+	stored = true
+
+	return u.recordRevision(ctx, a, arrow.RevisionInstall)
+}
+
+// assignPorts gives an arrow its ID and a port for each of its
+// netbridge entries before its methods run, so they can use them.
+func (u *ArrowsUsecase) assignPorts(
+	ctx context.Context,
+	a *arrow.Arrow,
+) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+
+	if err := u.repositories.GetArrows().AssignPorts(ctx, a); err != nil {
+		return fmt.Errorf("failed to assign ports to %s: %w", a.Namespace, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package arrows
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/rabbytesoftware/quiver/internal/core/config"
+	"github.com/rabbytesoftware/quiver/internal/infrastructure"
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+	"github.com/rabbytesoftware/quiver/internal/models/runtime"
+	"github.com/rabbytesoftware/quiver/internal/repositories"
+)
+
+func TestArrowsUsecase_AssignPorts(t *testing.T) {
+	t.Setenv("QUIVER_DATABASE_PATH", t.TempDir())
+
+	usecase := NewArrowsUsecase(repositories.NewRepositories(infrastructure.NewInfrastructure()))
+	cs2 := &arrow.Arrow{
+		Namespace: "cs2@1.0.0",
+		Netbridge: []port.PortRule{{Name: "GAME_PORT", Protocol: port.ProtocolUDP}},
+	}
+
+	if err := usecase.assignPorts(context.Background(), cs2); err != nil {
+		t.Fatalf("assignPorts() returned error: %v", err)
+	}
+
+	if cs2.ID == uuid.Nil {
+		t.Error("Expected the arrow to get its ID before its ports")
+	}
+
+	allowed, err := port.ParseRanges(config.GetNetbridge().AllowedPorts)
+	if err != nil || len(allowed) == 0 || !allowed[0].Contains(cs2.Netbridge[0].StartPort) {
+		t.Errorf("Expected a port from %q, got %+v", config.GetNetbridge().AllowedPorts, cs2.Netbridge[0])
+	}
+}
+
+func TestArrowsUsecase_Install_ReleasesPorts(t *testing.T) {
+	t.Setenv("QUIVER_DATABASE_PATH", t.TempDir())
+
+	usecase := NewArrowsUsecase(repositories.NewRepositories(infrastructure.NewInfrastructure()))
+	ctx := context.Background()
+
+	// ? Without an install method for this OS the install fails
+	// ? after its ports were assigned.
+	cs2 := &arrow.Arrow{
+		Namespace: "cs2@1.0.0",
+		Netbridge: []port.PortRule{{Name: "GAME_PORT", Protocol: port.ProtocolUDP}},
+	}
+	if err := usecase.install(ctx, cs2, runtime.ActionInstall, nil); err == nil {
+		t.Fatal("Expected the install to fail")
+	}
+
+	other := &arrow.Arrow{
+		ID:        uuid.New(),
+		Netbridge: []port.PortRule{{Name: "GAME_PORT", Protocol: port.ProtocolUDP}},
+	}
+	ports, err := usecase.repositories.GetArrows().PreviewPorts(ctx, other, nil)
+	if err != nil {
+		t.Fatalf("PreviewPorts() returned error: %v", err)
+	}
+	if ports[0].StartPort != cs2.Netbridge[0].StartPort {
+		t.Errorf("Expected port %d of the failed install to be free again, got %d", cs2.Netbridge[0].StartPort, ports[0].StartPort)
+	}
+}
@@ -0,0 +1,109 @@
+package arrows
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/runtime"
+)
+
+// Start renders an installed arrow's config templates, runs its
+// execute method and forwards its ports. Hand edited config files
+// are kept.
+func (u *ArrowsUsecase) Start(
+	ctx context.Context,
+	namespace arrow.ArrowNamespace,
+) error {
+	current, err := u.installed(ctx, namespace)
+	if err != nil {
+		return err
+	}
+
+	if _, err := u.render(ctx, current, false); err != nil {
+		return err
+	}
+
+	if err := u.repositories.GetArrows().Start(ctx, current); err != nil {
+		return err
+	}
+
+	u.openPorts(ctx, current)
+	return nil
+}
+
+// Stop stops an installed arrow if it is running and closes its
+// forwarded ports.
+func (u *ArrowsUsecase) Stop(
+	ctx context.Context,
+	namespace arrow.ArrowNamespace,
+) error {
+	current, err := u.installed(ctx, namespace)
+	if err != nil {
+		return err
+	}
+
+	if err := u.repositories.GetArrows().Stop(ctx, current); err != nil {
+		return err
+	}
+
+	u.closePorts(ctx, current)
+	return nil
+}
+
+// Restart stops an installed arrow and starts it again with
+// freshly rendered config templates, forwarding its ports again.
+func (u *ArrowsUsecase) Restart(
+	ctx context.Context,
+	namespace arrow.ArrowNamespace,
+) error {
+	current, err := u.installed(ctx, namespace)
+	if err != nil {
+		return err
+	}
+
+	if err := u.repositories.GetArrows().Stop(ctx, current); err != nil {
+		return err
+	}
+
+	if _, err := u.render(ctx, current, false); err != nil {
+		return err
+	}
+
+	if err := u.repositories.GetArrows().Start(ctx, current); err != nil {
+		return err
+	}
+
+	u.openPorts(ctx, current)
+	return nil
+}
+
+// RunMethod runs a method declared in an installed arrow's manifest,
+// such as a map rotation. Methods that change the installation have
+// their own operations and are rejected.
+func (u *ArrowsUsecase) RunMethod(
+	ctx context.Context,
+	namespace arrow.ArrowNamespace,
+	method string,
+) error {
+	action := runtime.Action(method)
+	if err := runnable(action); err != nil {
+		return err
+	}
+
+	current, err := u.installed(ctx, namespace)
+	if err != nil {
+		return err
+	}
+
+	return u.repositories.GetArrows().Run(ctx, current, action)
+}
+
+func runnable(action runtime.Action) error {
+	switch action {
+	case "", runtime.ActionInstall, runtime.ActionUninstall, runtime.ActionUpdate:
+		return fmt.Errorf("%w: method %q cannot be run directly", ErrUnsupportedAction, action)
+	}
+
+	return nil
+}
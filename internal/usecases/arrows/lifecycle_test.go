@@ -0,0 +1,48 @@
+package arrows
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rabbytesoftware/quiver/internal/infrastructure"
+	"github.com/rabbytesoftware/quiver/internal/models/runtime"
+	"github.com/rabbytesoftware/quiver/internal/repositories"
+)
+
+func TestRunnable(t *testing.T) {
+	for _, action := range []runtime.Action{"", runtime.ActionInstall, runtime.ActionUninstall, runtime.ActionUpdate} {
+		if err := runnable(action); !errors.Is(err, ErrUnsupportedAction) {
+			t.Errorf("Expected %q to be rejected, got %v", action, err)
+		}
+	}
+
+	for _, action := range []runtime.Action{runtime.ActionExecute, runtime.ActionValidate, "rotate_map"} {
+		if err := runnable(action); err != nil {
+			t.Errorf("Expected %q to be runnable, got %v", action, err)
+		}
+	}
+}
+
+func TestArrowsUsecase_Lifecycle_NotInstalled(t *testing.T) {
+	t.Setenv("QUIVER_DATABASE_PATH", t.TempDir())
+	usecase := NewArrowsUsecase(repositories.NewRepositories(infrastructure.NewInfrastructure()))
+	ctx := context.Background()
+
+	operations := map[string]func() error{
+		"start":   func() error { return usecase.Start(ctx, "cs2") },
+		"stop":    func() error { return usecase.Stop(ctx, "cs2") },
+		"restart": func() error { return usecase.Restart(ctx, "cs2") },
+		"method":  func() error { return usecase.RunMethod(ctx, "cs2", "rotate_map") },
+	}
+
+	for name, operation := range operations {
+		if err := operation(); !errors.Is(err, ErrNotInstalled) {
+			t.Errorf("Expected %s to return ErrNotInstalled, got %v", name, err)
+		}
+	}
+
+	if err := usecase.RunMethod(ctx, "cs2", "install"); !errors.Is(err, ErrUnsupportedAction) {
+		t.Errorf("Expected install to be rejected before the lookup, got %v", err)
+	}
+}
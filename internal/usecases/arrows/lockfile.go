@@ -0,0 +1,218 @@
+package arrows
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/lockfile"
+	"github.com/rabbytesoftware/quiver/internal/models/runtime"
+)
+
+var ErrLockfileMismatch = errors.New("lockfile does not match upstream")
+
+type manifestFetcher interface {
+	FetchManifest(ctx context.Context, manifestURL string) (*arrow.Arrow, error)
+}
+
+// ExportLockfile describes every installed arrow, dependencies first.
+func (u *ArrowsUsecase) ExportLockfile(ctx context.Context) (*lockfile.Lockfile, error) {
+	installed, err := u.repositories.GetArrows().Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ordered, err := installOrder(installed)
+	if err != nil {
+		return nil, err
+	}
+
+	return lockfile.New(ordered, time.Now()), nil
+}
+
+// ImportLockfile installs exactly what a lockfile describes. Every
+// manifest is checked against upstream, and every new arrow against
+// the host, before anything is installed, so a mismatch there leaves
+// the host untouched. Artifacts are checked as the install downloads
+// them.
+func (u *ArrowsUsecase) ImportLockfile(
+	ctx context.Context,
+	lock *lockfile.Lockfile,
+) (*Plan, error) {
+	verified, err := verifyLockfile(ctx, u.repositories.GetQuivers(), lock)
+	if err != nil {
+		return nil, err
+	}
+
+	all, err := u.repositories.GetArrows().Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	installed := map[string]arrow.Arrow{}
+	for _, a := range all {
+		installed[a.Name] = a
+	}
+
+	plan := &Plan{}
+	var pending []*arrow.Arrow
+	for _, a := range verified {
+		if current, ok := installed[a.Name]; ok {
+			if current.Version != a.Version {
+				return plan, fmt.Errorf(
+					"%w: lockfile pins %s but %s@%s is installed",
+					ErrDependencyConflict,
+					a.Namespace,
+					current.Name,
+					current.Version,
+				)
+			}
+			continue
+		}
+
+		pending = append(pending, a)
+	}
+
+	if plan.Warnings, err = u.admit(ctx, pending); err != nil {
+		return plan, err
+	}
+
+	for _, a := range verified {
+		if current, ok := installed[a.Name]; ok {
+			plan.Steps = append(plan.Steps, PlanStep{Arrow: &current, Installed: true})
+			continue
+		}
+
+		// ? Artifacts are hashed while the install downloads them, so
+		// ? they are only fetched once.
+		locked := a.Artifacts
+		verify := func() error {
+			if err := compareArtifacts(locked, a.Artifacts); err != nil {
+				return fmt.Errorf("%w: %s: %s", ErrLockfileMismatch, a.Namespace, err)
+			}
+			return nil
+		}
+
+		if err := u.install(ctx, a, runtime.ActionInstall, verify); err != nil {
+			return plan, err
+		}
+
+		plan.Steps = append(plan.Steps, PlanStep{Arrow: a})
+	}
+
+	return plan, nil
+}
+
+// verifyLockfile fetches the locked manifests, applies the locked
+// variables and ports, and compares manifest hashes. All mismatches
+// are reported together. The locked artifacts are kept on each arrow
+// for the install to compare with.
+func verifyLockfile(
+	ctx context.Context,
+	manifests manifestFetcher,
+	lock *lockfile.Lockfile,
+) ([]*arrow.Arrow, error) {
+	var (
+		verified []*arrow.Arrow
+		errs     []error
+	)
+
+	for _, entry := range lock.Arrows {
+		a, err := verifyEntry(ctx, manifests, entry)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		verified = append(verified, a)
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return verified, nil
+}
+
+func verifyEntry(
+	ctx context.Context,
+	manifests manifestFetcher,
+	entry lockfile.Entry,
+) (*arrow.Arrow, error) {
+	a, err := manifests.FetchManifest(ctx, entry.ManifestURL.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch manifest of %s: %w", entry.Namespace, err)
+	}
+
+	if a.Name != entry.Name || a.Version != entry.Version {
+		return nil, fmt.Errorf(
+			"%w: %s now points to %s@%s",
+			ErrLockfileMismatch,
+			entry.ManifestURL,
+			a.Name,
+			a.Version,
+		)
+	}
+
+	if entry.ManifestHash != "" && a.ManifestHash != entry.ManifestHash {
+		return nil, fmt.Errorf("%w: manifest of %s has changed", ErrLockfileMismatch, entry.Namespace)
+	}
+
+	a.Source = entry.Source
+	entry.Apply(a)
+
+	a.Artifacts = entry.Artifacts
+
+	return a, nil
+}
+
+func compareArtifacts(locked, current []arrow.Artifact) error {
+	checksums := map[string]string{}
+	for _, artifact := range current {
+		checksums[artifact.URL] = artifact.SHA256
+	}
+
+	for _, artifact := range locked {
+		checksum, ok := checksums[artifact.URL]
+		if !ok {
+			return fmt.Errorf("artifact %s is no longer downloaded", artifact.URL)
+		}
+		if checksum != artifact.SHA256 {
+			return fmt.Errorf("artifact %s has checksum %s, expected %s", artifact.URL, checksum, artifact.SHA256)
+		}
+		delete(checksums, artifact.URL)
+	}
+
+	for _, artifact := range current {
+		if _, ok := checksums[artifact.URL]; ok {
+			return fmt.Errorf("artifact %s is not in the lockfile", artifact.URL)
+		}
+	}
+
+	return nil
+}
+
+// installOrder sorts installed arrows so dependencies come first.
+func installOrder(installed []arrow.Arrow) ([]arrow.Arrow, error) {
+	selected := selections{}
+	requested := make([]arrow.ArrowNamespace, 0, len(installed))
+
+	for i := range installed {
+		selected[installed[i].Name] = &selection{arrow: &installed[i]}
+		requested = append(requested, installed[i].Namespace)
+	}
+
+	plan, err := order(requested, selected)
+	if err != nil {
+		return nil, err
+	}
+
+	ordered := make([]arrow.Arrow, 0, len(plan.Steps))
+	for _, step := range plan.Steps {
+		ordered = append(ordered, *step.Arrow)
+	}
+
+	return ordered, nil
+}
@@ -0,0 +1,182 @@
+package arrows
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rabbytesoftware/quiver/internal/infrastructure"
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/lockfile"
+	"github.com/rabbytesoftware/quiver/internal/models/system"
+	"github.com/rabbytesoftware/quiver/internal/models/variable"
+	"github.com/rabbytesoftware/quiver/internal/repositories"
+)
+
+type fakeUpstream struct {
+	manifests map[string]*arrow.Arrow
+}
+
+func (f *fakeUpstream) FetchManifest(ctx context.Context, manifestURL string) (*arrow.Arrow, error) {
+	a, ok := f.manifests[manifestURL]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+
+	copied := *a
+	copied.Variables = append([]variable.Variable{}, a.Variables...)
+
+	return &copied, nil
+}
+
+func lockedArrow(name, version, hash string) *arrow.Arrow {
+	a := newArrow(name, version)
+	a.ManifestURL = system.URL("https://quiver.ar/arrows/" + name + ".yaml")
+	a.ManifestHash = hash
+	a.Variables = []variable.Variable{{Name: "MAX_PLAYERS", Default: "12"}}
+
+	return a
+}
+
+func newUpstream(arrows ...*arrow.Arrow) *fakeUpstream {
+	upstream := &fakeUpstream{manifests: map[string]*arrow.Arrow{}}
+
+	for _, a := range arrows {
+		upstream.manifests[a.ManifestURL.String()] = a
+	}
+
+	return upstream
+}
+
+func lockFor(arrows ...*arrow.Arrow) *lockfile.Lockfile {
+	lock := &lockfile.Lockfile{Lockfile: lockfile.LockfileV1}
+	for _, a := range arrows {
+		locked := *a
+		locked.Artifacts = []arrow.Artifact{{URL: "https://cdn/" + a.Name, SHA256: "sum-" + a.Name}}
+		lock.Arrows = append(lock.Arrows, lockfile.NewEntry(&locked))
+	}
+
+	return lock
+}
+
+func TestVerifyLockfile(t *testing.T) {
+	steamcmd := lockedArrow("steamcmd", "1.0.0", "hash-a")
+	cs2 := lockedArrow("cs2", "2.0.0", "hash-b")
+
+	lock := lockFor(steamcmd, cs2)
+	lock.Arrows[1].Variables["MAX_PLAYERS"] = "32"
+
+	upstream := newUpstream(steamcmd, cs2)
+
+	verified, err := verifyLockfile(context.Background(), upstream, lock)
+	if err != nil {
+		t.Fatalf("verifyLockfile() returned error: %v", err)
+	}
+	if len(verified) != 2 || verified[0].Name != "steamcmd" || verified[1].Name != "cs2" {
+		t.Fatalf("Expected steamcmd then cs2, got %v", verified)
+	}
+	if verified[1].Variables[0].Current() != "32" {
+		t.Error("Expected the locked variables to be applied")
+	}
+	if len(verified[1].Artifacts) != 1 || verified[1].Artifacts[0].SHA256 != "sum-cs2" {
+		t.Errorf("Expected the locked artifacts to be kept for the install, got %v", verified[1].Artifacts)
+	}
+}
+
+func TestVerifyLockfile_Mismatches(t *testing.T) {
+	testCases := []struct {
+		name   string
+		mutate func(upstream *fakeUpstream)
+	}{
+		{"manifest changed", func(u *fakeUpstream) {
+			u.manifests["https://quiver.ar/arrows/cs2.yaml"].ManifestHash = "other"
+		}},
+		{"version changed", func(u *fakeUpstream) {
+			u.manifests["https://quiver.ar/arrows/cs2.yaml"].Version = "2.0.1"
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cs2 := lockedArrow("cs2", "2.0.0", "hash-b")
+			lock := lockFor(cs2)
+			upstream := newUpstream(lockedArrow("cs2", "2.0.0", "hash-b"))
+			tc.mutate(upstream)
+
+			_, err := verifyLockfile(context.Background(), upstream, lock)
+			if !errors.Is(err, ErrLockfileMismatch) {
+				t.Errorf("Expected ErrLockfileMismatch, got %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifyLockfile_ReportsEveryEntry(t *testing.T) {
+	lock := lockFor(lockedArrow("a", "1.0.0", "h"), lockedArrow("b", "1.0.0", "h"))
+
+	_, err := verifyLockfile(context.Background(), newUpstream(), lock)
+	if err == nil {
+		t.Fatal("verifyLockfile() should fail when manifests are gone")
+	}
+	if !strings.Contains(err.Error(), "a@1.0.0") || !strings.Contains(err.Error(), "b@1.0.0") {
+		t.Errorf("Expected both entries to be reported, got %v", err)
+	}
+}
+
+func TestCompareArtifacts(t *testing.T) {
+	locked := []arrow.Artifact{{URL: "https://cdn/cs2", SHA256: "sum-cs2"}}
+
+	testCases := []struct {
+		name       string
+		downloaded []arrow.Artifact
+		valid      bool
+	}{
+		{"same", []arrow.Artifact{{URL: "https://cdn/cs2", SHA256: "sum-cs2"}}, true},
+		{"changed", []arrow.Artifact{{URL: "https://cdn/cs2", SHA256: "tampered"}}, false},
+		{"removed", nil, false},
+		{"added", []arrow.Artifact{{URL: "https://cdn/cs2", SHA256: "sum-cs2"}, {URL: "https://cdn/extra", SHA256: "x"}}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := compareArtifacts(locked, tc.downloaded); (err == nil) != tc.valid {
+				t.Errorf("compareArtifacts() error = %v, valid = %v", err, tc.valid)
+			}
+		})
+	}
+}
+
+func TestInstallOrder(t *testing.T) {
+	installed := []arrow.Arrow{
+		*newArrow("cs2", "1.0.0", "metamod@^1.0.0", "steamcmd"),
+		*newArrow("metamod", "1.0.0", "steamcmd"),
+		*newArrow("steamcmd", "1.0.0"),
+	}
+
+	ordered, err := installOrder(installed)
+	if err != nil {
+		t.Fatalf("installOrder() returned error: %v", err)
+	}
+
+	var names []string
+	for _, a := range ordered {
+		names = append(names, a.Name)
+	}
+	if strings.Join(names, ",") != "steamcmd,metamod,cs2" {
+		t.Errorf("Expected steamcmd,metamod,cs2, got %v", names)
+	}
+}
+
+func TestArrowsUsecase_ExportLockfile_Empty(t *testing.T) {
+	t.Setenv("QUIVER_DATABASE_PATH", t.TempDir())
+	usecase := NewArrowsUsecase(repositories.NewRepositories(infrastructure.NewInfrastructure()))
+
+	lock, err := usecase.ExportLockfile(context.Background())
+	if err != nil {
+		t.Fatalf("ExportLockfile() returned error: %v", err)
+	}
+	if lock.Lockfile != lockfile.LockfileV1 || len(lock.Arrows) != 0 {
+		t.Errorf("Expected an empty lockfile, got %+v", lock)
+	}
+}
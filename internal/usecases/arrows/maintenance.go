@@ -0,0 +1,284 @@
+package arrows
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rabbytesoftware/quiver/internal/core/watcher"
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/runtime"
+)
+
+var ErrInvalidPolicy = errors.New("invalid update policy")
+
+// maintenanceLogSize is how many maintenance results are kept.
+const maintenanceLogSize = 100
+
+type MaintenanceStatus string
+
+const (
+	MaintenanceUpdated    MaintenanceStatus = "updated"
+	MaintenanceNotified   MaintenanceStatus = "notified"
+	MaintenanceSkipped    MaintenanceStatus = "skipped"
+	MaintenanceRolledBack MaintenanceStatus = "rolled_back"
+	MaintenanceFailed     MaintenanceStatus = "failed"
+)
+
+// MaintenanceResult is what the update policy of an arrow did
+// about an available update.
+type MaintenanceResult struct {
+	Namespace arrow.ArrowNamespace `json:"namespace"`
+	Status    MaintenanceStatus    `json:"status"`
+	From      string               `json:"from"`
+	To        string               `json:"to"`
+	Reason    string               `json:"reason,omitempty"`
+	At        time.Time            `json:"at"`
+}
+
+type instance interface {
+	Start(ctx context.Context, arrow *arrow.Arrow) error
+	Stop(ctx context.Context, arrow *arrow.Arrow) error
+	Running(arrow *arrow.Arrow) bool
+	Probe(ctx context.Context, arrow *arrow.Arrow) (string, error)
+}
+
+// SetUpdatePolicy changes how an installed arrow is kept up to date.
+func (u *ArrowsUsecase) SetUpdatePolicy(
+	ctx context.Context,
+	namespace arrow.ArrowNamespace,
+	policy arrow.UpdatePolicy,
+) (*arrow.Arrow, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPolicy, err)
+	}
+
+	current, err := u.installed(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	current.UpdatePolicy = policy.Effective()
+
+	return u.repositories.GetArrows().Update(ctx, current)
+}
+
+// Maintenance returns the latest results of the update
+// policies, oldest first.
+func (u *ArrowsUsecase) Maintenance() []MaintenanceResult {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	return append([]MaintenanceResult{}, u.maintenance...)
+}
+
+// RunMaintenance applies the update policy of every installed arrow
+// to the updates found by the last outdated check.
+func (u *ArrowsUsecase) RunMaintenance(
+	ctx context.Context,
+	now time.Time,
+) []MaintenanceResult {
+	updates := map[string]Update{}
+	for _, update := range u.Outdated(ctx, false).Updates {
+		updates[update.Installed.Name] = update
+	}
+
+	installed, err := u.repositories.GetArrows().Get(ctx)
+	if err != nil {
+		if watcher.GetWatcher() != nil {
+			watcher.Warn(fmt.Sprintf("Failed to list installed arrows for maintenance: %s", err))
+		}
+		return nil
+	}
+
+	var results []MaintenanceResult
+
+	for _, current := range installed {
+		update, ok := updates[current.Name]
+		if !ok || update.Installed.Version != current.Version {
+			continue
+		}
+
+		result, ok := maintain(
+			ctx,
+			u.repositories.GetArrows(),
+			u.Update,
+			u.Rollback,
+			&current,
+			&update,
+			now,
+		)
+		if !ok || !u.logMaintenance(result) {
+			continue
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// WatchMaintenance runs the update policies on every interval
+// until ctx is done.
+func (u *ArrowsUsecase) WatchMaintenance(
+	ctx context.Context,
+	interval time.Duration,
+) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			u.RunMaintenance(ctx, now)
+		}
+	}
+}
+
+// logMaintenance keeps a result unless it repeats the last one
+// recorded for the same arrow, so a skipped update in a window or
+// a pending notification is only reported once.
+func (u *ArrowsUsecase) logMaintenance(result MaintenanceResult) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	for i := len(u.maintenance) - 1; i >= 0; i-- {
+		last := u.maintenance[i]
+		if last.Namespace.Name() != result.Namespace.Name() {
+			continue
+		}
+
+		if last.Status == result.Status && last.To == result.To && last.Reason == result.Reason {
+			return false
+		}
+		break
+	}
+
+	u.maintenance = append(u.maintenance, result)
+	if len(u.maintenance) > maintenanceLogSize {
+		u.maintenance = u.maintenance[len(u.maintenance)-maintenanceLogSize:]
+	}
+
+	return true
+}
+
+// maintain applies the update policy of an arrow. Automatic updates
+// only run inside the maintenance window and when the validate probe
+// reports no connected players: the instance is stopped, updated,
+// validated, rolled back if validation fails, and started again if
+// it was running. The second return value is false when the policy
+// had nothing to do.
+func maintain(
+	ctx context.Context,
+	inst instance,
+	update func(context.Context, arrow.ArrowNamespace) (*Plan, error),
+	rollback func(context.Context, arrow.ArrowNamespace) (*arrow.Arrow, error),
+	current *arrow.Arrow,
+	available *Update,
+	now time.Time,
+) (MaintenanceResult, bool) {
+	policy := current.UpdatePolicy.Effective()
+
+	result := MaintenanceResult{
+		Namespace: current.Namespace,
+		From:      current.Version,
+		To:        available.Latest.Version,
+		At:        now,
+	}
+
+	switch {
+	case policy.Mode == arrow.UpdateManual:
+		return result, false
+	case policy.Mode == arrow.UpdateNotify:
+		result.Status = MaintenanceNotified
+		return result, true
+	case available.Compatible == nil:
+		result.Status = MaintenanceNotified
+		result.Reason = "major update requires a manual update"
+		return result, true
+	case !policy.InWindow(now):
+		return result, false
+	}
+
+	target := available.Compatible
+	result.To = target.Version
+
+	output, err := inst.Probe(ctx, current)
+	if err != nil {
+		result.Status = MaintenanceSkipped
+		result.Reason = "validate probe failed: " + err.Error()
+		return result, true
+	}
+
+	if players, ok := runtime.ParsePlayers(output); ok && players > 0 {
+		result.Status = MaintenanceSkipped
+		result.Reason = fmt.Sprintf("%d players connected", players)
+		return result, true
+	}
+
+	running := inst.Running(current)
+	if running {
+		if err := inst.Stop(ctx, current); err != nil {
+			result.Status = MaintenanceFailed
+			result.Reason = err.Error()
+			return result, true
+		}
+	}
+
+	restart := func(a *arrow.Arrow) {
+		if !running {
+			return
+		}
+
+		if err := inst.Start(ctx, a); err != nil {
+			result.Reason = joinReason(result.Reason, err.Error())
+		}
+	}
+
+	namespace := arrow.ArrowNamespace(current.Name + "@" + target.Version)
+
+	plan, err := update(ctx, namespace)
+	if err != nil {
+		result.Status = MaintenanceFailed
+		result.Reason = err.Error()
+		restart(current)
+		return result, true
+	}
+
+	updated := target
+	for _, step := range plan.Steps {
+		if step.Arrow.Name == current.Name {
+			updated = step.Arrow
+		}
+	}
+
+	if _, err := inst.Probe(ctx, updated); err != nil {
+		result.Status = MaintenanceRolledBack
+		result.Reason = "validation failed: " + err.Error()
+
+		restored, rollbackErr := rollback(ctx, updated.Namespace)
+		if rollbackErr != nil {
+			result.Status = MaintenanceFailed
+			result.Reason = joinReason(result.Reason, "rollback failed: "+rollbackErr.Error())
+			return result, true
+		}
+
+		restart(restored)
+		return result, true
+	}
+
+	result.Status = MaintenanceUpdated
+	restart(updated)
+
+	return result, true
+}
+
+func joinReason(reason, extra string) string {
+	if reason == "" {
+		return extra
+	}
+	return reason + "; " + extra
+}
@@ -0,0 +1,246 @@
+package arrows
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rabbytesoftware/quiver/internal/infrastructure"
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/repositories"
+)
+
+type fakeInstance struct {
+	running  bool
+	output   string
+	probeErr map[string]error
+	calls    []string
+}
+
+func (f *fakeInstance) Start(ctx context.Context, a *arrow.Arrow) error {
+	f.calls = append(f.calls, "start "+a.Version)
+	f.running = true
+	return nil
+}
+
+func (f *fakeInstance) Stop(ctx context.Context, a *arrow.Arrow) error {
+	f.calls = append(f.calls, "stop "+a.Version)
+	f.running = false
+	return nil
+}
+
+func (f *fakeInstance) Running(a *arrow.Arrow) bool {
+	return f.running
+}
+
+func (f *fakeInstance) Probe(ctx context.Context, a *arrow.Arrow) (string, error) {
+	f.calls = append(f.calls, "probe "+a.Version)
+	return f.output, f.probeErr[a.Version]
+}
+
+type fakeMaintainer struct {
+	updated    []arrow.ArrowNamespace
+	rolledBack bool
+	updateErr  error
+}
+
+func (f *fakeMaintainer) update(ctx context.Context, namespace arrow.ArrowNamespace) (*Plan, error) {
+	if f.updateErr != nil {
+		return nil, f.updateErr
+	}
+
+	f.updated = append(f.updated, namespace)
+	v, _ := namespace.Constraint()
+	return &Plan{Steps: []PlanStep{{Arrow: newArrow("cs2", strings.TrimPrefix(v.String(), "="))}}}, nil
+}
+
+func (f *fakeMaintainer) rollback(ctx context.Context, namespace arrow.ArrowNamespace) (*arrow.Arrow, error) {
+	f.rolledBack = true
+	return newArrow("cs2", "1.0.0"), nil
+}
+
+func maintained(mode arrow.UpdateMode) (*arrow.Arrow, *Update) {
+	current := newArrow("cs2", "1.0.0")
+	current.UpdatePolicy = arrow.UpdatePolicy{Mode: mode, Window: "* 4-5 * * *"}
+
+	return current, &Update{
+		Installed:  current,
+		Latest:     newArrow("cs2", "2.0.0"),
+		Compatible: newArrow("cs2", "1.2.0"),
+	}
+}
+
+var (
+	inWindow  = time.Date(2025, 7, 1, 4, 30, 0, 0, time.UTC)
+	outWindow = time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+)
+
+func runMaintain(inst *fakeInstance, m *fakeMaintainer, current *arrow.Arrow, update *Update, now time.Time) (MaintenanceResult, bool) {
+	return maintain(context.Background(), inst, m.update, m.rollback, current, update, now)
+}
+
+func TestMaintain_Policies(t *testing.T) {
+	testCases := []struct {
+		name   string
+		mode   arrow.UpdateMode
+		now    time.Time
+		acted  bool
+		status MaintenanceStatus
+	}{
+		{"manual", arrow.UpdateManual, inWindow, false, ""},
+		{"default is manual", "", inWindow, false, ""},
+		{"notify", arrow.UpdateNotify, outWindow, true, MaintenanceNotified},
+		{"automatic outside window", arrow.UpdateAutomatic, outWindow, false, ""},
+		{"automatic inside window", arrow.UpdateAutomatic, inWindow, true, MaintenanceUpdated},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			inst, m := &fakeInstance{}, &fakeMaintainer{}
+			current, update := maintained(tc.mode)
+
+			result, acted := runMaintain(inst, m, current, update, tc.now)
+			if acted != tc.acted || result.Status != tc.status {
+				t.Errorf("Expected (%v, %q), got (%v, %q)", tc.acted, tc.status, acted, result.Status)
+			}
+
+			if tc.status != MaintenanceUpdated && len(m.updated) != 0 {
+				t.Errorf("Expected no update, got %v", m.updated)
+			}
+		})
+	}
+}
+
+func TestMaintain_AutomaticUpdate(t *testing.T) {
+	inst, m := &fakeInstance{running: true}, &fakeMaintainer{}
+	current, update := maintained(arrow.UpdateAutomatic)
+
+	result, _ := runMaintain(inst, m, current, update, inWindow)
+
+	if result.Status != MaintenanceUpdated || result.From != "1.0.0" || result.To != "1.2.0" {
+		t.Errorf("Expected an update from 1.0.0 to 1.2.0, got %+v", result)
+	}
+	if len(m.updated) != 1 || m.updated[0] != "cs2@1.2.0" {
+		t.Errorf("Expected cs2@1.2.0 to be installed, got %v", m.updated)
+	}
+
+	expected := []string{"probe 1.0.0", "stop 1.0.0", "probe 1.2.0", "start 1.2.0"}
+	if strings.Join(inst.calls, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected calls %v, got %v", expected, inst.calls)
+	}
+}
+
+func TestMaintain_NotRunningStaysStopped(t *testing.T) {
+	inst, m := &fakeInstance{}, &fakeMaintainer{}
+	current, update := maintained(arrow.UpdateAutomatic)
+
+	runMaintain(inst, m, current, update, inWindow)
+
+	for _, call := range inst.calls {
+		if strings.HasPrefix(call, "start") || strings.HasPrefix(call, "stop") {
+			t.Errorf("Expected a stopped instance to stay stopped, got %v", inst.calls)
+		}
+	}
+}
+
+func TestMaintain_PlayersConnected(t *testing.T) {
+	inst, m := &fakeInstance{running: true, output: "players: 4"}, &fakeMaintainer{}
+	current, update := maintained(arrow.UpdateAutomatic)
+
+	result, acted := runMaintain(inst, m, current, update, inWindow)
+
+	if !acted || result.Status != MaintenanceSkipped || result.Reason != "4 players connected" {
+		t.Errorf("Expected the update to be skipped, got %+v", result)
+	}
+	if len(m.updated) != 0 || !inst.running {
+		t.Error("Expected the instance to be left alone")
+	}
+}
+
+func TestMaintain_ValidationFailureRollsBack(t *testing.T) {
+	inst := &fakeInstance{running: true, probeErr: map[string]error{"1.2.0": errors.New("unhealthy")}}
+	m := &fakeMaintainer{}
+	current, update := maintained(arrow.UpdateAutomatic)
+
+	result, _ := runMaintain(inst, m, current, update, inWindow)
+
+	if result.Status != MaintenanceRolledBack || !m.rolledBack {
+		t.Errorf("Expected a rollback, got %+v", result)
+	}
+	if last := inst.calls[len(inst.calls)-1]; last != "start 1.0.0" {
+		t.Errorf("Expected the restored version to be started, got %v", inst.calls)
+	}
+}
+
+func TestMaintain_UpdateFailureRestarts(t *testing.T) {
+	inst := &fakeInstance{running: true}
+	m := &fakeMaintainer{updateErr: errors.New("download failed")}
+	current, update := maintained(arrow.UpdateAutomatic)
+
+	result, _ := runMaintain(inst, m, current, update, inWindow)
+
+	if result.Status != MaintenanceFailed || result.Reason != "download failed" {
+		t.Errorf("Expected a failure, got %+v", result)
+	}
+	if !inst.running {
+		t.Error("Expected the instance to be started again")
+	}
+}
+
+func TestMaintain_MajorUpdateOnlyNotifies(t *testing.T) {
+	inst, m := &fakeInstance{}, &fakeMaintainer{}
+	current, update := maintained(arrow.UpdateAutomatic)
+	update.Compatible = nil
+
+	result, acted := runMaintain(inst, m, current, update, inWindow)
+
+	if !acted || result.Status != MaintenanceNotified || result.To != "2.0.0" {
+		t.Errorf("Expected a notification for 2.0.0, got %+v", result)
+	}
+	if len(m.updated) != 0 {
+		t.Errorf("Expected no update, got %v", m.updated)
+	}
+}
+
+func TestArrowsUsecase_LogMaintenance(t *testing.T) {
+	usecase := NewArrowsUsecase(nil)
+	result := MaintenanceResult{Namespace: "cs2@1.0.0", Status: MaintenanceSkipped, To: "1.2.0", Reason: "4 players connected"}
+
+	if !usecase.logMaintenance(result) {
+		t.Error("Expected the first result to be logged")
+	}
+	if usecase.logMaintenance(result) {
+		t.Error("Expected a repeated result to be dropped")
+	}
+
+	result.Status = MaintenanceUpdated
+	result.Reason = ""
+	if !usecase.logMaintenance(result) {
+		t.Error("Expected a new result to be logged")
+	}
+
+	for i := 0; i < maintenanceLogSize+10; i++ {
+		usecase.logMaintenance(MaintenanceResult{Namespace: arrow.ArrowNamespace("a@1.0.0"), Reason: strings.Repeat("x", i)})
+	}
+	if len(usecase.Maintenance()) != maintenanceLogSize {
+		t.Errorf("Expected the log to be capped at %d, got %d", maintenanceLogSize, len(usecase.Maintenance()))
+	}
+}
+
+func TestArrowsUsecase_SetUpdatePolicy(t *testing.T) {
+	t.Setenv("QUIVER_DATABASE_PATH", t.TempDir())
+	usecase := NewArrowsUsecase(repositories.NewRepositories(infrastructure.NewInfrastructure()))
+	ctx := context.Background()
+
+	_, err := usecase.SetUpdatePolicy(ctx, "cs2", arrow.UpdatePolicy{Mode: arrow.UpdateAutomatic})
+	if !errors.Is(err, ErrInvalidPolicy) {
+		t.Errorf("Expected ErrInvalidPolicy, got %v", err)
+	}
+
+	_, err = usecase.SetUpdatePolicy(ctx, "cs2", arrow.UpdatePolicy{Mode: arrow.UpdateNotify})
+	if !errors.Is(err, ErrNotInstalled) {
+		t.Errorf("Expected ErrNotInstalled, got %v", err)
+	}
+}
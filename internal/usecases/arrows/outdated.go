@@ -0,0 +1,120 @@
+package arrows
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+)
+
+// OutdatedReport lists installed arrows with newer releases
+// as of the last time the quivers were checked.
+type OutdatedReport struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Updates   []Update  `json:"updates"`
+	Errors    []string  `json:"errors"`
+}
+
+// Outdated returns the last report, checking the quivers first when
+// refresh is set or when no check has run yet.
+func (u *ArrowsUsecase) Outdated(
+	ctx context.Context,
+	refresh bool,
+) *OutdatedReport {
+	if !refresh {
+		u.mu.RLock()
+		report := u.outdated
+		u.mu.RUnlock()
+
+		if report != nil {
+			return report
+		}
+	}
+
+	return u.CheckOutdated(ctx)
+}
+
+// CheckOutdated compares every installed arrow with the releases
+// currently listed in the configured quivers and caches the result.
+func (u *ArrowsUsecase) CheckOutdated(ctx context.Context) *OutdatedReport {
+	installed, err := u.repositories.GetArrows().Get(ctx)
+	report := checkOutdated(ctx, installed, u.CheckUpdate)
+	if err != nil {
+		report.Errors = append(report.Errors, err.Error())
+	}
+
+	u.mu.Lock()
+	u.outdated = report
+	u.mu.Unlock()
+
+	return report
+}
+
+// WatchUpdates checks for updates right away and then on every
+// interval until ctx is done. A zero interval disables the checks.
+func (u *ArrowsUsecase) WatchUpdates(
+	ctx context.Context,
+	interval time.Duration,
+) {
+	if interval <= 0 {
+		return
+	}
+
+	u.CheckOutdated(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			u.CheckOutdated(ctx)
+		}
+	}
+}
+
+// UpdateCheckInterval parses the configured interval, falling back
+// to six hours when it is empty or invalid.
+func UpdateCheckInterval(value string) time.Duration {
+	const fallback = 6 * time.Hour
+
+	if value == "" {
+		return fallback
+	}
+
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		return fallback
+	}
+
+	return interval
+}
+
+func checkOutdated(
+	ctx context.Context,
+	installed []arrow.Arrow,
+	check func(context.Context, *arrow.Arrow) (*Update, error),
+) *OutdatedReport {
+	report := &OutdatedReport{
+		CheckedAt: time.Now(),
+		Updates:   []Update{},
+		Errors:    []string{},
+	}
+
+	for i := range installed {
+		update, err := check(ctx, &installed[i])
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %s", installed[i].Namespace, err))
+			continue
+		}
+
+		if update != nil {
+			report.Updates = append(report.Updates, *update)
+		}
+	}
+
+	return report
+}
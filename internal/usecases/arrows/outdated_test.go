@@ -0,0 +1,122 @@
+package arrows
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rabbytesoftware/quiver/internal/infrastructure"
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/repositories"
+)
+
+func TestCheckOutdated(t *testing.T) {
+	installed := []arrow.Arrow{
+		*newArrow("cs2", "1.0.0"),
+		*newArrow("steamcmd", "1.0.0"),
+		*newArrow("broken", "1.0.0"),
+	}
+
+	report := checkOutdated(context.Background(), installed, func(ctx context.Context, a *arrow.Arrow) (*Update, error) {
+		switch a.Name {
+		case "cs2":
+			return &Update{Installed: a, Latest: newArrow("cs2", "1.1.0")}, nil
+		case "broken":
+			return nil, errors.New("quiver unreachable")
+		}
+		return nil, nil
+	})
+
+	if len(report.Updates) != 1 || report.Updates[0].Latest.Version != "1.1.0" {
+		t.Errorf("Expected one update to cs2 1.1.0, got %+v", report.Updates)
+	}
+	if len(report.Errors) != 1 || report.Errors[0] != "broken@1.0.0: quiver unreachable" {
+		t.Errorf("Expected the broken arrow to be reported, got %v", report.Errors)
+	}
+	if report.CheckedAt.IsZero() {
+		t.Error("Expected CheckedAt to be set")
+	}
+}
+
+func TestFindUpdate_Changelog(t *testing.T) {
+	latest := newArrow("cs2", "2.0.0")
+	compatible := newArrow("cs2", "1.1.0")
+	compatible.Changelog = "https://example.com/cs2/CHANGELOG.md"
+
+	update, err := findUpdate(newArrow("cs2", "1.0.0"), []*arrow.Arrow{latest, compatible})
+	if err != nil {
+		t.Fatalf("findUpdate() returned error: %v", err)
+	}
+	if update.Changelog != compatible.Changelog {
+		t.Errorf("Expected the compatible release changelog, got %q", update.Changelog)
+	}
+
+	latest.Changelog = "https://example.com/cs2/2.0.0.md"
+	update, _ = findUpdate(newArrow("cs2", "1.0.0"), []*arrow.Arrow{latest, compatible})
+	if update.Changelog != latest.Changelog {
+		t.Errorf("Expected the latest release changelog, got %q", update.Changelog)
+	}
+}
+
+func TestArrowsUsecase_Outdated_Caches(t *testing.T) {
+	t.Setenv("QUIVER_DATABASE_PATH", t.TempDir())
+	usecase := NewArrowsUsecase(repositories.NewRepositories(infrastructure.NewInfrastructure()))
+	ctx := context.Background()
+
+	first := usecase.Outdated(ctx, false)
+	if first == nil || len(first.Updates) != 0 {
+		t.Fatalf("Expected an empty report, got %+v", first)
+	}
+
+	if usecase.Outdated(ctx, false) != first {
+		t.Error("Outdated() should return the cached report")
+	}
+	if usecase.Outdated(ctx, true) == first {
+		t.Error("Outdated() should check again when asked to refresh")
+	}
+}
+
+func TestArrowsUsecase_WatchUpdates(t *testing.T) {
+	t.Setenv("QUIVER_DATABASE_PATH", t.TempDir())
+	usecase := NewArrowsUsecase(repositories.NewRepositories(infrastructure.NewInfrastructure()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		usecase.WatchUpdates(ctx, time.Millisecond)
+		close(done)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("WatchUpdates() did not stop after cancellation")
+	}
+
+	if usecase.Outdated(context.Background(), false).CheckedAt.IsZero() {
+		t.Error("WatchUpdates() should have produced a report")
+	}
+
+	// A zero interval returns immediately without checking.
+	usecase.WatchUpdates(context.Background(), 0)
+}
+
+func TestUpdateCheckInterval(t *testing.T) {
+	testCases := map[string]time.Duration{
+		"":      6 * time.Hour,
+		"30m":   30 * time.Minute,
+		"0":     0,
+		"-1h":   6 * time.Hour,
+		"often": 6 * time.Hour,
+	}
+
+	for value, expected := range testCases {
+		if got := UpdateCheckInterval(value); got != expected {
+			t.Errorf("UpdateCheckInterval(%q) = %v, expected %v", value, got, expected)
+		}
+	}
+}
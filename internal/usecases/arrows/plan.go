@@ -0,0 +1,33 @@
+package arrows
+
+import (
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+)
+
+// PlanStep is a single arrow within an install plan.
+type PlanStep struct {
+	Arrow      *arrow.Arrow           `json:"arrow"`
+	RequiredBy []arrow.ArrowNamespace `json:"required_by"`
+	Installed  bool                   `json:"installed"`
+}
+
+// Plan lists arrows in install order: every arrow appears
+// after all of the arrows it depends on. Warnings name the
+// requirements that are below the recommended tier.
+type Plan struct {
+	Steps    []PlanStep `json:"steps"`
+	Warnings []string   `json:"warnings,omitempty"`
+}
+
+// Pending returns the steps that still have to be installed.
+func (p *Plan) Pending() []PlanStep {
+	var pending []PlanStep
+
+	for _, step := range p.Steps {
+		if !step.Installed {
+			pending = append(pending, step)
+		}
+	}
+
+	return pending
+}
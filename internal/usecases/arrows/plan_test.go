@@ -0,0 +1,21 @@
+package arrows
+
+import "testing"
+
+func TestPlan_Pending(t *testing.T) {
+	plan := &Plan{
+		Steps: []PlanStep{
+			{Arrow: newArrow("steamcmd", "1.0.0"), Installed: true},
+			{Arrow: newArrow("cs2", "0.0.1")},
+		},
+	}
+
+	pending := plan.Pending()
+	if len(pending) != 1 || pending[0].Arrow.Name != "cs2" {
+		t.Errorf("Expected only cs2 to be pending, got %v", pending)
+	}
+
+	if len((&Plan{}).Pending()) != 0 {
+		t.Error("Expected an empty plan to have nothing pending")
+	}
+}
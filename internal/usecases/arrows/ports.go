@@ -0,0 +1,106 @@
+package arrows
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rabbytesoftware/quiver/internal/core/watcher"
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+)
+
+type forwarder interface {
+	Get(ctx context.Context) ([]arrow.Arrow, error)
+	Update(ctx context.Context, arrow *arrow.Arrow) (*arrow.Arrow, error)
+	Running(arrow *arrow.Arrow) bool
+	ReconcilePorts(ctx context.Context) error
+	RefreshPorts(ctx context.Context, arrow *arrow.Arrow) bool
+	CheckPorts(ctx context.Context, arrow *arrow.Arrow) bool
+}
+
+// ReconcilePorts renews the port mappings of running arrows, brings
+// back the ones the gateway lost, tests whether they can be reached
+// from the internet and stores the resulting statuses.
+func (u *ArrowsUsecase) ReconcilePorts(ctx context.Context) error {
+	return reconcilePorts(ctx, u.repositories.GetArrows())
+}
+
+func reconcilePorts(ctx context.Context, f forwarder) error {
+	errs := []error{f.ReconcilePorts(ctx)}
+
+	installed, err := f.Get(ctx)
+	if err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+
+	for _, a := range installed {
+		if !f.Running(&a) {
+			continue
+		}
+
+		refreshed := f.RefreshPorts(ctx, &a)
+		checked := f.CheckPorts(ctx, &a)
+
+		if refreshed || checked {
+			if _, err := f.Update(ctx, &a); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// WatchPorts reconciles port mappings every interval until ctx is
+// done.
+func (u *ArrowsUsecase) WatchPorts(
+	ctx context.Context,
+	interval time.Duration,
+) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := u.ReconcilePorts(ctx); err != nil && watcher.GetWatcher() != nil {
+				watcher.Warn(fmt.Sprintf("Failed to keep ports forwarded: %s", err))
+			}
+		}
+	}
+}
+
+// ClosePorts removes every port mapping from the gateway, so none
+// outlives Quiver.
+func (u *ArrowsUsecase) ClosePorts(ctx context.Context) error {
+	return u.repositories.GetArrows().CloseAllPorts(ctx)
+}
+
+// openPorts forwards the ports of an arrow that was started. A
+// failure is logged rather than returned, as the instance still
+// runs for local players and Reconcile retries the forward.
+func (u *ArrowsUsecase) openPorts(ctx context.Context, a *arrow.Arrow) {
+	err := u.repositories.GetArrows().OpenPorts(ctx, a)
+	if _, updateErr := u.repositories.GetArrows().Update(ctx, a); updateErr != nil {
+		err = errors.Join(err, updateErr)
+	}
+
+	if err != nil && watcher.GetWatcher() != nil {
+		watcher.Warn(fmt.Sprintf("Failed to forward the ports of %s: %s", a.Namespace, err))
+	}
+}
+
+// closePorts removes the port mappings of an arrow that stopped.
+func (u *ArrowsUsecase) closePorts(ctx context.Context, a *arrow.Arrow) {
+	err := u.repositories.GetArrows().ClosePorts(ctx, a)
+	if _, updateErr := u.repositories.GetArrows().Update(ctx, a); updateErr != nil {
+		err = errors.Join(err, updateErr)
+	}
+
+	if err != nil && watcher.GetWatcher() != nil {
+		watcher.Warn(fmt.Sprintf("Failed to close the ports of %s: %s", a.Namespace, err))
+	}
+}
@@ -0,0 +1,93 @@
+package arrows
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+)
+
+type fakeForwarder struct {
+	arrows       []arrow.Arrow
+	running      map[string]bool
+	unreachable  map[string]bool
+	reconcileErr error
+	reconciled   int
+	updated      []string
+}
+
+func (f *fakeForwarder) Get(ctx context.Context) ([]arrow.Arrow, error) {
+	return f.arrows, nil
+}
+
+func (f *fakeForwarder) Update(ctx context.Context, a *arrow.Arrow) (*arrow.Arrow, error) {
+	f.updated = append(f.updated, a.Name)
+	return a, nil
+}
+
+func (f *fakeForwarder) Running(a *arrow.Arrow) bool {
+	return f.running[a.Name]
+}
+
+func (f *fakeForwarder) ReconcilePorts(ctx context.Context) error {
+	f.reconciled++
+	return f.reconcileErr
+}
+
+// RefreshPorts marks every port enabled, as if the mappings were
+// renewed.
+func (f *fakeForwarder) RefreshPorts(ctx context.Context, a *arrow.Arrow) bool {
+	changed := false
+	for i := range a.Netbridge {
+		if !a.Netbridge[i].ForwardingStatus.IsEnabled() {
+			a.Netbridge[i].ForwardingStatus = port.ForwardingStatusEnabled
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+// CheckPorts diagnoses the ports of unreachable arrows.
+func (f *fakeForwarder) CheckPorts(ctx context.Context, a *arrow.Arrow) bool {
+	changed := false
+	for i := range a.Netbridge {
+		if f.unreachable[a.Name] && !a.Netbridge[i].Reachability.IsUnreachable() {
+			a.Netbridge[i].Reachability = port.ReachabilityUnreachable
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+func TestReconcilePorts(t *testing.T) {
+	rules := func(status port.ForwardingStatus) []port.PortRule {
+		return []port.PortRule{{Name: "GAME_PORT", StartPort: 40128, EndPort: 40128, ForwardingStatus: status}}
+	}
+
+	f := &fakeForwarder{
+		arrows: []arrow.Arrow{
+			{Name: "cs2", Netbridge: rules(port.ForwardingStatusError)},
+			{Name: "minecraft", Netbridge: rules(port.ForwardingStatusEnabled)},
+			{Name: "stopped", Netbridge: rules(port.ForwardingStatusError)},
+			{Name: "valheim", Netbridge: rules(port.ForwardingStatusEnabled)},
+		},
+		running:      map[string]bool{"cs2": true, "minecraft": true, "valheim": true},
+		unreachable:  map[string]bool{"valheim": true},
+		reconcileErr: errors.New("gateway busy"),
+	}
+
+	if err := reconcilePorts(context.Background(), f); !errors.Is(err, f.reconcileErr) {
+		t.Errorf("Expected the reconcile error to be returned, got %v", err)
+	}
+
+	if f.reconciled != 1 {
+		t.Errorf("Expected one reconcile, got %d", f.reconciled)
+	}
+	if len(f.updated) != 2 || f.updated[0] != "cs2" || f.updated[1] != "valheim" {
+		t.Errorf("Expected only the running arrows whose status changed to be saved, got %v", f.updated)
+	}
+}
@@ -0,0 +1,368 @@
+package arrows
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+	reqs "github.com/rabbytesoftware/quiver/internal/models/requirement"
+	"github.com/rabbytesoftware/quiver/internal/models/runtime"
+	"github.com/rabbytesoftware/quiver/internal/models/system"
+)
+
+var (
+	ErrNotInstalled      = errors.New("arrow is not installed")
+	ErrUnsupportedAction = errors.New("unsupported action")
+)
+
+// Download is an artifact fetched by a GET step. Size is -1 when
+// the server does not report it.
+type Download struct {
+	URL   string `json:"url"`
+	Size  int64  `json:"size"`
+	Error string `json:"error,omitempty"`
+}
+
+// PreviewStep is what running one action on one arrow would do.
+type PreviewStep struct {
+	Arrow        *arrow.Arrow           `json:"arrow"`
+	RequiredBy   []arrow.ArrowNamespace `json:"required_by"`
+	Action       runtime.Action         `json:"action"`
+	Steps        []string               `json:"steps"`
+	Downloads    []Download             `json:"downloads"`
+	Ports        []port.PortRule        `json:"ports"`
+	Requirements []reqs.Check           `json:"requirements"`
+	Error        string                 `json:"error,omitempty"`
+}
+
+// Preview is the dry-run result of an install, update or uninstall.
+// Disk sizes are in MB and DownloadSize in bytes, counting only the
+// downloads whose size is known.
+type Preview struct {
+	Action        runtime.Action  `json:"action"`
+	Steps         []PreviewStep   `json:"steps"`
+	DownloadSize  int64           `json:"download_size"`
+	DiskRequired  int             `json:"disk_required"`
+	DiskAvailable int             `json:"disk_available"`
+	Ports         []port.PortRule `json:"ports"`
+	Warnings      []string        `json:"warnings"`
+	Ready         bool            `json:"ready"`
+}
+
+// inspector is the part of the arrows repository a preview reads from.
+type inspector interface {
+	requirementsChecker
+	Steps(arrow *arrow.Arrow, action runtime.Action) ([]string, error)
+	ArtifactSize(ctx context.Context, url string) (int64, error)
+	AvailableDisk(ctx context.Context) (int, error)
+	PreviewPorts(ctx context.Context, arrow *arrow.Arrow, reserved []port.Range) ([]port.PortRule, error)
+}
+
+// Preview resolves what an action would do to an arrow and its
+// dependencies without running anything or touching the database.
+func (u *ArrowsUsecase) Preview(
+	ctx context.Context,
+	namespace arrow.ArrowNamespace,
+	action runtime.Action,
+) (*Preview, error) {
+	var steps []PreviewStep
+
+	switch action {
+	case runtime.ActionInstall:
+		plan, err := u.Resolve(ctx, namespace)
+		if err != nil {
+			return nil, err
+		}
+		steps = planSteps(plan, nil)
+
+	case runtime.ActionUpdate:
+		plan, installed, err := u.planUpdate(ctx, namespace)
+		if err != nil {
+			return nil, err
+		}
+		steps = planSteps(plan, installed)
+
+	case runtime.ActionUninstall:
+		installed, err := u.installed(ctx, namespace)
+		if err != nil {
+			return nil, err
+		}
+		all, err := u.repositories.GetArrows().Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		steps = []PreviewStep{{
+			Arrow:      installed,
+			RequiredBy: dependents(installed.Name, all),
+			Action:     runtime.ActionUninstall,
+		}}
+
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedAction, action)
+	}
+
+	all, err := u.repositories.GetArrows().Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return inspect(ctx, u.repositories.GetArrows(), action, steps, all), nil
+}
+
+// planUpdate resolves the newest release matching namespace while
+// letting the installed version of that arrow be replaced.
+func (u *ArrowsUsecase) planUpdate(
+	ctx context.Context,
+	namespace arrow.ArrowNamespace,
+) (*Plan, *arrow.Arrow, error) {
+	current, err := u.installed(ctx, namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	all, err := u.repositories.GetArrows().Get(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var others []arrow.Arrow
+	for _, a := range all {
+		if a.Name != current.Name {
+			others = append(others, a)
+		}
+	}
+
+	plan, err := NewResolver(u.repositories.GetQuivers()).Resolve(
+		ctx,
+		[]arrow.ArrowNamespace{namespace},
+		others,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// ? Resolving to the version already installed means there is
+	// ? nothing to update, so the plan is left empty.
+	for _, step := range plan.Steps {
+		if step.Arrow.Name == current.Name && step.Arrow.Version == current.Version {
+			return &Plan{}, current, nil
+		}
+	}
+
+	return plan, current, nil
+}
+
+func (u *ArrowsUsecase) installed(
+	ctx context.Context,
+	namespace arrow.ArrowNamespace,
+) (*arrow.Arrow, error) {
+	all, err := u.repositories.GetArrows().Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range all {
+		if a.Name == namespace.Name() {
+			return &a, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrNotInstalled, namespace.Name())
+}
+
+// planSteps turns the pending part of a plan into preview steps. The
+// arrow being updated uses its update method when it declares one.
+func planSteps(plan *Plan, updating *arrow.Arrow) []PreviewStep {
+	var steps []PreviewStep
+
+	for _, step := range plan.Pending() {
+		action := runtime.ActionInstall
+		if updating != nil && step.Arrow.Name == updating.Name && hasMethod(step.Arrow, runtime.ActionUpdate) {
+			action = runtime.ActionUpdate
+		}
+
+		steps = append(steps, PreviewStep{
+			Arrow:      step.Arrow,
+			RequiredBy: step.RequiredBy,
+			Action:     action,
+		})
+	}
+
+	return steps
+}
+
+// inspect fills in the steps, downloads, ports and requirement checks
+// of every preview step and sums up what the whole action needs.
+// Requirements are checked against what installed arrows leave free.
+func inspect(
+	ctx context.Context,
+	repository inspector,
+	action runtime.Action,
+	steps []PreviewStep,
+	installed []arrow.Arrow,
+) *Preview {
+	preview := &Preview{
+		Action:   action,
+		Steps:    steps,
+		Ports:    []port.PortRule{},
+		Warnings: []string{},
+		Ready:    true,
+	}
+
+	var pending []*arrow.Arrow
+	for _, step := range steps {
+		if step.Action != runtime.ActionUninstall {
+			pending = append(pending, step.Arrow)
+		}
+	}
+	reports := checkRequirements(ctx, repository, pending, installed)
+
+	// ? Ports handed to earlier steps are not recorded yet, so later
+	// ? steps are told to leave them alone.
+	var reserved []port.Range
+	for i := range preview.Steps {
+		step := &preview.Steps[i]
+
+		commands, err := repository.Steps(step.Arrow, step.Action)
+		if err != nil {
+			step.Error = err.Error()
+			preview.Ready = false
+		}
+		step.Steps = commands
+
+		if step.Action == runtime.ActionUninstall {
+			if len(step.RequiredBy) > 0 {
+				preview.Warnings = append(preview.Warnings, fmt.Sprintf(
+					"%s is required by %s",
+					step.Arrow.Namespace,
+					joinNamespaces(step.RequiredBy),
+				))
+			}
+			continue
+		}
+
+		step.Downloads = downloads(ctx, repository, commands)
+		for _, download := range step.Downloads {
+			switch {
+			case download.Error != "":
+				preview.Warnings = append(preview.Warnings, fmt.Sprintf("could not size %s: %s", download.URL, download.Error))
+			case download.Size < 0:
+				preview.Warnings = append(preview.Warnings, fmt.Sprintf("size of %s is unknown", download.URL))
+			default:
+				preview.DownloadSize += download.Size
+			}
+		}
+
+		ports, err := repository.PreviewPorts(ctx, step.Arrow, reserved)
+		if err != nil {
+			preview.Ready = false
+			preview.Warnings = append(preview.Warnings, fmt.Sprintf(
+				"could not assign ports to %s: %s",
+				step.Arrow.Namespace,
+				err,
+			))
+			ports = step.Arrow.Netbridge
+		} else {
+			for _, rule := range ports {
+				reserved = append(reserved, rule.Range())
+			}
+		}
+		step.Ports = ports
+		preview.Ports = append(preview.Ports, ports...)
+
+		report := reports[0]
+		reports = reports[1:]
+		if report != nil {
+			step.Requirements = report.Checks
+			preview.Ready = preview.Ready && report.Passed
+			preview.Warnings = append(preview.Warnings, requirementsWarnings(
+				[]*arrow.Arrow{step.Arrow},
+				[]*reqs.Report{report},
+			)...)
+		}
+
+		preview.DiskRequired += step.Arrow.Requirements.Disk
+	}
+
+	available, err := repository.AvailableDisk(ctx)
+	if err != nil {
+		preview.DiskAvailable = -1
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("could not read free disk space: %s", err))
+	} else {
+		preview.DiskAvailable = available
+		if available < preview.DiskRequired {
+			preview.Ready = false
+			preview.Warnings = append(preview.Warnings, fmt.Sprintf(
+				"%d MB of disk space needed but only %d MB available",
+				preview.DiskRequired,
+				available,
+			))
+		}
+	}
+
+	return preview
+}
+
+func downloads(
+	ctx context.Context,
+	repository inspector,
+	commands []string,
+) []Download {
+	result := []Download{}
+
+	for _, command := range commands {
+		url, ok := runtime.ParseStep(command).URL()
+		if !ok {
+			continue
+		}
+
+		download := Download{URL: url, Size: -1}
+		if size, err := repository.ArtifactSize(ctx, url); err != nil {
+			download.Error = err.Error()
+		} else {
+			download.Size = size
+		}
+
+		result = append(result, download)
+	}
+
+	return result
+}
+
+// dependents lists the installed arrows that depend on name.
+func dependents(name string, installed []arrow.Arrow) []arrow.ArrowNamespace {
+	var result []arrow.ArrowNamespace
+
+	for _, a := range installed {
+		for _, dependency := range a.Dependencies {
+			if dependency.Name() == name {
+				result = append(result, a.Namespace)
+				break
+			}
+		}
+	}
+
+	return result
+}
+
+func hasMethod(a *arrow.Arrow, action runtime.Action) bool {
+	for _, method := range a.Methods {
+		if method.OS == system.CurrentOS() && method.Action == action {
+			return true
+		}
+	}
+
+	return false
+}
+
+func joinNamespaces(namespaces []arrow.ArrowNamespace) string {
+	names := make([]string, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		names = append(names, namespace.String())
+	}
+
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,277 @@
+package arrows
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/rabbytesoftware/quiver/internal/infrastructure"
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+	reqs "github.com/rabbytesoftware/quiver/internal/models/requirement"
+	"github.com/rabbytesoftware/quiver/internal/models/runtime"
+	"github.com/rabbytesoftware/quiver/internal/models/system"
+	"github.com/rabbytesoftware/quiver/internal/repositories"
+)
+
+type fakeInspector struct {
+	steps     map[string][]string
+	sizes     map[string]int64
+	failing   map[string]bool
+	available int
+	diskErr   error
+	committed []reqs.Tier
+	portsErr  error
+	reserved  [][]port.Range
+}
+
+func (f *fakeInspector) Steps(a *arrow.Arrow, action runtime.Action) ([]string, error) {
+	steps, ok := f.steps[a.Name+"/"+action.String()]
+	if !ok {
+		return nil, errors.New("no method")
+	}
+
+	return steps, nil
+}
+
+func (f *fakeInspector) ArtifactSize(ctx context.Context, url string) (int64, error) {
+	size, ok := f.sizes[url]
+	if !ok {
+		return -1, errors.New("not found")
+	}
+
+	return size, nil
+}
+
+func (f *fakeInspector) CheckRequirements(
+	ctx context.Context,
+	requirements *reqs.Requirement,
+	committed reqs.Tier,
+) *reqs.Report {
+	f.committed = append(f.committed, committed)
+	return reqs.NewReport([]reqs.Check{{Name: "cpu_cores", Passed: !f.failing["cpu_cores"]}})
+}
+
+func (f *fakeInspector) AvailableDisk(ctx context.Context) (int, error) {
+	return f.available, f.diskErr
+}
+
+// PreviewPorts hands out ports from 27015 up, skipping reserved ones.
+func (f *fakeInspector) PreviewPorts(
+	ctx context.Context,
+	a *arrow.Arrow,
+	reserved []port.Range,
+) ([]port.PortRule, error) {
+	f.reserved = append(f.reserved, reserved)
+	if f.portsErr != nil {
+		return nil, f.portsErr
+	}
+
+	ports := append([]port.PortRule{}, a.Netbridge...)
+	next := 27015 + len(reserved)
+	for i := range ports {
+		ports[i].StartPort, ports[i].EndPort = next, next
+		next++
+	}
+
+	return ports, nil
+}
+
+func previewArrow(name string, disk int, ports ...string) *arrow.Arrow {
+	a := newArrow(name, "1.0.0")
+	a.Requirements.Disk = disk
+	for _, p := range ports {
+		a.Netbridge = append(a.Netbridge, port.PortRule{Name: p, Protocol: port.ProtocolTCP})
+	}
+
+	return a
+}
+
+func TestInspect(t *testing.T) {
+	repository := &fakeInspector{
+		steps: map[string][]string{
+			"steamcmd/install": {"GET: https://example.com/steamcmd.tar.gz", "UNCOMPRESS: steamcmd.tar.gz"},
+			"cs2/install":      {"GET: https://example.com/cs2.tar.gz", "./cs2 +rcon_password ********"},
+		},
+		sizes: map[string]int64{
+			"https://example.com/steamcmd.tar.gz": 1024,
+			"https://example.com/cs2.tar.gz":      30 << 30,
+		},
+		available: 100000,
+	}
+
+	preview := inspect(context.Background(), repository, runtime.ActionInstall, []PreviewStep{
+		{Arrow: previewArrow("steamcmd", 100), Action: runtime.ActionInstall},
+		{Arrow: previewArrow("cs2", 40000, "GAME_PORT", "RCON_PORT"), Action: runtime.ActionInstall},
+	}, nil)
+
+	if !preview.Ready {
+		t.Errorf("Expected the preview to be ready, got warnings %v", preview.Warnings)
+	}
+	if preview.DownloadSize != 1024+30<<30 {
+		t.Errorf("Expected download size %d, got %d", 1024+30<<30, preview.DownloadSize)
+	}
+	if preview.DiskRequired != 40100 || preview.DiskAvailable != 100000 {
+		t.Errorf("Expected 40100 of 100000 MB, got %d of %d", preview.DiskRequired, preview.DiskAvailable)
+	}
+	if len(preview.Ports) != 2 || preview.Ports[0].Name != "GAME_PORT" {
+		t.Errorf("Expected the cs2 ports, got %v", preview.Ports)
+	}
+	if len(preview.Steps[1].Steps) != 2 || !strings.Contains(preview.Steps[1].Steps[1], "********") {
+		t.Errorf("Expected masked steps, got %v", preview.Steps[1].Steps)
+	}
+	if len(preview.Steps[0].Downloads) != 1 || preview.Steps[0].Downloads[0].Size != 1024 {
+		t.Errorf("Expected one sized download, got %v", preview.Steps[0].Downloads)
+	}
+	if len(repository.committed) != 2 || repository.committed[1].Disk != 100 {
+		t.Errorf("Expected steamcmd to be committed when checking cs2, got %+v", repository.committed)
+	}
+}
+
+func TestInspect_NotReady(t *testing.T) {
+	repository := &fakeInspector{
+		steps: map[string][]string{
+			"cs2/install": {"GET: https://example.com/missing.tar.gz"},
+		},
+		failing:   map[string]bool{"cpu_cores": true},
+		available: 10,
+	}
+
+	preview := inspect(context.Background(), repository, runtime.ActionInstall, []PreviewStep{
+		{Arrow: previewArrow("cs2", 40000), Action: runtime.ActionInstall},
+		{Arrow: previewArrow("mystery", 0), Action: runtime.ActionInstall},
+	}, nil)
+
+	if preview.Ready {
+		t.Error("Expected the preview not to be ready")
+	}
+	if preview.Steps[1].Error == "" {
+		t.Error("Expected an error for an arrow without a method")
+	}
+	if preview.Steps[0].Downloads[0].Error == "" || preview.Steps[0].Downloads[0].Size != -1 {
+		t.Errorf("Expected an unsized download, got %+v", preview.Steps[0].Downloads[0])
+	}
+	if len(preview.Warnings) != 2 {
+		t.Errorf("Expected download and disk warnings, got %v", preview.Warnings)
+	}
+}
+
+func TestInspect_Ports(t *testing.T) {
+	repository := &fakeInspector{available: 100}
+
+	preview := inspect(context.Background(), repository, runtime.ActionInstall, []PreviewStep{
+		{Arrow: previewArrow("steamcmd", 0, "QUERY_PORT"), Action: runtime.ActionInstall},
+		{Arrow: previewArrow("cs2", 0, "GAME_PORT", "RCON_PORT"), Action: runtime.ActionInstall},
+	}, nil)
+
+	if len(preview.Ports) != 3 || preview.Ports[0].StartPort != 27015 || preview.Ports[2].StartPort != 27017 {
+		t.Errorf("Expected the ports an install would assign, got %+v", preview.Ports)
+	}
+	if preview.Steps[1].Ports[0].StartPort != 27016 {
+		t.Errorf("Expected the step to list its assigned ports, got %+v", preview.Steps[1].Ports)
+	}
+	if len(repository.reserved) != 2 || len(repository.reserved[1]) != 1 || repository.reserved[1][0].Start != 27015 {
+		t.Errorf("Expected ports of earlier steps to be reserved, got %v", repository.reserved)
+	}
+}
+
+func TestInspect_PortsExhausted(t *testing.T) {
+	repository := &fakeInspector{available: 100, portsErr: errors.New("no free port")}
+
+	preview := inspect(context.Background(), repository, runtime.ActionInstall, []PreviewStep{
+		{Arrow: previewArrow("cs2", 0, "GAME_PORT"), Action: runtime.ActionInstall},
+	}, nil)
+
+	if preview.Ready {
+		t.Error("Expected the preview not to be ready")
+	}
+	if !slices.ContainsFunc(preview.Warnings, func(w string) bool { return strings.Contains(w, "no free port") }) {
+		t.Errorf("Expected a port warning, got %v", preview.Warnings)
+	}
+}
+
+func TestInspect_UnknownDisk(t *testing.T) {
+	repository := &fakeInspector{diskErr: errors.New("unsupported")}
+
+	preview := inspect(context.Background(), repository, runtime.ActionInstall, nil, nil)
+
+	if preview.DiskAvailable != -1 || len(preview.Warnings) != 1 {
+		t.Errorf("Expected an unknown disk warning, got %d and %v", preview.DiskAvailable, preview.Warnings)
+	}
+}
+
+func TestInspect_Uninstall(t *testing.T) {
+	repository := &fakeInspector{
+		steps: map[string][]string{
+			"steamcmd/uninstall": {"REMOVE: /arrows/steamcmd"},
+		},
+	}
+
+	preview := inspect(context.Background(), repository, runtime.ActionUninstall, []PreviewStep{{
+		Arrow:      previewArrow("steamcmd", 100, "QUERY_PORT"),
+		Action:     runtime.ActionUninstall,
+		RequiredBy: []arrow.ArrowNamespace{"cs2@1.0.0"},
+	}}, nil)
+
+	if preview.DiskRequired != 0 || len(preview.Ports) != 0 {
+		t.Errorf("Uninstalling should not need disk or ports, got %d and %v", preview.DiskRequired, preview.Ports)
+	}
+	if len(preview.Warnings) != 1 || !strings.Contains(preview.Warnings[0], "cs2@1.0.0") {
+		t.Errorf("Expected a warning about dependents, got %v", preview.Warnings)
+	}
+}
+
+func TestPlanSteps(t *testing.T) {
+	updated := newArrow("cs2", "2.0.0", "steamcmd@^1.0.0")
+	updated.Methods = []runtime.Method{{OS: system.CurrentOS(), Action: runtime.ActionUpdate}}
+
+	plan := &Plan{Steps: []PlanStep{
+		{Arrow: newArrow("steamcmd", "1.0.0"), Installed: true},
+		{Arrow: newArrow("metamod", "1.0.0")},
+		{Arrow: updated},
+	}}
+
+	steps := planSteps(plan, newArrow("cs2", "1.0.0"))
+	if len(steps) != 2 {
+		t.Fatalf("Expected 2 pending steps, got %d", len(steps))
+	}
+	if steps[0].Action != runtime.ActionInstall || steps[1].Action != runtime.ActionUpdate {
+		t.Errorf("Expected install then update, got %s and %s", steps[0].Action, steps[1].Action)
+	}
+
+	steps = planSteps(&Plan{Steps: []PlanStep{{Arrow: newArrow("cs2", "2.0.0")}}}, newArrow("cs2", "1.0.0"))
+	if steps[0].Action != runtime.ActionInstall {
+		t.Errorf("Expected install when no update method exists, got %s", steps[0].Action)
+	}
+}
+
+func TestDependents(t *testing.T) {
+	installed := []arrow.Arrow{
+		*newArrow("cs2", "1.0.0", "steamcmd@^1.0.0"),
+		*newArrow("tf2", "1.0.0", "steamcmd"),
+		*newArrow("chat", "1.0.0"),
+	}
+
+	result := dependents("steamcmd", installed)
+	if len(result) != 2 || result[0] != "cs2@1.0.0" || result[1] != "tf2@1.0.0" {
+		t.Errorf("Expected cs2 and tf2, got %v", result)
+	}
+}
+
+func TestArrowsUsecase_Preview_Errors(t *testing.T) {
+	t.Setenv("QUIVER_DATABASE_PATH", t.TempDir())
+	usecase := NewArrowsUsecase(repositories.NewRepositories(infrastructure.NewInfrastructure()))
+	ctx := context.Background()
+
+	if _, err := usecase.Preview(ctx, "cs2", runtime.ActionExecute); !errors.Is(err, ErrUnsupportedAction) {
+		t.Errorf("Expected ErrUnsupportedAction, got %v", err)
+	}
+	if _, err := usecase.Preview(ctx, "cs2", runtime.ActionUninstall); !errors.Is(err, ErrNotInstalled) {
+		t.Errorf("Expected ErrNotInstalled, got %v", err)
+	}
+	if _, err := usecase.Preview(ctx, "cs2", runtime.ActionUpdate); !errors.Is(err, ErrNotInstalled) {
+		t.Errorf("Expected ErrNotInstalled, got %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+package arrows
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+	reqs "github.com/rabbytesoftware/quiver/internal/models/requirement"
+)
+
+var ErrRequirementsNotMet = errors.New("requirements not met")
+
+// requirementsChecker is the part of the arrows repository that
+// compares requirements with the host.
+type requirementsChecker interface {
+	CheckRequirements(
+		ctx context.Context,
+		requirements *reqs.Requirement,
+		committed reqs.Tier,
+	) *reqs.Report
+}
+
+// checkRequirements evaluates arrows in install order. The minimum
+// tier of every installed arrow that is kept counts as committed,
+// running or not, and so does every arrow earlier in the list.
+// Installed arrows already take up their disk space, so only the
+// disk of the arrows about to be installed is added.
+func checkRequirements(
+	ctx context.Context,
+	checker requirementsChecker,
+	arrows []*arrow.Arrow,
+	installed []arrow.Arrow,
+) []*reqs.Report {
+	replacing := map[string]bool{}
+	for _, a := range arrows {
+		replacing[a.Name] = true
+	}
+
+	var committed reqs.Tier
+	for _, a := range installed {
+		if replacing[a.Name] {
+			continue
+		}
+
+		tier := a.Requirements.Minimum()
+		tier.Disk = 0
+		committed = committed.Add(tier)
+	}
+
+	reports := make([]*reqs.Report, len(arrows))
+	for i, a := range arrows {
+		reports[i] = checker.CheckRequirements(ctx, &a.Requirements, committed)
+		committed = committed.Add(a.Requirements.Minimum())
+	}
+
+	return reports
+}
+
+// requirementsError explains every minimum requirement that is not
+// met, or returns nil when all arrows fit on the host.
+func requirementsError(arrows []*arrow.Arrow, reports []*reqs.Report) error {
+	var problems []string
+
+	for i, report := range reports {
+		if report == nil {
+			continue
+		}
+
+		for _, check := range report.Failed() {
+			problems = append(problems, fmt.Sprintf(
+				"%s: %s requires %s, %s available",
+				arrows[i].Namespace,
+				check.Name,
+				check.Required,
+				check.Detected,
+			))
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", ErrRequirementsNotMet, strings.Join(problems, "; "))
+}
+
+// requirementsWarnings lists what is below the recommended tier or
+// could not be checked, prefixed with the arrow it belongs to.
+func requirementsWarnings(arrows []*arrow.Arrow, reports []*reqs.Report) []string {
+	var warnings []string
+
+	for i, report := range reports {
+		if report == nil {
+			continue
+		}
+
+		for _, warning := range report.Warnings {
+			warnings = append(warnings, fmt.Sprintf("%s: %s", arrows[i].Namespace, warning))
+		}
+	}
+
+	return warnings
+}
+
+// admit checks that arrows fit on the host before anything is run
+// and returns the warnings to report alongside the plan.
+func (u *ArrowsUsecase) admit(ctx context.Context, arrows []*arrow.Arrow) ([]string, error) {
+	installed, err := u.repositories.GetArrows().Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := checkRequirements(ctx, u.repositories.GetArrows(), arrows, installed)
+
+	if err := requirementsError(arrows, reports); err != nil {
+		return nil, err
+	}
+
+	return requirementsWarnings(arrows, reports), nil
+}
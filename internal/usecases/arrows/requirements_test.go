@@ -0,0 +1,121 @@
+package arrows
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+	reqs "github.com/rabbytesoftware/quiver/internal/models/requirement"
+)
+
+// fakeHost checks requirements against a host with fixed totals.
+type fakeHost struct {
+	host      *reqs.Host
+	committed []reqs.Tier
+}
+
+func (f *fakeHost) CheckRequirements(
+	ctx context.Context,
+	requirements *reqs.Requirement,
+	committed reqs.Tier,
+) *reqs.Report {
+	f.committed = append(f.committed, committed)
+	return reqs.Evaluate(f.host, requirements, committed)
+}
+
+func newFakeHost() *fakeHost {
+	return &fakeHost{host: &reqs.Host{
+		OS:              "linux/amd64",
+		CpuCores:        8,
+		MemoryTotal:     16384,
+		MemoryAvailable: 16384,
+		DiskAvailable:   100000,
+		Errors:          map[string]string{},
+	}}
+}
+
+func sizedArrow(name string, cores, memory, disk int, recommended *reqs.Tier) *arrow.Arrow {
+	a := newArrow(name, "1.0.0")
+	a.Requirements = reqs.Requirement{
+		CpuCores:    cores,
+		Memory:      memory,
+		Disk:        disk,
+		OS:          "linux/amd64",
+		Recommended: recommended,
+	}
+
+	return a
+}
+
+func TestCheckRequirements_Committed(t *testing.T) {
+	checker := newFakeHost()
+
+	installed := []arrow.Arrow{
+		*sizedArrow("minecraft", 2, 4096, 20000, nil),
+		*sizedArrow("cs2", 2, 4096, 30000, nil),
+	}
+	pending := []*arrow.Arrow{
+		sizedArrow("steamcmd", 1, 512, 1000, nil),
+		sizedArrow("cs2", 2, 8192, 30000, nil),
+	}
+
+	reports := checkRequirements(context.Background(), checker, pending, installed)
+
+	if len(reports) != 2 {
+		t.Fatalf("Expected a report per arrow, got %d", len(reports))
+	}
+
+	first := reqs.Tier{CpuCores: 2, Memory: 4096}
+	if checker.committed[0] != first {
+		t.Errorf("Expected only the kept installed arrow without its disk, got %+v", checker.committed[0])
+	}
+	second := reqs.Tier{CpuCores: 3, Memory: 4608, Disk: 1000}
+	if checker.committed[1] != second {
+		t.Errorf("Expected the earlier step to be committed too, got %+v", checker.committed[1])
+	}
+	if !reports[0].Passed || !reports[1].Passed {
+		t.Errorf("Expected both arrows to fit, got %+v and %+v", reports[0], reports[1])
+	}
+}
+
+func TestRequirementsError(t *testing.T) {
+	checker := newFakeHost()
+
+	installed := []arrow.Arrow{*sizedArrow("minecraft", 2, 12288, 20000, nil)}
+	pending := []*arrow.Arrow{sizedArrow("cs2", 2, 8192, 30000, nil)}
+
+	err := requirementsError(pending, checkRequirements(context.Background(), checker, pending, installed))
+	if !errors.Is(err, ErrRequirementsNotMet) {
+		t.Fatalf("Expected memory committed to minecraft to block cs2, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "cs2@1.0.0: memory_mb requires 8192, 4096 available") {
+		t.Errorf("Expected the error to explain the shortfall, got %q", err)
+	}
+
+	if err := requirementsError(pending, checkRequirements(context.Background(), checker, pending, nil)); err != nil {
+		t.Errorf("Expected cs2 to fit on an empty host, got %v", err)
+	}
+	if err := requirementsError(pending, []*reqs.Report{nil}); err != nil {
+		t.Errorf("Expected arrows without a report to be admitted, got %v", err)
+	}
+}
+
+func TestRequirementsWarnings(t *testing.T) {
+	checker := newFakeHost()
+
+	pending := []*arrow.Arrow{
+		sizedArrow("cs2", 2, 4096, 30000, &reqs.Tier{CpuCores: 16, Memory: 8192, Disk: 50000}),
+	}
+	reports := checkRequirements(context.Background(), checker, pending, nil)
+
+	if err := requirementsError(pending, reports); err != nil {
+		t.Fatalf("Expected the minimum tier to be met, got %v", err)
+	}
+
+	warnings := requirementsWarnings(pending, reports)
+	if len(warnings) != 1 || !strings.HasPrefix(warnings[0], "cs2@1.0.0: cpu_cores") {
+		t.Errorf("Expected a warning for the cores below the recommended tier, got %v", warnings)
+	}
+}
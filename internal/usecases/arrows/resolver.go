@@ -0,0 +1,310 @@
+package arrows
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/version"
+)
+
+var (
+	ErrArrowNotFound      = errors.New("arrow not found")
+	ErrDependencyConflict = errors.New("dependency conflict")
+	ErrDependencyCycle    = errors.New("dependency cycle")
+)
+
+// ManifestSource provides every published version of an arrow.
+type ManifestSource interface {
+	FindArrow(ctx context.Context, name string) ([]*arrow.Arrow, error)
+}
+
+// Resolver turns requested arrows into an ordered install plan.
+// It walks the dependency graph, picking the newest version of every
+// arrow that satisfies all constraints and backtracking to older
+// versions when a later constraint cannot be met.
+type Resolver struct {
+	source     ManifestSource
+	candidates map[string][]*arrow.Arrow
+}
+
+func NewResolver(source ManifestSource) *Resolver {
+	return &Resolver{
+		source:     source,
+		candidates: map[string][]*arrow.Arrow{},
+	}
+}
+
+type requirement struct {
+	namespace  arrow.ArrowNamespace
+	requiredBy arrow.ArrowNamespace
+}
+
+type selection struct {
+	arrow      *arrow.Arrow
+	installed  bool
+	requiredBy []arrow.ArrowNamespace
+}
+
+type selections map[string]*selection
+
+// Resolve builds the install plan for the requested namespaces.
+// Installed arrows are pinned: they satisfy dependencies when their
+// version matches and cause a conflict when it does not.
+func (r *Resolver) Resolve(
+	ctx context.Context,
+	requested []arrow.ArrowNamespace,
+	installed []arrow.Arrow,
+) (*Plan, error) {
+	selected := selections{}
+	for i := range installed {
+		selected[installed[i].Name] = &selection{
+			arrow:     &installed[i],
+			installed: true,
+		}
+	}
+
+	pending := make([]requirement, 0, len(requested))
+	for _, namespace := range requested {
+		pending = append(pending, requirement{namespace: namespace})
+	}
+
+	solved, err := r.solve(ctx, pending, selected)
+	if err != nil {
+		return nil, err
+	}
+
+	return order(requested, solved)
+}
+
+func (r *Resolver) solve(
+	ctx context.Context,
+	pending []requirement,
+	selected selections,
+) (selections, error) {
+	if len(pending) == 0 {
+		return selected, nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	current, rest := pending[0], pending[1:]
+	name := current.namespace.Name()
+
+	constraint, err := current.namespace.Constraint()
+	if err != nil {
+		return nil, fmt.Errorf("invalid dependency %s required by %s: %w", current.namespace, describe(current.requiredBy), err)
+	}
+
+	if existing, ok := selected[name]; ok {
+		if !satisfies(existing.arrow, constraint) {
+			return nil, fmt.Errorf(
+				"%w: %s requires %s but %s@%s is %s",
+				ErrDependencyConflict,
+				describe(current.requiredBy),
+				current.namespace,
+				name,
+				existing.arrow.Version,
+				existingState(existing),
+			)
+		}
+
+		next := selected.clone()
+		next[name] = existing.withRequiredBy(current.requiredBy)
+
+		return r.solve(ctx, rest, next)
+	}
+
+	candidates, err := r.find(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	matched := false
+
+	for _, candidate := range candidates {
+		if !satisfies(candidate, constraint) {
+			continue
+		}
+		matched = true
+
+		next := selected.clone()
+		next[name] = (&selection{arrow: candidate}).withRequiredBy(current.requiredBy)
+
+		queue := append([]requirement{}, rest...)
+		for _, dependency := range candidate.Dependencies {
+			queue = append(queue, requirement{
+				namespace:  dependency,
+				requiredBy: candidate.Namespace,
+			})
+		}
+
+		solved, err := r.solve(ctx, queue, next)
+		if err == nil {
+			return solved, nil
+		}
+		if !errors.Is(err, ErrDependencyConflict) && !errors.Is(err, ErrArrowNotFound) {
+			return nil, err
+		}
+		lastErr = err
+	}
+
+	if !matched {
+		return nil, fmt.Errorf(
+			"%w: no version of %s satisfies %q required by %s",
+			ErrArrowNotFound,
+			name,
+			constraint.String(),
+			describe(current.requiredBy),
+		)
+	}
+
+	return nil, lastErr
+}
+
+// find returns the candidates for an arrow sorted newest first,
+// querying the source only once per arrow name.
+func (r *Resolver) find(
+	ctx context.Context,
+	name string,
+) ([]*arrow.Arrow, error) {
+	if candidates, ok := r.candidates[name]; ok {
+		return candidates, nil
+	}
+
+	candidates, err := r.source.FindArrow(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch manifests for %s: %w", name, err)
+	}
+
+	sorted := append([]*arrow.Arrow{}, candidates...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return version.Compare(sorted[i].Version, sorted[j].Version) > 0
+	})
+
+	r.candidates[name] = sorted
+
+	return sorted, nil
+}
+
+// order sorts the selected arrows so that dependencies come first,
+// failing when the chosen versions depend on each other in a cycle.
+func order(
+	requested []arrow.ArrowNamespace,
+	selected selections,
+) (*Plan, error) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := map[string]int{}
+	plan := &Plan{}
+	var path []string
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		current, ok := selected[name]
+		if !ok {
+			return nil
+		}
+
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			start := 0
+			for i, step := range path {
+				if step == name {
+					start = i
+				}
+			}
+			cycle := append(append([]string{}, path[start:]...), name)
+			return fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(cycle, " -> "))
+		}
+
+		state[name] = visiting
+		path = append(path, name)
+
+		// ? Installed arrows keep whatever they already depend on,
+		// ? so only freshly selected arrows are walked further.
+		if !current.installed {
+			for _, dependency := range current.arrow.Dependencies {
+				if err := visit(dependency.Name()); err != nil {
+					return err
+				}
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[name] = visited
+
+		plan.Steps = append(plan.Steps, PlanStep{
+			Arrow:      current.arrow,
+			RequiredBy: current.requiredBy,
+			Installed:  current.installed,
+		})
+
+		return nil
+	}
+
+	for _, namespace := range requested {
+		if err := visit(namespace.Name()); err != nil {
+			return nil, err
+		}
+	}
+
+	return plan, nil
+}
+
+func (s selections) clone() selections {
+	next := make(selections, len(s))
+	for name, value := range s {
+		next[name] = value
+	}
+
+	return next
+}
+
+func (s *selection) withRequiredBy(requiredBy arrow.ArrowNamespace) *selection {
+	next := *s
+	if requiredBy != "" {
+		next.requiredBy = append(append([]arrow.ArrowNamespace{}, s.requiredBy...), requiredBy)
+	}
+
+	return &next
+}
+
+// satisfies treats arrows with unparseable versions as never
+// matching, so they can not be selected by any constraint.
+func satisfies(a *arrow.Arrow, constraint version.Constraint) bool {
+	v, err := version.Parse(a.Version)
+	if err != nil {
+		return false
+	}
+
+	return constraint.Check(v)
+}
+
+func describe(requiredBy arrow.ArrowNamespace) string {
+	if requiredBy == "" {
+		return "the request"
+	}
+
+	return requiredBy.String()
+}
+
+func existingState(s *selection) string {
+	if s.installed {
+		return "installed"
+	}
+
+	return "already selected"
+}
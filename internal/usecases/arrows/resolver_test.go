@@ -0,0 +1,300 @@
+package arrows
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+)
+
+type fakeSource struct {
+	arrows  map[string][]*arrow.Arrow
+	err     error
+	queries map[string]int
+}
+
+func newFakeSource(arrows ...*arrow.Arrow) *fakeSource {
+	source := &fakeSource{
+		arrows:  map[string][]*arrow.Arrow{},
+		queries: map[string]int{},
+	}
+
+	for _, a := range arrows {
+		source.arrows[a.Name] = append(source.arrows[a.Name], a)
+	}
+
+	return source
+}
+
+func (f *fakeSource) FindArrow(ctx context.Context, name string) ([]*arrow.Arrow, error) {
+	f.queries[name]++
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return f.arrows[name], nil
+}
+
+func newArrow(name, version string, dependencies ...string) *arrow.Arrow {
+	a := &arrow.Arrow{
+		Name:      name,
+		Version:   version,
+		Namespace: arrow.NewArrowNamespace(name, version),
+	}
+
+	for _, dependency := range dependencies {
+		a.Dependencies = append(a.Dependencies, arrow.ArrowNamespace(dependency))
+	}
+
+	return a
+}
+
+func planNamespaces(plan *Plan) []string {
+	var namespaces []string
+	for _, step := range plan.Steps {
+		namespaces = append(namespaces, step.Arrow.Namespace.String())
+	}
+
+	return namespaces
+}
+
+func assertOrder(t *testing.T, plan *Plan, expected ...string) {
+	t.Helper()
+
+	got := planNamespaces(plan)
+	if strings.Join(got, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected plan %v, got %v", expected, got)
+	}
+}
+
+func TestResolver_DependenciesFirst(t *testing.T) {
+	source := newFakeSource(
+		newArrow("cs2", "0.0.1", "steamcmd"),
+		newArrow("steamcmd", "1.0.0", "steam-runtime"),
+		newArrow("steam-runtime", "3.0.0"),
+	)
+
+	plan, err := NewResolver(source).Resolve(context.Background(), []arrow.ArrowNamespace{"cs2"}, nil)
+	if err != nil {
+		t.Fatalf("Resolve() returned error: %v", err)
+	}
+
+	assertOrder(t, plan, "steam-runtime@3.0.0", "steamcmd@1.0.0", "cs2@0.0.1")
+
+	if len(plan.Steps[1].RequiredBy) != 1 || plan.Steps[1].RequiredBy[0] != "cs2@0.0.1" {
+		t.Errorf("Expected steamcmd to be required by cs2@0.0.1, got %v", plan.Steps[1].RequiredBy)
+	}
+	if len(plan.Steps[2].RequiredBy) != 0 {
+		t.Errorf("Expected the requested arrow to have no dependents, got %v", plan.Steps[2].RequiredBy)
+	}
+}
+
+func TestResolver_PicksNewestVersion(t *testing.T) {
+	source := newFakeSource(
+		newArrow("steamcmd", "1.2.0"),
+		newArrow("steamcmd", "1.10.0"),
+		newArrow("steamcmd", "1.9.0"),
+	)
+
+	plan, err := NewResolver(source).Resolve(context.Background(), []arrow.ArrowNamespace{"steamcmd"}, nil)
+	if err != nil {
+		t.Fatalf("Resolve() returned error: %v", err)
+	}
+
+	assertOrder(t, plan, "steamcmd@1.10.0")
+}
+
+func TestResolver_ExactConstraint(t *testing.T) {
+	source := newFakeSource(
+		newArrow("cs2", "0.0.1", "steamcmd@1.2.0"),
+		newArrow("steamcmd", "1.2.0"),
+		newArrow("steamcmd", "1.10.0"),
+	)
+
+	plan, err := NewResolver(source).Resolve(context.Background(), []arrow.ArrowNamespace{"cs2"}, nil)
+	if err != nil {
+		t.Fatalf("Resolve() returned error: %v", err)
+	}
+
+	assertOrder(t, plan, "steamcmd@1.2.0", "cs2@0.0.1")
+}
+
+func TestResolver_SharedDependencyOnce(t *testing.T) {
+	source := newFakeSource(
+		newArrow("app", "1.0.0", "left", "right"),
+		newArrow("left", "1.0.0", "base"),
+		newArrow("right", "1.0.0", "base"),
+		newArrow("base", "1.0.0"),
+	)
+
+	plan, err := NewResolver(source).Resolve(context.Background(), []arrow.ArrowNamespace{"app"}, nil)
+	if err != nil {
+		t.Fatalf("Resolve() returned error: %v", err)
+	}
+
+	assertOrder(t, plan, "base@1.0.0", "left@1.0.0", "right@1.0.0", "app@1.0.0")
+
+	if len(plan.Steps[0].RequiredBy) != 2 {
+		t.Errorf("Expected base to be required by 2 arrows, got %v", plan.Steps[0].RequiredBy)
+	}
+	if source.queries["base"] != 1 {
+		t.Errorf("Expected base manifests to be fetched once, got %d", source.queries["base"])
+	}
+}
+
+func TestResolver_Backtracks(t *testing.T) {
+	source := newFakeSource(
+		newArrow("a", "2.0.0", "c@1.0.0"),
+		newArrow("a", "1.0.0", "c@2.0.0"),
+		newArrow("b", "1.0.0", "c@2.0.0"),
+		newArrow("c", "1.0.0"),
+		newArrow("c", "2.0.0"),
+	)
+
+	plan, err := NewResolver(source).Resolve(context.Background(), []arrow.ArrowNamespace{"a", "b"}, nil)
+	if err != nil {
+		t.Fatalf("Resolve() returned error: %v", err)
+	}
+
+	assertOrder(t, plan, "c@2.0.0", "a@1.0.0", "b@1.0.0")
+}
+
+func TestResolver_Conflict(t *testing.T) {
+	source := newFakeSource(
+		newArrow("a", "1.0.0", "c@1.0.0"),
+		newArrow("b", "1.0.0", "c@2.0.0"),
+		newArrow("c", "1.0.0"),
+		newArrow("c", "2.0.0"),
+	)
+
+	_, err := NewResolver(source).Resolve(context.Background(), []arrow.ArrowNamespace{"a", "b"}, nil)
+	if !errors.Is(err, ErrDependencyConflict) {
+		t.Fatalf("Expected ErrDependencyConflict, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "b@1.0.0 requires c@2.0.0") {
+		t.Errorf("Expected the conflict to name both sides, got %q", err.Error())
+	}
+}
+
+func TestResolver_Cycle(t *testing.T) {
+	source := newFakeSource(
+		newArrow("a", "1.0.0", "b"),
+		newArrow("b", "1.0.0", "c"),
+		newArrow("c", "1.0.0", "a"),
+	)
+
+	_, err := NewResolver(source).Resolve(context.Background(), []arrow.ArrowNamespace{"a"}, nil)
+	if !errors.Is(err, ErrDependencyCycle) {
+		t.Fatalf("Expected ErrDependencyCycle, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "a -> b -> c -> a") {
+		t.Errorf("Expected the cycle path in the error, got %q", err.Error())
+	}
+}
+
+func TestResolver_NotFound(t *testing.T) {
+	source := newFakeSource(
+		newArrow("cs2", "0.0.1", "steamcmd@2.0.0"),
+		newArrow("steamcmd", "1.0.0"),
+	)
+
+	_, err := NewResolver(source).Resolve(context.Background(), []arrow.ArrowNamespace{"cs2"}, nil)
+	if !errors.Is(err, ErrArrowNotFound) {
+		t.Fatalf("Expected ErrArrowNotFound, got %v", err)
+	}
+
+	_, err = NewResolver(source).Resolve(context.Background(), []arrow.ArrowNamespace{"missing"}, nil)
+	if !errors.Is(err, ErrArrowNotFound) {
+		t.Fatalf("Expected ErrArrowNotFound for unknown arrows, got %v", err)
+	}
+}
+
+func TestResolver_InstalledArrowsArePinned(t *testing.T) {
+	source := newFakeSource(
+		newArrow("cs2", "0.0.1", "steamcmd"),
+		newArrow("steamcmd", "2.0.0"),
+	)
+	installed := []arrow.Arrow{*newArrow("steamcmd", "1.0.0")}
+
+	plan, err := NewResolver(source).Resolve(context.Background(), []arrow.ArrowNamespace{"cs2"}, installed)
+	if err != nil {
+		t.Fatalf("Resolve() returned error: %v", err)
+	}
+
+	assertOrder(t, plan, "steamcmd@1.0.0", "cs2@0.0.1")
+
+	if !plan.Steps[0].Installed {
+		t.Error("Expected installed steamcmd to be marked as installed")
+	}
+	if len(plan.Pending()) != 1 || plan.Pending()[0].Arrow.Name != "cs2" {
+		t.Errorf("Expected only cs2 to be pending, got %v", plan.Pending())
+	}
+	if source.queries["steamcmd"] != 0 {
+		t.Error("Expected installed arrows not to be fetched again")
+	}
+}
+
+func TestResolver_InstalledConflict(t *testing.T) {
+	source := newFakeSource(newArrow("cs2", "0.0.1", "steamcmd@2.0.0"))
+	installed := []arrow.Arrow{*newArrow("steamcmd", "1.0.0")}
+
+	_, err := NewResolver(source).Resolve(context.Background(), []arrow.ArrowNamespace{"cs2"}, installed)
+	if !errors.Is(err, ErrDependencyConflict) {
+		t.Fatalf("Expected ErrDependencyConflict, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "installed") {
+		t.Errorf("Expected the error to mention the installed version, got %q", err.Error())
+	}
+}
+
+func TestResolver_SourceError(t *testing.T) {
+	source := newFakeSource()
+	source.err = errors.New("quiver unreachable")
+
+	_, err := NewResolver(source).Resolve(context.Background(), []arrow.ArrowNamespace{"cs2"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "quiver unreachable") {
+		t.Fatalf("Expected the source error to be returned, got %v", err)
+	}
+}
+
+func TestResolver_CancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := NewResolver(newFakeSource(newArrow("cs2", "0.0.1"))).Resolve(ctx, []arrow.ArrowNamespace{"cs2"}, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestResolver_VersionRanges(t *testing.T) {
+	source := newFakeSource(
+		newArrow("server", "1.0.0", "steamcmd@^1.2.0"),
+		newArrow("steamcmd", "1.1.0"),
+		newArrow("steamcmd", "1.10.0"),
+		newArrow("steamcmd", "1.9.0"),
+		newArrow("steamcmd", "2.0.0"),
+		newArrow("steamcmd", "1.11.0-beta.1"),
+	)
+
+	plan, err := NewResolver(source).Resolve(context.Background(), []arrow.ArrowNamespace{"server@1.0.0"}, nil)
+	if err != nil {
+		t.Fatalf("Resolve() returned error: %v", err)
+	}
+
+	assertOrder(t, plan, "steamcmd@1.10.0", "server@1.0.0")
+}
+
+func TestResolver_InvalidConstraint(t *testing.T) {
+	source := newFakeSource(newArrow("server", "1.0.0"))
+
+	_, err := NewResolver(source).Resolve(context.Background(), []arrow.ArrowNamespace{"server@^one"}, nil)
+	if err == nil {
+		t.Fatal("Resolve() should reject an invalid constraint")
+	}
+	if errors.Is(err, ErrArrowNotFound) {
+		t.Errorf("Expected a constraint error, got %v", err)
+	}
+}
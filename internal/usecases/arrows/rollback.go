@@ -0,0 +1,164 @@
+package arrows
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+)
+
+var ErrNoRollback = errors.New("no previous version to roll back to")
+
+type historian interface {
+	History(ctx context.Context, arrowID uuid.UUID) ([]arrow.Revision, error)
+	SaveRevision(ctx context.Context, revision *arrow.Revision) error
+	Snapshot(ctx context.Context, arrow *arrow.Arrow) (string, error)
+	Restore(ctx context.Context, arrow *arrow.Arrow, snapshot string) error
+	RemoveSnapshot(snapshot string) error
+}
+
+// History returns the versions an installed arrow ran at, oldest first.
+func (u *ArrowsUsecase) History(
+	ctx context.Context,
+	namespace arrow.ArrowNamespace,
+) ([]arrow.Revision, error) {
+	current, err := u.installed(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.repositories.GetArrows().History(ctx, current.ID)
+}
+
+// Rollback restores the install tree, manifest and variable values
+// an arrow had before its last update.
+func (u *ArrowsUsecase) Rollback(
+	ctx context.Context,
+	namespace arrow.ArrowNamespace,
+) (*arrow.Arrow, error) {
+	current, err := u.installed(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	restored, err := rollback(ctx, u.repositories.GetArrows(), current, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.repositories.GetArrows().LinkData(ctx, restored); err != nil {
+		return nil, err
+	}
+
+	return u.repositories.GetArrows().Update(ctx, restored)
+}
+
+// rollback restores the newest revision that still has a snapshot.
+// The snapshot is consumed, so a second rollback in a row fails
+// until the arrow is updated again.
+func rollback(
+	ctx context.Context,
+	h historian,
+	current *arrow.Arrow,
+	now time.Time,
+) (*arrow.Arrow, error) {
+	history, err := h.History(ctx, current.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var target *arrow.Revision
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].HasSnapshot() {
+			target = &history[i]
+			break
+		}
+	}
+
+	if target == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoRollback, current.Name)
+	}
+
+	if err := h.Restore(ctx, current, target.Snapshot); err != nil {
+		return nil, err
+	}
+
+	restored := target.Arrow
+	restored.ID = current.ID
+	restored.UpdatePolicy = current.UpdatePolicy
+
+	if err := h.RemoveSnapshot(target.Snapshot); err != nil {
+		return nil, err
+	}
+	target.Snapshot = ""
+
+	if err := h.SaveRevision(ctx, target); err != nil {
+		return nil, err
+	}
+
+	if err := h.SaveRevision(ctx, arrow.NewRevision(&restored, arrow.RevisionRollback, now)); err != nil {
+		return nil, err
+	}
+
+	return &restored, nil
+}
+
+// keepSnapshot attaches the snapshot taken before an update to the
+// revision being replaced and drops older snapshots, so only the
+// previous version is kept on disk.
+func keepSnapshot(
+	ctx context.Context,
+	h historian,
+	previous *arrow.Arrow,
+	snapshot string,
+	now time.Time,
+) error {
+	history, err := h.History(ctx, previous.ID)
+	if err != nil {
+		return err
+	}
+
+	for i := range history {
+		if !history[i].HasSnapshot() {
+			continue
+		}
+
+		if err := h.RemoveSnapshot(history[i].Snapshot); err != nil {
+			return err
+		}
+		history[i].Snapshot = ""
+
+		if err := h.SaveRevision(ctx, &history[i]); err != nil {
+			return err
+		}
+	}
+
+	var revision *arrow.Revision
+	if len(history) > 0 {
+		revision = &history[len(history)-1]
+	} else {
+		revision = arrow.NewRevision(previous, arrow.RevisionInstall, now)
+	}
+	revision.Snapshot = snapshot
+
+	return h.SaveRevision(ctx, revision)
+}
+
+// recordRevision stores the state an arrow was left in by an action.
+func (u *ArrowsUsecase) recordRevision(
+	ctx context.Context,
+	a *arrow.Arrow,
+	action arrow.RevisionAction,
+) error {
+	revision := arrow.NewRevision(a, action, time.Now())
+
+	if err := u.repositories.GetArrows().SaveRevision(ctx, revision); err != nil {
+		return fmt.Errorf("failed to record %s of %s: %w", action, a.Namespace, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,194 @@
+package arrows
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rabbytesoftware/quiver/internal/infrastructure"
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/variable"
+	"github.com/rabbytesoftware/quiver/internal/repositories"
+)
+
+type fakeHistorian struct {
+	revisions map[uuid.UUID]arrow.Revision
+	restored  string
+	removed   []string
+	snapshots int
+}
+
+func newFakeHistorian() *fakeHistorian {
+	return &fakeHistorian{revisions: map[uuid.UUID]arrow.Revision{}}
+}
+
+func (f *fakeHistorian) History(ctx context.Context, arrowID uuid.UUID) ([]arrow.Revision, error) {
+	history := []arrow.Revision{}
+	for _, revision := range f.revisions {
+		if revision.ArrowID == arrowID {
+			history = append(history, revision)
+		}
+	}
+
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].CreatedAt.Before(history[j].CreatedAt)
+	})
+
+	return history, nil
+}
+
+func (f *fakeHistorian) SaveRevision(ctx context.Context, revision *arrow.Revision) error {
+	f.revisions[revision.ID] = *revision
+	return nil
+}
+
+func (f *fakeHistorian) Snapshot(ctx context.Context, a *arrow.Arrow) (string, error) {
+	f.snapshots++
+	return "/snapshots/" + a.Name + "/" + a.Version, nil
+}
+
+func (f *fakeHistorian) Restore(ctx context.Context, a *arrow.Arrow, snapshot string) error {
+	f.restored = snapshot
+	return nil
+}
+
+func (f *fakeHistorian) RemoveSnapshot(snapshot string) error {
+	f.removed = append(f.removed, snapshot)
+	return nil
+}
+
+func versionedArrow(id uuid.UUID, version, password string) *arrow.Arrow {
+	return &arrow.Arrow{
+		ID:        id,
+		Namespace: arrow.ArrowNamespace("cs2@" + version),
+		Name:      "cs2",
+		Version:   version,
+		Variables: []variable.Variable{{Name: "PASSWORD", Default: password}},
+	}
+}
+
+// update mimics ArrowsUsecase.Update for the history side.
+func update(t *testing.T, h *fakeHistorian, from, to *arrow.Arrow, now time.Time) {
+	t.Helper()
+
+	snapshot, _ := h.Snapshot(context.Background(), from)
+	if err := keepSnapshot(context.Background(), h, from, snapshot, now); err != nil {
+		t.Fatalf("keepSnapshot() error = %v", err)
+	}
+	h.SaveRevision(context.Background(), arrow.NewRevision(to, arrow.RevisionUpdate, now))
+}
+
+func TestRollback(t *testing.T) {
+	ctx := context.Background()
+	h := newFakeHistorian()
+	id := uuid.New()
+	start := time.Now()
+
+	v1 := versionedArrow(id, "1.0.0", "first")
+	v2 := versionedArrow(id, "2.0.0", "second")
+
+	h.SaveRevision(ctx, arrow.NewRevision(v1, arrow.RevisionInstall, start))
+	update(t, h, v1, v2, start.Add(time.Minute))
+
+	restored, err := rollback(ctx, h, v2, start.Add(2*time.Minute))
+	if err != nil {
+		t.Fatalf("rollback() error = %v", err)
+	}
+
+	if restored.Version != "1.0.0" || restored.ID != id {
+		t.Errorf("Expected cs2@1.0.0 with the same ID, got %+v", restored)
+	}
+	if restored.Variables[0].Default != "first" {
+		t.Errorf("Expected variables to be restored, got %+v", restored.Variables)
+	}
+	if h.restored != "/snapshots/cs2/1.0.0" {
+		t.Errorf("Expected the 1.0.0 snapshot to be restored, got %q", h.restored)
+	}
+
+	history, _ := h.History(ctx, id)
+	if len(history) != 3 {
+		t.Fatalf("Expected 3 revisions, got %d", len(history))
+	}
+	last := history[2]
+	if last.Action != arrow.RevisionRollback || last.Version != "1.0.0" {
+		t.Errorf("Expected a rollback revision to 1.0.0, got %+v", last)
+	}
+	for _, revision := range history {
+		if revision.HasSnapshot() {
+			t.Errorf("Expected the snapshot to be consumed, got %+v", revision)
+		}
+	}
+
+	if _, err := rollback(ctx, h, restored, start.Add(3*time.Minute)); !errors.Is(err, ErrNoRollback) {
+		t.Errorf("Expected ErrNoRollback on a second rollback, got %v", err)
+	}
+}
+
+func TestRollback_NoHistory(t *testing.T) {
+	h := newFakeHistorian()
+
+	_, err := rollback(context.Background(), h, versionedArrow(uuid.New(), "1.0.0", ""), time.Now())
+	if !errors.Is(err, ErrNoRollback) {
+		t.Errorf("Expected ErrNoRollback, got %v", err)
+	}
+}
+
+func TestKeepSnapshot_OnlyPreviousVersion(t *testing.T) {
+	ctx := context.Background()
+	h := newFakeHistorian()
+	id := uuid.New()
+	start := time.Now()
+
+	v1 := versionedArrow(id, "1.0.0", "")
+	v2 := versionedArrow(id, "2.0.0", "")
+	v3 := versionedArrow(id, "3.0.0", "")
+
+	h.SaveRevision(ctx, arrow.NewRevision(v1, arrow.RevisionInstall, start))
+	update(t, h, v1, v2, start.Add(time.Minute))
+	update(t, h, v2, v3, start.Add(2*time.Minute))
+
+	history, _ := h.History(ctx, id)
+
+	var kept []string
+	for _, revision := range history {
+		if revision.HasSnapshot() {
+			kept = append(kept, revision.Version)
+		}
+	}
+
+	if len(kept) != 1 || kept[0] != "2.0.0" {
+		t.Errorf("Expected only the 2.0.0 snapshot to be kept, got %v", kept)
+	}
+	if len(h.removed) != 1 || h.removed[0] != "/snapshots/cs2/1.0.0" {
+		t.Errorf("Expected the 1.0.0 snapshot to be removed, got %v", h.removed)
+	}
+}
+
+func TestKeepSnapshot_NoHistory(t *testing.T) {
+	ctx := context.Background()
+	h := newFakeHistorian()
+	v1 := versionedArrow(uuid.New(), "1.0.0", "")
+
+	if err := keepSnapshot(ctx, h, v1, "/snapshots/cs2/1.0.0", time.Now()); err != nil {
+		t.Fatalf("keepSnapshot() error = %v", err)
+	}
+
+	history, _ := h.History(ctx, v1.ID)
+	if len(history) != 1 || history[0].Snapshot != "/snapshots/cs2/1.0.0" {
+		t.Errorf("Expected a revision for the untracked version, got %+v", history)
+	}
+}
+
+func TestArrowsUsecase_Rollback_NotInstalled(t *testing.T) {
+	t.Setenv("QUIVER_DATABASE_PATH", t.TempDir())
+	usecase := NewArrowsUsecase(repositories.NewRepositories(infrastructure.NewInfrastructure()))
+
+	_, err := usecase.Rollback(context.Background(), arrow.ArrowNamespace("cs2"))
+	if !errors.Is(err, ErrNotInstalled) {
+		t.Errorf("Expected ErrNotInstalled, got %v", err)
+	}
+}
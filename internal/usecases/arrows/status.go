@@ -0,0 +1,67 @@
+package arrows
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+)
+
+type statusReader interface {
+	Get(ctx context.Context) ([]arrow.Arrow, error)
+	Running(arrow *arrow.Arrow) bool
+}
+
+// Status is what an installed arrow is doing: whether it runs, its
+// ports with their forwarding and reachability, and a warning for
+// each port that could not be reached.
+type Status struct {
+	Arrow    *arrow.Arrow    `json:"arrow"`
+	Running  bool            `json:"running"`
+	Ports    []port.PortRule `json:"ports"`
+	Warnings []string        `json:"warnings"`
+}
+
+// Status reports the state of an installed arrow as of the last
+// port reconcile.
+func (u *ArrowsUsecase) Status(
+	ctx context.Context,
+	namespace arrow.ArrowNamespace,
+) (*Status, error) {
+	return status(ctx, u.repositories.GetArrows(), namespace)
+}
+
+func status(
+	ctx context.Context,
+	repository statusReader,
+	namespace arrow.ArrowNamespace,
+) (*Status, error) {
+	all, err := repository.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range all {
+		a := &all[i]
+		if a.Name != namespace.Name() {
+			continue
+		}
+
+		result := &Status{
+			Arrow:    a,
+			Running:  repository.Running(a),
+			Ports:    append([]port.PortRule{}, a.Netbridge...),
+			Warnings: []string{},
+		}
+		for _, rule := range result.Ports {
+			if rule.Diagnosis != "" {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("%s: %s", rule.Name, rule.Diagnosis))
+			}
+		}
+
+		return result, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrNotInstalled, namespace.Name())
+}
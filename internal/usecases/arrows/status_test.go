@@ -0,0 +1,57 @@
+package arrows
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+)
+
+type fakeStatusReader struct {
+	installed []arrow.Arrow
+	running   bool
+}
+
+func (f *fakeStatusReader) Get(ctx context.Context) ([]arrow.Arrow, error) {
+	return f.installed, nil
+}
+
+func (f *fakeStatusReader) Running(a *arrow.Arrow) bool {
+	return f.running
+}
+
+func TestStatus(t *testing.T) {
+	cs2 := newArrow("cs2", "1.0.0")
+	cs2.Netbridge = []port.PortRule{
+		{Name: "GAME_PORT", StartPort: 27015, EndPort: 27015, Reachability: port.ReachabilityReachable},
+		{
+			Name:         "RCON_PORT",
+			StartPort:    27016,
+			EndPort:      27016,
+			Reachability: port.ReachabilityUnreachable,
+			Diagnosis:    "port 27016 is not reachable from the internet",
+		},
+	}
+	repository := &fakeStatusReader{installed: []arrow.Arrow{*cs2}, running: true}
+
+	result, err := status(context.Background(), repository, "cs2")
+	if err != nil {
+		t.Fatalf("status() returned error: %v", err)
+	}
+
+	if !result.Running || len(result.Ports) != 2 || result.Ports[1].Reachability != port.ReachabilityUnreachable {
+		t.Errorf("Expected a running cs2 with its ports, got %+v", result)
+	}
+	if len(result.Warnings) != 1 || result.Warnings[0] != "RCON_PORT: port 27016 is not reachable from the internet" {
+		t.Errorf("Expected the diagnosis as a warning, got %v", result.Warnings)
+	}
+}
+
+func TestStatus_NotInstalled(t *testing.T) {
+	_, err := status(context.Background(), &fakeStatusReader{}, "cs2")
+	if !errors.Is(err, ErrNotInstalled) {
+		t.Errorf("Expected ErrNotInstalled, got %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package arrows
+
+import (
+	"context"
+
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/template"
+)
+
+// Templates reports what rendering the config templates of an
+// installed arrow would change, with a diff for every target.
+func (u *ArrowsUsecase) Templates(
+	ctx context.Context,
+	namespace arrow.ArrowNamespace,
+) ([]template.Change, error) {
+	current, err := u.installed(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.repositories.GetArrows().PlanTemplates(ctx, current)
+}
+
+// RenderTemplates writes the config templates of an installed arrow.
+// Targets edited by hand since they were last rendered are only
+// overwritten when forced.
+func (u *ArrowsUsecase) RenderTemplates(
+	ctx context.Context,
+	namespace arrow.ArrowNamespace,
+	force bool,
+) ([]template.Change, error) {
+	current, err := u.installed(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.render(ctx, current, force)
+}
+
+// render writes the templates of an installed arrow and stores
+// what was rendered.
+func (u *ArrowsUsecase) render(
+	ctx context.Context,
+	a *arrow.Arrow,
+	force bool,
+) ([]template.Change, error) {
+	if len(a.Templates) == 0 {
+		return []template.Change{}, nil
+	}
+
+	changes, err := u.repositories.GetArrows().RenderTemplates(ctx, a, force)
+	if err != nil {
+		return changes, err
+	}
+
+	if _, err := u.repositories.GetArrows().Update(ctx, a); err != nil {
+		return changes, err
+	}
+
+	return changes, nil
+}
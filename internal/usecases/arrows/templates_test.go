@@ -0,0 +1,34 @@
+package arrows
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rabbytesoftware/quiver/internal/infrastructure"
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/repositories"
+)
+
+func TestArrowsUsecase_Templates_NotInstalled(t *testing.T) {
+	t.Setenv("QUIVER_DATABASE_PATH", t.TempDir())
+	usecase := NewArrowsUsecase(repositories.NewRepositories(infrastructure.NewInfrastructure()))
+	ctx := context.Background()
+
+	if _, err := usecase.Templates(ctx, "cs2"); !errors.Is(err, ErrNotInstalled) {
+		t.Errorf("Expected ErrNotInstalled, got %v", err)
+	}
+	if _, err := usecase.RenderTemplates(ctx, "cs2", true); !errors.Is(err, ErrNotInstalled) {
+		t.Errorf("Expected ErrNotInstalled, got %v", err)
+	}
+}
+
+func TestArrowsUsecase_Render_WithoutTemplates(t *testing.T) {
+	t.Setenv("QUIVER_DATABASE_PATH", t.TempDir())
+	usecase := NewArrowsUsecase(repositories.NewRepositories(infrastructure.NewInfrastructure()))
+
+	changes, err := usecase.render(context.Background(), &arrow.Arrow{Name: "cs2"}, false)
+	if err != nil || len(changes) != 0 {
+		t.Errorf("Expected arrows without templates to render nothing, got %v, %v", changes, err)
+	}
+}
@@ -0,0 +1,84 @@
+package arrows
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/system"
+	"github.com/rabbytesoftware/quiver/internal/models/version"
+)
+
+// Update describes a newer published release of an installed arrow.
+type Update struct {
+	Installed *arrow.Arrow `json:"installed"`
+	Latest    *arrow.Arrow `json:"latest"`
+	// Compatible is the newest release within ^installed, if any.
+	Compatible *arrow.Arrow `json:"compatible,omitempty"`
+	// Changelog comes from the newest release that declares one.
+	Changelog system.URL `json:"changelog,omitempty"`
+}
+
+// CheckUpdate looks up the published releases of an installed arrow
+// and returns nil when it is already the newest one.
+func (u *ArrowsUsecase) CheckUpdate(
+	ctx context.Context,
+	installed *arrow.Arrow,
+) (*Update, error) {
+	candidates, err := u.repositories.GetQuivers().FindArrow(ctx, installed.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch manifests for %s: %w", installed.Name, err)
+	}
+
+	return findUpdate(installed, candidates)
+}
+
+// findUpdate picks the newest stable release above the installed
+// version, plus the newest one that keeps API compatibility.
+func findUpdate(
+	installed *arrow.Arrow,
+	candidates []*arrow.Arrow,
+) (*Update, error) {
+	current, err := version.Parse(installed.Version)
+	if err != nil {
+		return nil, fmt.Errorf("installed arrow %s: %w", installed.Name, err)
+	}
+
+	compatible, err := version.ParseConstraint("^" + current.String())
+	if err != nil {
+		return nil, err
+	}
+
+	var update *Update
+	var latest, bestCompatible version.Version
+
+	for _, candidate := range candidates {
+		v, err := version.Parse(candidate.Version)
+		if err != nil || v.IsPrerelease() || !v.GreaterThan(current) {
+			continue
+		}
+
+		if update == nil {
+			update = &Update{Installed: installed}
+		}
+
+		if update.Latest == nil || v.GreaterThan(latest) {
+			update.Latest, latest = candidate, v
+		}
+
+		if compatible.Check(v) && (update.Compatible == nil || v.GreaterThan(bestCompatible)) {
+			update.Compatible, bestCompatible = candidate, v
+		}
+	}
+
+	if update != nil {
+		for _, candidate := range []*arrow.Arrow{update.Latest, update.Compatible} {
+			if candidate != nil && candidate.Changelog != "" {
+				update.Changelog = candidate.Changelog
+				break
+			}
+		}
+	}
+
+	return update, nil
+}
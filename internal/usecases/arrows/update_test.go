@@ -0,0 +1,66 @@
+package arrows
+
+import (
+	"testing"
+
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+)
+
+func TestFindUpdate(t *testing.T) {
+	installed := newArrow("cs2", "1.2.0")
+
+	update, err := findUpdate(installed, []*arrow.Arrow{
+		newArrow("cs2", "1.1.0"),
+		newArrow("cs2", "1.4.1"),
+		newArrow("cs2", "1.3.0"),
+		newArrow("cs2", "2.0.0"),
+		newArrow("cs2", "3.0.0-rc.1"),
+		newArrow("cs2", "not-a-version"),
+	})
+	if err != nil {
+		t.Fatalf("findUpdate() returned error: %v", err)
+	}
+	if update == nil {
+		t.Fatal("findUpdate() should report an update")
+	}
+	if update.Latest.Version != "2.0.0" {
+		t.Errorf("Expected latest 2.0.0, got %s", update.Latest.Version)
+	}
+	if update.Compatible == nil || update.Compatible.Version != "1.4.1" {
+		t.Errorf("Expected compatible 1.4.1, got %+v", update.Compatible)
+	}
+}
+
+func TestFindUpdate_CalendarVersions(t *testing.T) {
+	installed := newArrow("quiver.chat", "27.7.1-d278aae")
+
+	update, err := findUpdate(installed, []*arrow.Arrow{
+		newArrow("quiver.chat", "27.7.1-d278aae"),
+		newArrow("quiver.chat", "27.8.0-a1b2c3d"),
+	})
+	if err != nil {
+		t.Fatalf("findUpdate() returned error: %v", err)
+	}
+	if update == nil || update.Latest.Version != "27.8.0-a1b2c3d" {
+		t.Errorf("Expected an update to 27.8.0-a1b2c3d, got %+v", update)
+	}
+}
+
+func TestFindUpdate_UpToDate(t *testing.T) {
+	update, err := findUpdate(newArrow("cs2", "2.0.0"), []*arrow.Arrow{
+		newArrow("cs2", "1.0.0"),
+		newArrow("cs2", "2.0.0"),
+	})
+	if err != nil {
+		t.Fatalf("findUpdate() returned error: %v", err)
+	}
+	if update != nil {
+		t.Errorf("Expected no update, got %+v", update)
+	}
+}
+
+func TestFindUpdate_InvalidInstalledVersion(t *testing.T) {
+	if _, err := findUpdate(newArrow("cs2", "latest"), nil); err == nil {
+		t.Error("findUpdate() should reject an installed arrow without a valid version")
+	}
+}
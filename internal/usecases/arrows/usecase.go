@@ -1,9 +1,17 @@
 package arrows
 
-import "github.com/rabbytesoftware/quiver/internal/repositories"
+import (
+	"sync"
+
+	"github.com/rabbytesoftware/quiver/internal/repositories"
+)
 
 type ArrowsUsecase struct {
 	repositories *repositories.Repositories
+
+	mu          sync.RWMutex
+	outdated    *OutdatedReport
+	maintenance []MaintenanceResult
 }
 
 func NewArrowsUsecase(
@@ -0,0 +1,235 @@
+package arrows
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/rabbytesoftware/quiver/internal/core/secrets"
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/template"
+	"github.com/rabbytesoftware/quiver/internal/models/variable"
+)
+
+var ErrInvalidVariable = errors.New("invalid variable")
+
+// VariableValue is a variable of an installed arrow as shown to
+// clients. Sensitive values and defaults are masked unless revealed.
+type VariableValue struct {
+	Name      string                `json:"name"`
+	Value     string                `json:"value"`
+	Default   string                `json:"default"`
+	Set       bool                  `json:"set"`
+	Sensitive bool                  `json:"sensitive"`
+	Type      variable.VariableType `json:"type"`
+	Values    []string              `json:"values,omitempty"`
+	Min       int                   `json:"min,omitempty"`
+	Max       int                   `json:"max,omitempty"`
+}
+
+// Reconfiguration is the result of changing the variables of an
+// installed arrow.
+type Reconfiguration struct {
+	Variables []VariableValue        `json:"variables"`
+	Changes   []arrow.VariableChange `json:"changes"`
+	Templates []template.Change      `json:"templates"`
+	Restarted bool                   `json:"restarted"`
+}
+
+// Variables returns the current variable values of an installed
+// arrow. Sensitive values are masked unless reveal is set, which the
+// caller has to be authorized for.
+func (u *ArrowsUsecase) Variables(
+	ctx context.Context,
+	namespace arrow.ArrowNamespace,
+	reveal bool,
+) ([]VariableValue, error) {
+	current, err := u.installed(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return variableValues(current, reveal), nil
+}
+
+// VariableChanges returns who changed which variable of an
+// installed arrow, oldest first.
+func (u *ArrowsUsecase) VariableChanges(
+	ctx context.Context,
+	namespace arrow.ArrowNamespace,
+) ([]arrow.VariableChange, error) {
+	current, err := u.installed(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.repositories.GetArrows().VariableChanges(ctx, current.ID)
+}
+
+// SetVariables changes the variable values of an installed arrow. A nil
+// value resets a variable to its default. The values are validated
+// together, so either all of them or none are applied. Every change is
+// recorded in the audit trail under actor, the config templates are
+// rendered again and, with restart, a running instance is restarted so
+// it picks up the new values.
+func (u *ArrowsUsecase) SetVariables(
+	ctx context.Context,
+	namespace arrow.ArrowNamespace,
+	values map[string]*string,
+	actor string,
+	restart bool,
+) (*Reconfiguration, error) {
+	current, err := u.installed(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := reconfigure(current, values, actor, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	repository := u.repositories.GetArrows()
+	if _, err := repository.Update(ctx, current); err != nil {
+		return nil, err
+	}
+
+	for i := range changes {
+		if err := repository.RecordVariableChange(ctx, &changes[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &Reconfiguration{
+		Changes:   changes,
+		Templates: []template.Change{},
+	}
+
+	if len(changes) > 0 {
+		if result.Templates, err = u.render(ctx, current, false); err != nil {
+			return nil, err
+		}
+
+		if restart && repository.Running(current) {
+			if err := repository.Stop(ctx, current); err != nil {
+				return nil, err
+			}
+			if err := repository.Start(ctx, current); err != nil {
+				return nil, err
+			}
+			u.openPorts(ctx, current)
+			result.Restarted = true
+		}
+	}
+
+	result.Variables = variableValues(current, false)
+
+	return result, nil
+}
+
+// reconfigure applies values to the variables of current and returns
+// what changed. Nothing is applied when any value is invalid.
+func reconfigure(
+	current *arrow.Arrow,
+	values map[string]*string,
+	actor string,
+	now time.Time,
+) ([]arrow.VariableChange, error) {
+	index := map[string]int{}
+	for i, v := range current.Variables {
+		index[v.Name] = i
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		i, ok := index[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s has no variable %s", current.Name, name))
+			continue
+		}
+
+		if value := values[name]; value != nil {
+			if err := current.Variables[i].Validate(*value); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidVariable, errors.Join(errs...))
+	}
+
+	changes := []arrow.VariableChange{}
+	for _, name := range names {
+		v := &current.Variables[index[name]]
+		from := v.Current()
+
+		v.Value = values[name]
+		if v.Value != nil {
+			value := *v.Value
+			v.Value = &value
+		}
+
+		if v.Sensitive {
+			secrets.Register(v.Current())
+		}
+
+		if to := v.Current(); to != from {
+			changes = append(changes, *arrow.NewVariableChange(current, *v, from, to, actor, now))
+		}
+	}
+
+	return changes, nil
+}
+
+// carryVariables keeps the values configured for an arrow when it is
+// replaced by another version, as long as they are still valid.
+func carryVariables(from, to *arrow.Arrow) {
+	for _, previous := range from.Variables {
+		if previous.Value == nil {
+			continue
+		}
+
+		for i := range to.Variables {
+			if to.Variables[i].Name != previous.Name || to.Variables[i].Validate(*previous.Value) != nil {
+				continue
+			}
+
+			value := *previous.Value
+			to.Variables[i].Value = &value
+		}
+	}
+}
+
+func variableValues(a *arrow.Arrow, reveal bool) []VariableValue {
+	values := make([]VariableValue, 0, len(a.Variables))
+
+	for _, v := range a.Variables {
+		value, def := v.Display(v.Current()), v.Display(v.Default)
+		if reveal {
+			value, def = v.Current(), v.Default
+		}
+
+		values = append(values, VariableValue{
+			Name:      v.Name,
+			Value:     value,
+			Default:   def,
+			Set:       v.Value != nil,
+			Sensitive: v.Sensitive,
+			Type:      v.Type,
+			Values:    v.Values,
+			Min:       v.Min,
+			Max:       v.Max,
+		})
+	}
+
+	return values
+}
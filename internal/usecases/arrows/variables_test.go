@@ -0,0 +1,158 @@
+package arrows
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rabbytesoftware/quiver/internal/core/secrets"
+	"github.com/rabbytesoftware/quiver/internal/infrastructure"
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/variable"
+	"github.com/rabbytesoftware/quiver/internal/repositories"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func variablesArrow() *arrow.Arrow {
+	return &arrow.Arrow{
+		Name: "cs2",
+		Variables: []variable.Variable{
+			{Name: "PORT", Default: "27015", Type: variable.VariableTypeNumber, Min: 1024, Max: 65535},
+			{Name: "MAP", Default: "de_dust2", Values: []string{"de_dust2", "de_inferno"}},
+			{Name: "PASSWORD", Default: "", Sensitive: true},
+		},
+	}
+}
+
+func TestArrowsUsecase_Variables_NotInstalled(t *testing.T) {
+	t.Setenv("QUIVER_DATABASE_PATH", t.TempDir())
+	usecase := NewArrowsUsecase(repositories.NewRepositories(infrastructure.NewInfrastructure()))
+	ctx := context.Background()
+
+	if _, err := usecase.Variables(ctx, "cs2", false); !errors.Is(err, ErrNotInstalled) {
+		t.Errorf("Expected ErrNotInstalled, got %v", err)
+	}
+	if _, err := usecase.VariableChanges(ctx, "cs2"); !errors.Is(err, ErrNotInstalled) {
+		t.Errorf("Expected ErrNotInstalled, got %v", err)
+	}
+	values := map[string]*string{"PORT": strPtr("27016")}
+	if _, err := usecase.SetVariables(ctx, "cs2", values, "admin", true); !errors.Is(err, ErrNotInstalled) {
+		t.Errorf("Expected ErrNotInstalled, got %v", err)
+	}
+}
+
+func TestReconfigure(t *testing.T) {
+	a := variablesArrow()
+	now := time.Now()
+
+	changes, err := reconfigure(a, map[string]*string{
+		"PORT":     strPtr("27016"),
+		"MAP":      strPtr("de_dust2"),
+		"PASSWORD": strPtr("hunter2"),
+	}, "admin", now)
+	if err != nil {
+		t.Fatalf("reconfigure() error = %v", err)
+	}
+
+	if len(changes) != 2 {
+		t.Fatalf("Expected unchanged values to be skipped, got %+v", changes)
+	}
+
+	if changes[0].Variable != "PASSWORD" || changes[0].To != variable.Masked || changes[0].Actor != "admin" {
+		t.Errorf("Expected a masked change of PASSWORD, got %+v", changes[0])
+	}
+	if changes[1].Variable != "PORT" || changes[1].From != "27015" || changes[1].To != "27016" {
+		t.Errorf("Expected PORT to change from 27015 to 27016, got %+v", changes[1])
+	}
+
+	if a.Variables[0].Current() != "27016" || a.Variables[2].Current() != "hunter2" {
+		t.Errorf("Expected the values to be applied, got %+v", a.Variables)
+	}
+	if secrets.Redact("hunter2") != secrets.Mask {
+		t.Error("Expected the new password to be registered as a secret")
+	}
+}
+
+func TestReconfigure_Reset(t *testing.T) {
+	a := variablesArrow()
+	a.Variables[0].Value = strPtr("27016")
+
+	changes, err := reconfigure(a, map[string]*string{"PORT": nil}, "admin", time.Now())
+	if err != nil {
+		t.Fatalf("reconfigure() error = %v", err)
+	}
+
+	if len(changes) != 1 || changes[0].To != "27015" || a.Variables[0].Value != nil {
+		t.Errorf("Expected PORT to be reset to its default, got %+v, %+v", changes, a.Variables[0])
+	}
+}
+
+func TestReconfigure_Invalid(t *testing.T) {
+	testCases := []struct {
+		name   string
+		values map[string]*string
+	}{
+		{"out of range", map[string]*string{"PORT": strPtr("80")}},
+		{"not allowed", map[string]*string{"MAP": strPtr("de_nuke")}},
+		{"unknown", map[string]*string{"RCON": strPtr("secret")}},
+		{"one of many", map[string]*string{"PORT": strPtr("27016"), "MAP": strPtr("de_nuke")}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := variablesArrow()
+
+			_, err := reconfigure(a, tc.values, "admin", time.Now())
+			if !errors.Is(err, ErrInvalidVariable) {
+				t.Fatalf("Expected ErrInvalidVariable, got %v", err)
+			}
+
+			for _, v := range a.Variables {
+				if v.Value != nil {
+					t.Errorf("Expected nothing to be applied, got %s=%s", v.Name, *v.Value)
+				}
+			}
+		})
+	}
+}
+
+func TestCarryVariables(t *testing.T) {
+	from := variablesArrow()
+	from.Variables[0].Value = strPtr("27016")
+	from.Variables[1].Value = strPtr("de_inferno")
+
+	to := variablesArrow()
+	to.Variables[1].Values = []string{"de_dust2"}
+
+	carryVariables(from, to)
+
+	if to.Variables[0].Current() != "27016" {
+		t.Errorf("Expected PORT to be carried over, got %s", to.Variables[0].Current())
+	}
+	if to.Variables[1].Value != nil {
+		t.Errorf("Expected a value no longer allowed to be dropped, got %s", *to.Variables[1].Value)
+	}
+}
+
+func TestVariableValues_MasksSensitive(t *testing.T) {
+	a := variablesArrow()
+	a.Variables[2].Value = strPtr("hunter2")
+
+	values := variableValues(a, false)
+
+	if values[2].Value != variable.Masked || !values[2].Set {
+		t.Errorf("Expected the password to be masked, got %+v", values[2])
+	}
+	if values[0].Value != "27015" || values[0].Set {
+		t.Errorf("Expected PORT to show its default, got %+v", values[0])
+	}
+
+	revealed := variableValues(a, true)
+	if revealed[2].Value != "hunter2" {
+		t.Errorf("Expected the password to be revealed, got %+v", revealed[2])
+	}
+}
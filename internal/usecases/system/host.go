@@ -0,0 +1,25 @@
+package system
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rabbytesoftware/quiver/internal/models/requirement"
+)
+
+var ErrProbeUnavailable = errors.New("host probing is not available")
+
+// Host reports what the machine offers to arrows: platform, CPU,
+// memory, free disk and network link speed.
+func (u *SystemUsecase) Host(ctx context.Context) (*requirement.Host, error) {
+	if u.repositories == nil {
+		return nil, ErrProbeUnavailable
+	}
+
+	host := u.repositories.GetSystem().Host(ctx)
+	if host == nil {
+		return nil, ErrProbeUnavailable
+	}
+
+	return host, nil
+}
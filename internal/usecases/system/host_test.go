@@ -0,0 +1,23 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rabbytesoftware/quiver/internal/infrastructure"
+	"github.com/rabbytesoftware/quiver/internal/repositories"
+)
+
+func TestSystemUsecase_Host(t *testing.T) {
+	usecase := NewSystemUsecase(repositories.NewRepositories(infrastructure.NewInfrastructure()))
+
+	host, err := usecase.Host(context.Background())
+	if err != nil || host == nil {
+		t.Fatalf("Host() = %v, %v", host, err)
+	}
+
+	if _, err := NewSystemUsecase(nil).Host(context.Background()); !errors.Is(err, ErrProbeUnavailable) {
+		t.Errorf("Expected ErrProbeUnavailable, got %v", err)
+	}
+}
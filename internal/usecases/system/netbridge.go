@@ -0,0 +1,51 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rabbytesoftware/quiver/internal/core/config"
+	"github.com/rabbytesoftware/quiver/internal/core/watcher"
+	"github.com/rabbytesoftware/quiver/internal/models/port"
+)
+
+var ErrNetbridgeUnavailable = errors.New("netbridge is not available")
+
+// Netbridge reports whether ports can be forwarded on the gateway
+// and which strategy does it.
+func (u *SystemUsecase) Netbridge(ctx context.Context) (*port.NetbridgeStatus, error) {
+	if u.repositories == nil {
+		return nil, ErrNetbridgeUnavailable
+	}
+
+	status := u.repositories.GetSystem().Netbridge(ctx)
+	if status == nil {
+		return nil, ErrNetbridgeUnavailable
+	}
+
+	return status, nil
+}
+
+// ProbeNetbridge picks the port forwarding strategy at startup, so
+// the first forwarded port does not wait for discovery, and logs
+// which one is active.
+func (u *SystemUsecase) ProbeNetbridge(ctx context.Context) {
+	if u.repositories == nil || !config.GetNetbridge().Enabled {
+		return
+	}
+
+	err := u.repositories.GetSystem().ProbeNetbridge(ctx)
+	if watcher.GetWatcher() == nil {
+		return
+	}
+
+	if err != nil {
+		watcher.Warn(fmt.Sprintf("Port forwarding is not available: %s", err))
+		return
+	}
+
+	if status := u.repositories.GetSystem().Netbridge(ctx); status != nil {
+		watcher.Info(fmt.Sprintf("Forwarding ports with %s", status.Active))
+	}
+}
@@ -0,0 +1,29 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rabbytesoftware/quiver/internal/infrastructure"
+	"github.com/rabbytesoftware/quiver/internal/infrastructure/netbridge"
+	"github.com/rabbytesoftware/quiver/internal/repositories"
+)
+
+func TestSystemUsecase_Netbridge(t *testing.T) {
+	usecase := NewSystemUsecase(repositories.NewRepositories(&infrastructure.Infrastructure{
+		Netbridge: netbridge.NewNetbridgeWith(false),
+	}))
+
+	status, err := usecase.Netbridge(context.Background())
+	if err != nil || status == nil || status.Enabled {
+		t.Fatalf("Netbridge() = %+v, %v, expected a disabled netbridge", status, err)
+	}
+
+	usecase.ProbeNetbridge(context.Background())
+	NewSystemUsecase(nil).ProbeNetbridge(context.Background())
+
+	if _, err := NewSystemUsecase(nil).Netbridge(context.Background()); !errors.Is(err, ErrNetbridgeUnavailable) {
+		t.Errorf("Expected ErrNetbridgeUnavailable, got %v", err)
+	}
+}
@@ -0,0 +1,367 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rabbytesoftware/quiver/internal/core/watcher"
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/schedule"
+	"github.com/rabbytesoftware/quiver/internal/repositories"
+	"github.com/rabbytesoftware/quiver/internal/usecases/arrows"
+)
+
+var (
+	ErrInvalidTask  = errors.New("invalid task")
+	ErrTaskNotFound = errors.New("task not found")
+	ErrTaskRunning  = errors.New("task is already running")
+)
+
+// runHistorySize is how many runs are returned per task by default.
+const runHistorySize = 20
+
+// executor runs task actions on installed arrows.
+type executor interface {
+	Start(ctx context.Context, namespace arrow.ArrowNamespace) error
+	Stop(ctx context.Context, namespace arrow.ArrowNamespace) error
+	Restart(ctx context.Context, namespace arrow.ArrowNamespace) error
+	RunMethod(ctx context.Context, namespace arrow.ArrowNamespace, method string) error
+	Backup(ctx context.Context, namespace arrow.ArrowNamespace, quiesce bool) (*arrow.Backup, error)
+}
+
+type TasksUsecase struct {
+	repositories *repositories.Repositories
+	arrows       executor
+
+	// ? A task runs once at a time, however long a run takes.
+	mu      sync.Mutex
+	running map[uuid.UUID]bool
+}
+
+func NewTasksUsecase(
+	repositories *repositories.Repositories,
+	arrows *arrows.ArrowsUsecase,
+) *TasksUsecase {
+	return &TasksUsecase{
+		repositories: repositories,
+		arrows:       arrows,
+		running:      map[uuid.UUID]bool{},
+	}
+}
+
+// List returns every scheduled task, oldest first.
+func (u *TasksUsecase) List(ctx context.Context) ([]*schedule.Task, error) {
+	return u.repositories.GetTasks().Get(ctx)
+}
+
+// Create schedules a task on an installed arrow.
+func (u *TasksUsecase) Create(
+	ctx context.Context,
+	task *schedule.Task,
+) (*schedule.Task, error) {
+	if err := task.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTask, err)
+	}
+
+	installed, err := u.installed(ctx, task.Arrow)
+	if err != nil {
+		return nil, err
+	}
+	if !installed {
+		return nil, fmt.Errorf("%w: %s", arrows.ErrNotInstalled, task.Arrow)
+	}
+
+	task.ID = uuid.New()
+	task.LastRun = nil
+	task.CreatedAt = time.Now()
+
+	if err := u.repositories.GetTasks().Save(ctx, task); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// Get returns a single task.
+func (u *TasksUsecase) Get(
+	ctx context.Context,
+	id uuid.UUID,
+) (*schedule.Task, error) {
+	task, err := u.repositories.GetTasks().GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrTaskNotFound, id)
+	}
+
+	return task, nil
+}
+
+// SetEnabled pauses or resumes a task.
+func (u *TasksUsecase) SetEnabled(
+	ctx context.Context,
+	id uuid.UUID,
+	enabled bool,
+) (*schedule.Task, error) {
+	task, err := u.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	task.Enabled = enabled
+	if err := u.repositories.GetTasks().Save(ctx, task); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// Delete unschedules a task and forgets its runs.
+func (u *TasksUsecase) Delete(
+	ctx context.Context,
+	id uuid.UUID,
+) error {
+	if _, err := u.Get(ctx, id); err != nil {
+		return err
+	}
+
+	return u.repositories.GetTasks().Delete(ctx, id)
+}
+
+// Runs returns the latest runs of a task, newest first.
+func (u *TasksUsecase) Runs(
+	ctx context.Context,
+	id uuid.UUID,
+) ([]*schedule.Run, error) {
+	if _, err := u.Get(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return u.repositories.GetTasks().Runs(ctx, id, runHistorySize)
+}
+
+// RunNow runs a task right away, outside of its schedule.
+func (u *TasksUsecase) RunNow(
+	ctx context.Context,
+	id uuid.UUID,
+) (*schedule.Run, error) {
+	task, err := u.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !u.claim(task.ID) {
+		return nil, fmt.Errorf("%w: %s", ErrTaskRunning, describe(task))
+	}
+	defer u.release(task.ID)
+
+	return u.execute(ctx, task, time.Now()), nil
+}
+
+// RunDue runs every enabled task whose schedule matches now, each in
+// its own goroutine, and waits for them. A task whose previous run
+// has not finished is recorded as skipped.
+func (u *TasksUsecase) RunDue(
+	ctx context.Context,
+	now time.Time,
+) []*schedule.Run {
+	tasks, err := u.repositories.GetTasks().Get(ctx)
+	if err != nil {
+		logWarn(fmt.Sprintf("Failed to load scheduled tasks: %s", err))
+		return nil
+	}
+
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		runs []*schedule.Run
+	)
+
+	for _, task := range tasks {
+		if !task.Due(now) {
+			continue
+		}
+
+		if !u.claim(task.ID) {
+			skipped := u.skip(ctx, task, now, "the previous run has not finished")
+
+			mu.Lock()
+			runs = append(runs, skipped)
+			mu.Unlock()
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer u.release(task.ID)
+
+			run := u.execute(ctx, task, now)
+
+			mu.Lock()
+			runs = append(runs, run)
+			mu.Unlock()
+		}()
+	}
+
+	wg.Wait()
+	return runs
+}
+
+// Watch runs due tasks at the start of every minute until ctx is
+// done. Tasks are read from the database on every tick, so they
+// survive restarts. A tick does not wait for the runs of the
+// previous one, so a long run only holds up its own task.
+func (u *TasksUsecase) Watch(ctx context.Context) {
+	for {
+		now := time.Now()
+		next := now.Truncate(time.Minute).Add(time.Minute)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(next.Sub(now)):
+			go u.RunDue(ctx, next)
+		}
+	}
+}
+
+// claim marks a task as running, reporting false when it already is.
+func (u *TasksUsecase) claim(id uuid.UUID) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.running[id] {
+		return false
+	}
+	u.running[id] = true
+
+	return true
+}
+
+func (u *TasksUsecase) release(id uuid.UUID) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	delete(u.running, id)
+}
+
+// skip records and logs that a due task did not run.
+func (u *TasksUsecase) skip(
+	ctx context.Context,
+	task *schedule.Task,
+	now time.Time,
+	reason string,
+) *schedule.Run {
+	run := schedule.NewSkippedRun(task, now, reason)
+
+	if err := u.repositories.GetTasks().RecordRun(ctx, run); err != nil {
+		logWarn(fmt.Sprintf("Failed to record run of task %s: %s", task.ID, err))
+	}
+
+	logRun(task, run)
+
+	return run
+}
+
+// execute runs a task, records the outcome and logs it.
+func (u *TasksUsecase) execute(
+	ctx context.Context,
+	task *schedule.Task,
+	now time.Time,
+) *schedule.Run {
+	startedAt := time.Now()
+	err := dispatch(ctx, u.arrows, task)
+	run := schedule.NewRun(task, startedAt, time.Now(), err)
+
+	task.LastRun = &now
+	if saveErr := u.repositories.GetTasks().Save(ctx, task); saveErr != nil {
+		logWarn(fmt.Sprintf("Failed to save task %s: %s", task.ID, saveErr))
+	}
+
+	if recordErr := u.repositories.GetTasks().RecordRun(ctx, run); recordErr != nil {
+		logWarn(fmt.Sprintf("Failed to record run of task %s: %s", task.ID, recordErr))
+	}
+
+	logRun(task, run)
+
+	return run
+}
+
+func dispatch(
+	ctx context.Context,
+	arrows executor,
+	task *schedule.Task,
+) error {
+	namespace := arrow.ArrowNamespace(task.Arrow)
+
+	switch task.Action {
+	case schedule.TaskStart:
+		return arrows.Start(ctx, namespace)
+	case schedule.TaskStop:
+		return arrows.Stop(ctx, namespace)
+	case schedule.TaskRestart:
+		return arrows.Restart(ctx, namespace)
+	case schedule.TaskMethod:
+		return arrows.RunMethod(ctx, namespace, task.Method)
+	case schedule.TaskBackup:
+		_, err := arrows.Backup(ctx, namespace, task.Quiesce)
+		return err
+	default:
+		return fmt.Errorf("%w: %s", ErrInvalidTask, task.Action)
+	}
+}
+
+func (u *TasksUsecase) installed(ctx context.Context, name string) (bool, error) {
+	all, err := u.repositories.GetArrows().Get(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	for _, a := range all {
+		if a.Name == arrow.ArrowNamespace(name).Name() {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func describe(task *schedule.Task) string {
+	action := string(task.Action)
+	if task.Action == schedule.TaskMethod {
+		action += " " + task.Method
+	}
+
+	if task.Name != "" {
+		return fmt.Sprintf("%q (%s %s)", task.Name, action, task.Arrow)
+	}
+	return fmt.Sprintf("%s %s", action, task.Arrow)
+}
+
+// logRun reports a run through the watcher, when it is running.
+func logRun(task *schedule.Task, run *schedule.Run) {
+	duration := run.FinishedAt.Sub(run.StartedAt).Round(time.Millisecond)
+
+	switch run.Status {
+	case schedule.RunFailed:
+		logWarn(fmt.Sprintf("Scheduled task %s failed after %s: %s", describe(task), duration, run.Error))
+		return
+	case schedule.RunSkipped:
+		logWarn(fmt.Sprintf("Scheduled task %s was skipped at %s: %s", describe(task), run.StartedAt.Format("15:04"), run.Error))
+		return
+	}
+
+	if watcher.GetWatcher() != nil {
+		watcher.Info(fmt.Sprintf("Scheduled task %s succeeded in %s", describe(task), duration))
+	}
+}
+
+func logWarn(message string) {
+	if watcher.GetWatcher() != nil {
+		watcher.Warn(message)
+	}
+}
@@ -0,0 +1,280 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rabbytesoftware/quiver/internal/infrastructure"
+	"github.com/rabbytesoftware/quiver/internal/models/arrow"
+	"github.com/rabbytesoftware/quiver/internal/models/schedule"
+	"github.com/rabbytesoftware/quiver/internal/repositories"
+	"github.com/rabbytesoftware/quiver/internal/usecases/arrows"
+)
+
+type fakeExecutor struct {
+	mu    sync.Mutex
+	calls []string
+	err   error
+
+	// started and release, when set, hold start calls until the
+	// test lets them finish.
+	started chan struct{}
+	release chan struct{}
+}
+
+func (f *fakeExecutor) record(call string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, call)
+	return f.err
+}
+
+func (f *fakeExecutor) Start(ctx context.Context, namespace arrow.ArrowNamespace) error {
+	if f.started != nil {
+		f.started <- struct{}{}
+		<-f.release
+	}
+	return f.record("start " + namespace.String())
+}
+
+func (f *fakeExecutor) Stop(ctx context.Context, namespace arrow.ArrowNamespace) error {
+	return f.record("stop " + namespace.String())
+}
+
+func (f *fakeExecutor) Restart(ctx context.Context, namespace arrow.ArrowNamespace) error {
+	return f.record("restart " + namespace.String())
+}
+
+func (f *fakeExecutor) RunMethod(ctx context.Context, namespace arrow.ArrowNamespace, method string) error {
+	return f.record(method + " " + namespace.String())
+}
+
+func (f *fakeExecutor) Backup(ctx context.Context, namespace arrow.ArrowNamespace, quiesce bool) (*arrow.Backup, error) {
+	call := "backup " + namespace.String()
+	if quiesce {
+		call += " quiesced"
+	}
+	return &arrow.Backup{}, f.record(call)
+}
+
+func newTestUsecase(t *testing.T) (*TasksUsecase, *fakeExecutor) {
+	t.Helper()
+	t.Setenv("QUIVER_DATABASE_PATH", t.TempDir())
+
+	executor := &fakeExecutor{}
+	usecase := NewTasksUsecase(repositories.NewRepositories(infrastructure.NewInfrastructure()), nil)
+	usecase.arrows = executor
+
+	return usecase, executor
+}
+
+func saveTask(t *testing.T, usecase *TasksUsecase, task *schedule.Task) *schedule.Task {
+	t.Helper()
+
+	task.ID = uuid.New()
+	task.CreatedAt = time.Now()
+	if err := usecase.repositories.GetTasks().Save(context.Background(), task); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	return task
+}
+
+func TestDispatch(t *testing.T) {
+	testCases := []struct {
+		task     schedule.Task
+		expected string
+	}{
+		{schedule.Task{Arrow: "cs2", Action: schedule.TaskStart}, "start cs2"},
+		{schedule.Task{Arrow: "cs2", Action: schedule.TaskStop}, "stop cs2"},
+		{schedule.Task{Arrow: "cs2", Action: schedule.TaskRestart}, "restart cs2"},
+		{schedule.Task{Arrow: "cs2", Action: schedule.TaskMethod, Method: "rotate_map"}, "rotate_map cs2"},
+		{schedule.Task{Arrow: "cs2", Action: schedule.TaskBackup, Quiesce: true}, "backup cs2 quiesced"},
+	}
+
+	for _, tc := range testCases {
+		executor := &fakeExecutor{}
+		if err := dispatch(context.Background(), executor, &tc.task); err != nil {
+			t.Errorf("dispatch(%s) error = %v", tc.task.Action, err)
+		}
+		if len(executor.calls) != 1 || executor.calls[0] != tc.expected {
+			t.Errorf("Expected %q, got %v", tc.expected, executor.calls)
+		}
+	}
+
+	err := dispatch(context.Background(), &fakeExecutor{}, &schedule.Task{Action: "reboot"})
+	if !errors.Is(err, ErrInvalidTask) {
+		t.Errorf("Expected ErrInvalidTask, got %v", err)
+	}
+}
+
+func TestTasksUsecase_Create_Errors(t *testing.T) {
+	usecase, _ := newTestUsecase(t)
+	ctx := context.Background()
+
+	_, err := usecase.Create(ctx, &schedule.Task{Arrow: "cs2", Cron: "0 4 * *", Action: schedule.TaskRestart})
+	if !errors.Is(err, ErrInvalidTask) {
+		t.Errorf("Expected ErrInvalidTask, got %v", err)
+	}
+
+	_, err = usecase.Create(ctx, &schedule.Task{Arrow: "cs2", Cron: "0 4 * * *", Action: schedule.TaskRestart})
+	if !errors.Is(err, arrows.ErrNotInstalled) {
+		t.Errorf("Expected ErrNotInstalled, got %v", err)
+	}
+}
+
+func TestTasksUsecase_RunDue(t *testing.T) {
+	usecase, executor := newTestUsecase(t)
+	ctx := context.Background()
+	now := time.Date(2025, 7, 1, 4, 0, 0, 0, time.UTC)
+
+	restart := saveTask(t, usecase, &schedule.Task{Arrow: "cs2", Cron: "0 4 * * *", Action: schedule.TaskRestart, Enabled: true})
+	saveTask(t, usecase, &schedule.Task{Arrow: "cs2", Cron: "0 5 * * *", Action: schedule.TaskStop, Enabled: true})
+	saveTask(t, usecase, &schedule.Task{Arrow: "cs2", Cron: "0 4 * * *", Action: schedule.TaskStart})
+
+	runs := usecase.RunDue(ctx, now)
+	if len(runs) != 1 || runs[0].TaskID != restart.ID || runs[0].Status != schedule.RunSucceeded {
+		t.Fatalf("Expected only the restart task to run, got %+v", runs)
+	}
+	if len(executor.calls) != 1 || executor.calls[0] != "restart cs2" {
+		t.Errorf("Expected a restart, got %v", executor.calls)
+	}
+
+	if runs := usecase.RunDue(ctx, now.Add(30*time.Second)); len(runs) != 0 {
+		t.Errorf("Expected the task not to run twice in the same minute, got %+v", runs)
+	}
+
+	stored, err := usecase.Get(ctx, restart.ID)
+	if err != nil || stored.LastRun == nil || !stored.LastRun.Equal(now) {
+		t.Errorf("Expected the last run to be persisted, got %+v (%v)", stored, err)
+	}
+
+	history, err := usecase.Runs(ctx, restart.ID)
+	if err != nil || len(history) != 1 {
+		t.Errorf("Expected one recorded run, got %+v (%v)", history, err)
+	}
+}
+
+func TestTasksUsecase_RunDue_LongRun(t *testing.T) {
+	usecase, executor := newTestUsecase(t)
+	executor.started = make(chan struct{})
+	executor.release = make(chan struct{})
+	ctx := context.Background()
+	now := time.Date(2025, 7, 1, 4, 0, 0, 0, time.UTC)
+
+	start := saveTask(t, usecase, &schedule.Task{Arrow: "cs2", Cron: "* * * * *", Action: schedule.TaskStart, Enabled: true})
+	saveTask(t, usecase, &schedule.Task{Arrow: "tf2", Cron: "1 4 * * *", Action: schedule.TaskRestart, Enabled: true})
+
+	done := make(chan []*schedule.Run)
+	go func() { done <- usecase.RunDue(ctx, now) }()
+	<-executor.started
+
+	// ? The start task is still running a minute later.
+	runs := usecase.RunDue(ctx, now.Add(time.Minute))
+	if len(runs) != 2 {
+		t.Fatalf("Expected the restart to run and the start to be skipped, got %+v", runs)
+	}
+	for _, run := range runs {
+		if run.TaskID == start.ID && run.Status != schedule.RunSkipped {
+			t.Errorf("Expected the running task to be skipped, got %+v", run)
+		}
+		if run.TaskID != start.ID && run.Status != schedule.RunSucceeded {
+			t.Errorf("Expected the other task to run, got %+v", run)
+		}
+	}
+
+	if _, err := usecase.RunNow(ctx, start.ID); !errors.Is(err, ErrTaskRunning) {
+		t.Errorf("Expected ErrTaskRunning, got %v", err)
+	}
+
+	close(executor.release)
+	if first := <-done; len(first) != 1 || first[0].Status != schedule.RunSucceeded {
+		t.Errorf("Expected the long run to succeed, got %+v", first)
+	}
+
+	history, err := usecase.Runs(ctx, start.ID)
+	if err != nil || len(history) != 2 {
+		t.Errorf("Expected the run and the skipped run to be recorded, got %+v (%v)", history, err)
+	}
+}
+
+func TestTasksUsecase_RunNow_RecordsFailure(t *testing.T) {
+	usecase, executor := newTestUsecase(t)
+	ctx := context.Background()
+	executor.err = errors.New("process not found")
+
+	task := saveTask(t, usecase, &schedule.Task{Arrow: "cs2", Cron: "0 4 * * *", Action: schedule.TaskStop})
+
+	run, err := usecase.RunNow(ctx, task.ID)
+	if err != nil {
+		t.Fatalf("RunNow() error = %v", err)
+	}
+	if run.Status != schedule.RunFailed || run.Error != "process not found" {
+		t.Errorf("Expected a failed run, got %+v", run)
+	}
+
+	if _, err := usecase.RunNow(ctx, uuid.New()); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("Expected ErrTaskNotFound, got %v", err)
+	}
+}
+
+func TestTasksUsecase_SetEnabledAndDelete(t *testing.T) {
+	usecase, _ := newTestUsecase(t)
+	ctx := context.Background()
+
+	task := saveTask(t, usecase, &schedule.Task{Arrow: "cs2", Cron: "0 4 * * *", Action: schedule.TaskStart, Enabled: true})
+
+	paused, err := usecase.SetEnabled(ctx, task.ID, false)
+	if err != nil || paused.Enabled {
+		t.Fatalf("Expected the task to be paused, got %+v (%v)", paused, err)
+	}
+
+	if err := usecase.Delete(ctx, task.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	tasks, _ := usecase.List(ctx)
+	if len(tasks) != 0 {
+		t.Errorf("Expected no tasks, got %+v", tasks)
+	}
+
+	if err := usecase.Delete(ctx, task.ID); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("Expected ErrTaskNotFound, got %v", err)
+	}
+}
+
+func TestTasksUsecase_Watch_StopsWithContext(t *testing.T) {
+	usecase, _ := newTestUsecase(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		usecase.Watch(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("Expected Watch to return once the context is done")
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	task := &schedule.Task{Arrow: "cs2", Action: schedule.TaskMethod, Method: "rotate_map"}
+	if got := describe(task); got != "method rotate_map cs2" {
+		t.Errorf("Unexpected description %q", got)
+	}
+
+	task.Name = "nightly rotation"
+	if got := describe(task); got != `"nightly rotation" (method rotate_map cs2)` {
+		t.Errorf("Unexpected description %q", got)
+	}
+}
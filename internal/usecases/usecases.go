@@ -5,20 +5,25 @@ import (
 	"github.com/rabbytesoftware/quiver/internal/usecases/arrows"
 	"github.com/rabbytesoftware/quiver/internal/usecases/quivers"
 	"github.com/rabbytesoftware/quiver/internal/usecases/system"
+	"github.com/rabbytesoftware/quiver/internal/usecases/tasks"
 )
 
 type Usecases struct {
 	Arrows  *arrows.ArrowsUsecase
 	Quivers *quivers.QuiversUsecase
 	System  *system.SystemUsecase
+	Tasks   *tasks.TasksUsecase
 }
 
 func NewUsecases(
 	repositories *repositories.Repositories,
 ) *Usecases {
+	arrowsUsecase := arrows.NewArrowsUsecase(repositories)
+
 	return &Usecases{
-		Arrows:  arrows.NewArrowsUsecase(repositories),
+		Arrows:  arrowsUsecase,
 		Quivers: quivers.NewQuiversUsecase(repositories),
 		System:  system.NewSystemUsecase(repositories),
+		Tasks:   tasks.NewTasksUsecase(repositories, arrowsUsecase),
 	}
 }
@@ -28,6 +28,10 @@ func TestNewUsecases(t *testing.T) {
 	if usecases.System == nil {
 		t.Error("System usecase is not initialized")
 	}
+
+	if usecases.Tasks == nil {
+		t.Error("Tasks usecase is not initialized")
+	}
 }
 
 func TestUsecasesStructure(t *testing.T) {